@@ -22,6 +22,8 @@ func adminCommands() []*cobra.Command {
 		adminMigrations(),
 		adminPlugins(),
 		adminBroadcasts(),
+		adminQuotas(),
+		adminResourceClasses(),
 		adminErrors(),
 		adminCurl(),
 	}
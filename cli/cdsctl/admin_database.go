@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/cli"
@@ -20,6 +23,7 @@ func adminDatabase() *cobra.Command {
 		cli.NewCommand(adminDatabaseSignatureRoll, adminDatabaseSignatureRollFunc, nil),
 		cli.NewGetCommand(adminDatabaseEncryptionResume, adminDatabaseEncryptionResumeFunc, nil),
 		cli.NewCommand(adminDatabaseEncryptionRoll, adminDatabaseEncryptionRollFunc, nil),
+		cli.NewCommand(adminDatabaseRotateKeys, adminDatabaseRotateKeysFunc, nil),
 	})
 }
 
@@ -126,3 +130,84 @@ func adminDatabaseEncryptionRollFunc(args cli.Values) error {
 	return nil
 
 }
+
+var adminDatabaseRotateKeys = cli.Command{
+	Name:  "rotate-keys",
+	Short: "Encrypt and sign again all the data in database with the latest keys",
+	Long: `Encrypt and sign again all the data in database with the latest encryption and signature keys, by batches of tuples.
+
+When all the entities are rolled, older keys are no longer used and can be removed from the API configuration.
+If the rotation is interrupted, it can be resumed with the --from flag:
+
+	cdsctl admin database rotate-keys --from project.dbProjectKey:1234
+`,
+	VariadicArgs: cli.Arg{
+		Name: "entity",
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "from",
+			Usage: "Resume the rotation from an entity and the last rolled primary key (ie. entity:pk)",
+		},
+		{
+			Name:    "batch-size",
+			Usage:   "Number of tuples rolled by request",
+			Default: "100",
+		},
+	},
+}
+
+func adminDatabaseRotateKeysFunc(args cli.Values) error {
+	batchSize, err := args.GetInt64("batch-size")
+	if err != nil {
+		return err
+	}
+
+	entities := args.GetStringSlice("entity")
+	if len(entities) == 0 {
+		entities, err = client.AdminDatabaseRotationEntities()
+		if err != nil {
+			return err
+		}
+	}
+
+	var fromEntity, from string
+	if f := args.GetString("from"); f != "" {
+		i := strings.LastIndex(f, ":")
+		if i < 0 {
+			return fmt.Errorf("invalid value for --from, expected entity:pk")
+		}
+		fromEntity, from = f[:i], f[i+1:]
+		var found bool
+		for i, e := range entities {
+			if e == fromEntity {
+				entities = entities[i:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown entity %s", fromEntity)
+		}
+	}
+
+	for _, e := range entities {
+		if e != fromEntity {
+			from = ""
+		}
+		for {
+			res, err := client.AdminDatabaseRotateEntity(e, from, batchSize)
+			if err != nil {
+				fmt.Printf("rotation interrupted, resume it with: --from %s:%s\n", e, from)
+				return err
+			}
+			fmt.Printf("%s: %d/%d\n", e, res.Done, res.Total)
+			if res.Next == "" {
+				break
+			}
+			from = res.Next
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminQuotasCmd = cli.Command{
+	Name:  "quotas",
+	Short: "Manage CDS job quotas",
+	Long: `Job quotas limit the number of jobs running at the same time for a project, a group or a worker model.
+
+The target of a quota is the project key, the group name or the worker model path (ex: shared.infra/debian).`,
+}
+
+func adminQuotas() *cobra.Command {
+	return cli.NewCommand(adminQuotasCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminQuotasListCmd, adminQuotasListRun, nil),
+		cli.NewCommand(adminQuotasSetCmd, adminQuotasSetRun, nil),
+		cli.NewCommand(adminQuotasDeleteCmd, adminQuotasDeleteRun, nil),
+	})
+}
+
+var adminQuotasListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS job quotas with their usage",
+}
+
+func adminQuotasListRun(v cli.Values) (cli.ListResult, error) {
+	usage, err := client.QueueJobQuotas()
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(usage), nil
+}
+
+var adminQuotasSetCmd = cli.Command{
+	Name:  "set",
+	Short: "Create or update a CDS job quota",
+	Args: []cli.Arg{
+		{Name: "type"},
+		{Name: "target"},
+		{Name: "max-running-jobs"},
+	},
+	Example: `cdsctl admin quotas set project MY_PROJECT 20
+cdsctl admin quotas set group my-group 50
+cdsctl admin quotas set worker_model shared.infra/debian 10`,
+}
+
+func adminQuotasSetRun(v cli.Values) error {
+	max, err := v.GetInt64("max-running-jobs")
+	if err != nil {
+		return fmt.Errorf("max-running-jobs parameter have to be an integer")
+	}
+	return client.AdminJobQuotaSet(sdk.JobQuota{
+		Type:           v.GetString("type"),
+		Target:         v.GetString("target"),
+		MaxRunningJobs: max,
+	})
+}
+
+var adminQuotasDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a CDS job quota",
+	Args: []cli.Arg{
+		{Name: "type"},
+		{Name: "target"},
+	},
+}
+
+func adminQuotasDeleteRun(v cli.Values) error {
+	return client.AdminJobQuotaDelete(v.GetString("type"), v.GetString("target"))
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminResourceClassesCmd = cli.Command{
+	Name:  "resource-classes",
+	Short: "Manage CDS resource classes",
+	Long: `Resource classes are named cpu and memory pairs (ex: small, large) that jobs can require with a resource-class requirement.
+
+The cpu is a number of CPUs (ex: 2 or 0.5), the memory is in megabytes.`,
+}
+
+func adminResourceClasses() *cobra.Command {
+	return cli.NewCommand(adminResourceClassesCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminResourceClassesListCmd, adminResourceClassesListRun, nil),
+		cli.NewCommand(adminResourceClassesSetCmd, adminResourceClassesSetRun, nil),
+		cli.NewCommand(adminResourceClassesDeleteCmd, adminResourceClassesDeleteRun, nil),
+	})
+}
+
+var adminResourceClassesListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS resource classes",
+}
+
+func adminResourceClassesListRun(v cli.Values) (cli.ListResult, error) {
+	cs, err := client.ResourceClassList()
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(cs), nil
+}
+
+var adminResourceClassesSetCmd = cli.Command{
+	Name:  "set",
+	Short: "Create or update a CDS resource class",
+	Args: []cli.Arg{
+		{Name: "name"},
+		{Name: "cpu"},
+		{Name: "memory"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "description",
+			Usage: "Description of the resource class",
+		},
+	},
+	Example: `cdsctl admin resource-classes set small 0.5 1024
+cdsctl admin resource-classes set large 4 8192 --description "For the big builds"`,
+}
+
+func adminResourceClassesSetRun(v cli.Values) error {
+	cpu, err := strconv.ParseFloat(v.GetString("cpu"), 64)
+	if err != nil {
+		return fmt.Errorf("cpu parameter have to be a number")
+	}
+	memory, err := v.GetInt64("memory")
+	if err != nil {
+		return fmt.Errorf("memory parameter have to be an integer")
+	}
+	return client.ResourceClassSet(sdk.ResourceClass{
+		Name:        v.GetString("name"),
+		Description: v.GetString("description"),
+		CPU:         cpu,
+		Memory:      memory,
+	})
+}
+
+var adminResourceClassesDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a CDS resource class",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func adminResourceClassesDeleteRun(v cli.Values) error {
+	return client.ResourceClassDelete(v.GetString("name"))
+}
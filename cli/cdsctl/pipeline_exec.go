@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/engine/worker/pkg/localexec"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var pipelineExecCmd = cli.Command{
+	Name:  "exec",
+	Short: "Run a pipeline file locally",
+	Long: `Run all the jobs of a pipeline file on your host, in your shell or in a docker container with --image.
+
+Only script, gitClone and checkout steps are run, other builtin actions and plugins need a CDS worker and are skipped.
+
+When authenticated, actions are loaded from CDS and project variables can be used with --project:
+
+	cdsctl pipeline exec build.pip.yml --project MYPROJ
+
+Otherwise, variables can be given with a yaml or json file that contains the variables by name:
+
+	cdsctl pipeline exec build.pip.yml --vars vars.yml --image golang:1.13
+`,
+	Args: []cli.Arg{
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "project",
+			Usage: "Key of the project to use the variables from",
+		},
+		{
+			Name:  "vars",
+			Usage: "Path of a yaml or json file that contains variables (ie. cds.proj.name: value)",
+		},
+		{
+			Name:  "image",
+			Usage: "Docker image used to run the script steps instead of the host shell",
+		},
+		{
+			Name:  "workspace",
+			Usage: "Directory where the jobs are run, a temporary directory is used and removed if not set",
+		},
+	},
+}
+
+func pipelineExecRun(v cli.Values) error {
+	// technical logs of the builtin actions are only displayed in verbose mode
+	logLevel := "error"
+	if v.GetBool("verbose") || os.Getenv("CDS_VERBOSE") == "true" {
+		logLevel = "debug"
+	}
+	log.Initialize(&log.Conf{Level: logLevel})
+
+	path := v.GetString("path")
+	reader, format, err := exportentities.OpenPath(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close() // nolint
+	btes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	payload, err := exportentities.ParsePipeline(format, btes)
+	if err != nil {
+		return err
+	}
+	pip, err := payload.Pipeline()
+	if err != nil {
+		return err
+	}
+
+	opts := localexec.Options{
+		Workspace: v.GetString("workspace"),
+		Image:     v.GetString("image"),
+		Output:    os.Stdout,
+	}
+
+	authenticated := client != nil && cfg != nil && cfg.Host != ""
+
+	if projectKey := v.GetString("project"); projectKey != "" {
+		if !authenticated {
+			return fmt.Errorf("you must be logged in to use the variables of project %s", projectKey)
+		}
+		vars, err := client.ProjectVariablesList(projectKey)
+		if err != nil {
+			return err
+		}
+		opts.Parameters = append(opts.Parameters, sdk.VariablesToParameters("cds.proj", vars)...)
+		sdk.AddParameter(&opts.Parameters, "cds.project", sdk.StringParameter, projectKey)
+	}
+
+	if varsPath := v.GetString("vars"); varsPath != "" {
+		reader, format, err := exportentities.OpenPath(varsPath)
+		if err != nil {
+			return err
+		}
+		defer reader.Close() // nolint
+		btes, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		var vars map[string]string
+		if err := exportentities.Unmarshal(btes, format, &vars); err != nil {
+			return fmt.Errorf("invalid vars file %s: %v", varsPath, err)
+		}
+		opts.Parameters = sdk.ParametersMerge(opts.Parameters, sdk.ParametersFromMap(vars))
+	}
+
+	if authenticated {
+		opts.ResolveAction = func(groupName, name string) (*sdk.Action, error) {
+			if groupName == "" {
+				groupName = sdk.SharedInfraGroupName
+			}
+			return client.ActionGet(groupName, name)
+		}
+	}
+
+	if opts.Workspace == "" {
+		opts.Workspace, err = ioutil.TempDir("", "cdsctl-exec")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(opts.Workspace) // nolint
+	}
+
+	res, err := localexec.Run(context.Background(), *pip, opts)
+	if err != nil {
+		return err
+	}
+	if res.Status != sdk.StatusSuccess {
+		return fmt.Errorf("pipeline %s: %s", pip.Name, res.Status)
+	}
+	fmt.Printf("pipeline %s: %s\n", pip.Name, res.Status)
+	return nil
+}
@@ -30,6 +30,7 @@ func projectCommands() []*cobra.Command {
 		projectVariable(),
 		projectIntegration(),
 		projectRepositoryManager(),
+		projectLock(),
 	}
 }
 
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var projectLockCmd = cli.Command{
+	Name:  "lock",
+	Short: "Manage CDS project locks shared by workflows",
+}
+
+func projectLock() *cobra.Command {
+	return cli.NewCommand(projectLockCmd, nil, []*cobra.Command{
+		cli.NewListCommand(projectLockListCmd, projectLockListRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectLockReleaseCmd, projectLockReleaseRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var projectLockListCmd = cli.Command{
+	Name:  "list",
+	Short: "List held and waiting CDS project locks",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectLockListRun(v cli.Values) (cli.ListResult, error) {
+	locks, err := client.ProjectLockList(v.GetString(_ProjectKey))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(locks), nil
+}
+
+var projectLockReleaseCmd = cli.Command{
+	Name:  "release",
+	Short: "Release a CDS project lock, the next waiting pipeline will be triggered (admin only)",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "lock-name"},
+	},
+}
+
+func projectLockReleaseRun(v cli.Values) error {
+	if err := client.ProjectLockRelease(v.GetString(_ProjectKey), v.GetString("lock-name")); err != nil {
+		return err
+	}
+	fmt.Printf("Lock %s released in project %s\n", v.GetString("lock-name"), v.GetString(_ProjectKey))
+	return nil
+}
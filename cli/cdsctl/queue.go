@@ -50,6 +50,7 @@ type jobCLI struct {
 	WorkflowName string        `cli:"workflow_name"`
 	NodeName     string        `cli:"pipeline_name"`
 	Status       string        `cli:"status"`
+	Priority     string        `cli:"priority"`
 	URL          string        `cli:"url"`
 	Since        string        `cli:"since"`
 	Duration     time.Duration `cli:"-"`
@@ -71,12 +72,16 @@ func getJobQueue(status ...string) ([]jobCLI, error) {
 	jobsUI := make([]jobCLI, len(jobs))
 
 	for k, jr := range jobs {
+		if jr.Priority == "" {
+			jr.Priority = sdk.JobPriorityNormal
+		}
 		jobsUI[k] = jobCLI{
 			Run:          getVarsInPbj("cds.run", jr.Parameters),
 			ProjectKey:   getVarsInPbj("cds.project", jr.Parameters),
 			WorkflowName: getVarsInPbj("cds.workflow", jr.Parameters),
 			NodeName:     getVarsInPbj("cds.node", jr.Parameters),
 			Status:       jr.Status,
+			Priority:     jr.Priority,
 			URL:          generateQueueJobURL(baseURL, jr.Parameters),
 			Since:        fmt.Sprintf(sdk.Round(time.Since(jr.Queued), time.Second).String()),
 			Duration:     time.Since(jr.Queued),
@@ -196,12 +201,13 @@ func writeLines(ctx context.Context, t *text.Text, status string) {
 }
 
 func generateQueueJobLine(job jobCLI) string {
-	row := make([]string, 4)
+	row := make([]string, 5)
 	row[0] = pad(job.Since, 8)
 	row[1] = pad(job.Run, 6)
-	row[2] = fmt.Sprintf("%s ➤ %s", pad(job.ProjectKey+"/"+job.WorkflowName, 30), pad(job.NodeName, 20))
-	row[3] = fmt.Sprintf("➤ %s", pad(job.TriggeredBy, 17))
-	return fmt.Sprintf("%s %s %s %s\n", row[0], row[1], row[2], row[3])
+	row[2] = pad(job.Priority, 6)
+	row[3] = fmt.Sprintf("%s ➤ %s", pad(job.ProjectKey+"/"+job.WorkflowName, 30), pad(job.NodeName, 20))
+	row[4] = fmt.Sprintf("➤ %s", pad(job.TriggeredBy, 17))
+	return fmt.Sprintf("%s %s %s %s %s\n", row[0], row[1], row[2], row[3], row[4])
 }
 
 func pad(t string, size int) string {
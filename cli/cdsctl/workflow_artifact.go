@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 
@@ -21,6 +23,8 @@ func workflowArtifact() *cobra.Command {
 	return cli.NewCommand(workflowArtifactCmd, nil, []*cobra.Command{
 		cli.NewListCommand(workflowArtifactListCmd, workflowArtifactListRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowArtifactDownloadCmd, workflowArtifactDownloadRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowArtifactVerifyCmd, workflowArtifactVerifyRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowArtifactRetentionCmd, workflowArtifactRetentionRun, nil, withAllCommandModifiers()...),
 	})
 }
 
@@ -48,6 +52,39 @@ func workflowArtifactListRun(v cli.Values) (cli.ListResult, error) {
 	return cli.AsListResult(workflowArtifacts), nil
 }
 
+var workflowArtifactRetentionCmd = cli.Command{
+	Name:  "retention",
+	Short: "List the artifacts kept or deleted by the retention policy of a Workflow, nothing is deleted",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "deleted",
+			Usage: "list only the artifacts that will be deleted",
+			Type:  cli.FlagBool,
+		},
+	},
+}
+
+func workflowArtifactRetentionRun(v cli.Values) (cli.ListResult, error) {
+	report, err := client.WorkflowArtifactRetention(v.GetString(_ProjectKey), v.GetString(_WorkflowName))
+	if err != nil {
+		return nil, err
+	}
+	items := report.Items
+	if v.GetBool("deleted") {
+		items = make([]sdk.WorkflowArtifactRetentionItem, 0, len(report.Items))
+		for _, it := range report.Items {
+			if !it.Keep {
+				items = append(items, it)
+			}
+		}
+	}
+	return cli.AsListResult(items), nil
+}
+
 var workflowArtifactDownloadCmd = cli.Command{
 	Name:  "download",
 	Short: "Download artifacts of one Workflow Run",
@@ -168,3 +205,111 @@ func workflowArtifactDownloadRun(v cli.Values) error {
 	}
 	return nil
 }
+
+var workflowArtifactVerifyCmd = cli.Command{
+	Name:  "verify",
+	Short: "Verify a downloaded file against the signed provenance of an artifact of one Workflow Run",
+	Long: `Check that the file is an artifact of the workflow run and that its provenance is signed by the provenance key of the project:
+
+	cdsctl workflow artifact verify MYPROJECT my-workflow 42 ./my-binary
+
+The public key can be given to verify without trusting the CDS API for the key:
+
+	cdsctl workflow artifact verify MYPROJECT my-workflow 42 ./my-binary --public-key ./provenance.pub
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "number"},
+		{Name: "file"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "artifact-name",
+			Usage: "name of the artifact, default to the file name",
+		},
+		{
+			Name:  "public-key",
+			Usage: "path of the armored PGP public key, default to the public key of the project",
+		},
+	},
+}
+
+func workflowArtifactVerifyRun(v cli.Values) error {
+	number, err := strconv.ParseInt(v.GetString("number"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("number parameter have to be an integer")
+	}
+
+	filePath := v.GetString("file")
+	name := v.GetString("artifact-name")
+	if name == "" {
+		name = filepath.Base(filePath)
+	}
+
+	artifacts, err := client.WorkflowRunArtifacts(v.GetString(_ProjectKey), v.GetString(_WorkflowName), number)
+	if err != nil {
+		return err
+	}
+	var art *sdk.WorkflowNodeRunArtifact
+	for i := range artifacts {
+		if artifacts[i].Name == name {
+			art = &artifacts[i]
+			break
+		}
+	}
+	if art == nil {
+		return fmt.Errorf("artifact %s not found in workflow run %d", name, number)
+	}
+
+	sha512sum, err := sdk.FileSHA512sum(filePath)
+	if err != nil {
+		return err
+	}
+	if sha512sum != art.SHA512sum {
+		return fmt.Errorf("invalid sha512sum for file %s: %s, artifact %s: %s", filePath, sha512sum, art.Name, art.SHA512sum)
+	}
+
+	var publicKey string
+	if v.GetString("public-key") != "" {
+		btes, err := ioutil.ReadFile(v.GetString("public-key"))
+		if err != nil {
+			return fmt.Errorf("cannot read public key: %v", err)
+		}
+		publicKey = string(btes)
+	} else {
+		k, err := client.WorkflowArtifactProvenanceKey(v.GetString(_ProjectKey), v.GetString(_WorkflowName))
+		if err != nil {
+			return err
+		}
+		publicKey = k.Public
+	}
+
+	provenance, err := client.WorkflowNodeRunArtifactProvenance(v.GetString(_ProjectKey), v.GetString(_WorkflowName), art.ID)
+	if err != nil {
+		return err
+	}
+
+	statement, err := provenance.Verify(publicKey, art.Name, sha512sum)
+	if err != nil {
+		return err
+	}
+	config := statement.Predicate.BuildConfig
+	if config.Workflow != v.GetString(_WorkflowName) || config.RunNumber != number {
+		return fmt.Errorf("provenance of %s is for workflow %s run %d", art.Name, config.Workflow, config.RunNumber)
+	}
+
+	fmt.Printf("File %s verified: artifact %s of workflow %s/%s run %d.%d, pipeline %s, job %s\n", filePath, art.Name,
+		config.Project, config.Workflow, config.RunNumber, config.SubNumber, config.Node, config.Job)
+	for _, m := range statement.Predicate.Materials {
+		fmt.Printf("Built from %s %s\n", m.URI, m.Digest["sha1"])
+	}
+	if config.WorkerModel != "" {
+		fmt.Printf("Built by worker %s with model %s\n", config.WorkerName, config.WorkerModel)
+	} else {
+		fmt.Printf("Built by worker %s\n", config.WorkerName)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowPurgeCmd = cli.Command{
+	Name:  "purge",
+	Short: "Purge the runs of a CDS workflow with its run retention rules",
+	Long: `Purge the runs of a CDS workflow with the run_retention rules of the workflow, without waiting for the purge of the API.
+With --dry-run, nothing is purged and the runs kept or purged by the rules are listed with the rule that applies.`,
+	Example: `cdsctl workflow purge MYPROJECT myworkflow --dry-run # List the runs kept or purged by the rules
+cdsctl workflow purge MYPROJECT myworkflow --purged # Purge the runs and list them
+	`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "dry-run",
+			Usage: "list the runs that will be purged, nothing is purged",
+			Type:  cli.FlagBool,
+		},
+		{
+			Name:  "purged",
+			Usage: "list only the purged runs",
+			Type:  cli.FlagBool,
+		},
+	},
+}
+
+func workflowPurgeRun(v cli.Values) (cli.ListResult, error) {
+	report, err := client.WorkflowRunRetention(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetBool("dry-run"))
+	if err != nil {
+		return nil, err
+	}
+	items := report.Items
+	if v.GetBool("purged") {
+		items = make([]sdk.WorkflowRunRetentionItem, 0, len(report.Items))
+		for _, it := range report.Items {
+			if !it.Keep {
+				items = append(items, it)
+			}
+		}
+	}
+	return cli.AsListResult(items), nil
+}
@@ -7,10 +7,12 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk/exportentities"
 )
 
 var workflowPushCmd = cli.Command{
@@ -23,6 +25,10 @@ For example if you have a workflow with pipelines build and tests you can push y
 
 	cdsctl workflow push tests.pip.yml build.pip.yml myWorkflow.yml
 
+Composite actions used by the pipelines should be pushed from the actions directory
+
+	cdsctl workflow push .cds/*.yml .cds/actions/*.yml
+
 	`,
 	Ctx: []cli.Arg{
 		{Name: _ProjectKey},
@@ -62,10 +68,12 @@ func workflowPushRun(c cli.Values) error {
 		}
 
 		fmt.Println("Reading file ", cli.Magenta(file))
-		if dir == "" {
-			dir = filepath.Dir(file)
+		fileDir := filepath.Dir(file)
+		if dir == "" || dir == filepath.Join(fileDir, exportentities.CompositeActionsDirectory) {
+			dir = fileDir
 		}
-		if dir != filepath.Dir(file) {
+		// Composite actions could be in the actions directory of the workflow files directory
+		if dir != fileDir && filepath.Join(dir, exportentities.CompositeActionsDirectory) != fileDir {
 			return fmt.Errorf("files must be ine the same directory")
 		}
 
@@ -106,6 +114,11 @@ func workflowPushRun(c cli.Values) error {
 func workflowFilesToTarWriter(files []string, buf io.Writer) error {
 	tw := tar.NewWriter(buf)
 
+	dirs := make(map[string]bool, len(files))
+	for _, file := range files {
+		dirs[filepath.Dir(file)] = true
+	}
+
 	// add some files to the archive
 	for _, file := range files {
 		filBuf, err := ioutil.ReadFile(file)
@@ -113,8 +126,14 @@ func workflowFilesToTarWriter(files []string, buf io.Writer) error {
 			return err
 		}
 
+		name := filepath.Base(file)
+		// Composite actions are referenced by their path so the directory should be kept
+		if d := filepath.Dir(file); filepath.Base(d) == exportentities.CompositeActionsDirectory && dirs[filepath.Dir(d)] {
+			name = path.Join(exportentities.CompositeActionsDirectory, name)
+		}
+
 		hdr := &tar.Header{
-			Name: filepath.Base(file),
+			Name: name,
 			Mode: 0600,
 			Size: int64(len(filBuf)),
 		}
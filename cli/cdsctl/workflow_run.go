@@ -85,6 +85,13 @@ var workflowRunManualCmd = cli.Command{
 			Usage:     "Synchronise your pipelines with your last editions. Must be used with flag run-number",
 			Type:      cli.FlagBool,
 		},
+		{
+			Name:  "priority",
+			Usage: "Priority of the jobs in the queue: high, normal or low",
+			IsValid: func(s string) bool {
+				return sdk.IsValidJobPriority(s) == nil
+			},
+		},
 	},
 }
 
@@ -93,7 +100,9 @@ func workflowRunManualRun(v cli.Values) error {
 		return fmt.Errorf("Could not use flag --sync without flag --run-number")
 	}
 
-	manual := sdk.WorkflowNodeRunManual{}
+	manual := sdk.WorkflowNodeRunManual{
+		Priority: v.GetString("priority"),
+	}
 	if strings.TrimSpace(v.GetString("data")) != "" {
 		data := map[string]interface{}{}
 		if err := json.Unmarshal([]byte(v.GetString("data")), &data); err != nil {
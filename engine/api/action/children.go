@@ -21,9 +21,11 @@ func insertActionChild(db gorp.SqlExecutor, child sdk.Action, actionID int64, ex
 		ChildID:        child.ID,
 		ExecOrder:      int64(execOrder), // TODO exec order can be int 64
 		StepName:       child.StepName,
+		StepID:         child.StepID,
 		Optional:       child.Optional,
 		AlwaysExecuted: child.AlwaysExecuted,
 		Enabled:        child.Enabled,
+		Timeout:        child.Timeout,
 	}
 	if err := insertEdge(db, &ae); err != nil {
 		return err
@@ -39,6 +39,8 @@ type actionEdge struct {
 	Optional       bool   `db:"optional"`
 	AlwaysExecuted bool   `db:"always_executed"`
 	StepName       string `db:"step_name"`
+	StepID         string `db:"step_id"`
+	Timeout        int64  `db:"timeout"`
 	// aggregates
 	Parameters []actionEdgeParameter `db:"-"`
 	Child      *sdk.Action           `db:"-"`
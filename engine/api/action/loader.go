@@ -151,9 +151,11 @@ func loadChildren(ctx context.Context, db gorp.SqlExecutor, as ...*sdk.Action) e
 			// init child from edge child then override with edge attributes and parameters
 			child := *edges[i].Child
 			child.StepName = edges[i].StepName
+			child.StepID = edges[i].StepID
 			child.Optional = edges[i].Optional
 			child.AlwaysExecuted = edges[i].AlwaysExecuted
 			child.Enabled = edges[i].Enabled
+			child.Timeout = edges[i].Timeout
 
 			// replace action parameter with value configured by user when he created the child action
 			params := make([]sdk.Parameter, len(child.Parameters))
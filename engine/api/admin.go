@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
@@ -247,3 +249,95 @@ func (api *API) postAdminDatabaseRollEncryptedEntityByPrimaryKey() service.Handl
 		return nil
 	}
 }
+
+// rotationEntity returns the table and the key column of an entity that contains encrypted or signed data.
+func rotationEntity(entity string) (table, key string, mapped bool, err error) {
+	if e, ok := gorpmapping.Mapping[entity]; ok && (e.SignedEntity || e.EncryptedEntity) {
+		return e.Name, e.Keys[0], true, nil
+	}
+	if key, ok := secret.GetColumnTableKey(entity); ok {
+		return entity, key, false, nil
+	}
+	return "", "", false, sdk.NewErrorFrom(sdk.ErrNotFound, "unknown encrypted or signed entity %s", entity)
+}
+
+func (api *API) getAdminDatabaseRotationEntities() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var entities []string
+		for k, e := range gorpmapping.Mapping {
+			if e.SignedEntity || e.EncryptedEntity {
+				entities = append(entities, k)
+			}
+		}
+		sort.Strings(entities)
+		entities = append(entities, secret.ListColumnTables()...)
+		return service.WriteJSON(w, entities, http.StatusOK)
+	}
+}
+
+func (api *API) postAdminDatabaseRotationEntity() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		entity := vars["entity"]
+		from := FormString(r, "from")
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return err
+		}
+		if limit <= 0 {
+			limit = 100
+		}
+
+		table, key, mapped, err := rotationEntity(entity)
+		if err != nil {
+			return err
+		}
+
+		pks, err := gorpmapping.ListPrimaryKeys(api.mustDB(), table, key, from, int64(limit))
+		if err != nil {
+			return err
+		}
+
+		// Each tuple is locked and rolled in its own transaction to keep the database available
+		for _, pk := range pks {
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return sdk.WrapError(err, "cannot start transaction")
+			}
+			if _, err := tx.Exec(fmt.Sprintf(`select 1 from "%s" where %s::text = $1 for update`, table, key), pk); err != nil {
+				_ = tx.Rollback()
+				return sdk.WithStack(err)
+			}
+			if mapped {
+				err = gorpmapping.RollTupleByPrimaryKey(ctx, tx, entity, pk)
+			} else {
+				err = secret.RollColumns(tx, table, pk)
+			}
+			if err != nil {
+				_ = tx.Rollback()
+				return sdk.WrapError(err, "cannot roll %s %s", entity, pk)
+			}
+			if err := tx.Commit(); err != nil {
+				return sdk.WithStack(err)
+			}
+		}
+
+		res := sdk.DatabaseKeyRotation{Entity: entity}
+		if len(pks) == limit {
+			res.Next = pks[len(pks)-1]
+		}
+		res.Total, err = gorpmapping.CountPrimaryKeys(api.mustDB(), table, key, "")
+		if err != nil {
+			return err
+		}
+		res.Done = res.Total
+		if res.Next != "" {
+			res.Done, err = gorpmapping.CountPrimaryKeys(api.mustDB(), table, key, res.Next)
+			if err != nil {
+				return err
+			}
+		}
+
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/ovh/cds/engine/api/authentication/gitlab"
 	"github.com/ovh/cds/engine/api/authentication/ldap"
 	"github.com/ovh/cds/engine/api/authentication/local"
+	"github.com/ovh/cds/engine/api/authentication/oidc"
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/broadcast"
 	"github.com/ovh/cds/engine/api/cache"
@@ -132,6 +133,18 @@ type Configuration struct {
 			ApplicationID  string `toml:"applicationID" json:"-" comment:"#######\n Gitlab OAuth Application ID"`
 			Secret         string `toml:"secret" json:"-"  comment:"Gitlab OAuth Application Secret"`
 		} `toml:"gitlab" json:"gitlab"`
+		OIDC struct {
+			Enabled        bool   `toml:"enabled" default:"false" json:"enabled"`
+			SignupDisabled bool   `toml:"signupDisabled" default:"false" json:"signupDisabled"`
+			URL            string `toml:"url" json:"url" comment:"#######\n OpenID Connect issuer URL, discovery document should be available at <url>/.well-known/openid-configuration"`
+			ClientID       string `toml:"clientId" json:"-" comment:"#######\n OpenID Connect Client ID"`
+			ClientSecret   string `toml:"clientSecret" json:"-" comment:"OpenID Connect Client Secret"`
+			Scopes         string `toml:"scopes" json:"scopes" default:"openid profile email" comment:"Space separated scopes requested to the provider"`
+			UsernameClaim  string `toml:"usernameClaim" json:"usernameClaim" default:"preferred_username" comment:"#######\n Claims used for user's username, fullname and email"`
+			FullnameClaim  string `toml:"fullnameClaim" json:"fullnameClaim" default:"name"`
+			EmailClaim     string `toml:"emailClaim" json:"emailClaim" default:"email"`
+			GroupsClaim    string `toml:"groupsClaim" json:"groupsClaim" default:"" comment:"#######\n Claim that contains user's groups, user will be added to CDS groups with the same names.\n Let empty to disable groups synchronization"`
+		} `toml:"oidc" json:"oidc"`
 	} `toml:"auth" comment:"##############################\n CDS Authentication Settings#\n#############################" json:"auth"`
 	SMTP struct {
 		Disable  bool   `toml:"disable" default:"true" json:"disable" comment:"Set to false to enable the internal SMTP client"`
@@ -142,6 +155,9 @@ type Configuration struct {
 		Password string `toml:"password" json:"-"`
 		From     string `toml:"from" default:"no-reply@cds.local" json:"from"`
 	} `toml:"smtp" comment:"#####################\n# CDS SMTP Settings \n####################" json:"smtp"`
+	ChatNotification struct {
+		AllowedHosts []string `toml:"allowedHosts" comment:"Hosts that the Slack, Mattermost and Teams notifications can be sent to even if they resolve to a private or loopback address, other private destinations are refused" json:"allowedHosts"`
+	} `toml:"chatNotification" comment:"#################################\n# CDS Chat Notifications Settings \n################################" json:"chatNotification"`
 	Artifact struct {
 		Mode  string `toml:"mode" default:"local" comment:"swift, awss3 or local" json:"mode"`
 		Local struct {
@@ -189,9 +205,14 @@ type Configuration struct {
 		URL         string `toml:"url" comment:"Example: http://localhost:9000" json:"url"`
 	} `toml:"graylog" json:"graylog" comment:"###########################\n Graylog Search. \n When CDS API generates errors, you can fetch them with cdsctl. \n Examples: \n $ cdsctl admin errors get <error-id> \n $ cdsctl admin errors get 55f6e977-d39b-11e8-8513-0242ac110007 \n##########################"`
 	Log struct {
-		StepMaxSize    int64 `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
-		ServiceMaxSize int64 `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
+		StepMaxSize    int64  `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
+		ServiceMaxSize int64  `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
+		Storage        string `toml:"storage" default:"database" comment:"Where the content of the logs is stored: database or objectstore (the artifact storage is used)" json:"storage"`
 	} `toml:"log" json:"log" comment:"###########################\n Log settings.\n##########################"`
+	WorkerCache struct {
+		MaxSize        int64 `toml:"maxSize" default:"2147483648" comment:"Max size of a worker cache in bytes, 0 for no limit (default: 2GB)" json:"maxSize"`
+		ProjectMaxSize int64 `toml:"projectMaxSize" default:"10737418240" comment:"Max size of all the worker caches of a project in a storage integration in bytes, least recently used caches are deleted beyond this size, 0 for no limit (default: 10GB)" json:"projectMaxSize"`
+	} `toml:"workerCache" json:"workerCache" comment:"###########################\n Worker cache settings.\n##########################"`
 }
 
 // ServiceConfiguration is the configuration of external service
@@ -493,6 +514,15 @@ func (a *API) Serve(ctx context.Context) error {
 		return fmt.Errorf("cannot initialize storage: %v", err)
 	}
 
+	switch a.Config.Log.Storage {
+	case "", workflow.LogStorageDatabase:
+	case workflow.LogStorageObjectStore:
+		log.Info(ctx, "Initializing log storage on %s objectstore...", a.Config.Artifact.Mode)
+		workflow.SetLogStorage(workflow.NewObjectStoreLogStorage(a.SharedStorage))
+	default:
+		return fmt.Errorf("unsupported log storage : %s", a.Config.Log.Storage)
+	}
+
 	log.Info(ctx, "Initializing database connection...")
 	//Intialize database
 	a.DBConnectionFactory, err = database.Init(
@@ -544,7 +574,7 @@ func (a *API) Serve(ctx context.Context) error {
 	}
 
 	// Intialize notification package
-	notification.Init(a.Config.URL.UI)
+	notification.Init(a.Config.URL.UI, a.Config.ChatNotification.AllowedHosts)
 
 	log.Info(ctx, "Initializing Authentication drivers...")
 	a.AuthenticationDrivers = make(map[sdk.AuthConsumerType]sdk.AuthDriver)
@@ -599,6 +629,27 @@ func (a *API) Serve(ctx context.Context) error {
 		)
 	}
 
+	if a.Config.Auth.OIDC.Enabled {
+		a.AuthenticationDrivers[sdk.ConsumerOIDC], err = oidc.NewDriver(
+			ctx,
+			a.Config.Auth.OIDC.SignupDisabled,
+			a.Config.URL.UI,
+			oidc.Config{
+				URL:           a.Config.Auth.OIDC.URL,
+				ClientID:      a.Config.Auth.OIDC.ClientID,
+				ClientSecret:  a.Config.Auth.OIDC.ClientSecret,
+				Scopes:        strings.Fields(a.Config.Auth.OIDC.Scopes),
+				UsernameClaim: a.Config.Auth.OIDC.UsernameClaim,
+				FullnameClaim: a.Config.Auth.OIDC.FullnameClaim,
+				EmailClaim:    a.Config.Auth.OIDC.EmailClaim,
+				GroupsClaim:   a.Config.Auth.OIDC.GroupsClaim,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	if a.Config.Auth.CorporateSSO.Enabled {
 		driverConfig := corpsso.Config{
 			MailDomain: a.Config.Auth.CorporateSSO.MailDomain,
@@ -666,6 +717,9 @@ func (a *API) Serve(ctx context.Context) error {
 	sdk.GoRoutine(ctx, "authentication.SessionCleaner", func(ctx context.Context) {
 		authentication.SessionCleaner(ctx, a.mustDB)
 	}, a.PanicDump())
+	sdk.GoRoutine(ctx, "api.nodeRunApprovalTimeoutRoutine", func(ctx context.Context) {
+		a.nodeRunApprovalTimeoutRoutine(ctx)
+	}, a.PanicDump())
 
 	migrate.Add(ctx, sdk.Migration{Name: "RefactorGroupMembership", Release: "0.44.0", Blocker: true, Automatic: true, ExecFunc: func(ctx context.Context) error {
 		return migrate.RefactorGroupMembership(ctx, a.DBConnectionFactory.GetDBMap())
@@ -7,6 +7,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -86,6 +87,8 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(dbApplicationKey{}, "application_key", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbApplicationVulnerability{}, "application_vulnerability", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbApplicationVariable{}, "application_variable", true, "id"))
+	secret.RegisterColumn(secret.Column{Table: "application", Key: "id", Name: "vcs_strategy", JSON: true})
+	secret.RegisterColumn(secret.Column{Table: "application_deployment_strategy", Key: "application_id", Name: "config", JSON: true})
 }
 
 type sqlApplicationJSON struct {
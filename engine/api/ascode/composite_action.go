@@ -0,0 +1,222 @@
+package ascode
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/interpolate"
+)
+
+// compositeActionInputTypes are the types allowed for the inputs of a composite action
+var compositeActionInputTypes = []string{sdk.StringParameter, sdk.TextParameter, sdk.NumberParameter, sdk.BooleanParameter}
+
+// CheckCompositeActions checks the inputs, outputs and steps of given composite actions.
+func CheckCompositeActions(actions map[string]exportentities.CompositeAction) error {
+	for p, a := range actions {
+		if len(a.Steps) == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "composite action %s has no step", p)
+		}
+		if _, err := a.Actions(); err != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid step in composite action %s: %v", p, err)
+		}
+		for name, in := range a.Inputs {
+			if in.Type != "" && !sdk.IsInArray(in.Type, compositeActionInputTypes) {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid type %s for input %s of composite action %s", in.Type, name, p)
+			}
+			if in.Default != nil {
+				if err := checkCompositeActionInputValue(in, *in.Default); err != nil {
+					return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid default value for input %s of composite action %s: %v", name, p, err)
+				}
+			}
+		}
+		for name, out := range a.Outputs {
+			if out.Value == "" {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "output %s of composite action %s has no value", name, p)
+			}
+		}
+	}
+	return nil
+}
+
+// NewCompositeActionsPipeliner returns a pipeliner that replaces the steps of given pipeline that use a composite
+// action by the steps of the composite action.
+func NewCompositeActionsPipeliner(p exportentities.PipelineV1, actions map[string]exportentities.CompositeAction) exportentities.Pipeliner {
+	return compositeActionsPipeline{PipelineV1: p, actions: actions}
+}
+
+type compositeActionsPipeline struct {
+	exportentities.PipelineV1
+	actions map[string]exportentities.CompositeAction
+}
+
+func (p compositeActionsPipeline) Pipeline() (*sdk.Pipeline, error) {
+	pip, err := p.PipelineV1.Pipeline()
+	if err != nil {
+		return pip, err
+	}
+
+	for i := range pip.Stages {
+		for j := range pip.Stages[i].Jobs {
+			job := &pip.Stages[i].Jobs[j]
+			// Pipeline files are at the root of the .cds folder
+			steps, err := p.expandSteps(".", job.Action.Actions, nil)
+			if err != nil {
+				return pip, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid job %s: %v", job.Action.Name, err)
+			}
+			job.Action.Actions = steps
+		}
+	}
+
+	return pip, nil
+}
+
+// expandSteps replaces composite action steps by their own steps, dir is the directory of the file that contains the steps.
+func (p compositeActionsPipeline) expandSteps(dir string, steps []sdk.Action, parents []string) ([]sdk.Action, error) {
+	res := make([]sdk.Action, 0, len(steps))
+	for _, step := range steps {
+		if !exportentities.IsCompositeActionPath(step.Name) {
+			res = append(res, step)
+			continue
+		}
+
+		ref, ok := exportentities.CompositeActionPath(dir, step.Name)
+		if !ok {
+			return nil, fmt.Errorf("composite action %s should be in the .cds folder", step.Name)
+		}
+		a, ok := p.actions[ref]
+		if !ok {
+			return nil, fmt.Errorf("composite action %s not found", step.Name)
+		}
+		if sdk.IsInArray(ref, parents) {
+			return nil, fmt.Errorf("composite action %s can't use itself", ref)
+		}
+
+		inputs, err := computeCompositeActionInputs(ref, a, step.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		children, err := a.Actions()
+		if err != nil {
+			return nil, fmt.Errorf("invalid step in composite action %s: %v", ref, err)
+		}
+		for i := range children {
+			for j := range children[i].Parameters {
+				children[i].Parameters[j].Value, err = interpolateCompositeActionInputs(children[i].Parameters[j].Value, inputs)
+				if err != nil {
+					return nil, fmt.Errorf("invalid step in composite action %s: %v", ref, err)
+				}
+			}
+		}
+		children, err = p.expandSteps(path.Dir(ref), children, append(parents, ref))
+		if err != nil {
+			return nil, err
+		}
+		if len(a.Outputs) > 0 {
+			export, err := exportCompositeActionOutputs(ref, a, inputs)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, export)
+		}
+
+		// Step options given to the composite action are applied to all its steps
+		for i := range children {
+			children[i].Enabled = children[i].Enabled && step.Enabled
+			children[i].Optional = children[i].Optional || step.Optional
+			children[i].AlwaysExecuted = children[i].AlwaysExecuted || step.AlwaysExecuted
+		}
+		res = append(res, children...)
+	}
+	return res, nil
+}
+
+// computeCompositeActionInputs returns the value of all inputs of a composite action from the given step parameters.
+func computeCompositeActionInputs(ref string, a exportentities.CompositeAction, params []sdk.Parameter) (map[string]string, error) {
+	inputs := make(map[string]string, len(a.Inputs))
+	for _, param := range params {
+		in, ok := a.Inputs[param.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown input %s for composite action %s", param.Name, ref)
+		}
+		if err := checkCompositeActionInputValue(in, param.Value); err != nil {
+			return nil, fmt.Errorf("invalid value for input %s of composite action %s: %v", param.Name, ref, err)
+		}
+		inputs[param.Name] = param.Value
+	}
+	for name, in := range a.Inputs {
+		if _, ok := inputs[name]; ok {
+			continue
+		}
+		if in.Default == nil {
+			return nil, fmt.Errorf("missing input %s for composite action %s", name, ref)
+		}
+		inputs[name] = *in.Default
+	}
+	return inputs, nil
+}
+
+func checkCompositeActionInputValue(in exportentities.CompositeActionInput, value string) error {
+	// Value will be interpolated by the worker
+	if strings.Contains(value, "{{") {
+		return nil
+	}
+	switch in.Type {
+	case sdk.NumberParameter:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%s is not a number", value)
+		}
+	case sdk.BooleanParameter:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s is not a boolean", value)
+		}
+	}
+	return nil
+}
+
+// interpolateCompositeActionInputs replaces the inputs in given value, other variables are left to the worker.
+func interpolateCompositeActionInputs(s string, inputs map[string]string) (string, error) {
+	return interpolate.Do(s, inputs)
+}
+
+// shellQuote returns the value quoted for a shell script, single quotes in the value are escaped.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// exportCompositeActionOutputs returns a script step that exports the outputs of a composite action as build variables.
+func exportCompositeActionOutputs(ref string, a exportentities.CompositeAction, inputs map[string]string) (sdk.Action, error) {
+	names := make([]string, 0, len(a.Outputs))
+	for name := range a.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		value, err := interpolateCompositeActionInputs(a.Outputs[name].Value, inputs)
+		if err != nil {
+			return sdk.Action{}, fmt.Errorf("invalid value for output %s of composite action %s: %v", name, ref, err)
+		}
+		lines[i] = fmt.Sprintf("worker export %s %s", name, shellQuote(value))
+	}
+
+	return sdk.Action{
+		Name:     sdk.ScriptAction,
+		Type:     sdk.BuiltinAction,
+		StepName: fmt.Sprintf("Export outputs of %s", ref),
+		Enabled:  true,
+		Parameters: []sdk.Parameter{
+			{
+				Name:  "script",
+				Value: strings.Join(lines, "\n"),
+				Type:  sdk.TextParameter,
+			},
+		},
+	}, nil
+}
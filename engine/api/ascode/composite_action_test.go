@@ -0,0 +1,153 @@
+package ascode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func TestCompositeActionsPipeliner(t *testing.T) {
+	var build exportentities.CompositeAction
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`
+name: build
+inputs:
+  version:
+    type: string
+  race:
+    type: boolean
+    default: "false"
+  message:
+    type: string
+    default: it's built
+outputs:
+  binary:
+    value: dist/app-{{ .version }}
+  message:
+    value: "{{.message}} ({{.cds.version}})"
+steps:
+- script: go build -race={{.race}} -o dist/app-{{.version}}
+- ./test.yml:
+    race: "{{.race}}"
+`), &build))
+	var test exportentities.CompositeAction
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`
+name: test
+inputs:
+  race:
+    type: boolean
+steps:
+- script: go test -race={{.race}} ./...
+`), &test))
+	actions := map[string]exportentities.CompositeAction{
+		"actions/build.yml": build,
+		"actions/test.yml":  test,
+	}
+	require.NoError(t, CheckCompositeActions(actions))
+
+	var pip exportentities.PipelineV1
+	require.NoError(t, yaml.Unmarshal([]byte(`
+version: v1.0
+name: build
+jobs:
+- job: build
+  steps:
+  - checkout: '{{.cds.workspace}}'
+  - ./actions/build.yml:
+      version: "1.0.0"
+    optional: true
+  - script: ls {{.cds.build.binary}}
+`), &pip))
+
+	p, err := NewCompositeActionsPipeliner(pip, actions).Pipeline()
+	require.NoError(t, err)
+	require.Len(t, p.Stages, 1)
+	require.Len(t, p.Stages[0].Jobs, 1)
+
+	steps := p.Stages[0].Jobs[0].Action.Actions
+	require.Len(t, steps, 5)
+	require.Equal(t, sdk.CheckoutApplicationAction, steps[0].Name)
+	require.Equal(t, "go build -race=false -o dist/app-1.0.0", steps[1].Parameters[0].Value)
+	require.True(t, steps[1].Optional)
+	require.Equal(t, "go test -race=false ./...", steps[2].Parameters[0].Value)
+	require.True(t, steps[2].Optional)
+	require.Equal(t, sdk.ScriptAction, steps[3].Name)
+	require.Equal(t, "worker export binary 'dist/app-1.0.0'\nworker export message 'it'\\''s built ({{.cds.version}})'", steps[3].Parameters[0].Value)
+	require.Equal(t, "ls {{.cds.build.binary}}", steps[4].Parameters[0].Value)
+	require.False(t, steps[4].Optional)
+}
+
+func TestCompositeActionsPipelinerInvalid(t *testing.T) {
+	yes := "yes"
+	tests := []struct {
+		name    string
+		actions map[string]exportentities.CompositeAction
+		step    string
+	}{
+		{
+			name:    "not found",
+			actions: map[string]exportentities.CompositeAction{},
+			step:    "./actions/build.yml: {}",
+		},
+		{
+			name:    "outside the .cds folder",
+			actions: map[string]exportentities.CompositeAction{},
+			step:    "../build.yml: {}",
+		},
+		{
+			name: "unknown input",
+			actions: map[string]exportentities.CompositeAction{
+				"actions/build.yml": {Steps: []exportentities.Step{{Script: "make"}}},
+			},
+			step: "./actions/build.yml: {target: all}",
+		},
+		{
+			name: "missing input",
+			actions: map[string]exportentities.CompositeAction{
+				"actions/build.yml": {
+					Inputs: map[string]exportentities.CompositeActionInput{"target": {}},
+					Steps:  []exportentities.Step{{Script: "make {{.target}}"}},
+				},
+			},
+			step: "./actions/build.yml: {}",
+		},
+		{
+			name: "invalid input type",
+			actions: map[string]exportentities.CompositeAction{
+				"actions/build.yml": {
+					Inputs: map[string]exportentities.CompositeActionInput{"verbose": {Type: sdk.BooleanParameter, Default: &yes}},
+					Steps:  []exportentities.Step{{Script: "make"}},
+				},
+			},
+			step: "./actions/build.yml: {verbose: maybe}",
+		},
+		{
+			name: "recursive",
+			actions: map[string]exportentities.CompositeAction{
+				"actions/build.yml": {
+					Steps: []exportentities.Step{{StepCustom: exportentities.StepCustom{"./build.yml": {}}}},
+				},
+			},
+			step: "./actions/build.yml: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pip exportentities.PipelineV1
+			require.NoError(t, yaml.Unmarshal([]byte("version: v1.0\nname: build\njobs:\n- job: build\n  steps:\n  - "+tt.step), &pip))
+			_, err := NewCompositeActionsPipeliner(pip, tt.actions).Pipeline()
+			require.Error(t, err)
+		})
+	}
+
+	// Invalid boolean default value
+	require.Error(t, CheckCompositeActions(map[string]exportentities.CompositeAction{
+		"actions/build.yml": {
+			Inputs: map[string]exportentities.CompositeActionInput{"verbose": {Type: sdk.BooleanParameter, Default: &yes}},
+			Steps:  []exportentities.Step{{Script: "make"}},
+		},
+	}))
+}
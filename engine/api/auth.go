@@ -260,6 +260,13 @@ func (api *API) postAuthSigninHandler() service.Handler {
 			}
 		}
 
+		// If the auth driver manages groups, synchronize the user's groups
+		if userInfo.Groups != nil {
+			if err := group.SyncUserGroups(ctx, tx, consumer.AuthentifiedUserID, consumerType, userInfo.Groups); err != nil {
+				return err
+			}
+		}
+
 		// Generate a new session for consumer
 		session, err := authentication.NewSession(ctx, tx, consumer, driver.GetSessionDuration(), userInfo.MFA)
 		if err != nil {
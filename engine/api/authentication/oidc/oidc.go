@@ -0,0 +1,349 @@
+package oidc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/sdk"
+)
+
+var _ sdk.AuthDriverWithRedirect = new(authDriver)
+var _ sdk.AuthDriverWithSigninStateToken = new(authDriver)
+
+// Config for OpenID Connect auth driver.
+type Config struct {
+	URL           string
+	ClientID      string
+	ClientSecret  string
+	Scopes        []string
+	UsernameClaim string
+	FullnameClaim string
+	EmailClaim    string
+	// GroupsClaim is the claim that contains user's groups, groups are not synchronized if empty.
+	GroupsClaim string
+}
+
+// provider metadata from discovery document.
+type provider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewDriver returns a new OpenID Connect auth driver for given config,
+// provider endpoints are loaded from its discovery document.
+func NewDriver(ctx context.Context, signupDisabled bool, cdsURL string, cfg Config) (sdk.AuthDriver, error) {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "preferred_username"
+	}
+	if cfg.FullnameClaim == "" {
+		cfg.FullnameClaim = "name"
+	}
+	if cfg.EmailClaim == "" {
+		cfg.EmailClaim = "email"
+	}
+
+	d := &authDriver{
+		signupDisabled: signupDisabled,
+		cdsURL:         cdsURL,
+		config:         cfg,
+	}
+
+	u := strings.TrimSuffix(cfg.URL, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(ctx, u, "", &d.provider); err != nil {
+		return nil, sdk.WrapError(err, "cannot get openid connect discovery document")
+	}
+	if strings.TrimSuffix(d.provider.Issuer, "/") != strings.TrimSuffix(cfg.URL, "/") {
+		return nil, sdk.WithStack(fmt.Errorf("openid connect issuer %s does not match configured url %s", d.provider.Issuer, cfg.URL))
+	}
+	if d.provider.AuthorizationEndpoint == "" || d.provider.TokenEndpoint == "" || d.provider.JWKSURI == "" {
+		return nil, sdk.WithStack(fmt.Errorf("invalid openid connect discovery document from %s", u))
+	}
+
+	return d, nil
+}
+
+type authDriver struct {
+	signupDisabled bool
+	cdsURL         string
+	config         Config
+	provider       provider
+	keysMutex      sync.RWMutex
+	keys           jose.JSONWebKeySet
+}
+
+func (d *authDriver) GetManifest() sdk.AuthDriverManifest {
+	return sdk.AuthDriverManifest{
+		Type:           sdk.ConsumerOIDC,
+		SignupDisabled: d.signupDisabled,
+	}
+}
+
+func (d *authDriver) redirectURI() string {
+	return d.cdsURL + "/auth/callback/oidc"
+}
+
+func (d *authDriver) GetSigninURI(signinState sdk.AuthSigninConsumerToken) (sdk.AuthDriverSigningRedirect, error) {
+	// Generate a new state value for the auth signin request
+	jws, err := authentication.NewDefaultSigninStateToken(signinState.Origin,
+		signinState.RedirectURI, signinState.IsFirstConnection)
+	if err != nil {
+		return sdk.AuthDriverSigningRedirect{}, err
+	}
+
+	// PKCE code challenge from the S256 of the code verifier
+	challenge := sha256.Sum256([]byte(codeVerifier(jws)))
+
+	values := url.Values{}
+	values.Set("client_id", d.config.ClientID)
+	values.Set("response_type", "code")
+	values.Set("scope", strings.Join(d.config.Scopes, " "))
+	values.Set("redirect_uri", d.redirectURI())
+	values.Set("state", jws)
+	values.Set("nonce", nonce(jws))
+	values.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	values.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.provider.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return sdk.AuthDriverSigningRedirect{
+		Method: http.MethodGet,
+		URL:    d.provider.AuthorizationEndpoint + sep + values.Encode(),
+	}, nil
+}
+
+func (d *authDriver) GetSessionDuration() time.Duration {
+	return time.Hour * 24 * 30 // 1 month session
+}
+
+func (d *authDriver) CheckSigninRequest(req sdk.AuthConsumerSigninRequest) error {
+	if code, ok := req["code"]; !ok || code == "" {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing or invalid openid connect code")
+	}
+	return nil
+}
+
+func (d *authDriver) CheckSigninStateToken(req sdk.AuthConsumerSigninRequest) error {
+	// Check if state is given and if its valid
+	state, okState := req["state"]
+	if !okState {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing state value")
+	}
+	return authentication.CheckDefaultSigninStateToken(state)
+}
+
+func (d *authDriver) GetUserInfo(ctx context.Context, req sdk.AuthConsumerSigninRequest) (sdk.AuthDriverUserInfo, error) {
+	var info sdk.AuthDriverUserInfo
+
+	config := &oauth2.Config{
+		ClientID:     d.config.ClientID,
+		ClientSecret: d.config.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  d.provider.AuthorizationEndpoint,
+			TokenURL: d.provider.TokenEndpoint,
+		},
+		RedirectURL: d.redirectURI(),
+		Scopes:      d.config.Scopes,
+	}
+
+	ctx2 := context.WithValue(ctx, oauth2.HTTPClient, http.DefaultClient)
+	t, err := config.Exchange(ctx2, req["code"],
+		oauth2.SetAuthURLParam("code_verifier", codeVerifier(req["state"])),
+	)
+	if err != nil {
+		return info, sdk.WrapError(err, "cannot get openid connect token with given code")
+	}
+
+	rawIDToken, ok := t.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return info, sdk.NewErrorFrom(sdk.ErrUnauthorized, "missing id token in openid connect token response")
+	}
+
+	claims, err := d.verifyIDToken(ctx, rawIDToken, nonce(req["state"]))
+	if err != nil {
+		return info, err
+	}
+
+	// Claims can be missing from the id token depending on provider configuration,
+	// in this case we try to get them from the user info endpoint.
+	if d.provider.UserinfoEndpoint != "" && (claimString(claims, d.config.UsernameClaim) == "" || claimString(claims, d.config.EmailClaim) == "") {
+		var userInfoClaims map[string]interface{}
+		if err := getJSON(ctx, d.provider.UserinfoEndpoint, t.AccessToken, &userInfoClaims); err != nil {
+			return info, sdk.WrapError(err, "cannot get user info from openid connect provider")
+		}
+		// Subject from user info must match the one from the verified id token
+		if claimString(userInfoClaims, "sub") == claimString(claims, "sub") {
+			for k, v := range userInfoClaims {
+				if _, ok := claims[k]; !ok {
+					claims[k] = v
+				}
+			}
+		}
+	}
+
+	info.ExternalID = claimString(claims, "sub")
+	info.Username = claimString(claims, d.config.UsernameClaim)
+	info.Fullname = claimString(claims, d.config.FullnameClaim)
+	info.Email = claimString(claims, d.config.EmailClaim)
+	if info.ExternalID == "" || info.Username == "" {
+		return info, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing subject or %s claim from openid connect provider", d.config.UsernameClaim)
+	}
+
+	if d.config.GroupsClaim != "" {
+		info.Groups = claimGroups(claims, d.config.GroupsClaim)
+	}
+
+	return info, nil
+}
+
+// verifyIDToken checks id token signature, issuer, audience, expiration and nonce then returns its claims.
+func (d *authDriver) verifyIDToken(ctx context.Context, rawIDToken, expectedNonce string) (map[string]interface{}, error) {
+	tok, err := jwt.ParseSigned(rawIDToken)
+	if err != nil {
+		return nil, sdk.NewError(sdk.ErrUnauthorized, fmt.Errorf("cannot parse id token: %v", err))
+	}
+	if len(tok.Headers) == 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrUnauthorized, "missing id token header")
+	}
+
+	key, err := d.getKey(ctx, tok.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var std jwt.Claims
+	var claims map[string]interface{}
+	if err := tok.Claims(key, &std, &claims); err != nil {
+		return nil, sdk.NewError(sdk.ErrUnauthorized, fmt.Errorf("id token verification failed: %v", err))
+	}
+
+	if err := std.ValidateWithLeeway(jwt.Expected{
+		Issuer:   d.provider.Issuer,
+		Audience: jwt.Audience{d.config.ClientID},
+		Time:     time.Now(),
+	}, time.Minute); err != nil {
+		return nil, sdk.NewError(sdk.ErrUnauthorized, fmt.Errorf("invalid id token: %v", err))
+	}
+
+	if claimString(claims, "nonce") != expectedNonce {
+		return nil, sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid id token nonce")
+	}
+
+	return claims, nil
+}
+
+// getKey returns the provider key for given id, keys are reloaded if not found to handle rotation.
+func (d *authDriver) getKey(ctx context.Context, keyID string) (jose.JSONWebKey, error) {
+	d.keysMutex.RLock()
+	key, found := findKey(d.keys, keyID)
+	d.keysMutex.RUnlock()
+	if found {
+		return key, nil
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := getJSON(ctx, d.provider.JWKSURI, "", &keys); err != nil {
+		return key, sdk.WrapError(err, "cannot get openid connect provider keys")
+	}
+
+	d.keysMutex.Lock()
+	d.keys = keys
+	d.keysMutex.Unlock()
+
+	key, found = findKey(keys, keyID)
+	if !found {
+		return key, sdk.NewErrorFrom(sdk.ErrUnauthorized, "unknown id token signing key %q", keyID)
+	}
+	return key, nil
+}
+
+func findKey(keys jose.JSONWebKeySet, keyID string) (jose.JSONWebKey, bool) {
+	if ks := keys.Key(keyID); len(ks) > 0 {
+		return ks[0], true
+	}
+	if keyID == "" && len(keys.Keys) == 1 {
+		return keys.Keys[0], true
+	}
+	return jose.JSONWebKey{}, false
+}
+
+// codeVerifier returns the PKCE code verifier for a signin state. The verifier is derived from
+// the state with CDS signing key so that it can be computed again when the user comes back.
+func codeVerifier(state string) string { return deriveFromState("code_verifier", state) }
+
+// nonce returns the id token nonce for a signin state.
+func nonce(state string) string { return deriveFromState("nonce", state) }
+
+func deriveFromState(label, state string) string {
+	key := sha256.Sum256(x509.MarshalPKCS1PrivateKey(authentication.GetSigningKey()))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write([]byte(label + ":" + state)) // nolint
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func claimString(claims map[string]interface{}, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
+
+// claimGroups returns group names from given claim, path prefix given by some providers is removed (ie. "/my-group").
+func claimGroups(claims map[string]interface{}, name string) []string {
+	groups := []string{}
+	switch v := claims[name].(type) {
+	case string:
+		groups = append(groups, strings.TrimPrefix(v, "/"))
+	case []interface{}:
+		for i := range v {
+			if s, ok := v[i].(string); ok {
+				groups = append(groups, strings.TrimPrefix(s, "/"))
+			}
+		}
+	}
+	return groups
+}
+
+func getJSON(ctx context.Context, u, accessToken string, i interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer res.Body.Close() // nolint
+
+	if res.StatusCode != http.StatusOK {
+		return sdk.WithStack(fmt.Errorf("cannot get %s: %s", u, res.Status))
+	}
+	return sdk.WithStack(json.NewDecoder(res.Body).Decode(i))
+}
@@ -0,0 +1,124 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestGetUserInfo(t *testing.T) {
+	require.NoError(t, authentication.Init("cds_test", test.SigningKey))
+
+	providerKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: providerKey, KeyID: "my-key"}}, nil)
+	require.NoError(t, err)
+
+	var challenge, expectedNonce string
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(provider{
+			Issuer:                srv.URL,
+			AuthorizationEndpoint: srv.URL + "/auth",
+			TokenEndpoint:         srv.URL + "/token",
+			JWKSURI:               srv.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: providerKey.Public(), KeyID: "my-key", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "my-code", r.Form.Get("code"))
+		// check PKCE code verifier
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		idToken, err := jwt.Signed(signer).Claims(jwt.Claims{
+			Issuer:   srv.URL,
+			Subject:  "f0c8e9d2",
+			Audience: jwt.Audience{"cds"},
+			Expiry:   jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		}).Claims(map[string]interface{}{
+			"nonce":              expectedNonce,
+			"preferred_username": "fry",
+			"name":               "Philip J. Fry",
+			"email":              "fry@planet-express.futurama",
+			"groups":             []string{"/delivery", "crew"},
+		}).CompactSerialize()
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "my-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   60,
+			"id_token":     idToken,
+		})
+	})
+
+	d, err := NewDriver(context.TODO(), false, "http://cds.ui", Config{
+		URL:          srv.URL,
+		ClientID:     "cds",
+		ClientSecret: "my-secret",
+		GroupsClaim:  "groups",
+	})
+	require.NoError(t, err)
+
+	redirect, err := d.(sdk.AuthDriverWithRedirect).GetSigninURI(sdk.AuthSigninConsumerToken{})
+	require.NoError(t, err)
+	u, err := url.Parse(redirect.URL)
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/auth", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	assert.Equal(t, "http://cds.ui/auth/callback/oidc", u.Query().Get("redirect_uri"))
+	assert.Equal(t, "openid profile email", u.Query().Get("scope"))
+	challenge = u.Query().Get("code_challenge")
+	expectedNonce = u.Query().Get("nonce")
+	state := u.Query().Get("state")
+
+	req := sdk.AuthConsumerSigninRequest{"code": "my-code", "state": state}
+	require.NoError(t, d.CheckSigninRequest(req))
+	require.NoError(t, d.(sdk.AuthDriverWithSigninStateToken).CheckSigninStateToken(req))
+
+	info, err := d.GetUserInfo(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.AuthDriverUserInfo{
+		ExternalID: "f0c8e9d2",
+		Username:   "fry",
+		Fullname:   "Philip J. Fry",
+		Email:      "fry@planet-express.futurama",
+		Groups:     []string{"delivery", "crew"},
+	}, info)
+
+	// Id token with an invalid nonce should be rejected
+	expectedNonce = "invalid"
+	_, err = d.GetUserInfo(context.TODO(), req)
+	require.Error(t, err)
+}
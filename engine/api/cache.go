@@ -8,10 +8,56 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workercache"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
+// workerCacheReader counts the bytes read from a worker cache upload and fails beyond max size.
+type workerCacheReader struct {
+	io.ReadCloser
+	size    int64
+	maxSize int64
+}
+
+func (r *workerCacheReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.size += int64(n)
+	if r.maxSize > 0 && r.size > r.maxSize {
+		return n, sdk.NewErrorFrom(sdk.ErrWrongRequest, "worker cache exceeds the max size of %d bytes", r.maxSize)
+	}
+	return n, err
+}
+
+func (api *API) checkWorkerCacheSize(size int64) error {
+	if api.Config.WorkerCache.MaxSize > 0 && size > api.Config.WorkerCache.MaxSize {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "worker cache size of %d bytes exceeds the max size of %d bytes", size, api.Config.WorkerCache.MaxSize)
+	}
+	return nil
+}
+
+// evictWorkerCaches deletes the least recently used caches of the project if it exceeds its quota.
+func (api *API) evictWorkerCaches(ctx context.Context, driver objectstore.Driver, p *sdk.Project, integrationName string) {
+	if err := workercache.Evict(ctx, api.mustDB(), driver, p.Key, p.ID, integrationName, api.Config.WorkerCache.ProjectMaxSize); err != nil {
+		log.Error(ctx, "cannot evict worker caches of project %s: %v", p.Key, err)
+	}
+}
+
+// touchWorkerCache updates the last access date of a worker cache, the cache may not be indexed
+// if it was pushed before worker caches were indexed.
+func (api *API) touchWorkerCache(ctx context.Context, p *sdk.Project, integrationName, tag string) error {
+	c, err := workercache.LoadByTag(ctx, api.mustDB(), p.ID, integrationName, tag)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return nil
+	}
+	return workercache.UpdateLastAccess(api.mustDB(), c)
+}
+
 func (api *API) postPushCacheHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if isWorker := isWorker(ctx); !isWorker {
@@ -32,21 +78,49 @@ func (api *API) postPushCacheHandler() service.Handler {
 		}
 		defer r.Body.Close()
 
-		cacheObject := sdk.Cache{
-			Name:    "cache.tar",
-			Project: vars[permProjectKey],
-			Tag:     tag,
+		if err := api.checkWorkerCacheSize(r.ContentLength); err != nil {
+			return err
 		}
 
-		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, vars[permProjectKey], vars["integrationName"])
+		p, err := project.Load(api.mustDB(), api.Cache, vars[permProjectKey])
 		if err != nil {
 			return err
 		}
 
-		if _, err := storageDriver.Store(&cacheObject, r.Body); err != nil {
+		integrationName := vars["integrationName"]
+		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, p.Key, integrationName)
+		if err != nil {
+			return err
+		}
+
+		// a cache is immutable, registering it fails if it already exists
+		c := sdk.WorkerCache{
+			ProjectID:       p.ID,
+			IntegrationName: integrationName,
+			Tag:             tag,
+		}
+		if err := workercache.Register(ctx, api.mustDB(), &c); err != nil {
+			return err
+		}
+
+		body := &workerCacheReader{ReadCloser: r.Body, maxSize: api.Config.WorkerCache.MaxSize}
+		if _, err := storageDriver.Store(workercache.Object(p.Key, tag), body); err != nil {
+			if errD := workercache.Delete(api.mustDB(), &c); errD != nil {
+				log.Error(ctx, "cannot delete worker cache %s: %v", tag, errD)
+			}
+			if err := api.checkWorkerCacheSize(body.size); err != nil {
+				return err
+			}
 			return sdk.WrapError(err, "cannot store cache")
 		}
 
+		c.Size = body.size
+		if err := workercache.Confirm(api.mustDB(), &c); err != nil {
+			return err
+		}
+
+		api.evictWorkerCaches(ctx, storageDriver, p, integrationName)
+
 		return nil
 	}
 }
@@ -66,20 +140,25 @@ func (api *API) getPullCacheHandler() service.Handler {
 			return sdk.WithStack(sdk.ErrInvalidName)
 		}
 
-		cacheObject := sdk.Cache{
-			Project: vars[permProjectKey],
-			Name:    "cache.tar",
-			Tag:     tag,
+		p, err := project.Load(api.mustDB(), api.Cache, vars[permProjectKey])
+		if err != nil {
+			return err
 		}
 
-		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, vars[permProjectKey], vars["integrationName"])
+		integrationName := vars["integrationName"]
+		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, p.Key, integrationName)
 		if err != nil {
 			return err
 		}
 
+		if err := api.touchWorkerCache(ctx, p, integrationName, tag); err != nil {
+			return err
+		}
+
+		cacheObject := workercache.Object(p.Key, tag)
 		s, temporaryURLSupported := storageDriver.(objectstore.DriverWithRedirect)
 		if storageDriver.TemporaryURLSupported() && temporaryURLSupported { // with temp URL
-			fURL, _, err := s.FetchURL(&cacheObject)
+			fURL, _, err := s.FetchURL(cacheObject)
 			if err != nil {
 				return sdk.WrapError(err, "cannot fetch cache object")
 			}
@@ -89,7 +168,7 @@ func (api *API) getPullCacheHandler() service.Handler {
 			return nil
 		}
 
-		ioread, err := storageDriver.Fetch(ctx, &cacheObject)
+		ioread, err := storageDriver.Fetch(ctx, cacheObject)
 		if err != nil {
 			return sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrNotFound, "cannot fetch artifact cache.tar"))
 		}
@@ -121,7 +200,21 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 			return sdk.WithStack(sdk.ErrInvalidName)
 		}
 
-		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, vars[permProjectKey], vars["integrationName"])
+		var req sdk.Cache
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		if err := api.checkWorkerCacheSize(req.Size); err != nil {
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, vars[permProjectKey])
+		if err != nil {
+			return err
+		}
+
+		integrationName := vars["integrationName"]
+		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, p.Key, integrationName)
 		if err != nil {
 			return err
 		}
@@ -131,14 +224,24 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 			return sdk.WrapError(sdk.ErrNotImplemented, "cast error")
 		}
 
-		cacheObject := sdk.Cache{
-			Name:    "cache.tar",
-			Project: vars[permProjectKey],
-			Tag:     tag,
+		// a cache is immutable, registering it fails if it already exists. It stays pending until
+		// the worker calls back once it is uploaded to the temporary URL.
+		c := sdk.WorkerCache{
+			ProjectID:       p.ID,
+			IntegrationName: integrationName,
+			Tag:             tag,
+			Size:            req.Size,
+		}
+		if err := workercache.Register(ctx, api.mustDB(), &c); err != nil {
+			return err
 		}
 
-		url, key, err := store.StoreURL(&cacheObject, "application/tar")
+		cacheObject := workercache.Object(p.Key, tag)
+		url, key, err := store.StoreURL(cacheObject, "application/tar")
 		if err != nil {
+			if errD := workercache.Delete(api.mustDB(), &c); errD != nil {
+				log.Error(ctx, "cannot delete worker cache %s: %v", tag, errD)
+			}
 			return sdk.WrapError(err, "cannot store cache")
 		}
 		cacheObject.TmpURL = url
@@ -148,6 +251,51 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 	}
 }
 
+func (api *API) postPushCacheWithTempURLCallbackHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		tag := vars["tag"]
+
+		p, err := project.Load(api.mustDB(), api.Cache, vars[permProjectKey])
+		if err != nil {
+			return err
+		}
+
+		integrationName := vars["integrationName"]
+		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, p.Key, integrationName)
+		if err != nil {
+			return err
+		}
+
+		if !storageDriver.TemporaryURLSupported() {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		c, err := workercache.LoadByTag(ctx, api.mustDB(), p.ID, integrationName, tag)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "cannot find worker cache %s", tag)
+		}
+		if c.Confirmed {
+			return nil
+		}
+
+		if err := workercache.Confirm(api.mustDB(), c); err != nil {
+			return err
+		}
+
+		api.evictWorkerCaches(ctx, storageDriver, p, integrationName)
+
+		return nil
+	}
+}
+
 func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if isWorker := isWorker(ctx); !isWorker {
@@ -163,7 +311,13 @@ func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 			return sdk.WithStack(sdk.ErrInvalidName)
 		}
 
-		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, vars[permProjectKey], vars["integrationName"])
+		p, err := project.Load(api.mustDB(), api.Cache, vars[permProjectKey])
+		if err != nil {
+			return err
+		}
+
+		integrationName := vars["integrationName"]
+		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, p.Key, integrationName)
 		if err != nil {
 			return err
 		}
@@ -173,13 +327,12 @@ func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 			return sdk.WrapError(sdk.ErrNotImplemented, "cast error")
 		}
 
-		cacheObject := sdk.Cache{
-			Name:    "cache.tar",
-			Project: vars[permProjectKey],
-			Tag:     tag,
+		if err := api.touchWorkerCache(ctx, p, integrationName, tag); err != nil {
+			return err
 		}
 
-		url, key, err := store.FetchURL(&cacheObject)
+		cacheObject := workercache.Object(p.Key, tag)
+		url, key, err := store.FetchURL(cacheObject)
 		if err != nil {
 			return sdk.WrapError(err, "cannot get tmp URL")
 		}
@@ -189,3 +342,40 @@ func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 		return service.WriteJSON(w, cacheObject, http.StatusOK)
 	}
 }
+
+func (api *API) getResolveCacheHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		tag := vars["tag"]
+
+		// check tag name pattern
+		regexp := sdk.NamePatternRegex
+		if !regexp.MatchString(tag) {
+			return sdk.WithStack(sdk.ErrInvalidName)
+		}
+
+		restoreKeys, err := QueryStrings(r, "restoreKey")
+		if err != nil {
+			return sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, vars[permProjectKey])
+		if err != nil {
+			return err
+		}
+
+		c, err := workercache.Resolve(ctx, api.mustDB(), p.ID, vars["integrationName"], tag, restoreKeys)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no worker cache found for tag %s", tag)
+		}
+
+		return service.WriteJSON(w, c, http.StatusOK)
+	}
+}
@@ -199,3 +199,32 @@ func LoadTupleByPrimaryKey(db gorp.SqlExecutor, entity string, pk interface{}, o
 
 	return val, nil
 }
+
+// ListPrimaryKeys returns ordered primary keys of a table that are greater than given one.
+func ListPrimaryKeys(db gorp.SqlExecutor, table, key, from string, limit int64) ([]string, error) {
+	var res []string
+	var err error
+	if from == "" {
+		query := fmt.Sprintf(`select %s::text from "%s" group by %s order by %s limit $1`, key, table, key, key)
+		_, err = db.Select(&res, query, limit)
+	} else {
+		query := fmt.Sprintf(`select %s::text from "%s" where %s > $1 group by %s order by %s limit $2`, key, table, key, key, key)
+		_, err = db.Select(&res, query, from, limit)
+	}
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return res, nil
+}
+
+// CountPrimaryKeys returns the number of primary keys of a table, only keys lower or equal than given one are counted if set.
+func CountPrimaryKeys(db gorp.SqlExecutor, table, key, to string) (int64, error) {
+	var n int64
+	var err error
+	if to == "" {
+		n, err = db.SelectInt(fmt.Sprintf(`select count(distinct %s) from "%s"`, key, table))
+	} else {
+		n, err = db.SelectInt(fmt.Sprintf(`select count(distinct %s) from "%s" where %s <= $1`, key, table, key), to)
+	}
+	return n, sdk.WithStack(err)
+}
@@ -1,6 +1,7 @@
 package gorpmapping
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ovh/cds/sdk"
@@ -38,3 +39,20 @@ func RollEncryptedTupleByPrimaryKey(db gorp.SqlExecutor, entity string, pk inter
 
 	return nil
 }
+
+// RollTupleByPrimaryKey encrypts and signs again a tuple with the latest keys.
+func RollTupleByPrimaryKey(ctx context.Context, db gorp.SqlExecutor, entity string, pk interface{}) error {
+	e, ok := Mapping[entity]
+	if !ok {
+		return sdk.WithStack(errors.New("unknown entity"))
+	}
+
+	// updating a signed entity also encrypts its data again
+	if e.SignedEntity {
+		return RollSignedTupleByPrimaryKey(ctx, db, entity, pk)
+	}
+	if e.EncryptedEntity {
+		return RollEncryptedTupleByPrimaryKey(db, entity, pk)
+	}
+	return nil
+}
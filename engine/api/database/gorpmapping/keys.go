@@ -72,3 +72,19 @@ func ConfigureKeys(signatureKeys, encryptionKeys *[]keyloader.KeyConfig) error {
 
 	return globalErr
 }
+
+// EncryptionKey returns the rolling key used to encrypt data, it decrypts data encrypted with any of its keys.
+func EncryptionKey() symmecrypt.Key {
+	return encryptionKey
+}
+
+// LatestKey returns the key of a rolling key that is used to encrypt or sign new data.
+func LatestKey(k symmecrypt.Key) symmecrypt.Key {
+	if w, ok := k.(interface{ Key() symmecrypt.Key }); ok {
+		k = w.Key()
+	}
+	if c, ok := k.(symmecrypt.CompositeKey); ok && len(c) > 0 {
+		return c[0]
+	}
+	return k
+}
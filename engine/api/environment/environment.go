@@ -16,7 +16,7 @@ import (
 func LoadEnvironments(db gorp.SqlExecutor, projectKey string) ([]sdk.Environment, error) {
 	var envs []sdk.Environment
 
-	query := `SELECT environment.id, environment.name, environment.last_modified, environment.from_repository
+	query := `SELECT environment.id, environment.name, environment.last_modified, environment.from_repository, environment.lock_name
 		  FROM environment
 		  JOIN project ON project.id = environment.project_id
 		  WHERE project.projectKey = $1
@@ -33,7 +33,7 @@ func LoadEnvironments(db gorp.SqlExecutor, projectKey string) ([]sdk.Environment
 	for rows.Next() {
 		var env sdk.Environment
 		var lastModified time.Time
-		if err := rows.Scan(&env.ID, &env.Name, &lastModified, &env.FromRepository); err != nil {
+		if err := rows.Scan(&env.ID, &env.Name, &lastModified, &env.FromRepository, &env.Lock); err != nil {
 			return envs, sdk.WithStack(err)
 		}
 		env.LastModified = lastModified.Unix()
@@ -73,10 +73,10 @@ func LoadEnvironmentByID(db gorp.SqlExecutor, ID int64) (*sdk.Environment, error
 		return &sdk.DefaultEnv, nil
 	}
 	var env sdk.Environment
-	query := `SELECT environment.id, environment.name, environment.project_id, environment.from_repository
+	query := `SELECT environment.id, environment.name, environment.project_id, environment.from_repository, environment.lock_name
 		  	FROM environment
 		 	WHERE id = $1`
-	if err := db.QueryRow(query, ID).Scan(&env.ID, &env.Name, &env.ProjectID, &env.FromRepository); err != nil {
+	if err := db.QueryRow(query, ID).Scan(&env.ID, &env.Name, &env.ProjectID, &env.FromRepository, &env.Lock); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrEnvironmentNotFound
 		}
@@ -92,12 +92,12 @@ func LoadEnvironmentByName(db gorp.SqlExecutor, projectKey, envName string) (*sd
 	}
 
 	var env sdk.Environment
-	query := `SELECT environment.id, environment.name,  environment.project_id, environment.from_repository, environment.last_modified
+	query := `SELECT environment.id, environment.name,  environment.project_id, environment.from_repository, environment.lock_name, environment.last_modified
 		  FROM environment
 		  JOIN project ON project.id = environment.project_id
 		  WHERE project.projectKey = $1 AND environment.name = $2`
 	var lastModified time.Time
-	if err := db.QueryRow(query, projectKey, envName).Scan(&env.ID, &env.Name, &env.ProjectID, &env.FromRepository, &lastModified); err != nil {
+	if err := db.QueryRow(query, projectKey, envName).Scan(&env.ID, &env.Name, &env.ProjectID, &env.FromRepository, &env.Lock, &lastModified); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrorWithData(sdk.ErrEnvironmentNotFound, envName)
 		}
@@ -181,15 +181,18 @@ func loadDependencies(db gorp.SqlExecutor, env *sdk.Environment) error {
 
 // InsertEnvironment Insert new environment
 func InsertEnvironment(db gorp.SqlExecutor, env *sdk.Environment) error {
-	query := `INSERT INTO environment (name, project_id, from_repository) VALUES($1, $2, $3) RETURNING id, last_modified`
+	query := `INSERT INTO environment (name, project_id, from_repository, lock_name) VALUES($1, $2, $3, $4) RETURNING id, last_modified`
 
 	rx := sdk.NamePatternRegex
 	if !rx.MatchString(env.Name) {
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid environment name. It should match %s", sdk.NamePattern))
 	}
+	if err := sdk.IsValidWorkflowNodeRunLockName(env.Lock); err != nil {
+		return err
+	}
 
 	var lastModified time.Time
-	err := db.QueryRow(query, env.Name, env.ProjectID, env.FromRepository).Scan(&env.ID, &lastModified)
+	err := db.QueryRow(query, env.Name, env.ProjectID, env.FromRepository, env.Lock).Scan(&env.ID, &lastModified)
 	if err != nil {
 		pqerr, ok := err.(*pq.Error)
 		if ok {
@@ -209,9 +212,12 @@ func UpdateEnvironment(db gorp.SqlExecutor, environment *sdk.Environment) error
 	if !rx.MatchString(environment.Name) {
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid environment name. It should match %s", sdk.NamePattern))
 	}
+	if err := sdk.IsValidWorkflowNodeRunLockName(environment.Lock); err != nil {
+		return err
+	}
 
-	query := `UPDATE environment SET name=$1, from_repository=$3 WHERE id=$2`
-	if _, err := db.Exec(query, environment.Name, environment.ID, environment.FromRepository); err != nil {
+	query := `UPDATE environment SET name=$1, from_repository=$3, lock_name=$4 WHERE id=$2`
+	if _, err := db.Exec(query, environment.Name, environment.ID, environment.FromRepository, environment.Lock); err != nil {
 		return err
 	}
 	return nil
@@ -51,6 +51,7 @@ func ParseAndImport(db gorp.SqlExecutor, proj sdk.Project, eenv exportentities.E
 	env := new(sdk.Environment)
 	env.Name = eenv.Name
 	env.FromRepository = opts.FromRepository
+	env.Lock = eenv.Lock
 	if exist {
 		env.ID = oldEnv.ID
 	}
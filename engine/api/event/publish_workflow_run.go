@@ -173,3 +173,21 @@ func PublishWorkflowNodeJobRun(ctx context.Context, db gorp.SqlExecutor, pkey st
 	}
 	publishRunWorkflow(ctx, e, pkey, wr.Workflow.Name, "", "", "", 0, 0, jr.Status, nil, wr.Workflow.EventIntegrations)
 }
+
+// PublishWorkflowNodeRunApproval publish event on the approval gate of a workflow node run
+func PublishWorkflowNodeRunApproval(ctx context.Context, pkey string, wr sdk.WorkflowRun, a sdk.WorkflowNodeRunApproval, d *sdk.WorkflowNodeRunApprovalDecision) {
+	e := sdk.EventRunWorkflowNodeApproval{
+		ID:           a.ID,
+		NodeRunID:    a.WorkflowNodeRunID,
+		NodeName:     a.WorkflowNodeName,
+		Status:       a.Status,
+		MinApprovals: a.MinApprovals,
+		Approvals:    a.Approvals(),
+	}
+	if d != nil {
+		e.Username = d.Username
+		e.Approved = d.Approved
+		e.Comment = d.Comment
+	}
+	publishRunWorkflow(ctx, e, pkey, wr.Workflow.Name, "", "", "", wr.Number, wr.LastSubNumber, a.Status, wr.Tags, wr.Workflow.EventIntegrations)
+}
@@ -23,12 +23,15 @@ type LinkGroupUser struct {
 	GroupID            int64  `db:"group_id"`
 	AuthentifiedUserID string `db:"authentified_user_id"`
 	Admin              bool   `db:"group_admin"`
+	// ConsumerType is set when the link is managed by an auth driver groups synchronization
+	ConsumerType sdk.AuthConsumerType `db:"consumer_type"`
 	gorpmapping.SignedEntity
 }
 
 func (c LinkGroupUser) Canonical() gorpmapping.CanonicalForms {
-	_ = []interface{}{c.ID, c.AuthentifiedUserID, c.GroupID, c.Admin} // Checks that fields exists at compilation
+	_ = []interface{}{c.ID, c.AuthentifiedUserID, c.GroupID, c.Admin, c.ConsumerType} // Checks that fields exists at compilation
 	return []gorpmapping.CanonicalForm{
+		"{{print .ID}}{{.AuthentifiedUserID}}{{print .GroupID}}{{print .Admin}}{{print .ConsumerType}}",
 		"{{print .ID}}{{.AuthentifiedUserID}}{{print .GroupID}}{{print .Admin}}",
 	}
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
 // DeleteUserFromGroup remove user from group
@@ -59,3 +60,55 @@ func CheckUserInDefaultGroup(ctx context.Context, db gorp.SqlExecutor, userID st
 
 	return nil
 }
+
+// SyncUserGroups updates the groups of a user from the groups given by an auth driver.
+// The user is added to the existing groups that match given names and removed from the groups
+// previously added by the same driver that are not given anymore, unknown groups are ignored.
+// Links created manually or promoted to group admin are never removed.
+func SyncUserGroups(ctx context.Context, db gorp.SqlExecutor, userID string, consumerType sdk.AuthConsumerType, groupNames []string) error {
+	gs, err := LoadAllByNames(ctx, db, groupNames)
+	if err != nil {
+		return err
+	}
+
+	links, err := LoadLinksGroupUserForUserIDs(ctx, db, []string{userID})
+	if err != nil {
+		return err
+	}
+
+	for _, g := range gs {
+		var found bool
+		for _, l := range links {
+			if l.GroupID == g.ID {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		log.Debug("group.SyncUserGroups> adding user %s to group %s from %s consumer", userID, g.Name, consumerType)
+		if err := InsertLinkGroupUser(ctx, db, &LinkGroupUser{
+			GroupID:            g.ID,
+			AuthentifiedUserID: userID,
+			ConsumerType:       consumerType,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for i := range links {
+		if links[i].ConsumerType != consumerType || links[i].Admin {
+			continue
+		}
+		if gs.HasOneOf(links[i].GroupID) {
+			continue
+		}
+		log.Debug("group.SyncUserGroups> removing user %s from group %d from %s consumer", userID, links[i].GroupID, consumerType)
+		if err := DeleteLinkGroupUser(db, &links[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package group_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSyncUserGroups(t *testing.T) {
+	db, _, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+
+	admin, _ := assets.InsertAdminUser(t, db)
+	u, _ := assets.InsertLambdaUser(t, db)
+
+	g1 := &sdk.Group{Name: sdk.RandomString(10)}
+	g2 := &sdk.Group{Name: sdk.RandomString(10)}
+	g3 := &sdk.Group{Name: sdk.RandomString(10)}
+	for _, g := range []*sdk.Group{g1, g2, g3} {
+		require.NoError(t, group.Create(context.TODO(), db, g, admin.ID))
+	}
+
+	// Membership of g3 is managed manually
+	require.NoError(t, group.InsertLinkGroupUser(context.TODO(), db, &group.LinkGroupUser{
+		GroupID:            g3.ID,
+		AuthentifiedUserID: u.ID,
+	}))
+
+	isMember := func(g *sdk.Group) bool {
+		l, err := group.LoadLinkGroupUserForGroupIDAndUserID(context.TODO(), db, g.ID, u.ID)
+		if err != nil {
+			require.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+			return false
+		}
+		return l != nil
+	}
+
+	require.NoError(t, group.SyncUserGroups(context.TODO(), db, u.ID, sdk.ConsumerOIDC, []string{g1.Name, g2.Name, "unknown-group"}))
+	assert.True(t, isMember(g1))
+	assert.True(t, isMember(g2))
+	assert.True(t, isMember(g3))
+
+	require.NoError(t, group.SyncUserGroups(context.TODO(), db, u.ID, sdk.ConsumerOIDC, []string{g2.Name}))
+	assert.False(t, isMember(g1))
+	assert.True(t, isMember(g2))
+	assert.True(t, isMember(g3))
+
+	// Links managed by another driver are not removed
+	require.NoError(t, group.SyncUserGroups(context.TODO(), db, u.ID, sdk.ConsumerGitlab, nil))
+	assert.True(t, isMember(g2))
+
+	require.NoError(t, group.SyncUserGroups(context.TODO(), db, u.ID, sdk.ConsumerOIDC, nil))
+	assert.False(t, isMember(g2))
+	assert.True(t, isMember(g3))
+}
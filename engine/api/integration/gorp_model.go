@@ -2,7 +2,7 @@ package integration
 
 import (
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
-
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -13,5 +13,6 @@ type dbProjectIntegration sdk.ProjectIntegration
 func init() {
 	gorpmapping.Register(gorpmapping.New(integrationModel{}, "integration_model", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbProjectIntegration{}, "project_integration", true, "id"))
-
+	secret.RegisterColumn(secret.Column{Table: "integration_model", Key: "id", Name: "public_configurations", JSON: true})
+	secret.RegisterColumn(secret.Column{Table: "project_integration", Key: "id", Name: "config", JSON: true})
 }
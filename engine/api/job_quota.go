@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ovh/cds/engine/api/jobquota"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getAdminJobQuotasHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		quotas, err := jobquota.LoadAll(ctx, api.mustDB())
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, quotas, http.StatusOK)
+	}
+}
+
+// postAdminJobQuotaHandler creates or updates the job quota for a type and a target.
+func (api *API) postAdminJobQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var q sdk.JobQuota
+		if err := service.UnmarshalBody(r, &q); err != nil {
+			return err
+		}
+		if err := q.IsValid(); err != nil {
+			return err
+		}
+
+		old, err := jobquota.LoadByTypeAndTarget(ctx, api.mustDB(), q.Type, q.Target)
+		if err != nil {
+			return err
+		}
+		if old != nil {
+			old.MaxRunningJobs = q.MaxRunningJobs
+			if err := jobquota.Update(api.mustDB(), old); err != nil {
+				return err
+			}
+			return service.WriteJSON(w, old, http.StatusOK)
+		}
+
+		if err := jobquota.Insert(api.mustDB(), &q); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, q, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteAdminJobQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		quotaType := r.FormValue("type")
+		target := r.FormValue("target")
+
+		q, err := jobquota.LoadByTypeAndTarget(ctx, api.mustDB(), quotaType, target)
+		if err != nil {
+			return err
+		}
+		if q == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no %s quota for %s", quotaType, target)
+		}
+
+		if err := jobquota.Delete(api.mustDB(), *q); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// getWorkflowJobQueueQuotasHandler returns the number of running and waiting jobs for each job quota.
+func (api *API) getWorkflowJobQueueQuotasHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		quotas, err := jobquota.LoadState(ctx, api.mustDB(), api.Cache)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, quotas.Usage(), http.StatusOK)
+	}
+}
@@ -0,0 +1,85 @@
+package jobquota
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func getAll(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.JobQuota, error) {
+	res := []dbJobQuota{}
+	if err := gorpmapping.GetAll(ctx, db, q, &res); err != nil {
+		return nil, sdk.WrapError(err, "cannot get job quotas")
+	}
+
+	qs := make([]sdk.JobQuota, len(res))
+	for i := range res {
+		qs[i] = sdk.JobQuota(res[i])
+	}
+	return qs, nil
+}
+
+// LoadAll returns all the job quotas.
+func LoadAll(ctx context.Context, db gorp.SqlExecutor) ([]sdk.JobQuota, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM job_quota
+    ORDER BY type, target
+  `)
+	return getAll(ctx, db, query)
+}
+
+// LoadByTypeAndTarget returns the job quota for given type and target, or nil if not found.
+func LoadByTypeAndTarget(ctx context.Context, db gorp.SqlExecutor, quotaType, target string) (*sdk.JobQuota, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM job_quota
+    WHERE type = $1
+    AND target = $2
+  `).Args(quotaType, target)
+	var q dbJobQuota
+	found, err := gorpmapping.Get(ctx, db, query, &q)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get job quota")
+	}
+	if !found {
+		return nil, nil
+	}
+	res := sdk.JobQuota(q)
+	return &res, nil
+}
+
+// Insert a job quota in database.
+func Insert(db gorp.SqlExecutor, q *sdk.JobQuota) error {
+	q.Created = time.Now()
+	q.LastModified = q.Created
+	dbq := dbJobQuota(*q)
+	if err := gorpmapping.Insert(db, &dbq); err != nil {
+		return sdk.WrapError(err, "cannot insert job quota")
+	}
+	*q = sdk.JobQuota(dbq)
+	return nil
+}
+
+// Update a job quota in database.
+func Update(db gorp.SqlExecutor, q *sdk.JobQuota) error {
+	q.LastModified = time.Now()
+	dbq := dbJobQuota(*q)
+	if err := gorpmapping.Update(db, &dbq); err != nil {
+		return sdk.WrapError(err, "cannot update job quota")
+	}
+	return nil
+}
+
+// Delete a job quota from database.
+func Delete(db gorp.SqlExecutor, q sdk.JobQuota) error {
+	dbq := dbJobQuota(q)
+	if err := gorpmapping.Delete(db, &dbq); err != nil {
+		return sdk.WrapError(err, "cannot delete job quota")
+	}
+	return nil
+}
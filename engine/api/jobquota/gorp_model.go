@@ -0,0 +1,12 @@
+package jobquota
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbJobQuota sdk.JobQuota
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbJobQuota{}, "job_quota", true, "id"))
+}
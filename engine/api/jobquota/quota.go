@@ -0,0 +1,317 @@
+package jobquota
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// subject is what the job quotas apply to in a job run.
+type subject struct {
+	projectKey  string
+	groups      []string
+	workerModel string
+}
+
+func newSubject(projectKey string, groups sdk.Groups, reqs sdk.RequirementList) subject {
+	s := subject{
+		projectKey:  projectKey,
+		groups:      make([]string, len(groups)),
+		workerModel: sdk.WorkerModelPathFromRequirements(reqs),
+	}
+	for i := range groups {
+		s.groups[i] = groups[i].Name
+	}
+	return s
+}
+
+func (s subject) matches(q sdk.JobQuota) bool {
+	switch q.Type {
+	case sdk.JobQuotaTypeProject:
+		return s.projectKey == q.Target
+	case sdk.JobQuotaTypeGroup:
+		return sdk.IsInArray(q.Target, s.groups)
+	case sdk.JobQuotaTypeWorkerModel:
+		return s.workerModel == q.Target
+	}
+	return false
+}
+
+// State is the usage of the job quotas, built from the running and waiting jobs. The waiting jobs booked by a
+// hatchery are counted as running.
+type State struct {
+	quotas           []sdk.JobQuota
+	running          []int64
+	waiting          []int64
+	bookedJobs       map[int64]struct{}
+	projectKeys      map[int64]string
+	runningByProject map[int64]int
+}
+
+// LoadState loads the job quotas and counts the running and waiting jobs for each of them.
+func LoadState(ctx context.Context, db gorp.SqlExecutor, store cache.Store) (*State, error) {
+	quotas, err := LoadAll(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newState(quotas)
+
+	var counts = []struct {
+		ProjectID int64 `db:"project_id"`
+		Count     int   `db:"count"`
+	}{}
+	query := `SELECT project_id, COUNT(id) "count" FROM workflow_node_run_job WHERE status = $1 GROUP BY project_id`
+	if _, err := db.Select(&counts, query, sdk.StatusBuilding); err != nil {
+		return nil, sdk.WrapError(err, "cannot count running jobs")
+	}
+	for _, c := range counts {
+		s.runningByProject[c.ProjectID] = c.Count
+	}
+
+	// Jobs are loaded only if there is some quota to check
+	if len(quotas) == 0 {
+		return s, nil
+	}
+	if err := s.countJobs(ctx, db, store, 0); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// newStateForJob returns a state with the job quotas that apply to given job, and the project that the jobs to count
+// belong to, 0 if the jobs of all the projects must be counted.
+func newStateForJob(ctx context.Context, db gorp.SqlExecutor, j sdk.WorkflowNodeJobRun) (*State, int64, error) {
+	quotas, err := LoadAll(ctx, db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	projectKey, err := db.SelectStr("SELECT projectkey FROM project WHERE id = $1", j.ProjectID)
+	if err != nil {
+		return nil, 0, sdk.WrapError(err, "cannot load project key")
+	}
+	sub := newSubject(projectKey, j.ExecGroups, j.Job.Action.Requirements)
+
+	var matched []sdk.JobQuota
+	onlyProject := true
+	for _, q := range quotas {
+		if sub.matches(q) {
+			matched = append(matched, q)
+			onlyProject = onlyProject && q.Type == sdk.JobQuotaTypeProject
+		}
+	}
+
+	s := newState(matched)
+	s.projectKeys[j.ProjectID] = projectKey
+
+	// Only the jobs of the project can match when all the quotas are project quotas
+	if onlyProject {
+		return s, j.ProjectID, nil
+	}
+	return s, 0, nil
+}
+
+func newState(quotas []sdk.JobQuota) *State {
+	return &State{
+		quotas:           quotas,
+		running:          make([]int64, len(quotas)),
+		waiting:          make([]int64, len(quotas)),
+		bookedJobs:       make(map[int64]struct{}),
+		projectKeys:      make(map[int64]string),
+		runningByProject: make(map[int64]int),
+	}
+}
+
+// countJobs counts the running and waiting jobs for each quota of the state, only for given project if not 0.
+func (s *State) countJobs(ctx context.Context, db gorp.SqlExecutor, store cache.Store, projectID int64) error {
+	var jobs = []struct {
+		ID           int64          `db:"id"`
+		ProjectID    int64          `db:"project_id"`
+		ProjectKey   string         `db:"projectkey"`
+		Status       string         `db:"status"`
+		ExecGroups   sql.NullString `db:"exec_groups"`
+		Requirements sql.NullString `db:"requirements"`
+	}{}
+	query := `
+	SELECT workflow_node_run_job.id, workflow_node_run_job.project_id, project.projectkey, workflow_node_run_job.status,
+		workflow_node_run_job.exec_groups, workflow_node_run_job.job->'action'->'requirements' "requirements"
+	FROM workflow_node_run_job
+	JOIN project ON project.id = workflow_node_run_job.project_id
+	WHERE workflow_node_run_job.status = ANY(string_to_array($1, ','))
+	AND ($2 = 0 OR workflow_node_run_job.project_id = $2)`
+	if _, err := db.Select(&jobs, query, strings.Join([]string{sdk.StatusBuilding, sdk.StatusWaiting}, ","), projectID); err != nil {
+		return sdk.WrapError(err, "cannot load jobs")
+	}
+
+	for _, j := range jobs {
+		s.projectKeys[j.ProjectID] = j.ProjectKey
+		var groups sdk.Groups
+		if err := gorpmapping.JSONNullString(j.ExecGroups, &groups); err != nil {
+			return sdk.WrapError(err, "cannot read exec groups")
+		}
+		var reqs sdk.RequirementList
+		if err := gorpmapping.JSONNullString(j.Requirements, &reqs); err != nil {
+			return sdk.WrapError(err, "cannot read requirements")
+		}
+		sub := newSubject(j.ProjectKey, groups, reqs)
+		var matched []int
+		for i := range s.quotas {
+			if sub.matches(s.quotas[i]) {
+				matched = append(matched, i)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		running := j.Status == sdk.StatusBuilding
+		if !running && workflow.IsNodeJobRunBooked(ctx, store, j.ID) {
+			s.bookedJobs[j.ID] = struct{}{}
+			running = true
+		}
+		for _, i := range matched {
+			if running {
+				s.running[i]++
+			} else {
+				s.waiting[i]++
+			}
+		}
+	}
+
+	return nil
+}
+
+// Usage returns the number of running and waiting jobs for each job quota.
+func (s *State) Usage() []sdk.JobQuotaUsage {
+	usage := make([]sdk.JobQuotaUsage, len(s.quotas))
+	for i, q := range s.quotas {
+		usage[i] = sdk.JobQuotaUsage{
+			Type:           q.Type,
+			Target:         q.Target,
+			MaxRunningJobs: q.MaxRunningJobs,
+			RunningJobs:    s.running[i],
+			WaitingJobs:    s.waiting[i],
+		}
+	}
+	return usage
+}
+
+// RunningJobsByProject returns the number of running jobs by project ID.
+func (s *State) RunningJobsByProject() map[int64]int {
+	return s.runningByProject
+}
+
+func (s *State) subject(j sdk.WorkflowNodeJobRun) subject {
+	projectKey, ok := s.projectKeys[j.ProjectID]
+	if !ok {
+		projectKey = sdk.ParameterValue(j.Parameters, "cds.project")
+	}
+	return newSubject(projectKey, j.ExecGroups, j.Job.Action.Requirements)
+}
+
+// Check returns an error if one of the job quotas that apply to given job is reached.
+func (s *State) Check(j sdk.WorkflowNodeJobRun) error {
+	sub := s.subject(j)
+	// a job already booked is counted in the running jobs
+	_, booked := s.bookedJobs[j.ID]
+	for i, q := range s.quotas {
+		if !sub.matches(q) {
+			continue
+		}
+		running := s.running[i]
+		if booked {
+			running--
+		}
+		if running >= q.MaxRunningJobs {
+			return sdk.NewErrorFrom(sdk.ErrJobQuotaReached, "%s quota reached for %s (%d running jobs)", q.Type, q.Target, running)
+		}
+	}
+	return nil
+}
+
+// CheckAndBook checks the job quotas that apply to given job then books it with given func. The quotas are locked
+// until the job is booked, so that concurrent bookings can't exceed them.
+func CheckAndBook(ctx context.Context, db gorp.SqlExecutor, store cache.Store, j sdk.WorkflowNodeJobRun, book func() error) error {
+	s, projectID, err := newStateForJob(ctx, db, j)
+	if err != nil {
+		return err
+	}
+	if len(s.quotas) == 0 {
+		return book()
+	}
+
+	// quotas are loaded ordered by type and target so locks are always taken in the same order
+	for _, q := range s.quotas {
+		k := cache.Key("jobquota", "lock", q.Type, q.Target)
+		locked, err := store.Lock(k, 10*time.Second, 100, 50)
+		if err != nil {
+			return err
+		}
+		if !locked {
+			return sdk.NewErrorFrom(sdk.ErrJobQuotaReached, "%s quota for %s is locked by another booking", q.Type, q.Target)
+		}
+		defer func() {
+			if err := store.Unlock(k); err != nil {
+				log.Error(ctx, "jobquota.CheckAndBook> cannot unlock %s: %v", k, err)
+			}
+		}()
+	}
+
+	// jobs are counted once the quotas are locked, so the jobs booked concurrently are counted
+	if err := s.countJobs(ctx, db, store, projectID); err != nil {
+		return err
+	}
+	if err := s.Check(j); err != nil {
+		return err
+	}
+	return book()
+}
+
+// Filter returns the waiting jobs that can be started without exceeding the job quotas, oldest first as given.
+// Jobs that are not waiting are kept.
+func (s *State) Filter(jobs []sdk.WorkflowNodeJobRun) []sdk.WorkflowNodeJobRun {
+	if len(s.quotas) == 0 {
+		return jobs
+	}
+
+	planned := make([]int64, len(s.quotas))
+	res := make([]sdk.WorkflowNodeJobRun, 0, len(jobs))
+jobs:
+	for _, j := range jobs {
+		if j.Status != sdk.StatusWaiting {
+			res = append(res, j)
+			continue
+		}
+		// booked jobs are already counted in the running jobs
+		if _, booked := s.bookedJobs[j.ID]; booked {
+			res = append(res, j)
+			continue
+		}
+		sub := s.subject(j)
+		var matched []int
+		for i, q := range s.quotas {
+			if !sub.matches(q) {
+				continue
+			}
+			if s.running[i]+planned[i] >= q.MaxRunningJobs {
+				continue jobs
+			}
+			matched = append(matched, i)
+		}
+		for _, i := range matched {
+			planned[i]++
+		}
+		res = append(res, j)
+	}
+	return res
+}
@@ -0,0 +1,79 @@
+package jobquota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestStateFilter(t *testing.T) {
+	s := &State{
+		quotas: []sdk.JobQuota{
+			{Type: sdk.JobQuotaTypeProject, Target: "PROJ1", MaxRunningJobs: 2},
+			{Type: sdk.JobQuotaTypeWorkerModel, Target: "shared.infra/debian", MaxRunningJobs: 1},
+		},
+		running:     []int64{1, 0},
+		waiting:     []int64{3, 1},
+		projectKeys: map[int64]string{1: "PROJ1", 2: "PROJ2"},
+	}
+
+	debian := sdk.RequirementList{{Name: "debian", Type: sdk.ModelRequirement, Value: "debian --privileged"}}
+	jobs := []sdk.WorkflowNodeJobRun{
+		{ID: 1, ProjectID: 1, Status: sdk.StatusWaiting},
+		{ID: 2, ProjectID: 1, Status: sdk.StatusWaiting},
+		{ID: 3, ProjectID: 2, Status: sdk.StatusWaiting},
+		{ID: 4, ProjectID: 2, Status: sdk.StatusWaiting},
+		{ID: 5, ProjectID: 1, Status: sdk.StatusBuilding},
+	}
+	jobs[2].Job.Action.Requirements = debian
+	jobs[3].Job.Action.Requirements = debian
+
+	res := s.Filter(jobs)
+	ids := make([]int64, len(res))
+	for i := range res {
+		ids[i] = res[i].ID
+	}
+	// PROJ1 has one slot left and the debian model one slot
+	assert.Equal(t, []int64{1, 3, 5}, ids)
+
+	require.NoError(t, s.Check(jobs[0]))
+	s.running[0] = 2
+	err := s.Check(jobs[0])
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrJobQuotaReached))
+	require.NoError(t, s.Check(jobs[2]))
+
+	usage := s.Usage()
+	require.Len(t, usage, 2)
+	assert.Equal(t, int64(2), usage[0].RunningJobs)
+	assert.Equal(t, int64(1), usage[1].WaitingJobs)
+}
+
+func TestStateBookedJobs(t *testing.T) {
+	// job 1 is booked and counted as running
+	s := &State{
+		quotas:      []sdk.JobQuota{{Type: sdk.JobQuotaTypeProject, Target: "PROJ1", MaxRunningJobs: 2}},
+		running:     []int64{2},
+		waiting:     []int64{1},
+		bookedJobs:  map[int64]struct{}{1: {}},
+		projectKeys: map[int64]string{1: "PROJ1"},
+	}
+
+	jobs := []sdk.WorkflowNodeJobRun{
+		{ID: 1, ProjectID: 1, Status: sdk.StatusWaiting},
+		{ID: 2, ProjectID: 1, Status: sdk.StatusWaiting},
+	}
+
+	res := s.Filter(jobs)
+	require.Len(t, res, 1)
+	assert.Equal(t, int64(1), res[0].ID)
+
+	// the booked job can be booked again by its hatchery but not another job
+	require.NoError(t, s.Check(jobs[0]))
+	err := s.Check(jobs[1])
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrJobQuotaReached))
+}
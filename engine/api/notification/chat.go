@@ -0,0 +1,150 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// chatAllowedHosts are the hosts that chat notifications can be sent to even if they resolve to a private address
+var chatAllowedHosts []string
+
+var chatHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		Proxy:       http.ProxyFromEnvironment,
+		DialContext: chatDialContext,
+	},
+}
+
+// chatDialContext refuses to connect to loopback, private and link-local addresses, unless the host is allowed in the
+// configuration. The resolved address is checked so a public host name can't be used to reach the internal network.
+func chatDialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !sdk.IsInArray(host, chatAllowedHosts) {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			ip, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicIP(net.ParseIP(ip)) {
+				return fmt.Errorf("destination %s of host %s is not allowed", ip, host)
+			}
+			return nil
+		}
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+var privateNetworks = func() []*net.IPNet {
+	var res []*net.IPNet
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"} {
+		_, n, _ := net.ParseCIDR(cidr)
+		res = append(res, n)
+	}
+	return res
+}()
+
+type slackMessage struct {
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+type teamsMessage struct {
+	Type       string `json:"@type"`
+	Context    string `json:"@context"`
+	ThemeColor string `json:"themeColor,omitempty"`
+	Summary    string `json:"summary"`
+	Title      string `json:"title"`
+	Text       string `json:"text"`
+}
+
+// chatMessage returns the payload expected by the chat webhook for given notification type
+func chatMessage(notifType, channel, status string, notif sdk.EventNotif) (interface{}, error) {
+	switch notifType {
+	case sdk.SlackUserNotification, sdk.MattermostUserNotification:
+		// Slack and Mattermost incoming webhooks share the same payload
+		text := notif.Body
+		if notif.Subject != "" {
+			text = fmt.Sprintf("*%s*\n%s", notif.Subject, notif.Body)
+		}
+		return slackMessage{Channel: channel, Text: text}, nil
+	case sdk.TeamsUserNotification:
+		m := teamsMessage{
+			Type:    "MessageCard",
+			Context: "https://schema.org/extensions",
+			Summary: notif.Subject,
+			Title:   notif.Subject,
+			Text:    notif.Body,
+		}
+		switch status {
+		case sdk.StatusSuccess:
+			m.ThemeColor = "21BA45"
+		case sdk.StatusFail:
+			m.ThemeColor = "DB2828"
+		}
+		return m, nil
+	}
+	return nil, sdk.WithStack(fmt.Errorf("invalid chat notification type %s", notifType))
+}
+
+// sendChatNotif sends user notification to a chat webhook
+func sendChatNotif(ctx context.Context, notifType, webhookURL, channel, status string, notif sdk.EventNotif) error {
+	msg, err := chatMessage(notifType, channel, status, notif)
+	if err != nil {
+		return err
+	}
+	btes, err := json.Marshal(msg)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+
+	if err := sdk.CheckChatWebhookURL(webhookURL); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(btes))
+	if err != nil {
+		return sdk.WrapError(err, "invalid %s webhook url", notifType)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	log.Info(ctx, "notification.sendChatNotif> Send %s notif '%s'", notifType, notif.Subject)
+	resp, err := chatHTTPClient.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "cannot send %s notification", notifType)
+	}
+	defer resp.Body.Close() // nolint
+	if resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return sdk.WithStack(fmt.Errorf("cannot send %s notification: webhook returned %d: %s", notifType, resp.StatusCode, body))
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ovh/symmecrypt/keyloader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+// chatServer is a stand-in for chat webhooks that records received messages, its loopback address is allowed
+func chatServer(t *testing.T, status int) (*httptest.Server, chan map[string]interface{}) {
+	chatAllowedHosts = []string{"127.0.0.1"}
+	received := make(chan map[string]interface{}, 10)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var msg map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		received <- msg
+		w.WriteHeader(status)
+	}))
+	return s, received
+}
+
+func Test_sendChatNotif(t *testing.T) {
+	s, received := chatServer(t, http.StatusOK)
+	defer s.Close()
+
+	notif := sdk.EventNotif{Subject: "PROJ/my-workflow#12 Fail", Body: "http://cds/run/12"}
+
+	require.NoError(t, sendChatNotif(context.TODO(), sdk.SlackUserNotification, s.URL, "#builds", sdk.StatusFail, notif))
+	msg := <-received
+	assert.Equal(t, "#builds", msg["channel"])
+	assert.Equal(t, "*PROJ/my-workflow#12 Fail*\nhttp://cds/run/12", msg["text"])
+
+	require.NoError(t, sendChatNotif(context.TODO(), sdk.MattermostUserNotification, s.URL, "", sdk.StatusFail, notif))
+	msg = <-received
+	_, hasChannel := msg["channel"]
+	assert.False(t, hasChannel)
+	assert.Equal(t, "*PROJ/my-workflow#12 Fail*\nhttp://cds/run/12", msg["text"])
+
+	require.NoError(t, sendChatNotif(context.TODO(), sdk.TeamsUserNotification, s.URL, "", sdk.StatusFail, notif))
+	msg = <-received
+	assert.Equal(t, "MessageCard", msg["@type"])
+	assert.Equal(t, "PROJ/my-workflow#12 Fail", msg["title"])
+	assert.Equal(t, "http://cds/run/12", msg["text"])
+	assert.Equal(t, "DB2828", msg["themeColor"])
+
+	require.Error(t, sendChatNotif(context.TODO(), sdk.JabberUserNotification, s.URL, "", sdk.StatusFail, notif))
+}
+
+func Test_sendChatNotifError(t *testing.T) {
+	s, received := chatServer(t, http.StatusNotFound)
+	defer s.Close()
+
+	err := sendChatNotif(context.TODO(), sdk.SlackUserNotification, s.URL, "", sdk.StatusSuccess, sdk.EventNotif{Body: "test"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook returned 404")
+	<-received
+}
+
+func Test_sendChatNotifRefusedDestination(t *testing.T) {
+	s, received := chatServer(t, http.StatusOK)
+	defer s.Close()
+	chatAllowedHosts = nil
+
+	err := sendChatNotif(context.TODO(), sdk.SlackUserNotification, s.URL, "", sdk.StatusSuccess, sdk.EventNotif{Body: "test"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not allowed")
+
+	err = sendChatNotif(context.TODO(), sdk.SlackUserNotification, "file:///etc/passwd", "", sdk.StatusSuccess, sdk.EventNotif{Body: "test"})
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest))
+
+	select {
+	case <-received:
+		t.Fatal("notification should not be sent")
+	default:
+	}
+}
+
+func TestGetUserWorkflowEventsChat(t *testing.T) {
+	s, received := chatServer(t, http.StatusOK)
+	defer s.Close()
+
+	sigKeys := []keyloader.KeyConfig{{
+		Identifier: gorpmapping.KeySignIdentifier,
+		Cipher:     "hmac",
+		Timestamp:  time.Now().Unix(),
+		Key:        "8f17c90d5306028bdf6ef66cc6da387aca9dd57a11f44e5e2752228398b7d165",
+	}}
+	encryptKeys := []keyloader.KeyConfig{{
+		Identifier: gorpmapping.KeyEcnryptionIdentifier,
+		Cipher:     "xchacha20-poly1305",
+		Timestamp:  time.Now().Unix(),
+		Key:        "fd27b8872bdefeb207bbefc1a82e94039b85d3ec68d891e22a5dcaa81542fc6b",
+	}}
+	require.NoError(t, gorpmapping.ConfigureKeys(&sigKeys, &encryptKeys))
+	webhookURL, err := secret.EncryptValue(s.URL + "/{{.cds.project}}")
+	require.NoError(t, err)
+
+	w := sdk.Workflow{
+		Name:       "my-workflow",
+		ProjectKey: "PROJ",
+		Notifications: []sdk.WorkflowNotification{
+			{
+				Type:           sdk.SlackUserNotification,
+				SourceNodeRefs: []string{"build"},
+				Settings: sdk.UserNotificationSettings{
+					OnSuccess:  sdk.UserNotificationChange,
+					OnFailure:  sdk.UserNotificationAlways,
+					WebhookURL: webhookURL,
+					Template:   &sdk.UserNotificationTemplateChat,
+				},
+			},
+		},
+	}
+	nr := sdk.WorkflowNodeRun{
+		WorkflowNodeName: "build",
+		Number:           12,
+		Status:           sdk.StatusFail,
+		BuildParameters: []sdk.Parameter{
+			{Name: "cds.project", Value: "PROJ"},
+			{Name: "cds.workflow", Value: "my-workflow"},
+			{Name: "cds.version", Value: "12"},
+			{Name: "cds.node", Value: "build"},
+			{Name: "git.branch", Value: "master"},
+			{Name: "cds.triggered_by.username", Value: "fry"},
+		},
+	}
+
+	// Notification is sent on failure
+	GetUserWorkflowEvents(context.TODO(), nil, nil, w, nil, nr)
+	select {
+	case msg := <-received:
+		assert.Equal(t, "*PROJ/my-workflow#12 Fail*\nPipeline build on branch master triggered by fry\n/project/PROJ/workflow/my-workflow/run/12", msg["text"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification was not sent")
+	}
+
+	// Notification is not sent when success follows success
+	nr.Status = sdk.StatusSuccess
+	previous := sdk.WorkflowNodeRun{ID: 1, Status: sdk.StatusSuccess}
+	GetUserWorkflowEvents(context.TODO(), nil, nil, w, &previous, nr)
+	select {
+	case <-received:
+		t.Fatal("notification should not be sent")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
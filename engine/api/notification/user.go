@@ -7,6 +7,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/interpolate"
@@ -19,8 +20,9 @@ var (
 )
 
 // Init initializes notification package
-func Init(uiurl string) {
+func Init(uiurl string, chatWebhookAllowedHosts []string) {
 	uiURL = uiurl
+	chatAllowedHosts = chatWebhookAllowedHosts
 }
 
 // GetUserWorkflowEvents return events to send for the given workflow run
@@ -121,6 +123,31 @@ func GetUserWorkflowEvents(ctx context.Context, db gorp.SqlExecutor, store cache
 					log.Error(ctx, "notification.GetUserWorkflowEvents> unable to handle event %+v: %v", jn, err)
 				}
 				go sendMailNotif(ctx, notif)
+
+			case sdk.SlackUserNotification, sdk.MattermostUserNotification, sdk.TeamsUserNotification:
+				jn := &notif.Settings
+				webhookURL, err := secret.DecryptValue(jn.WebhookURL)
+				if err != nil {
+					log.Error(ctx, "notification[%s].GetUserWorkflowEvents> unable to decrypt webhook url: %v", notif.Type, err)
+					break
+				}
+				webhookURL, err = interpolate.Do(webhookURL, params)
+				if err != nil {
+					log.Error(ctx, "notification[%s].GetUserWorkflowEvents> unable to interpolate webhook url: %v", notif.Type, err)
+					break
+				}
+				e, err := getWorkflowEvent(jn, params)
+				if err != nil {
+					log.Error(ctx, "notification.GetUserWorkflowEvents> unable to handle event %+v: %v", jn, err)
+					break
+				}
+				go func(notifType, channel string) {
+					ctx, cancel := context.WithTimeout(context.Background(), chatHTTPClient.Timeout)
+					defer cancel()
+					if err := sendChatNotif(ctx, notifType, webhookURL, channel, nr.Status, e); err != nil {
+						log.Error(ctx, "notification[%s].GetUserWorkflowEvents> %v", notifType, err)
+					}
+				}(notif.Type, jn.Channel)
 			}
 		}
 	}
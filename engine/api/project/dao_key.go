@@ -6,6 +6,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -119,13 +120,17 @@ func DeleteProjectKey(db gorp.SqlExecutor, projectID int64, keyName string) erro
 }
 
 func loadBuiltinKey(db gorp.SqlExecutor, projectID int64) (*sdk.ProjectKey, error) {
+	return loadBuiltinKeyByName(db, projectID, BuiltinGPGKey)
+}
+
+func loadBuiltinKeyByName(db gorp.SqlExecutor, projectID int64, keyName string) (*sdk.ProjectKey, error) {
 	query := gorpmapping.NewQuery(`
 	SELECT * 
 	FROM project_key
 	WHERE project_id = $1 
 	AND builtin = true 
-	AND name = 'builtin'
-	`).Args(projectID)
+	AND name = $2
+	`).Args(projectID, keyName)
 	var k dbProjectKey
 	found, err := gorpmapping.Get(context.Background(), db, query, &k, gorpmapping.GetOptions.WithDecryption)
 	if err != nil {
@@ -144,3 +149,37 @@ func loadBuiltinKey(db gorp.SqlExecutor, projectID int64) (*sdk.ProjectKey, erro
 	}
 	return &k.ProjectKey, nil
 }
+
+// LoadArtifactProvenanceKey returns the builtin PGP key used to sign the provenance of artifacts, the key is
+// generated at the first call. As a builtin key it is not given to the jobs.
+func LoadArtifactProvenanceKey(db gorp.SqlExecutor, projectID int64) (*sdk.ProjectKey, error) {
+	k, err := loadBuiltinKeyByName(db, projectID, sdk.ArtifactProvenanceKeyName)
+	if err == nil {
+		return k, nil
+	}
+	if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil, sdk.WrapError(err, "cannot load artifact provenance key")
+	}
+
+	pgpKey, err := keys.GeneratePGPKeyPair(sdk.ArtifactProvenanceKeyName)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot generate artifact provenance key")
+	}
+	newKey := sdk.ProjectKey{
+		Name:      sdk.ArtifactProvenanceKeyName,
+		Type:      pgpKey.Type,
+		KeyID:     pgpKey.KeyID,
+		Public:    pgpKey.Public,
+		Private:   pgpKey.Private,
+		ProjectID: projectID,
+		Builtin:   true,
+	}
+	if err := InsertKey(db, &newKey); err != nil {
+		// the key may have been created by a concurrent upload
+		if k, errL := loadBuiltinKeyByName(db, projectID, sdk.ArtifactProvenanceKeyName); errL == nil {
+			return k, nil
+		}
+		return nil, sdk.WrapError(err, "cannot insert artifact provenance key")
+	}
+	return &newKey, nil
+}
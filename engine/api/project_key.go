@@ -95,6 +95,9 @@ func (api *API) addKeyInProjectHandler() service.Handler {
 		if !strings.HasPrefix(newKey.Name, "proj-") {
 			newKey.Name = "proj-" + newKey.Name
 		}
+		if newKey.Name == sdk.ArtifactProvenanceKeyName {
+			return sdk.NewErrorFrom(sdk.ErrInvalidKeyName, "key name %s is reserved", newKey.Name)
+		}
 
 		switch newKey.Type {
 		case sdk.KeyTypeSSH:
@@ -0,0 +1,163 @@
+package purge
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// applyArtifactRetention sets which artifacts are kept by the retention policy, items are ordered latest run first.
+func applyArtifactRetention(r sdk.WorkflowArtifactRetention, items []sdk.WorkflowArtifactRetentionItem, now time.Time) {
+	runsByBranch := make(map[string][]int64)
+	for i := range items {
+		it := &items[i]
+
+		runs := runsByBranch[it.Branch]
+		if len(runs) == 0 || runs[len(runs)-1] != it.RunNumber {
+			runs = append(runs, it.RunNumber)
+			runsByBranch[it.Branch] = runs
+		}
+
+		it.Keep = true
+		switch {
+		case !sdk.StatusIsTerminated(it.RunStatus):
+			it.Reason = "run in progress"
+		case r.KeepReleases && it.GitTag != "":
+			it.Reason = fmt.Sprintf("release %s", it.GitTag)
+		case r.KeepLastPerBranch > 0 && int64(len(runs)) <= r.KeepLastPerBranch:
+			it.Reason = fmt.Sprintf("last %d runs of branch %s", r.KeepLastPerBranch, it.Branch)
+		case r.KeepDays > 0 && now.Sub(it.Created) < time.Duration(r.KeepDays)*24*time.Hour:
+			it.Reason = fmt.Sprintf("uploaded less than %d days ago", r.KeepDays)
+		default:
+			if t, ok := r.MatchTag(it.RunTags); ok {
+				it.Reason = fmt.Sprintf("run tag %s", t)
+			} else {
+				it.Keep = false
+				it.Reason = ""
+			}
+		}
+	}
+}
+
+// ArtifactRetentionReport returns the artifacts of the workflow that are kept or deleted by its retention policy.
+func ArtifactRetentionReport(db gorp.SqlExecutor, projectKey string, wf sdk.Workflow) (*sdk.WorkflowArtifactRetentionReport, error) {
+	if wf.ArtifactRetention == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no artifact retention policy on workflow %s", wf.Name)
+	}
+
+	items, err := workflow.LoadArtifactsForRetention(db, wf.ID)
+	if err != nil {
+		return nil, err
+	}
+	applyArtifactRetention(*wf.ArtifactRetention, items, time.Now())
+
+	return &sdk.WorkflowArtifactRetentionReport{
+		ProjectKey:   projectKey,
+		WorkflowName: wf.Name,
+		Retention:    *wf.ArtifactRetention,
+		Items:        items,
+	}, nil
+}
+
+// artifactsRetention applies the retention policies of the workflows on their artifacts
+func artifactsRetention(ctx context.Context, db gorp.SqlExecutor, sharedStorage objectstore.Driver) error {
+	var res []struct {
+		ID                int64          `db:"id"`
+		Name              string         `db:"name"`
+		ProjectKey        string         `db:"projectkey"`
+		ArtifactRetention sql.NullString `db:"artifact_retention"`
+	}
+	query := `
+		SELECT workflow.id, workflow.name, project.projectkey, workflow.artifact_retention
+		FROM workflow
+		JOIN project ON project.id = workflow.project_id
+		WHERE workflow.artifact_retention IS NOT NULL
+		AND workflow.to_delete = false
+		ORDER BY workflow.id ASC`
+	if _, err := db.Select(&res, query); err != nil {
+		return sdk.WrapError(err, "unable to load workflows with artifact retention")
+	}
+
+	for _, r := range res {
+		wf := sdk.Workflow{ID: r.ID, Name: r.Name, ArtifactRetention: &sdk.WorkflowArtifactRetention{}}
+		if err := gorpmapping.JSONNullString(r.ArtifactRetention, wf.ArtifactRetention); err != nil {
+			log.Error(ctx, "artifactsRetention> unable to read artifact retention of workflow %d: %v", r.ID, err)
+			continue
+		}
+
+		report, err := ArtifactRetentionReport(db, r.ProjectKey, wf)
+		if err != nil {
+			log.Error(ctx, "artifactsRetention> unable to compute artifact retention of workflow %s/%s: %v", r.ProjectKey, r.Name, err)
+			continue
+		}
+
+		n, size := report.DeletedSize()
+		if n == 0 {
+			continue
+		}
+		if report.Retention.DryRun {
+			log.Info(ctx, "artifactsRetention> dry run for workflow %s/%s: %d artifacts (%d bytes) would be deleted", r.ProjectKey, r.Name, n, size)
+			continue
+		}
+
+		log.Info(ctx, "artifactsRetention> deleting %d artifacts (%d bytes) of workflow %s/%s", n, size, r.ProjectKey, r.Name)
+		for _, it := range report.Items {
+			if it.Keep {
+				continue
+			}
+			if err := deleteArtifact(ctx, db, sharedStorage, r.ProjectKey, r.ID, it.ArtifactID); err != nil {
+				log.Error(ctx, "artifactsRetention> unable to delete artifact %s of workflow %s/%s run %d: %v", it.Name, r.ProjectKey, r.Name, it.RunNumber, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func deleteArtifact(ctx context.Context, db gorp.SqlExecutor, sharedStorage objectstore.Driver, projectKey string, workflowID, artifactID int64) error {
+	art, err := workflow.LoadArtifactByIDs(db, workflowID, artifactID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load artifact %d", artifactID)
+	}
+
+	integrationName, err := artifactIntegrationName(db, *art)
+	if err != nil {
+		return err
+	}
+	storageDriver, err := objectstore.GetDriver(ctx, db, sharedStorage, projectKey, integrationName)
+	if err != nil {
+		return err
+	}
+
+	if err := storageDriver.Delete(ctx, art); err != nil {
+		return sdk.WrapError(err, "cannot delete artifact %s from storage", art.Name)
+	}
+	// artifacts uploaded before provenance was introduced don't have one
+	if err := workflow.DeleteArtifactProvenance(ctx, storageDriver, art); err != nil {
+		log.Debug("deleteArtifact> cannot delete provenance of %s: %v", art.Name, err)
+	}
+
+	return workflow.DeleteArtifact(db, art.ID)
+}
+
+// artifactIntegrationName returns the name of the storage integration of the artifact.
+func artifactIntegrationName(db gorp.SqlExecutor, art sdk.WorkflowNodeRunArtifact) (string, error) {
+	if art.ProjectIntegrationID == nil || *art.ProjectIntegrationID == 0 {
+		return sdk.DefaultStorageIntegrationName, nil
+	}
+	projectIntegration, err := integration.LoadProjectIntegrationByID(db, *art.ProjectIntegrationID, false)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot load project integration %d", *art.ProjectIntegrationID)
+	}
+	return projectIntegration.Name, nil
+}
@@ -0,0 +1,62 @@
+package purge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_applyArtifactRetention(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+
+	items := []sdk.WorkflowArtifactRetentionItem{
+		{Name: "in-progress", RunNumber: 10, RunStatus: sdk.StatusBuilding, Branch: "master", Created: old},
+		{Name: "master-9", RunNumber: 9, RunStatus: sdk.StatusSuccess, Branch: "master", Created: old},
+		{Name: "master-9-bis", RunNumber: 9, RunStatus: sdk.StatusSuccess, Branch: "master", Created: old},
+		{Name: "feat-8", RunNumber: 8, RunStatus: sdk.StatusFail, Branch: "feat", Created: old},
+		{Name: "master-7", RunNumber: 7, RunStatus: sdk.StatusSuccess, Branch: "master", Created: now.Add(-time.Hour)},
+		{Name: "master-6", RunNumber: 6, RunStatus: sdk.StatusSuccess, Branch: "master", Created: old},
+		{Name: "release-5", RunNumber: 5, RunStatus: sdk.StatusSuccess, GitTag: "v1.2.0", Created: old},
+		{Name: "prod-4", RunNumber: 4, RunStatus: sdk.StatusSuccess, Branch: "master", Created: old,
+			RunTags: []sdk.WorkflowRunTag{{Tag: "environment", Value: "production"}}},
+		{Name: "feat-3", RunNumber: 3, RunStatus: sdk.StatusSuccess, Branch: "feat", Created: old},
+	}
+
+	r := sdk.WorkflowArtifactRetention{
+		KeepLastPerBranch: 2,
+		KeepDays:          7,
+		KeepTags:          []string{"environment=prod*"},
+		KeepReleases:      true,
+	}
+	assert.NoError(t, r.IsValid())
+
+	applyArtifactRetention(r, items, now)
+
+	kept := map[string]string{}
+	var deleted []string
+	for _, it := range items {
+		if it.Keep {
+			kept[it.Name] = it.Reason
+		} else {
+			deleted = append(deleted, it.Name)
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"in-progress":  "run in progress",
+		"master-9":     "last 2 runs of branch master",
+		"master-9-bis": "last 2 runs of branch master",
+		"feat-8":       "last 2 runs of branch feat",
+		"master-7":     "uploaded less than 7 days ago",
+		"release-5":    "release v1.2.0",
+		"prod-4":       "run tag environment=prod*",
+		"feat-3":       "last 2 runs of branch feat",
+	}, kept)
+	assert.Equal(t, []string{"master-6"}, deleted)
+
+	assert.Error(t, sdk.WorkflowArtifactRetention{}.IsValid(), "a policy without rule deletes everything")
+	assert.Error(t, sdk.WorkflowArtifactRetention{KeepDays: 1, KeepTags: []string{"environment"}}.IsValid())
+}
@@ -9,7 +9,6 @@ import (
 	"go.opencensus.io/stats"
 
 	"github.com/ovh/cds/engine/api/cache"
-	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/project"
@@ -36,6 +35,16 @@ func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMa
 				log.Warning(ctx, "purge> Error on deleteWorkflowRunsHistory : %v", err)
 			}
 
+			log.Debug("purge> Applying run retention rules...")
+			if err := runsRetention(ctx, DBFunc(), store, workflowRunsMarkToDelete); err != nil {
+				log.Warning(ctx, "purge> Error on runsRetention : %v", err)
+			}
+
+			log.Debug("purge> Applying artifact retention policies...")
+			if err := artifactsRetention(ctx, DBFunc(), sharedStorage); err != nil {
+				log.Warning(ctx, "purge> Error on artifactsRetention : %v", err)
+			}
+
 			log.Debug("purge> Deleting all workflow marked to delete....")
 			if err := workflows(ctx, DBFunc(), store, workflowRunsMarkToDelete); err != nil {
 				log.Warning(ctx, "purge> Error on workflows : %v", err)
@@ -172,7 +181,7 @@ func deleteWorkflowRunsHistory(ctx context.Context, db gorp.SqlExecutor, store c
 	return nil
 }
 
-// DeleteArtifacts removes artifacts from storage
+// DeleteArtifacts removes artifacts and logs from storage
 func DeleteArtifacts(ctx context.Context, db gorp.SqlExecutor, store cache.Store, sharedStorage objectstore.Driver, workflowRunID int64) error {
 	wr, err := workflow.LoadRunByID(db, workflowRunID, workflow.LoadRunOptions{WithArtifacts: true, DisableDetailledNodeRun: false, WithDeleted: true})
 	if err != nil {
@@ -193,17 +202,15 @@ func DeleteArtifacts(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 	driversContainers := []driversContainersT{}
 	for _, wnrs := range wr.WorkflowNodeRuns {
 		for _, wnr := range wnrs {
+			if err := workflow.DeleteNodeRunLogs(ctx, db, wnr.ID); err != nil {
+				log.Error(ctx, "error while deleting logs prj:%v wnr:%v err:%v", proj.Key, wnr.ID, err)
+			}
+
 			for _, art := range wnr.Artifacts {
-				var integrationName string
-				if art.ProjectIntegrationID != nil && *art.ProjectIntegrationID > 0 {
-					projectIntegration, err := integration.LoadProjectIntegrationByID(db, *art.ProjectIntegrationID, false)
-					if err != nil {
-						log.Error(ctx, "Cannot load LoadProjectIntegrationByID %s/%d", proj.Key, *art.ProjectIntegrationID)
-						continue
-					}
-					integrationName = projectIntegration.Name
-				} else {
-					integrationName = sdk.DefaultStorageIntegrationName
+				integrationName, err := artifactIntegrationName(db, art)
+				if err != nil {
+					log.Error(ctx, "Cannot load LoadProjectIntegrationByID %s/%d", proj.Key, *art.ProjectIntegrationID)
+					continue
 				}
 
 				var found bool
@@ -234,6 +241,10 @@ func DeleteArtifacts(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 					log.Error(ctx, "error while deleting container prj:%v wnr:%v name:%v err:%v", proj.Key, wnr.ID, art.GetPath(), err)
 					continue
 				}
+				// artifacts uploaded before provenance was introduced don't have one
+				if err := workflow.DeleteArtifactProvenance(ctx, storageDriver, &art); err != nil {
+					log.Debug("DeleteArtifacts> cannot delete provenance of %s: %v", art.Name, err)
+				}
 			}
 		}
 	}
@@ -0,0 +1,196 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"go.opencensus.io/stats"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// applyRunRetention sets which runs are kept by the run retention rules, items are ordered latest run first.
+// existingBranches is nil if the branches of the repository are unknown, then runs of deleted branches are not detected.
+func applyRunRetention(r sdk.WorkflowRunRetention, historyLength int64, defaultBranch string, existingBranches map[string]bool, items []sdk.WorkflowRunRetentionItem, now time.Time) {
+	runsByBranch := make(map[string]int64)
+	for i := range items {
+		it := &items[i]
+		runsByBranch[it.Branch]++
+		rank := runsByBranch[it.Branch]
+
+		it.Keep = true
+		if !sdk.StatusIsTerminated(it.Status) {
+			it.Reason = "run in progress"
+			continue
+		}
+		if env, ok := r.KeptEnvironment(it.Environments); ok {
+			it.Reason = fmt.Sprintf("deployed to %s", env)
+			continue
+		}
+		if r.DeletedBranchDays > 0 && existingBranches != nil && it.Branch != "" && !existingBranches[it.Branch] &&
+			now.Sub(it.LastModified) > time.Duration(r.DeletedBranchDays)*24*time.Hour {
+			it.Keep = false
+			it.Reason = fmt.Sprintf("branch %s deleted", it.Branch)
+			continue
+		}
+
+		var limit int64
+		var rule string
+		if pattern, keep, ok := r.BranchRule(it.Branch); r.DefaultBranch > 0 && defaultBranch != "" && it.Branch == defaultBranch {
+			limit, rule = r.DefaultBranch, "of default branch "+it.Branch
+		} else if ok {
+			limit, rule = keep, fmt.Sprintf("of branch %s (%s)", it.Branch, pattern)
+		} else if it.Branch != "" {
+			limit, rule = historyLength, "of branch "+it.Branch
+		} else {
+			limit, rule = historyLength, "without branch"
+		}
+
+		// without history length, the runs of the branches that don't match any rule are never purged
+		if limit <= 0 {
+			it.Reason = "no retention rule"
+			continue
+		}
+		if rank <= limit {
+			it.Reason = fmt.Sprintf("last %d runs %s", limit, rule)
+			continue
+		}
+		it.Keep = false
+		it.Reason = fmt.Sprintf("older than the last %d runs %s", limit, rule)
+	}
+}
+
+// repositoryBranches returns the default branch and the branches of the repository of the workflow root application.
+// It returns a nil map if the workflow is not linked to a repository.
+func repositoryBranches(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, wf sdk.Workflow) (string, map[string]bool, error) {
+	if wf.WorkflowData.Node.Context == nil || wf.WorkflowData.Node.Context.ApplicationID == 0 {
+		return "", nil, nil
+	}
+	app, has := wf.Applications[wf.WorkflowData.Node.Context.ApplicationID]
+	if !has || app.VCSServer == "" || app.RepositoryFullname == "" {
+		return "", nil, nil
+	}
+
+	vcsServer := repositoriesmanager.GetProjectVCSServer(proj, app.VCSServer)
+	if vcsServer == nil {
+		return "", nil, sdk.NewErrorFrom(sdk.ErrNoReposManagerClientAuth, "cannot get repositories manager %s", app.VCSServer)
+	}
+	client, err := repositoriesmanager.AuthorizedClient(ctx, db, store, proj.Key, vcsServer)
+	if err != nil {
+		return "", nil, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrNoReposManagerClientAuth, "cannot get client for %s %s", proj.Key, app.VCSServer))
+	}
+	branches, err := client.Branches(ctx, app.RepositoryFullname)
+	if err != nil {
+		return "", nil, sdk.WrapError(err, "cannot list branches for %s/%s", app.VCSServer, app.RepositoryFullname)
+	}
+
+	var defaultBranch string
+	existing := make(map[string]bool, len(branches))
+	for _, b := range branches {
+		existing[b.DisplayID] = true
+		if b.Default {
+			defaultBranch = b.DisplayID
+		}
+	}
+	return defaultBranch, existing, nil
+}
+
+// RunRetentionReport returns the runs of the workflow that are kept or purged by its run retention.
+// The workflow should be loaded with its applications if the rules need the branches of the repository.
+func RunRetentionReport(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, wf sdk.Workflow) (*sdk.WorkflowRunRetentionReport, error) {
+	if wf.RunRetention == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no run retention on workflow %s", wf.Name)
+	}
+
+	var defaultBranch string
+	var existingBranches map[string]bool
+	if wf.RunRetention.NeedsRepository() {
+		var err error
+		// without the branches, runs of the default branch or of deleted branches could be wrongly purged
+		defaultBranch, existingBranches, err = repositoryBranches(ctx, db, store, proj, wf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := workflow.LoadRunsForRetention(db, wf.ID)
+	if err != nil {
+		return nil, err
+	}
+	applyRunRetention(*wf.RunRetention, wf.HistoryLength, defaultBranch, existingBranches, items, time.Now())
+
+	return &sdk.WorkflowRunRetentionReport{
+		ProjectKey:    proj.Key,
+		WorkflowName:  wf.Name,
+		Retention:     *wf.RunRetention,
+		HistoryLength: wf.HistoryLength,
+		DefaultBranch: defaultBranch,
+		Items:         items,
+	}, nil
+}
+
+// runsRetention marks to delete the runs purged by the run retention of the workflows
+func runsRetention(ctx context.Context, db gorp.SqlExecutor, store cache.Store, workflowRunsMarkToDelete *stats.Int64Measure) error {
+	var res []struct {
+		ID        int64 `db:"id"`
+		ProjectID int64 `db:"project_id"`
+	}
+	query := `
+		SELECT id, project_id
+		FROM workflow
+		WHERE run_retention IS NOT NULL
+		AND to_delete = false
+		ORDER BY id ASC`
+	if _, err := db.Select(&res, query); err != nil {
+		return sdk.WrapError(err, "unable to load workflows with run retention")
+	}
+
+	projects := map[int64]sdk.Project{}
+	for _, r := range res {
+		proj, has := projects[r.ProjectID]
+		if !has {
+			p, err := project.LoadByID(db, store, r.ProjectID)
+			if err != nil {
+				log.Error(ctx, "runsRetention> unable to load project %d: %v", r.ProjectID, err)
+				continue
+			}
+			projects[r.ProjectID] = *p
+			proj = *p
+		}
+
+		wf, err := workflow.LoadByID(ctx, db, store, proj, r.ID, workflow.LoadOptions{})
+		if err != nil {
+			log.Error(ctx, "runsRetention> unable to load workflow %d: %v", r.ID, err)
+			continue
+		}
+
+		report, err := RunRetentionReport(ctx, db, store, proj, *wf)
+		if err != nil {
+			log.Error(ctx, "runsRetention> unable to compute run retention of workflow %s/%s: %v", proj.Key, wf.Name, err)
+			continue
+		}
+
+		ids := report.PurgedRunIDs()
+		if len(ids) == 0 {
+			continue
+		}
+		log.Info(ctx, "runsRetention> marking %d runs to delete for workflow %s/%s", len(ids), proj.Key, wf.Name)
+		if err := workflow.MarkWorkflowRunsAsDelete(db, ids); err != nil {
+			log.Error(ctx, "runsRetention> unable to mark runs to delete for workflow %s/%s: %v", proj.Key, wf.Name, err)
+			continue
+		}
+		if workflowRunsMarkToDelete != nil {
+			observability.Record(ctx, workflowRunsMarkToDelete, int64(len(ids)))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package purge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_applyRunRetention(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+
+	items := []sdk.WorkflowRunRetentionItem{
+		{Number: 12, Status: sdk.StatusBuilding, Branch: "feature/b", LastModified: now},
+		{Number: 11, Status: sdk.StatusSuccess, Branch: "master", LastModified: now},
+		{Number: 10, Status: sdk.StatusSuccess, Branch: "feature/b", LastModified: now},
+		{Number: 9, Status: sdk.StatusFail, Branch: "feature/b", LastModified: now},
+		{Number: 8, Status: sdk.StatusSuccess, Branch: "master", LastModified: old},
+		{Number: 7, Status: sdk.StatusSuccess, Branch: "master", LastModified: old, Environments: []string{"staging", "production"}},
+		{Number: 6, Status: sdk.StatusSuccess, Branch: "master", LastModified: old},
+		{Number: 5, Status: sdk.StatusSuccess, Branch: "feature/a", LastModified: old},
+		{Number: 4, Status: sdk.StatusSuccess, Branch: "feature/c", LastModified: now},
+		{Number: 3, Status: sdk.StatusSuccess, Branch: "dev", LastModified: old},
+		{Number: 2, Status: sdk.StatusSuccess, Branch: "dev", LastModified: old},
+		{Number: 1, Status: sdk.StatusSuccess, Branch: "", LastModified: old},
+	}
+
+	r := sdk.WorkflowRunRetention{
+		DefaultBranch:     3,
+		Branches:          []sdk.WorkflowRunRetentionBranch{{Pattern: "feature/*", Keep: 2}},
+		DeletedBranchDays: 7,
+		KeepEnvironments:  []string{"production"},
+	}
+	assert.NoError(t, r.IsValid())
+
+	branches := map[string]bool{"master": true, "feature/b": true, "dev": true}
+	applyRunRetention(r, 1, "master", branches, items, now)
+
+	kept := map[int64]string{}
+	var purged []int64
+	for _, it := range items {
+		if it.Keep {
+			kept[it.Number] = it.Reason
+		} else {
+			purged = append(purged, it.Number)
+		}
+	}
+	assert.Equal(t, map[int64]string{
+		12: "run in progress",
+		11: "last 3 runs of default branch master",
+		10: "last 2 runs of branch feature/b (feature/*)",
+		8:  "last 3 runs of default branch master",
+		7:  "deployed to production",
+		4:  "last 2 runs of branch feature/c (feature/*)",
+		3:  "last 1 runs of branch dev",
+		1:  "last 1 runs without branch",
+	}, kept)
+	assert.Equal(t, []int64{9, 6, 5, 2}, purged)
+
+	// without the branches of the repository, runs of deleted branches are not detected
+	applyRunRetention(r, 1, "", nil, items, now)
+	assert.True(t, items[7].Keep, "feature/a is kept when branches are unknown")
+
+	assert.Error(t, sdk.WorkflowRunRetention{Branches: []sdk.WorkflowRunRetentionBranch{{Pattern: "feature/*"}}}.IsValid())
+	assert.Error(t, sdk.WorkflowRunRetention{Branches: []sdk.WorkflowRunRetentionBranch{{Pattern: "[", Keep: 1}}}.IsValid())
+	assert.Error(t, sdk.WorkflowRunRetention{DeletedBranchDays: -1}.IsValid())
+}
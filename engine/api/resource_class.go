@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/resourceclass"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getResourceClassesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		cs, err := resourceclass.LoadAll(ctx, api.mustDB())
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, cs, http.StatusOK)
+	}
+}
+
+func (api *API) getResourceClassHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["name"]
+
+		c, err := resourceclass.LoadByName(ctx, api.mustDB(), name)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no resource class %s", name)
+		}
+		return service.WriteJSON(w, c, http.StatusOK)
+	}
+}
+
+// postResourceClassHandler creates or updates the resource class for a name.
+func (api *API) postResourceClassHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var c sdk.ResourceClass
+		if err := service.UnmarshalBody(r, &c); err != nil {
+			return err
+		}
+		if err := c.IsValid(); err != nil {
+			return err
+		}
+
+		old, err := resourceclass.LoadByName(ctx, api.mustDB(), c.Name)
+		if err != nil {
+			return err
+		}
+		if old != nil {
+			old.Description = c.Description
+			old.CPU = c.CPU
+			old.Memory = c.Memory
+			if err := resourceclass.Update(api.mustDB(), old); err != nil {
+				return err
+			}
+			return service.WriteJSON(w, old, http.StatusOK)
+		}
+
+		if err := resourceclass.Insert(api.mustDB(), &c); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, c, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteResourceClassHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["name"]
+
+		c, err := resourceclass.LoadByName(ctx, api.mustDB(), name)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no resource class %s", name)
+		}
+
+		if err := resourceclass.Delete(api.mustDB(), *c); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
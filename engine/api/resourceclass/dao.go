@@ -0,0 +1,84 @@
+package resourceclass
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func getAll(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.ResourceClass, error) {
+	res := []dbResourceClass{}
+	if err := gorpmapping.GetAll(ctx, db, q, &res); err != nil {
+		return nil, sdk.WrapError(err, "cannot get resource classes")
+	}
+
+	cs := make([]sdk.ResourceClass, len(res))
+	for i := range res {
+		cs[i] = sdk.ResourceClass(res[i])
+	}
+	return cs, nil
+}
+
+// LoadAll returns all the resource classes.
+func LoadAll(ctx context.Context, db gorp.SqlExecutor) ([]sdk.ResourceClass, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM resource_class
+    ORDER BY name
+  `)
+	return getAll(ctx, db, query)
+}
+
+// LoadByName returns the resource class for given name, or nil if not found.
+func LoadByName(ctx context.Context, db gorp.SqlExecutor, name string) (*sdk.ResourceClass, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM resource_class
+    WHERE name = $1
+  `).Args(name)
+	var c dbResourceClass
+	found, err := gorpmapping.Get(ctx, db, query, &c)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get resource class")
+	}
+	if !found {
+		return nil, nil
+	}
+	res := sdk.ResourceClass(c)
+	return &res, nil
+}
+
+// Insert a resource class in database.
+func Insert(db gorp.SqlExecutor, c *sdk.ResourceClass) error {
+	c.Created = time.Now()
+	c.LastModified = c.Created
+	dbc := dbResourceClass(*c)
+	if err := gorpmapping.Insert(db, &dbc); err != nil {
+		return sdk.WrapError(err, "cannot insert resource class")
+	}
+	*c = sdk.ResourceClass(dbc)
+	return nil
+}
+
+// Update a resource class in database.
+func Update(db gorp.SqlExecutor, c *sdk.ResourceClass) error {
+	c.LastModified = time.Now()
+	dbc := dbResourceClass(*c)
+	if err := gorpmapping.Update(db, &dbc); err != nil {
+		return sdk.WrapError(err, "cannot update resource class")
+	}
+	return nil
+}
+
+// Delete a resource class from database.
+func Delete(db gorp.SqlExecutor, c sdk.ResourceClass) error {
+	dbc := dbResourceClass(c)
+	if err := gorpmapping.Delete(db, &dbc); err != nil {
+		return sdk.WrapError(err, "cannot delete resource class")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package resourceclass
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbResourceClass sdk.ResourceClass
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbResourceClass{}, "resource_class", true, "id"))
+}
@@ -0,0 +1,200 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// Column is a database column that contains data encrypted with Encrypt.
+type Column struct {
+	Table string
+	// Key is the column used to browse the table, it can be shared by several rows
+	Key  string
+	Name string
+	// JSON is true if the column contains a json document with values encrypted with EncryptValue
+	JSON bool
+}
+
+var columns []Column
+
+// RegisterColumn registers a column that contains encrypted data to allow its rotation.
+func RegisterColumn(c Column) {
+	columns = append(columns, c)
+}
+
+// ListColumnTables returns the names of the tables that contain encrypted columns.
+func ListColumnTables() []string {
+	var tables []string
+	for _, c := range columns {
+		if !sdk.IsInArray(c.Table, tables) {
+			tables = append(tables, c.Table)
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// GetColumnTableKey returns the key column of a table that contains encrypted columns.
+func GetColumnTableKey(table string) (string, bool) {
+	for _, c := range columns {
+		if c.Table == table {
+			return c.Key, true
+		}
+	}
+	return "", false
+}
+
+// IsEncryptedWithLatestKey returns true if data is encrypted with the latest rolling database encryption key.
+func IsEncryptedWithLatestKey(data []byte) bool {
+	if !strings.HasPrefix(string(data), versionedPrefix) {
+		return false
+	}
+	k := gorpmapping.EncryptionKey()
+	if k == nil {
+		return false
+	}
+	_, err := gorpmapping.LatestKey(k).Decrypt(data[len(versionedPrefix):])
+	return err == nil
+}
+
+// Roll encrypts again with the latest key data that was encrypted with an older one.
+// Returns false if data is not encrypted or already encrypted with the latest key.
+func Roll(data []byte) ([]byte, bool, error) {
+	if !strings.HasPrefix(string(data), prefix) && !strings.HasPrefix(string(data), versionedPrefix) {
+		return data, false, nil
+	}
+	if IsEncryptedWithLatestKey(data) {
+		return data, false, nil
+	}
+	clear, err := Decrypt(data)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err = Encrypt(clear)
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// rollValue is the same as Roll for a value encrypted with EncryptValue.
+func rollValue(v string) (string, bool, error) {
+	btes, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return v, false, nil
+	}
+	btes, rolled, err := Roll(btes)
+	if err != nil || !rolled {
+		return v, false, err
+	}
+	return base64.StdEncoding.EncodeToString(btes), true, nil
+}
+
+// rollJSON rolls all the values encrypted with EncryptValue in a json document.
+func rollJSON(data []byte) ([]byte, bool, error) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false, sdk.WithStack(fmt.Errorf("invalid json document: %v", err))
+	}
+
+	var rolled bool
+	var walk func(v interface{}) (interface{}, error)
+	walk = func(v interface{}) (interface{}, error) {
+		switch x := v.(type) {
+		case string:
+			s, r, err := rollValue(x)
+			rolled = rolled || r
+			return s, err
+		case map[string]interface{}:
+			for k := range x {
+				var err error
+				if x[k], err = walk(x[k]); err != nil {
+					return nil, err
+				}
+			}
+		case []interface{}:
+			for i := range x {
+				var err error
+				if x[i], err = walk(x[i]); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return v, nil
+	}
+
+	doc, err := walk(doc)
+	if err != nil || !rolled {
+		return data, false, err
+	}
+	btes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, sdk.WithStack(err)
+	}
+	return btes, true, nil
+}
+
+// RollColumns encrypts again with the latest key all the encrypted columns of the rows of a table for given key.
+// Rows are locked until the end of the transaction.
+func RollColumns(db gorp.SqlExecutor, table, key string) error {
+	for _, c := range columns {
+		if c.Table != table {
+			continue
+		}
+
+		type row struct {
+			CTID  string `db:"ctid"`
+			Value []byte `db:"value"`
+		}
+		var rows []row
+		// json documents are read as text, other columns contain the encrypted bytes
+		column := c.Name
+		if c.JSON {
+			column += "::text"
+		}
+		query := fmt.Sprintf(`select ctid::text as ctid, %s as value from "%s" where %s::text = $1 and %s is not null for update`, column, c.Table, c.Key, c.Name)
+		if _, err := db.Select(&rows, query, key); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		for _, r := range rows {
+			var value interface{}
+			if c.JSON {
+				btes, rolled, err := rollJSON(r.Value)
+				if err != nil {
+					return sdk.WrapError(err, "cannot roll %s.%s for %s", c.Table, c.Name, key)
+				}
+				if !rolled {
+					continue
+				}
+				value = string(btes)
+			} else {
+				btes, rolled, err := Roll(r.Value)
+				if err != nil {
+					return sdk.WrapError(err, "cannot roll %s.%s for %s", c.Table, c.Name, key)
+				}
+				if !rolled {
+					continue
+				}
+				value = btes
+			}
+
+			query := fmt.Sprintf(`update "%s" set %s = $1 where ctid = $2::tid`, c.Table, c.Name)
+			if _, err := db.Exec(query, value, r.CTID); err != nil {
+				return sdk.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
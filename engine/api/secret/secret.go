@@ -5,14 +5,13 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/hmac"
-	"crypto/rand"
 	"crypto/sha256"
 	"database/sql"
 	"encoding/base64"
 	"fmt"
-	"io"
 	"strings"
 
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -27,48 +26,51 @@ const (
 var (
 	key    []byte
 	prefix = "3DICC3It"
+	// versionedPrefix is set on data encrypted with the rolling database encryption key
+	versionedPrefix = "CDSv2key"
 )
 
 // Init secrets: cipherKey
-// cipherKey is set from viper configuration
+// cipherKey is set from viper configuration, it's only used to decrypt data encrypted before the rolling keys
 func Init(cipherKey string) {
 	key = []byte(cipherKey)
 }
 
-// Encrypt data using aes+hmac algorithm
-// Init() must be called before any encryption
+// Encrypt data with the latest rolling database encryption key
+// gorpmapping.ConfigureKeys() must be called before any encryption
 func Encrypt(data []byte) ([]byte, error) {
-	// Check key is ready
-	if key == nil {
-		log.Error(context.TODO(), "Missing key, init failed?")
-		return nil, sdk.ErrSecretKeyFetchFailed
-	}
-	// generate nonce
-	nonce := make([]byte, nonceSize)
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return nil, err
+	k := gorpmapping.EncryptionKey()
+	if k == nil {
+		log.Error(context.TODO(), "Missing database encryption key, init failed?")
+		return nil, sdk.WithStack(sdk.ErrSecretKeyFetchFailed)
 	}
-	// init aes cipher
-	c, err := aes.NewCipher(key)
+	ct, err := k.Encrypt(data)
 	if err != nil {
-		return nil, err
+		return nil, sdk.WithStack(fmt.Errorf("unable to encrypt secret: %v", err))
 	}
-	ctr := cipher.NewCTR(c, nonce)
-	// encrypt data
-	ct := make([]byte, len(data))
-	ctr.XORKeyStream(ct, data)
-	// add hmac
-	h := hmac.New(sha256.New, key[ckeySize:])
-	ct = append(nonce, ct...)
-	h.Write(ct)
-	ct = h.Sum(ct)
+	return append([]byte(versionedPrefix), ct...), nil
+}
 
-	return append([]byte(prefix), ct...), nil
+// Decrypt data encrypted with one of the rolling database encryption keys or with the legacy aes+hmac key
+func Decrypt(data []byte) ([]byte, error) {
+	if strings.HasPrefix(string(data), versionedPrefix) {
+		k := gorpmapping.EncryptionKey()
+		if k == nil {
+			log.Error(context.TODO(), "Missing database encryption key, init failed?")
+			return nil, sdk.WithStack(sdk.ErrSecretKeyFetchFailed)
+		}
+		out, err := k.Decrypt(data[len(versionedPrefix):])
+		if err != nil {
+			return nil, sdk.WithStack(fmt.Errorf("unable to decrypt secret: %v", err))
+		}
+		return out, nil
+	}
+	return decryptLegacy(data)
 }
 
-// Decrypt data using aes+hmac algorithm
+// decryptLegacy decrypts data using aes+hmac algorithm
 // Init() must be called before any decryption
-func Decrypt(data []byte) ([]byte, error) {
+func decryptLegacy(data []byte) ([]byte, error) {
 	if !strings.HasPrefix(string(data), prefix) {
 		return data, nil
 	}
@@ -2,30 +2,88 @@ package secret
 
 import (
 	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ovh/symmecrypt/keyloader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/sdk"
 )
 
+func TestMain(m *testing.M) {
+	sigKeys := []keyloader.KeyConfig{{
+		Identifier: gorpmapping.KeySignIdentifier,
+		Cipher:     "hmac",
+		Timestamp:  time.Now().Unix(),
+		Key:        "8f17c90d5306028bdf6ef66cc6da387aca9dd57a11f44e5e2752228398b7d165",
+	}}
+	encryptKeys := []keyloader.KeyConfig{{
+		Identifier: gorpmapping.KeyEcnryptionIdentifier,
+		Cipher:     "xchacha20-poly1305",
+		Timestamp:  time.Now().Unix(),
+		Key:        "fd27b8872bdefeb207bbefc1a82e94039b85d3ec68d891e22a5dcaa81542fc6b",
+	}}
+	if err := gorpmapping.ConfigureKeys(&sigKeys, &encryptKeys); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// encryptLegacy encrypts data like secrets were encrypted before the rolling keys
+func encryptLegacy(data []byte) []byte {
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		panic(err)
+	}
+	c, err := aes.NewCipher(key[:ckeySize])
+	if err != nil {
+		panic(err)
+	}
+	ctr := cipher.NewCTR(c, nonce)
+	ct := make([]byte, len(data))
+	ctr.XORKeyStream(ct, data)
+	h := hmac.New(sha256.New, key[ckeySize:])
+	ct = append(nonce, ct...)
+	h.Write(ct)
+	ct = h.Sum(ct)
+	return append([]byte(prefix), ct...)
+}
+
 func TestInvalidKey(t *testing.T) {
-	key = []byte("78eKVxLm6gwoH9LAQ15ZD5AOABo1Xb239fj209uf23hwefw34")
+	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
 	data := []byte("Hello world !")
+	ct := encryptLegacy(data)
 
-	_, err := Encrypt(data)
+	key = []byte("78eKVxLm6gwoH9LAQ15ZD5AOABo1Xb239fj209uf23hwefw34")
+	_, err := Decrypt(ct)
 	if err == nil {
-		t.Fatalf("Encrypt should have failed: %s", err)
+		t.Fatalf("Decrypt should have failed: %s", err)
 	}
 }
 
 func TestEncrypt(t *testing.T) {
-	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
 	data := []byte("Hello world !")
 
 	ct, err := Encrypt(data)
 	if err != nil {
 		t.Fatalf("Encrypt failed: %s", err)
 	}
+	if !strings.HasPrefix(string(ct), versionedPrefix) {
+		t.Fatalf("Encrypted data should start with %s", versionedPrefix)
+	}
 
 	clear, err := Decrypt(ct)
 	if err != nil {
@@ -37,8 +95,21 @@ func TestEncrypt(t *testing.T) {
 	}
 }
 
-func TestEncryptEmpty(t *testing.T) {
+func TestDecryptLegacy(t *testing.T) {
 	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+	data := []byte("Hello world !")
+
+	clear, err := Decrypt(encryptLegacy(data))
+	if err != nil {
+		t.Fatalf("Decrypt failed: %s", err)
+	}
+
+	if bytes.Compare(clear, data) != 0 {
+		t.Fatalf("Fail: Expected '%s', got '%s'", data, clear)
+	}
+}
+
+func TestEncryptEmpty(t *testing.T) {
 	data := []byte("")
 
 	ct, err := Encrypt(data)
@@ -99,3 +170,35 @@ func TestDecryptS(t *testing.T) {
 	}
 
 }
+
+func TestRoll(t *testing.T) {
+	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+	legacy := encryptLegacy([]byte("my-password"))
+
+	rolled, ok, err := Roll(legacy)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, IsEncryptedWithLatestKey(rolled))
+	clear, err := Decrypt(rolled)
+	require.NoError(t, err)
+	assert.Equal(t, "my-password", string(clear))
+
+	_, ok, err = Roll(rolled)
+	require.NoError(t, err)
+	assert.False(t, ok, "data encrypted with the latest key should not be rolled")
+
+	_, ok, err = Roll([]byte("not encrypted"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	doc := `{"password":{"type":"password","value":"` + base64.StdEncoding.EncodeToString(legacy) + `"},"user":{"type":"string","value":"fry"},"port":8080}`
+	btes, ok, err := rollJSON([]byte(doc))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Contains(t, string(btes), `"user":{"type":"string","value":"fry"}`)
+	assert.Contains(t, string(btes), `"port":8080`)
+
+	_, ok, err = rollJSON(btes)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
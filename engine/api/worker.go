@@ -243,10 +243,11 @@ func DisableWorker(ctx context.Context, db *gorp.DbMap, id string) error {
 		// Worker is awol while building !
 		// We need to restart this action
 		wNodeJob, errL := workflow.LoadNodeJobRun(ctx, tx, nil, jobID.Int64)
-		if errL == nil && wNodeJob.Retry < 3 {
-			if err := workflow.RestartWorkflowNodeJob(context.TODO(), db, *wNodeJob); err != nil {
+		if errL == nil {
+			restarted, err := workflow.RestartLostNodeJobRun(context.TODO(), db, *wNodeJob)
+			if err != nil {
 				log.Warning(ctx, "DisableWorker[%s]> Cannot restart workflow node run: %v", name, err)
-			} else {
+			} else if restarted {
 				log.Info(ctx, "DisableWorker[%s]> WorkflowNodeRun %d restarted after crash", name, jobID.Int64)
 			}
 		}
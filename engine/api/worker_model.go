@@ -44,6 +44,9 @@ func (api *API) postWorkerModelHandler() service.Handler {
 			if !data.Restricted && data.PatternName == "" {
 				return sdk.NewErrorFrom(sdk.ErrWorkerModelNoPattern, "missing model pattern name")
 			}
+			if data.ModelDocker.PodTemplate != nil {
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "only an administrator can set the pod template of a worker model")
+			}
 		}
 
 		tx, err := api.mustDB().Begin()
@@ -88,6 +88,9 @@ func (api *API) postWorkerModelImportHandler() service.Handler {
 				if !data.Restricted && data.PatternName == "" {
 					return sdk.NewErrorFrom(sdk.ErrWorkerModelNoPattern, "missing model pattern name")
 				}
+				if data.ModelDocker.PodTemplate != nil {
+					return sdk.NewErrorFrom(sdk.ErrForbidden, "only an administrator can set the pod template of a worker model")
+				}
 			}
 
 			// validate worker model type fields
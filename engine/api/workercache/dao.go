@@ -0,0 +1,112 @@
+package workercache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func getAll(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.WorkerCache, error) {
+	res := []dbWorkerCache{}
+	if err := gorpmapping.GetAll(ctx, db, q, &res); err != nil {
+		return nil, sdk.WrapError(err, "cannot get worker caches")
+	}
+
+	cs := make([]sdk.WorkerCache, len(res))
+	for i := range res {
+		cs[i] = sdk.WorkerCache(res[i])
+	}
+	return cs, nil
+}
+
+func get(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.WorkerCache, error) {
+	var c dbWorkerCache
+	found, err := gorpmapping.Get(ctx, db, q, &c)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get worker cache")
+	}
+	if !found {
+		return nil, nil
+	}
+	res := sdk.WorkerCache(c)
+	return &res, nil
+}
+
+// LoadAllByProjectIDAndIntegration returns all worker caches of a project stored in given integration,
+// the most recently used first.
+func LoadAllByProjectIDAndIntegration(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName string) ([]sdk.WorkerCache, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM worker_cache
+    WHERE project_id = $1 AND integration_name = $2
+    ORDER BY last_access DESC, id DESC
+  `).Args(projectID, integrationName)
+	return getAll(ctx, db, query)
+}
+
+// LoadByTag returns the worker cache for given tag, nil if it does not exist.
+func LoadByTag(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, tag string) (*sdk.WorkerCache, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM worker_cache
+    WHERE project_id = $1 AND integration_name = $2 AND tag = $3
+  `).Args(projectID, integrationName, tag)
+	return get(ctx, db, query)
+}
+
+// LoadLatestByTagPrefix returns the most recently created confirmed worker cache which tag starts with given prefix,
+// nil if it does not exist.
+func LoadLatestByTagPrefix(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, prefix string) (*sdk.WorkerCache, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM worker_cache
+    WHERE project_id = $1 AND integration_name = $2 AND substr(tag, 1, length($3)) = $3 AND confirmed = true
+    ORDER BY created DESC, id DESC
+    LIMIT 1
+  `).Args(projectID, integrationName, prefix)
+	return get(ctx, db, query)
+}
+
+// Insert a worker cache in database.
+func Insert(db gorp.SqlExecutor, c *sdk.WorkerCache) error {
+	c.Created = time.Now()
+	c.LastAccess = c.Created
+	dbc := dbWorkerCache(*c)
+	if err := gorpmapping.Insert(db, &dbc); err != nil {
+		if sdk.ErrorIs(err, sdk.ErrInvalidData) {
+			return sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrAlreadyExist, "worker cache %s already exists", c.Tag))
+		}
+		return sdk.WrapError(err, "cannot insert worker cache")
+	}
+	*c = sdk.WorkerCache(dbc)
+	return nil
+}
+
+// Update a worker cache in database.
+func Update(db gorp.SqlExecutor, c *sdk.WorkerCache) error {
+	dbc := dbWorkerCache(*c)
+	if err := gorpmapping.Update(db, &dbc); err != nil {
+		return sdk.WrapError(err, "cannot update worker cache")
+	}
+	return nil
+}
+
+// UpdateLastAccess sets the last access date of given worker cache to now.
+func UpdateLastAccess(db gorp.SqlExecutor, c *sdk.WorkerCache) error {
+	c.LastAccess = time.Now()
+	_, err := db.Exec("UPDATE worker_cache SET last_access = $2 WHERE id = $1", c.ID, c.LastAccess)
+	return sdk.WrapError(err, "cannot update worker cache %d last access", c.ID)
+}
+
+// Delete a worker cache from database.
+func Delete(db gorp.SqlExecutor, c *sdk.WorkerCache) error {
+	dbc := dbWorkerCache(*c)
+	if err := gorpmapping.Delete(db, &dbc); err != nil {
+		return sdk.WrapError(err, "cannot delete worker cache")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package workercache
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbWorkerCache sdk.WorkerCache
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbWorkerCache{}, "worker_cache", true, "id"))
+}
@@ -0,0 +1,130 @@
+package workercache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// ObjectName is the name of the tar file of a worker cache in the objectstore.
+const ObjectName = "cache.tar"
+
+// UploadTimeout is the max duration of the upload of a worker cache, it is the validity of the temporary URLs
+// given to the workers to upload their caches.
+const UploadTimeout = time.Hour
+
+// Object returns the objectstore object of a worker cache.
+func Object(projectKey, tag string) *sdk.Cache {
+	return &sdk.Cache{
+		Name:    ObjectName,
+		Project: projectKey,
+		Tag:     tag,
+	}
+}
+
+// Register indexes a worker cache before its upload, it stays pending until it is confirmed. A cache is immutable
+// so registering it fails if a confirmed cache already exists for the tag, but a pending one is taken over to
+// let a push retry after a failed upload.
+func Register(ctx context.Context, db gorp.SqlExecutor, c *sdk.WorkerCache) error {
+	existing, err := LoadByTag(ctx, db, c.ProjectID, c.IntegrationName, c.Tag)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		c.Confirmed = false
+		return Insert(db, c)
+	}
+	if existing.Confirmed {
+		return sdk.NewErrorFrom(sdk.ErrAlreadyExist, "worker cache %s already exists", c.Tag)
+	}
+
+	existing.Size = c.Size
+	existing.Created = time.Now()
+	existing.LastAccess = existing.Created
+	if err := Update(db, existing); err != nil {
+		return err
+	}
+	*c = *existing
+	return nil
+}
+
+// Confirm marks a worker cache as uploaded, it can then be resolved.
+func Confirm(db gorp.SqlExecutor, c *sdk.WorkerCache) error {
+	c.Confirmed = true
+	return Update(db, c)
+}
+
+// Resolve returns the worker cache to restore for given tag. If there is no cache for this tag,
+// restore keys are checked in order and the most recent cache which tag starts with the first
+// matching restore key is returned. It returns nil if there is no matching cache.
+// Pending caches, which upload is not confirmed, are never returned.
+func Resolve(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, tag string, restoreKeys []string) (*sdk.WorkerCache, error) {
+	c, err := LoadByTag(ctx, db, projectID, integrationName, tag)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil && c.Confirmed {
+		return c, nil
+	}
+
+	for _, k := range restoreKeys {
+		if k == "" {
+			continue
+		}
+		c, err := LoadLatestByTagPrefix(ctx, db, projectID, integrationName, k)
+		if err != nil || c != nil {
+			return c, err
+		}
+	}
+
+	return nil, nil
+}
+
+// Evict deletes the least recently used worker caches of a project until the total size of its caches
+// stored in the driver's integration is under given max size.
+func Evict(ctx context.Context, db gorp.SqlExecutor, driver objectstore.Driver, projectKey string, projectID int64, integrationName string, maxSize int64) error {
+	if maxSize <= 0 {
+		return nil
+	}
+
+	cs, err := LoadAllByProjectIDAndIntegration(ctx, db, projectID, integrationName)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cachesToEvict(cs, maxSize, time.Now()) {
+		if err := driver.Delete(ctx, Object(projectKey, c.Tag)); err != nil {
+			log.Warning(ctx, "workercache.Evict> cannot delete cache %s of project %s from storage: %v", c.Tag, projectKey, err)
+		}
+		if err := Delete(db, &c); err != nil {
+			return err
+		}
+		log.Info(ctx, "workercache.Evict> cache %s of project %s evicted (size: %d, last access: %s)", c.Tag, projectKey, c.Size, c.LastAccess)
+	}
+
+	return nil
+}
+
+// cachesToEvict returns the caches to delete to fit given max size. Caches are expected
+// to be sorted from the most to the least recently used, the most recently used one is always kept.
+// Pending caches registered less than UploadTimeout ago may still be uploading, they are never evicted.
+func cachesToEvict(cs []sdk.WorkerCache, maxSize int64, now time.Time) []sdk.WorkerCache {
+	var total int64
+	var res []sdk.WorkerCache
+	for i := range cs {
+		total += cs[i].Size
+		if i == 0 || total <= maxSize {
+			continue
+		}
+		if !cs[i].Confirmed && now.Sub(cs[i].Created) < UploadTimeout {
+			continue
+		}
+		res = append(res, cs[i])
+	}
+	return res
+}
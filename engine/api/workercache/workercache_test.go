@@ -0,0 +1,34 @@
+package workercache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_cachesToEvict(t *testing.T) {
+	now := time.Now()
+	cs := []sdk.WorkerCache{
+		{Tag: "a", Size: 40, Confirmed: true},
+		{Tag: "b", Size: 30, Confirmed: true},
+		{Tag: "c", Size: 20, Confirmed: true},
+		{Tag: "d", Size: 10, Confirmed: true},
+	}
+
+	assert.Empty(t, cachesToEvict(cs, 100, now))
+	assert.Equal(t, cs[3:], cachesToEvict(cs, 90, now))
+	assert.Equal(t, cs[2:], cachesToEvict(cs, 75, now))
+	// the most recently used cache is never evicted
+	assert.Equal(t, cs[1:], cachesToEvict(cs, 10, now))
+	assert.Empty(t, cachesToEvict(nil, 10, now))
+
+	// a pending cache is not evicted while it may still be uploading
+	cs[2].Confirmed = false
+	cs[2].Created = now.Add(-time.Minute)
+	assert.Equal(t, []sdk.WorkerCache{cs[1], cs[3]}, cachesToEvict(cs, 10, now))
+	cs[2].Created = now.Add(-2 * UploadTimeout)
+	assert.Equal(t, cs[1:], cachesToEvict(cs, 10, now))
+}
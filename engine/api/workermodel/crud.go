@@ -167,5 +167,11 @@ func CopyModelTypeData(old, data *sdk.Model) error {
 		}
 	}
 
+	// the pod template can only be changed by an administrator
+	data.ModelDocker.PodTemplate = nil
+	if data.Type == sdk.Docker && old.Type == sdk.Docker {
+		data.ModelDocker.PodTemplate = old.ModelDocker.PodTemplate
+	}
+
 	return nil
 }
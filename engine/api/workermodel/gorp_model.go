@@ -15,6 +15,7 @@ import (
 func init() {
 	gorpmapping.Register(gorpmapping.New(WorkerModel{}, "worker_model", true, "id"))
 	gorpmapping.Register(gorpmapping.New(workerModelPattern{}, "worker_model_pattern", true, "id"))
+	secret.RegisterColumn(secret.Column{Table: "worker_model", Key: "id", Name: "model", JSON: true})
 }
 
 // WorkerModel is a gorp wrapper around sdk.Model.
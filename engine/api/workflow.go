@@ -144,8 +144,9 @@ func (api *API) getWorkflowHandler() service.Handler {
 		w1.URLs.APIURL = api.Config.URL.API + api.Router.GetRoute("GET", api.getWorkflowHandler, map[string]string{"key": key, "permWorkflowName": w1.Name})
 		w1.URLs.UIURL = api.Config.URL.UI + "/project/" + key + "/workflow/" + w1.Name
 
-		//We filter project and workflow configuration key, because they are always set on insertHooks
-		w1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		//We filter project and workflow configuration key, because they are always set on insertHooks, and the webhook secret that is kept from the previous hook
+		w1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow, sdk.HookConfigWebHookSecret)
+		w1.HideNotificationsWebhookURL()
 		return service.WriteJSON(w, w1, http.StatusOK)
 	}
 }
@@ -400,8 +401,9 @@ func (api *API) postWorkflowHandler() service.Handler {
 		wf.Permissions.Writable = true
 		wf.Permissions.Executable = true
 
-		//We filter project and workflow configurtaion key, because they are always set on insertHooks
-		wf.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		//We filter project and workflow configurtaion key, because they are always set on insertHooks, and the webhook secret that is kept from the previous hook
+		wf.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow, sdk.HookConfigWebHookSecret)
+		wf.HideNotificationsWebhookURL()
 
 		return service.WriteJSON(w, wf, http.StatusCreated)
 	}
@@ -488,8 +490,9 @@ func (api *API) putWorkflowHandler() service.Handler {
 		}
 		wf1.Usage = &usage
 
-		//We filter project and workflow configuration key, because they are always set on insertHooks
-		wf1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		//We filter project and workflow configuration key, because they are always set on insertHooks, and the webhook secret that is kept from the previous hook
+		wf1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow, sdk.HookConfigWebHookSecret)
+		wf1.HideNotificationsWebhookURL()
 		return service.WriteJSON(w, wf1, http.StatusOK)
 	}
 }
@@ -0,0 +1,139 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+)
+
+// artifactProvenanceObject is the object stored next to an artifact for its provenance.
+type artifactProvenanceObject struct {
+	art *sdk.WorkflowNodeRunArtifact
+}
+
+func (o artifactProvenanceObject) GetName() string {
+	return o.art.GetName() + sdk.ArtifactProvenanceSuffix
+}
+
+func (o artifactProvenanceObject) GetPath() string {
+	return o.art.GetPath()
+}
+
+// NewArtifactProvenanceStatement returns the provenance statement of an artifact uploaded by given job run.
+func NewArtifactProvenanceStatement(nodeRun sdk.WorkflowNodeRun, nodeJobRun sdk.WorkflowNodeJobRun, art sdk.WorkflowNodeRunArtifact) sdk.ProvenanceStatement {
+	digest := map[string]string{"sha512": art.SHA512sum}
+	if art.MD5sum != "" {
+		digest["md5"] = art.MD5sum
+	}
+
+	s := sdk.ProvenanceStatement{
+		Type:          sdk.ProvenanceStatementType,
+		PredicateType: sdk.ProvenancePredicateType,
+		Subject:       []sdk.ProvenanceSubject{{Name: art.Name, Digest: digest}},
+		Predicate: sdk.ProvenancePredicate{
+			Builder:   sdk.ProvenanceBuilder{ID: nodeJobRun.Model},
+			BuildType: sdk.ProvenanceBuildType,
+			BuildConfig: sdk.ProvenanceBuildConfig{
+				Project:     sdk.ParameterValue(nodeJobRun.Parameters, "cds.project"),
+				Workflow:    sdk.ParameterValue(nodeJobRun.Parameters, "cds.workflow"),
+				RunNumber:   nodeRun.Number,
+				SubNumber:   nodeRun.SubNumber,
+				Node:        nodeRun.WorkflowNodeName,
+				Job:         nodeJobRun.Job.Action.Name,
+				WorkerName:  nodeJobRun.Job.WorkerName,
+				WorkerModel: nodeJobRun.Model,
+			},
+			Metadata: sdk.ProvenanceMetadata{
+				BuildInvocationID: strconv.FormatInt(nodeJobRun.ID, 10),
+				BuildStartedOn:    nodeJobRun.Start,
+			},
+		},
+	}
+
+	if nodeRun.VCSRepository != "" {
+		uri := sdk.ParameterValue(nodeJobRun.Parameters, "git.http_url")
+		if uri == "" {
+			uri = nodeRun.VCSRepository
+		}
+		m := sdk.ProvenanceMaterial{URI: "git+" + uri}
+		if nodeRun.VCSBranch != "" {
+			m.URI += "@refs/heads/" + nodeRun.VCSBranch
+		}
+		if nodeRun.VCSHash != "" {
+			m.Digest = map[string]string{"sha1": nodeRun.VCSHash}
+		}
+		s.Predicate.Materials = append(s.Predicate.Materials, m)
+	}
+
+	return s
+}
+
+// SignArtifactProvenance signs the provenance statement with given PGP project key.
+func SignArtifactProvenance(key sdk.ProjectKey, s sdk.ProvenanceStatement) (*sdk.ArtifactProvenance, error) {
+	if key.Type != sdk.KeyTypePGP {
+		return nil, sdk.WithStack(sdk.ErrKeyNotFound)
+	}
+
+	entity, err := keys.GetOpenPGPEntity(strings.NewReader(key.Private))
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	p := sdk.ArtifactProvenance{
+		PayloadType: sdk.ProvenancePayloadType,
+		Payload:     payload,
+	}
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, entity, bytes.NewReader(sdk.ProvenancePAE(p.PayloadType, p.Payload)), nil); err != nil {
+		return nil, sdk.WrapError(err, "cannot sign provenance")
+	}
+	p.Signatures = []sdk.ArtifactProvenanceSignature{{KeyID: key.KeyID, Sig: sig.String()}}
+
+	return &p, nil
+}
+
+// StoreArtifactProvenance stores the signed provenance next to the artifact.
+func StoreArtifactProvenance(storageDriver objectstore.Driver, art *sdk.WorkflowNodeRunArtifact, p sdk.ArtifactProvenance) error {
+	btes, err := json.Marshal(p)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := storageDriver.Store(artifactProvenanceObject{art: art}, ioutil.NopCloser(bytes.NewReader(btes))); err != nil {
+		return sdk.WrapError(err, "cannot store provenance of artifact %s", art.Name)
+	}
+	return nil
+}
+
+// LoadArtifactProvenance fetches the signed provenance stored next to the artifact.
+func LoadArtifactProvenance(ctx context.Context, storageDriver objectstore.Driver, art *sdk.WorkflowNodeRunArtifact) (*sdk.ArtifactProvenance, error) {
+	f, err := storageDriver.Fetch(ctx, artifactProvenanceObject{art: art})
+	if err != nil {
+		return nil, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrNotFound, "cannot find provenance of artifact %s", art.Name))
+	}
+	defer f.Close() // nolint
+
+	var p sdk.ArtifactProvenance
+	if err := json.NewDecoder(f).Decode(&p); err != nil {
+		return nil, sdk.WrapError(err, "cannot read provenance of artifact %s", art.Name)
+	}
+	return &p, nil
+}
+
+// DeleteArtifactProvenance removes the provenance stored next to the artifact.
+func DeleteArtifactProvenance(ctx context.Context, storageDriver objectstore.Driver, art *sdk.WorkflowNodeRunArtifact) error {
+	return storageDriver.Delete(ctx, artifactProvenanceObject{art: art})
+}
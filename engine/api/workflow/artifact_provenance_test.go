@@ -0,0 +1,120 @@
+package workflow_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSignArtifactProvenance(t *testing.T) {
+	k, err := keys.GeneratePGPKeyPair(sdk.ArtifactProvenanceKeyName)
+	require.NoError(t, err)
+	key := sdk.ProjectKey{Name: k.Name, Type: k.Type, KeyID: k.KeyID, Public: k.Public, Private: k.Private}
+
+	nodeRun := sdk.WorkflowNodeRun{
+		Number:           42,
+		WorkflowNodeName: "build",
+		VCSRepository:    "ovh/cds",
+		VCSBranch:        "master",
+		VCSHash:          "2a9e4b8f0c1d",
+	}
+	nodeJobRun := sdk.WorkflowNodeJobRun{
+		ID:    12,
+		Model: "shared.infra/debian",
+		Start: time.Now(),
+		Parameters: []sdk.Parameter{
+			{Name: "cds.project", Value: "PROJ"},
+			{Name: "cds.workflow", Value: "my-workflow"},
+			{Name: "git.http_url", Value: "https://github.com/ovh/cds.git"},
+		},
+	}
+	nodeJobRun.Job.Action.Name = "compile"
+	nodeJobRun.Job.WorkerName = "worker-1"
+	art := sdk.WorkflowNodeRunArtifact{Name: "cds-engine", SHA512sum: "b3ab9c", MD5sum: "1f3e"}
+
+	s := workflow.NewArtifactProvenanceStatement(nodeRun, nodeJobRun, art)
+	assert.Equal(t, "shared.infra/debian", s.Predicate.Builder.ID)
+	assert.Equal(t, sdk.ProvenanceBuildConfig{
+		Project:     "PROJ",
+		Workflow:    "my-workflow",
+		RunNumber:   42,
+		Node:        "build",
+		Job:         "compile",
+		WorkerName:  "worker-1",
+		WorkerModel: "shared.infra/debian",
+	}, s.Predicate.BuildConfig)
+	require.Len(t, s.Predicate.Materials, 1)
+	assert.Equal(t, "git+https://github.com/ovh/cds.git@refs/heads/master", s.Predicate.Materials[0].URI)
+	assert.Equal(t, "2a9e4b8f0c1d", s.Predicate.Materials[0].Digest["sha1"])
+
+	p, err := workflow.SignArtifactProvenance(key, s)
+	require.NoError(t, err)
+	require.Len(t, p.Signatures, 1)
+
+	verified, err := p.Verify(key.Public, "cds-engine", "b3ab9c")
+	require.NoError(t, err)
+	assert.Equal(t, s.Subject, verified.Subject)
+
+	_, err = p.Verify(key.Public, "cds-engine", "other")
+	assert.Error(t, err, "digest doesn't match")
+
+	tampered := *p
+	tampered.Payload = []byte(string(p.Payload[:len(p.Payload)-1]) + " }")
+	_, err = tampered.Verify(key.Public, "cds-engine", "b3ab9c")
+	assert.Error(t, err, "payload has been modified")
+
+	other, err := keys.GeneratePGPKeyPair("other")
+	require.NoError(t, err)
+	_, err = p.Verify(other.Public, "cds-engine", "b3ab9c")
+	assert.Error(t, err, "signed by another key")
+}
+
+func TestLoadNodeJobRunKeysWithoutArtifactProvenanceKey(t *testing.T) {
+	db, cache, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+
+	k, err := keys.GeneratePGPKeyPair("proj-mykey")
+	require.NoError(t, err)
+	require.NoError(t, project.InsertKey(db, &sdk.ProjectKey{Name: k.Name, Type: k.Type, KeyID: k.KeyID, Public: k.Public, Private: k.Private, ProjectID: proj.ID}))
+
+	provenanceKey, err := project.LoadArtifactProvenanceKey(db, proj.ID)
+	require.NoError(t, err)
+	assert.True(t, provenanceKey.Builtin)
+	assert.NotEmpty(t, provenanceKey.Private)
+
+	proj, err = project.Load(db, cache, key, project.LoadOptions.WithClearKeys)
+	require.NoError(t, err)
+
+	wr := &sdk.WorkflowRun{
+		Workflow: sdk.Workflow{
+			WorkflowData: sdk.WorkflowData{
+				Node: sdk.Node{ID: 1, Name: "build", Context: &sdk.NodeContext{}},
+			},
+		},
+	}
+	params, secrets, err := workflow.LoadNodeJobRunKeys(context.TODO(), db, proj, wr, &sdk.WorkflowNodeRun{WorkflowNodeID: 1})
+	require.NoError(t, err)
+
+	assert.Contains(t, sdk.ParametersToMap(params), "cds.key.proj-mykey.pub")
+	require.Len(t, secrets, 1)
+	assert.Equal(t, "cds.key.proj-mykey.priv", secrets[0].Name)
+	for _, p := range params {
+		assert.False(t, strings.Contains(p.Name, sdk.ArtifactProvenanceKeyName), "param %s", p.Name)
+		assert.NotEqual(t, provenanceKey.Public, p.Value)
+	}
+}
@@ -165,12 +165,14 @@ func (w *Workflow) PostInsert(db gorp.SqlExecutor) error {
 // PostGet is a db hook
 func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	var res = struct {
-		Metadata     sql.NullString `db:"metadata"`
-		PurgeTags    sql.NullString `db:"purge_tags"`
-		WorkflowData sql.NullString `db:"workflow_data"`
+		Metadata          sql.NullString `db:"metadata"`
+		PurgeTags         sql.NullString `db:"purge_tags"`
+		ArtifactRetention sql.NullString `db:"artifact_retention"`
+		RunRetention      sql.NullString `db:"run_retention"`
+		WorkflowData      sql.NullString `db:"workflow_data"`
 	}{}
 
-	if err := db.SelectOne(&res, "SELECT metadata, purge_tags, workflow_data FROM workflow WHERE id = $1", w.ID); err != nil {
+	if err := db.SelectOne(&res, "SELECT metadata, purge_tags, artifact_retention, run_retention, workflow_data FROM workflow WHERE id = $1", w.ID); err != nil {
 		return sdk.WrapError(err, "PostGet> Unable to load marshalled workflow")
 	}
 
@@ -186,6 +188,22 @@ func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	}
 	w.PurgeTags = purgeTags
 
+	if res.ArtifactRetention.Valid {
+		var retention sdk.WorkflowArtifactRetention
+		if err := gorpmapping.JSONNullString(res.ArtifactRetention, &retention); err != nil {
+			return sdk.WrapError(err, "Unable to unmarshall artifact retention")
+		}
+		w.ArtifactRetention = &retention
+	}
+
+	if res.RunRetention.Valid {
+		var retention sdk.WorkflowRunRetention
+		if err := gorpmapping.JSONNullString(res.RunRetention, &retention); err != nil {
+			return sdk.WrapError(err, "Unable to unmarshall run retention")
+		}
+		w.RunRetention = &retention
+	}
+
 	data := sdk.WorkflowData{}
 	if err := gorpmapping.JSONNullString(res.WorkflowData, &data); err != nil {
 		return sdk.WrapError(err, "Unable to unmarshall workflow data")
@@ -231,11 +249,29 @@ func (w *Workflow) PostUpdate(db gorp.SqlExecutor) error {
 		return errPt
 	}
 
+	var retention sql.NullString
+	if w.ArtifactRetention != nil {
+		var errR error
+		retention, errR = gorpmapping.JSONToNullString(w.ArtifactRetention)
+		if errR != nil {
+			return sdk.WrapError(errR, "Workflow.PostUpdate> Unable to marshall artifact retention")
+		}
+	}
+
+	var runRetention sql.NullString
+	if w.RunRetention != nil {
+		var errR error
+		runRetention, errR = gorpmapping.JSONToNullString(w.RunRetention)
+		if errR != nil {
+			return sdk.WrapError(errR, "Workflow.PostUpdate> Unable to marshall run retention")
+		}
+	}
+
 	data, errD := gorpmapping.JSONToNullString(w.WorkflowData)
 	if errD != nil {
 		return sdk.WrapError(errD, "Workflow.PostUpdate> Unable to marshall workflow data")
 	}
-	if _, err := db.Exec("update workflow set purge_tags = $1, workflow_data = $3 where id = $2", pt, w.ID, data); err != nil {
+	if _, err := db.Exec("update workflow set purge_tags = $1, workflow_data = $3, artifact_retention = $4, run_retention = $5 where id = $2", pt, w.ID, data, retention, runRetention); err != nil {
 		return err
 	}
 
@@ -683,6 +719,9 @@ func Insert(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sd
 
 	customVcsNotif := false
 	// Insert notifications
+	if err := encryptNotificationsWebhookURL(w.Notifications, nil); err != nil {
+		return err
+	}
 	for i := range w.Notifications {
 		n := &w.Notifications[i]
 		if n.Type == sdk.VCSUserNotification {
@@ -904,6 +943,15 @@ func Update(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sd
 		return err
 	}
 
+	// reload workflow to delete the current workflow data, and to keep the webhook urls of its notifications
+	oldWf, err := LoadByID(ctx, db, store, proj, wf.ID, LoadOptions{})
+	if err != nil {
+		return sdk.WrapError(err, "Unable to load existing workflow with proj:%s ID:%d", proj.Key, wf.ID)
+	}
+	if err := encryptNotificationsWebhookURL(wf.Notifications, oldWf.Notifications); err != nil {
+		return err
+	}
+
 	if err := DeleteNotifications(db, wf.ID); err != nil {
 		return sdk.WrapError(err, "unable to delete all notifications on workflow(%d - %s)", wf.ID, wf.Name)
 	}
@@ -911,12 +959,6 @@ func Update(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sd
 	if err := integration.DeleteFromWorkflow(db, wf.ID); err != nil {
 		return sdk.WrapError(err, "unable to delete all integrations on workflow(%d - %s)", wf.ID, wf.Name)
 	}
-
-	// reload workflow to delete the current workflow data
-	oldWf, err := LoadByID(ctx, db, store, proj, wf.ID, LoadOptions{})
-	if err != nil {
-		return sdk.WrapError(err, "Unable to load existing workflow with proj:%s ID:%d", proj.Key, wf.ID)
-	}
 	if err := DeleteWorkflowData(db, *oldWf); err != nil {
 		return sdk.WrapError(err, "unable to delete from old workflow data(%d - %s)", wf.ID, wf.Name)
 	}
@@ -1037,6 +1079,17 @@ func IsValid(ctx context.Context, store cache.Store, db gorp.SqlExecutor, w *sdk
 		return sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Invalid workflow name. It should match %s", sdk.NamePattern))
 	}
 
+	if w.ArtifactRetention != nil {
+		if err := w.ArtifactRetention.IsValid(); err != nil {
+			return err
+		}
+	}
+	if w.RunRetention != nil {
+		if err := w.RunRetention.IsValid(); err != nil {
+			return err
+		}
+	}
+
 	//Check refs
 	for _, j := range w.WorkflowData.Joins {
 		if len(j.JoinContext) == 0 {
@@ -1389,6 +1442,10 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		return nil, nil, nil, sdk.WithStack(sdk.ErrWorkflowAlreadyAsCode)
 	}
 
+	if err := ascode.CheckCompositeActions(data.Actions); err != nil {
+		return nil, nil, nil, err
+	}
+
 	tx, err := db.Begin()
 	if err != nil {
 		return nil, nil, nil, sdk.WrapError(err, "Unable to start tx")
@@ -1427,7 +1484,7 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		if opts != nil {
 			fromRepo = opts.FromRepository
 		}
-		pipDB, msgList, err := pipeline.ParseAndImport(ctx, tx, store, *proj, &pip, u, pipeline.ImportOptions{Force: true, FromRepository: fromRepo})
+		pipDB, msgList, err := pipeline.ParseAndImport(ctx, tx, store, *proj, ascode.NewCompositeActionsPipeliner(pip, data.Actions), u, pipeline.ImportOptions{Force: true, FromRepository: fromRepo})
 		if err != nil {
 			return nil, nil, nil, sdk.ErrorWithFallback(err, sdk.ErrWrongRequest, "unable to import pipeline %s/%s", proj.Key, pip.Name)
 		}
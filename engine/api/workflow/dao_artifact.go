@@ -1,6 +1,10 @@
 package workflow
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/sdk"
@@ -102,3 +106,90 @@ func InsertArtifact(db gorp.SqlExecutor, a *sdk.WorkflowNodeRunArtifact) error {
 	a.ID = wArtifactDB.ID
 	return nil
 }
+
+// LoadArtifactsForRetention loads the artifacts of the workflow runs not marked to delete, latest runs first,
+// with the run information needed to apply the artifact retention policy.
+func LoadArtifactsForRetention(db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowArtifactRetentionItem, error) {
+	var rows []struct {
+		ID            int64     `db:"id"`
+		Name          string    `db:"name"`
+		Tag           string    `db:"tag"`
+		Size          int64     `db:"size"`
+		Created       time.Time `db:"created"`
+		WorkflowRunID int64     `db:"workflow_run_id"`
+		RunNumber     int64     `db:"num"`
+		RunStatus     string    `db:"status"`
+		Branch        string    `db:"vcs_branch"`
+		GitTag        string    `db:"vcs_tag"`
+	}
+	query := `
+		SELECT
+			workflow_node_run_artifacts.id,
+			workflow_node_run_artifacts.name,
+			workflow_node_run_artifacts.tag,
+			coalesce(workflow_node_run_artifacts.size, 0) AS size,
+			workflow_node_run_artifacts.created,
+			workflow_node_run_artifacts.workflow_run_id,
+			workflow_run.num,
+			workflow_run.status,
+			coalesce(workflow_node_run.vcs_branch, '') AS vcs_branch,
+			coalesce(workflow_node_run.vcs_tag, '') AS vcs_tag
+		FROM workflow_node_run_artifacts
+		JOIN workflow_run ON workflow_run.id = workflow_node_run_artifacts.workflow_run_id
+		JOIN workflow_node_run ON workflow_node_run.id = workflow_node_run_artifacts.workflow_node_run_id
+		WHERE workflow_run.workflow_id = $1
+		AND workflow_run.to_delete = false
+		ORDER BY workflow_run.num DESC, workflow_node_run_artifacts.id ASC
+	`
+	if _, err := db.Select(&rows, query, workflowID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load artifacts of workflow %d", workflowID)
+	}
+
+	items := make([]sdk.WorkflowArtifactRetentionItem, len(rows))
+	runIDs := make([]string, 0, len(rows))
+	for i, r := range rows {
+		items[i] = sdk.WorkflowArtifactRetentionItem{
+			ArtifactID:    r.ID,
+			Name:          r.Name,
+			Tag:           r.Tag,
+			Size:          r.Size,
+			Created:       r.Created,
+			WorkflowRunID: r.WorkflowRunID,
+			RunNumber:     r.RunNumber,
+			RunStatus:     r.RunStatus,
+			Branch:        r.Branch,
+			GitTag:        r.GitTag,
+		}
+		if i == 0 || rows[i-1].WorkflowRunID != r.WorkflowRunID {
+			runIDs = append(runIDs, strconv.FormatInt(r.WorkflowRunID, 10))
+		}
+	}
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	var tags []sdk.WorkflowRunTag
+	if _, err := db.Select(&tags, `
+		SELECT workflow_run_id, tag, value
+		FROM workflow_run_tag
+		WHERE workflow_run_id = ANY(string_to_array($1, ',')::bigint[])`, strings.Join(runIDs, ",")); err != nil {
+		return nil, sdk.WrapError(err, "cannot load run tags of workflow %d", workflowID)
+	}
+	tagsByRun := make(map[int64][]sdk.WorkflowRunTag)
+	for _, t := range tags {
+		tagsByRun[t.WorkflowRunID] = append(tagsByRun[t.WorkflowRunID], t)
+	}
+	for i := range items {
+		items[i].RunTags = tagsByRun[items[i].WorkflowRunID]
+	}
+
+	return items, nil
+}
+
+// DeleteArtifact deletes an artifact from database.
+func DeleteArtifact(db gorp.SqlExecutor, id int64) error {
+	if _, err := db.Exec("DELETE FROM workflow_node_run_artifacts WHERE id = $1", id); err != nil {
+		return sdk.WrapError(err, "cannot delete artifact %d", id)
+	}
+	return nil
+}
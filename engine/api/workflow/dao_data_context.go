@@ -21,6 +21,10 @@ type sqlNodeContextData struct {
 	DefaultPipelineParameters sql.NullString `db:"default_pipeline_parameters"`
 	Conditions                sql.NullString `db:"conditions"`
 	Mutex                     bool           `db:"mutex"`
+	Lock                      string         `db:"lock_name"`
+	Approval                  sql.NullString `db:"approval"`
+	PreviewEnvironment        sql.NullString `db:"preview_environment"`
+	Priority                  string         `db:"priority"`
 }
 
 func insertNodeContextData(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) error {
@@ -71,6 +75,53 @@ func insertNodeContextData(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) er
 
 	tempContext.Mutex = n.Context.Mutex
 
+	if err := sdk.IsValidWorkflowNodeRunLockName(n.Context.Lock); err != nil {
+		return err
+	}
+	tempContext.Lock = n.Context.Lock
+
+	if err := sdk.IsValidJobPriority(n.Context.Priority); err != nil {
+		return err
+	}
+	tempContext.Priority = n.Context.Priority
+
+	if n.Context.Approval != nil {
+		if err := n.Context.Approval.IsValid(); err != nil {
+			return err
+		}
+		var errA error
+		tempContext.Approval, errA = gorpmapping.JSONToNullString(n.Context.Approval)
+		if errA != nil {
+			return sdk.WrapError(errA, "insertNodeContextData> Cannot stringify approval")
+		}
+	}
+
+	if n.Context.PreviewEnvironment != nil {
+		if n.Context.EnvironmentID == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "node %s with a preview environment should have an environment", n.Name)
+		}
+		if err := n.Context.PreviewEnvironment.IsValid(); err != nil {
+			return err
+		}
+		if teardown := n.Context.PreviewEnvironment.TeardownPipeline; teardown != "" {
+			var found bool
+			for _, pip := range w.Pipelines {
+				if pip.Name == teardown {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "teardown pipeline %s of the preview environment of node %s should be used by a node of the workflow", teardown, n.Name)
+			}
+		}
+		var errP error
+		tempContext.PreviewEnvironment, errP = gorpmapping.JSONToNullString(n.Context.PreviewEnvironment)
+		if errP != nil {
+			return sdk.WrapError(errP, "insertNodeContextData> Cannot stringify preview environment")
+		}
+	}
+
 	if n.Context.PipelineID != 0 {
 		//Checks pipeline parameters
 		if len(n.Context.DefaultPipelineParameters) > 0 {
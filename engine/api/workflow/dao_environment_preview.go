@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func getEnvironmentPreviews(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.EnvironmentPreview, error) {
+	res := []dbEnvironmentPreview{}
+	if err := gorpmapping.GetAll(ctx, db, q, &res); err != nil {
+		return nil, sdk.WrapError(err, "cannot get preview environments")
+	}
+
+	ps := make([]sdk.EnvironmentPreview, len(res))
+	for i := range res {
+		ps[i] = sdk.EnvironmentPreview(res[i])
+	}
+	return ps, nil
+}
+
+func getEnvironmentPreview(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.EnvironmentPreview, error) {
+	var p dbEnvironmentPreview
+	found, err := gorpmapping.Get(ctx, db, q, &p)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get preview environment")
+	}
+	if !found {
+		return nil, nil
+	}
+
+	res := sdk.EnvironmentPreview(p)
+	return &res, nil
+}
+
+// LoadEnvironmentPreviewsByWorkflowID returns all the preview environments created by given workflow.
+func LoadEnvironmentPreviewsByWorkflowID(ctx context.Context, db gorp.SqlExecutor, workflowID int64) ([]sdk.EnvironmentPreview, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM environment_preview
+    WHERE workflow_id = $1
+    ORDER BY created
+  `).Args(workflowID)
+	return getEnvironmentPreviews(ctx, db, query)
+}
+
+// LoadActiveEnvironmentPreviewsByBranch returns the active preview environments created by given workflow for a branch.
+func LoadActiveEnvironmentPreviewsByBranch(ctx context.Context, db gorp.SqlExecutor, workflowID int64, branch string) ([]sdk.EnvironmentPreview, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM environment_preview
+    WHERE workflow_id = $1
+    AND branch = $2
+    AND status = $3
+    FOR UPDATE SKIP LOCKED
+  `).Args(workflowID, branch, sdk.PreviewEnvironmentStatusActive)
+	return getEnvironmentPreviews(ctx, db, query)
+}
+
+// LoadActiveEnvironmentPreviewsByPullRequest returns the active preview environments created by given workflow for a pull request.
+func LoadActiveEnvironmentPreviewsByPullRequest(ctx context.Context, db gorp.SqlExecutor, workflowID int64, pullRequestID string) ([]sdk.EnvironmentPreview, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM environment_preview
+    WHERE workflow_id = $1
+    AND pull_request_id = $2
+    AND status = $3
+    FOR UPDATE SKIP LOCKED
+  `).Args(workflowID, pullRequestID, sdk.PreviewEnvironmentStatusActive)
+	return getEnvironmentPreviews(ctx, db, query)
+}
+
+// LoadEnvironmentPreviewByEnvironmentID returns the preview environment tracked for given environment, or nil if
+// the environment is not a preview environment.
+func LoadEnvironmentPreviewByEnvironmentID(ctx context.Context, db gorp.SqlExecutor, environmentID int64) (*sdk.EnvironmentPreview, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM environment_preview
+    WHERE environment_id = $1
+  `).Args(environmentID)
+	return getEnvironmentPreview(ctx, db, query)
+}
+
+func insertEnvironmentPreview(db gorp.SqlExecutor, p *sdk.EnvironmentPreview) error {
+	p.Status = sdk.PreviewEnvironmentStatusActive
+	p.Created = time.Now()
+	p.LastModified = p.Created
+	dbp := dbEnvironmentPreview(*p)
+	if err := gorpmapping.Insert(db, &dbp); err != nil {
+		return sdk.WrapError(err, "cannot insert preview environment")
+	}
+	*p = sdk.EnvironmentPreview(dbp)
+	return nil
+}
+
+func updateEnvironmentPreview(db gorp.SqlExecutor, p *sdk.EnvironmentPreview) error {
+	p.LastModified = time.Now()
+	dbp := dbEnvironmentPreview(*p)
+	if err := gorpmapping.Update(db, &dbp); err != nil {
+		return sdk.WrapError(err, "cannot update preview environment")
+	}
+	return nil
+}
@@ -50,7 +50,8 @@ workflow_node_run.uuid,
 workflow_node_run.outgoinghook,
 workflow_node_run.hook_execution_timestamp,
 workflow_node_run.execution_id,
-workflow_node_run.callback
+workflow_node_run.callback,
+workflow_node_run.outputs
 `
 
 const nodeRunTestsField string = ", workflow_node_run.tests"
@@ -378,6 +379,12 @@ func fromDBNodeRun(rr NodeRun, opts LoadRunOptions) (*sdk.WorkflowNodeRun, error
 		}
 	}
 
+	if rr.Outputs.Valid {
+		if err := gorpmapping.JSONNullString(rr.Outputs, &r.Outputs); err != nil {
+			return nil, sdk.WrapError(err, "fromDBNodeRun>Error loading node run %d: Outputs", r.ID)
+		}
+	}
+
 	return r, nil
 }
 
@@ -503,9 +510,28 @@ func makeDBNodeRun(n sdk.WorkflowNodeRun) (*NodeRun, error) {
 	}
 	nodeRunDB.OutgoingHook = oh
 
+	if n.Outputs != nil {
+		s, err := gorpmapping.JSONToNullString(n.Outputs)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to get json from outputs")
+		}
+		nodeRunDB.Outputs = s
+	}
+
 	return nodeRunDB, nil
 }
 
+//UpdateNodeRunOutputs updates outputs in table workflow_node_run
+func UpdateNodeRunOutputs(db gorp.SqlExecutor, nodeID int64, outputs map[string]string) error {
+	bts, err := json.Marshal(outputs)
+	if err != nil {
+		return sdk.WrapError(err, "unable to get json from outputs")
+	}
+
+	_, err = db.Exec("UPDATE workflow_node_run SET outputs = $1 WHERE id = $2", bts, nodeID)
+	return sdk.WrapError(err, "unable to update outputs of node run %d", nodeID)
+}
+
 //UpdateNodeRunBuildParameters updates build_parameters in table workflow_node_run
 func UpdateNodeRunBuildParameters(db gorp.SqlExecutor, nodeID int64, buildParameters []sdk.Parameter) error {
 	if buildParameters == nil {
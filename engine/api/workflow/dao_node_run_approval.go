@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func getNodeRunApprovals(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.WorkflowNodeRunApproval, error) {
+	res := []dbNodeRunApproval{}
+	if err := gorpmapping.GetAll(ctx, db, q, &res); err != nil {
+		return nil, sdk.WrapError(err, "cannot get workflow node run approvals")
+	}
+
+	as := make([]sdk.WorkflowNodeRunApproval, len(res))
+	for i := range res {
+		as[i] = sdk.WorkflowNodeRunApproval(res[i])
+	}
+	return as, nil
+}
+
+func getNodeRunApproval(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.WorkflowNodeRunApproval, error) {
+	var a dbNodeRunApproval
+	found, err := gorpmapping.Get(ctx, db, q, &a)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get workflow node run approval")
+	}
+	if !found {
+		return nil, nil
+	}
+
+	res := sdk.WorkflowNodeRunApproval(a)
+	res.Decisions, err = loadNodeRunApprovalDecisions(ctx, db, res.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// LoadNodeRunApprovalByNodeRunID returns the approval gate of given node run, or nil if there is no approval gate.
+func LoadNodeRunApprovalByNodeRunID(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) (*sdk.WorkflowNodeRunApproval, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_node_run_approval
+    WHERE workflow_node_run_id = $1
+  `).Args(nodeRunID)
+	return getNodeRunApproval(ctx, db, query)
+}
+
+// LoadAndLockNodeRunApprovalByNodeRunID returns the approval gate of given node run and locks it for update.
+func LoadAndLockNodeRunApprovalByNodeRunID(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) (*sdk.WorkflowNodeRunApproval, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_node_run_approval
+    WHERE workflow_node_run_id = $1
+    FOR UPDATE
+  `).Args(nodeRunID)
+	return getNodeRunApproval(ctx, db, query)
+}
+
+// LoadTimedOutNodeRunApprovals returns the pending approval gates that were not approved within their timeout.
+func LoadTimedOutNodeRunApprovals(ctx context.Context, db gorp.SqlExecutor) ([]sdk.WorkflowNodeRunApproval, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_node_run_approval
+    WHERE status = $1
+    AND timeout > 0
+    AND created + timeout * interval '1 second' < now()
+  `).Args(sdk.ApprovalStatusPending)
+	return getNodeRunApprovals(ctx, db, query)
+}
+
+func insertNodeRunApproval(db gorp.SqlExecutor, a *sdk.WorkflowNodeRunApproval) error {
+	a.Status = sdk.ApprovalStatusPending
+	a.Created = time.Now()
+	a.LastModified = a.Created
+	dba := dbNodeRunApproval(*a)
+	if err := gorpmapping.Insert(db, &dba); err != nil {
+		return sdk.WrapError(err, "cannot insert workflow node run approval")
+	}
+	*a = sdk.WorkflowNodeRunApproval(dba)
+	return nil
+}
+
+func updateNodeRunApproval(db gorp.SqlExecutor, a *sdk.WorkflowNodeRunApproval) error {
+	a.LastModified = time.Now()
+	dba := dbNodeRunApproval(*a)
+	if err := gorpmapping.Update(db, &dba); err != nil {
+		return sdk.WrapError(err, "cannot update workflow node run approval")
+	}
+	return nil
+}
+
+func loadNodeRunApprovalDecisions(ctx context.Context, db gorp.SqlExecutor, approvalID int64) ([]sdk.WorkflowNodeRunApprovalDecision, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_node_run_approval_decision
+    WHERE workflow_node_run_approval_id = $1
+    ORDER BY created
+  `).Args(approvalID)
+	res := []dbNodeRunApprovalDecision{}
+	if err := gorpmapping.GetAll(ctx, db, query, &res); err != nil {
+		return nil, sdk.WrapError(err, "cannot get workflow node run approval decisions")
+	}
+
+	ds := make([]sdk.WorkflowNodeRunApprovalDecision, len(res))
+	for i := range res {
+		ds[i] = sdk.WorkflowNodeRunApprovalDecision(res[i])
+	}
+	return ds, nil
+}
+
+func insertNodeRunApprovalDecision(db gorp.SqlExecutor, d *sdk.WorkflowNodeRunApprovalDecision) error {
+	d.Created = time.Now()
+	dbd := dbNodeRunApprovalDecision(*d)
+	if err := gorpmapping.Insert(db, &dbd); err != nil {
+		if sdk.ErrorIs(err, sdk.ErrInvalidData) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "user %s already gave a decision for this pipeline", d.Username)
+		}
+		return sdk.WrapError(err, "cannot insert workflow node run approval decision")
+	}
+	*d = sdk.WorkflowNodeRunApprovalDecision(dbd)
+	return nil
+}
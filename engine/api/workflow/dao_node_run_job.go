@@ -286,6 +286,37 @@ func LoadDeadNodeJobRun(ctx context.Context, db gorp.SqlExecutor, store cache.St
 	return deadJobs, nil
 }
 
+// LoadTimedOutNodeJobRun load NodeJobRuns which are Building for longer than their timeout plus given grace period
+func LoadTimedOutNodeJobRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, gracePeriod time.Duration) ([]sdk.WorkflowNodeJobRun, error) {
+	var jobsDB []JobRun
+	query := `
+		SELECT workflow_node_run_job.*
+		FROM workflow_node_run_job
+		WHERE status = $1
+		AND COALESCE((job->'action'->>'timeout')::BIGINT, 0) > 0
+		AND start + ((job->'action'->>'timeout')::BIGINT + $2) * interval '1 second' < now()`
+	if _, err := db.Select(&jobsDB, query, sdk.StatusBuilding, int64(gracePeriod/time.Second)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WithStack(err)
+	}
+
+	jobs := make([]sdk.WorkflowNodeJobRun, len(jobsDB))
+	for i := range jobsDB {
+		if store != nil {
+			getHatcheryInfo(ctx, store, &jobsDB[i])
+		}
+		jr, err := jobsDB[i].WorkflowNodeRunJob()
+		if err != nil {
+			return nil, err
+		}
+		jobs[i] = jr
+	}
+
+	return jobs, nil
+}
+
 //LoadAndLockNodeJobRunWait load for update a NodeJobRun given its ID
 func LoadAndLockNodeJobRunWait(ctx context.Context, db gorp.SqlExecutor, store cache.Store, id int64) (*sdk.WorkflowNodeJobRun, error) {
 	j := JobRun{}
@@ -371,6 +402,13 @@ func keyBookJob(id int64) string {
 	return cache.Key("book", "job", strconv.FormatInt(id, 10))
 }
 
+// IsNodeJobRunBooked returns true if the job is booked by a hatchery.
+func IsNodeJobRunBooked(ctx context.Context, store cache.Store, id int64) bool {
+	j := JobRun{ID: id}
+	getHatcheryInfo(ctx, store, &j)
+	return j.BookedBy.ID != 0
+}
+
 func getHatcheryInfo(ctx context.Context, store cache.Store, j *JobRun) {
 	h := sdk.Service{}
 	k := keyBookJob(j.ID)
@@ -383,10 +421,10 @@ func getHatcheryInfo(ctx context.Context, store cache.Store, j *JobRun) {
 	}
 }
 
-// replaceWorkflowJobRunInQueue restart workflow node job
-func replaceWorkflowJobRunInQueue(db gorp.SqlExecutor, wNodeJob sdk.WorkflowNodeJobRun) error {
-	query := "UPDATE workflow_node_run_job SET status = $1, retry = $2, worker_id = NULL WHERE id = $3"
-	if _, err := db.Exec(query, sdk.StatusWaiting, wNodeJob.Retry+1, wNodeJob.ID); err != nil {
+// replaceWorkflowJobRunInQueue restart workflow node job, it will not be visible in the queue before given queued date
+func replaceWorkflowJobRunInQueue(db gorp.SqlExecutor, wNodeJob sdk.WorkflowNodeJobRun, queued time.Time) error {
+	query := "UPDATE workflow_node_run_job SET status = $1, retry = $2, worker_id = NULL, queued = $3 WHERE id = $4"
+	if _, err := db.Exec(query, sdk.StatusWaiting, wNodeJob.Retry+1, queued, wNodeJob.ID); err != nil {
 		return sdk.WrapError(err, "Unable to set workflow_node_run_job id %d with status %s", wNodeJob.ID, sdk.StatusWaiting)
 	}
 
@@ -0,0 +1,109 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func getNodeRunLocks(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.WorkflowNodeRunLock, error) {
+	res := []dbNodeRunLock{}
+	if err := gorpmapping.GetAll(ctx, db, q, &res); err != nil {
+		return nil, sdk.WrapError(err, "cannot get workflow node run locks")
+	}
+
+	ls := make([]sdk.WorkflowNodeRunLock, len(res))
+	for i := range res {
+		ls[i] = sdk.WorkflowNodeRunLock(res[i])
+	}
+	return ls, nil
+}
+
+func getNodeRunLock(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.WorkflowNodeRunLock, error) {
+	var l dbNodeRunLock
+	found, err := gorpmapping.Get(ctx, db, q, &l)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get workflow node run lock")
+	}
+	if !found {
+		return nil, nil
+	}
+	res := sdk.WorkflowNodeRunLock(l)
+	return &res, nil
+}
+
+// LoadNodeRunLocksByProjectID returns all held or waiting locks for given project.
+func LoadNodeRunLocksByProjectID(ctx context.Context, db gorp.SqlExecutor, projectID int64) ([]sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_node_run_lock
+    WHERE project_id = $1
+    ORDER BY name, held DESC, id
+  `).Args(projectID)
+	return getNodeRunLocks(ctx, db, query)
+}
+
+func loadNodeRunLocksByNodeRunID(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) ([]sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_node_run_lock
+    WHERE workflow_node_run_id = $1
+  `).Args(nodeRunID)
+	return getNodeRunLocks(ctx, db, query)
+}
+
+func loadNodeRunLockByNodeRunIDAndName(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64, name string) (*sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_node_run_lock
+    WHERE workflow_node_run_id = $1 AND name = $2
+  `).Args(nodeRunID, name)
+	return getNodeRunLock(ctx, db, query)
+}
+
+// loadNodeRunLockHolder returns the node run lock that holds the lock for given name, or the first one in the queue.
+func loadNodeRunLockHolder(ctx context.Context, db gorp.SqlExecutor, projectID int64, name string) (*sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_node_run_lock
+    WHERE project_id = $1 AND name = $2
+    ORDER BY held DESC, id
+    LIMIT 1
+  `).Args(projectID, name)
+	return getNodeRunLock(ctx, db, query)
+}
+
+// lockNodeRunLockName takes a transaction level lock on given lock name to prevent concurrent updates of its queue.
+func lockNodeRunLockName(db gorp.SqlExecutor, projectID int64, name string) error {
+	_, err := db.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", fmt.Sprintf("workflow_node_run_lock-%d-%s", projectID, name))
+	return sdk.WrapError(err, "cannot lock workflow node run lock %s", name)
+}
+
+func insertNodeRunLock(db gorp.SqlExecutor, l *sdk.WorkflowNodeRunLock) error {
+	dbl := dbNodeRunLock(*l)
+	if err := gorpmapping.Insert(db, &dbl); err != nil {
+		return sdk.WrapError(err, "cannot insert workflow node run lock")
+	}
+	*l = sdk.WorkflowNodeRunLock(dbl)
+	return nil
+}
+
+func updateNodeRunLock(db gorp.SqlExecutor, l *sdk.WorkflowNodeRunLock) error {
+	dbl := dbNodeRunLock(*l)
+	if err := gorpmapping.Update(db, &dbl); err != nil {
+		return sdk.WrapError(err, "cannot update workflow node run lock")
+	}
+	return nil
+}
+
+func deleteNodeRunLock(db gorp.SqlExecutor, l *sdk.WorkflowNodeRunLock) error {
+	dbl := dbNodeRunLock(*l)
+	if err := gorpmapping.Delete(db, &dbl); err != nil {
+		return sdk.WrapError(err, "cannot delete workflow node run lock")
+	}
+	return nil
+}
@@ -2,9 +2,12 @@ package workflow
 
 import (
 	"database/sql"
+	"sort"
+	"strings"
 
 	"github.com/go-gorp/gorp"
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -95,6 +98,63 @@ func InsertNotification(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.WorkflowNot
 	return nil
 }
 
+// encryptNotificationsWebhookURL encrypts the webhook urls of the chat notifications. As the urls are never sent to users, a
+// placeholder is replaced by the url of the same notification in the old notifications, given by its id or by its type and sources.
+func encryptNotificationsWebhookURL(notifs []sdk.WorkflowNotification, oldNotifs []sdk.WorkflowNotification) error {
+	for i := range notifs {
+		n := &notifs[i]
+		if !sdk.IsChatUserNotification(n.Type) || n.Settings.WebhookURL == "" {
+			continue
+		}
+
+		if n.Settings.WebhookURL == sdk.PasswordPlaceholder {
+			old := findOldNotification(*n, oldNotifs)
+			if old == nil || old.Settings.WebhookURL == "" || old.Settings.WebhookURL == sdk.PasswordPlaceholder {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "webhook url of %s notification on %v is missing", n.Type, n.SourceNodeRefs)
+			}
+			n.Settings.WebhookURL = old.Settings.WebhookURL
+			continue
+		}
+
+		// the url is already encrypted if the notification comes from a loaded workflow
+		if _, err := secret.DecryptValue(n.Settings.WebhookURL); err == nil {
+			continue
+		}
+		if err := sdk.CheckChatWebhookURL(n.Settings.WebhookURL); err != nil {
+			return err
+		}
+		encrypted, err := secret.EncryptValue(n.Settings.WebhookURL)
+		if err != nil {
+			return sdk.WrapError(err, "cannot encrypt webhook url")
+		}
+		n.Settings.WebhookURL = encrypted
+	}
+	return nil
+}
+
+func findOldNotification(n sdk.WorkflowNotification, oldNotifs []sdk.WorkflowNotification) *sdk.WorkflowNotification {
+	if n.ID != 0 {
+		for i := range oldNotifs {
+			if oldNotifs[i].ID == n.ID {
+				return &oldNotifs[i]
+			}
+		}
+	}
+	refs := sortedRefs(n)
+	for i := range oldNotifs {
+		if oldNotifs[i].Type == n.Type && sortedRefs(oldNotifs[i]) == refs {
+			return &oldNotifs[i]
+		}
+	}
+	return nil
+}
+
+func sortedRefs(n sdk.WorkflowNotification) string {
+	refs := append([]string{}, n.SourceNodeRefs...)
+	sort.Strings(refs)
+	return strings.Join(refs, ",")
+}
+
 // PostInsert is a db hook
 func (no *Notification) PostInsert(db gorp.SqlExecutor) error {
 	b, err := gorpmapping.JSONToNullString(no.Settings)
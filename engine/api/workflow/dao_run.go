@@ -699,6 +699,12 @@ func PurgeWorkflowRun(ctx context.Context, db gorp.SqlExecutor, wf sdk.Workflow,
 		return nil
 	}
 
+	// the run retention rules are applied by the purge routine
+	if wf.RunRetention != nil {
+		log.Debug("PurgeWorkflowRun> workflow has run retention rules, skipping purge")
+		return nil
+	}
+
 	filteredPurgeTags := []string{}
 	for _, t := range wf.PurgeTags {
 		if t != "" {
@@ -870,6 +876,69 @@ func PurgeWorkflowRun(ctx context.Context, db gorp.SqlExecutor, wf sdk.Workflow,
 	return nil
 }
 
+// LoadRunsForRetention loads the workflow runs not marked to delete, latest first, with their branch
+// and the environments they deployed to, read from the run tags.
+func LoadRunsForRetention(db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowRunRetentionItem, error) {
+	var rows []struct {
+		ID           int64     `db:"id"`
+		Number       int64     `db:"num"`
+		Status       string    `db:"status"`
+		LastModified time.Time `db:"last_modified"`
+	}
+	query := `
+		SELECT id, num, status, last_modified
+		FROM workflow_run
+		WHERE workflow_id = $1
+		AND to_delete = false
+		ORDER BY num DESC
+	`
+	if _, err := db.Select(&rows, query, workflowID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load runs of workflow %d", workflowID)
+	}
+
+	items := make([]sdk.WorkflowRunRetentionItem, len(rows))
+	index := make(map[int64]int, len(rows))
+	for i, r := range rows {
+		items[i] = sdk.WorkflowRunRetentionItem{
+			WorkflowRunID: r.ID,
+			Number:        r.Number,
+			Status:        r.Status,
+			LastModified:  r.LastModified,
+		}
+		index[r.ID] = i
+	}
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	var tags []sdk.WorkflowRunTag
+	if _, err := db.Select(&tags, `
+		SELECT workflow_run_tag.workflow_run_id, workflow_run_tag.tag, workflow_run_tag.value
+		FROM workflow_run_tag
+		JOIN workflow_run ON workflow_run.id = workflow_run_tag.workflow_run_id
+		WHERE workflow_run.workflow_id = $1
+		AND workflow_run.to_delete = false
+		AND workflow_run_tag.tag = ANY(string_to_array($2, ',')::text[])`, workflowID, tagGitBranch+","+tagEnvironment); err != nil {
+		return nil, sdk.WrapError(err, "cannot load run tags of workflow %d", workflowID)
+	}
+	for _, t := range tags {
+		i, ok := index[t.WorkflowRunID]
+		if !ok {
+			continue
+		}
+		// values of the tags set by several nodes are joined with a comma
+		values := strings.Split(t.Value, ",")
+		switch t.Tag {
+		case tagGitBranch:
+			items[i].Branch = values[0]
+		case tagEnvironment:
+			items[i].Environments = values
+		}
+	}
+
+	return items, nil
+}
+
 // syncNodeRuns load the workflow node runs for a workflow run
 func syncNodeRuns(db gorp.SqlExecutor, wr *sdk.WorkflowRun, loadOpts LoadRunOptions) error {
 	var testsField string
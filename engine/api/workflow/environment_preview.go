@@ -0,0 +1,214 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// previewEnvironmentAuthor is the author of the variables of the preview environments created by CDS
+var previewEnvironmentAuthor = &sdk.AuthentifiedUser{Username: "cds.preview", Fullname: "CDS preview environment"}
+
+// resolvePreviewEnvironment replaces the environment of given node by its preview environment, which is created from
+// the environment of the node on the first run for a computed name. The build parameters of the node run are updated.
+func resolvePreviewEnvironment(ctx context.Context, db gorp.SqlExecutor, proj sdk.Project, wr *sdk.WorkflowRun, n *sdk.Node, nr *sdk.WorkflowNodeRun) error {
+	params := sdk.ParametersToMap(nr.BuildParameters)
+	name, err := n.Context.PreviewEnvironment.ComputeName(params)
+	if err != nil {
+		return err
+	}
+
+	// The node can already use a preview environment if it was processed before in the same workflow run
+	baseID := n.Context.EnvironmentID
+	current, err := LoadEnvironmentPreviewByEnvironmentID(ctx, db, baseID)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		baseID = current.BaseEnvironmentID
+	}
+
+	env, err := environment.LoadEnvironmentByName(db, proj.Key, name)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrEnvironmentNotFound) {
+		return sdk.WrapError(err, "cannot load environment %s", name)
+	}
+
+	if env != nil {
+		preview, err := LoadEnvironmentPreviewByEnvironmentID(ctx, db, env.ID)
+		if err != nil {
+			return err
+		}
+		if preview == nil {
+			return sdk.NewErrorFrom(sdk.ErrEnvironmentExist, "environment %s already exists and is not a preview environment", name)
+		}
+		// the teardown of a preview environment is run by the workflow that owns it, it can't be shared
+		if preview.WorkflowID != wr.WorkflowID || preview.BaseEnvironmentID != baseID {
+			return sdk.NewErrorFrom(sdk.ErrEnvironmentExist, "preview environment %s already exists for workflow %s, its name should be unique to the workflow and environment", name, preview.WorkflowName)
+		}
+		if preview.Status != sdk.PreviewEnvironmentStatusActive {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "preview environment %s is being torn down", name)
+		}
+		preview.WorkflowRunNumber = wr.Number
+		if err := updateEnvironmentPreview(db, preview); err != nil {
+			return err
+		}
+	} else {
+		env, err = createPreviewEnvironment(ctx, db, proj, wr, n, baseID, name, params)
+		if err != nil {
+			return err
+		}
+	}
+
+	n.Context.EnvironmentID = env.ID
+	n.Context.EnvironmentName = env.Name
+	wr.Workflow.Environments[env.ID] = *env
+
+	// Replace the variables of the base environment by the ones of the preview environment
+	for k := range params {
+		if strings.HasPrefix(k, "cds.env.") {
+			delete(params, k)
+		}
+	}
+	params["cds.environment"] = env.Name
+	params[sdk.PreviewEnvironmentNameParameter] = env.Name
+	nr.BuildParameters = sdk.ParametersFromMap(sdk.ParametersMapMerge(params, sdk.ParametersFromEnvironmentVariables(*env)))
+
+	return nil
+}
+
+// createPreviewEnvironment clones the base environment with the overridden variables of the preview environment.
+func createPreviewEnvironment(ctx context.Context, db gorp.SqlExecutor, proj sdk.Project, wr *sdk.WorkflowRun, n *sdk.Node, baseID int64, name string, params map[string]string) (*sdk.Environment, error) {
+	overrides, err := n.Context.PreviewEnvironment.ComputeVariables(params)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "%v", err)
+	}
+
+	variables, err := environment.LoadAllVariablesWithDecrytion(db, baseID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load variables of environment %d", baseID)
+	}
+	for i := range variables {
+		if v, ok := overrides[variables[i].Name]; ok {
+			variables[i].Value = v
+			delete(overrides, variables[i].Name)
+		}
+	}
+	for k, v := range overrides {
+		variables = append(variables, sdk.Variable{Name: k, Type: sdk.StringVariable, Value: v})
+	}
+
+	env := sdk.Environment{
+		Name:       name,
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+	}
+	if err := environment.InsertEnvironment(db, &env); err != nil {
+		return nil, sdk.WrapError(err, "cannot insert preview environment %s", name)
+	}
+	for i := range variables {
+		variables[i].ID = 0
+		if err := environment.InsertVariable(db, env.ID, &variables[i], previewEnvironmentAuthor); err != nil {
+			return nil, sdk.WrapError(err, "cannot insert variable %s in preview environment %s", variables[i].Name, name)
+		}
+	}
+
+	preview := sdk.EnvironmentPreview{
+		ProjectID:         proj.ID,
+		EnvironmentID:     env.ID,
+		EnvironmentName:   env.Name,
+		BaseEnvironmentID: baseID,
+		WorkflowID:        wr.WorkflowID,
+		WorkflowName:      wr.Workflow.Name,
+		WorkflowRunNumber: wr.Number,
+		WorkflowNodeName:  n.Name,
+		Repository:        params[tagGitRepository],
+		Branch:            params[tagGitBranch],
+		PullRequestID:     params[sdk.PreviewEnvironmentPullRequestParameter],
+		TeardownPipeline:  n.Context.PreviewEnvironment.TeardownPipeline,
+	}
+	if err := insertEnvironmentPreview(db, &preview); err != nil {
+		return nil, err
+	}
+	log.Info(ctx, "resolvePreviewEnvironment> preview environment %s created from environment %d for workflow %s", name, baseID, wr.Workflow.Name)
+
+	// Reload the environment to get the variables as they are given to the other environments of the workflow run
+	return environment.LoadEnvironmentByID(db, env.ID)
+}
+
+// PrepareEnvironmentPreviewTeardown marks given preview environment as being torn down and returns the node of the
+// workflow run that has to be run to tear it down. It returns nil if the preview environment has no teardown pipeline
+// or if the node can't be found, the environment can then be deleted.
+func PrepareEnvironmentPreviewTeardown(ctx context.Context, db gorp.SqlExecutor, p *sdk.EnvironmentPreview, wr *sdk.WorkflowRun) (*sdk.Node, error) {
+	if p.TeardownPipeline == "" {
+		return nil, nil
+	}
+
+	var teardownNode *sdk.Node
+	for _, n := range wr.Workflow.WorkflowData.Array() {
+		if n.Context == nil || n.Context.PipelineID == 0 {
+			continue
+		}
+		if pip, ok := wr.Workflow.Pipelines[n.Context.PipelineID]; ok && pip.Name == p.TeardownPipeline {
+			teardownNode = n
+			break
+		}
+	}
+	if teardownNode == nil {
+		log.Warning(ctx, "PrepareEnvironmentPreviewTeardown> cannot find a node with pipeline %s in workflow run %d", p.TeardownPipeline, wr.ID)
+		return nil, nil
+	}
+
+	env, err := environment.LoadEnvironmentByID(db, p.EnvironmentID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load preview environment %s", p.EnvironmentName)
+	}
+
+	// The teardown node runs in the context of the preview environment
+	teardownNode.Context.EnvironmentID = env.ID
+	teardownNode.Context.EnvironmentName = env.Name
+	wr.Workflow.Environments[env.ID] = *env
+
+	p.Status = sdk.PreviewEnvironmentStatusTearingDown
+	if err := updateEnvironmentPreview(db, p); err != nil {
+		return nil, err
+	}
+	return teardownNode, nil
+}
+
+// DeleteEnvironmentPreview deletes a preview environment and its tracking.
+func DeleteEnvironmentPreview(ctx context.Context, db gorp.SqlExecutor, p sdk.EnvironmentPreview) error {
+	if err := environment.DeleteEnvironment(db, p.EnvironmentID); err != nil {
+		return sdk.WrapError(err, "cannot delete preview environment %s", p.EnvironmentName)
+	}
+	log.Info(ctx, "DeleteEnvironmentPreview> preview environment %s deleted", p.EnvironmentName)
+	return nil
+}
+
+// completeEnvironmentPreviewTeardown deletes the preview environment torn down by given node run when it succeeded.
+func completeEnvironmentPreviewTeardown(ctx context.Context, db gorp.SqlExecutor, n *sdk.Node, nr *sdk.WorkflowNodeRun) error {
+	if n == nil || n.Context == nil || n.Context.EnvironmentID == 0 {
+		return nil
+	}
+	if teardown := sdk.ParameterFind(nr.BuildParameters, sdk.PreviewEnvironmentTeardownParameter); teardown == nil || teardown.Value != "true" {
+		return nil
+	}
+
+	p, err := LoadEnvironmentPreviewByEnvironmentID(ctx, db, n.Context.EnvironmentID)
+	if err != nil {
+		return err
+	}
+	if p == nil || p.Status != sdk.PreviewEnvironmentStatusTearingDown {
+		return nil
+	}
+
+	if nr.Status != sdk.StatusSuccess {
+		p.Status = sdk.PreviewEnvironmentStatusTeardownFailed
+		return updateEnvironmentPreview(db, p)
+	}
+	return DeleteEnvironmentPreview(ctx, db, *p)
+}
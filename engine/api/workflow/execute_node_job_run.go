@@ -1,7 +1,6 @@
 package workflow
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"sync"
@@ -556,7 +555,7 @@ func FreeNodeJobRun(ctx context.Context, store cache.Store, id int64) error {
 }
 
 //AddLog adds a build log
-func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, maxLogSize int64) error {
+func AddLog(ctx context.Context, db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, maxLogSize int64) error {
 	if job != nil {
 		logs.JobID = job.ID
 		logs.NodeRunID = job.WorkflowNodeRunID
@@ -574,14 +573,14 @@ func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, max
 	}
 
 	if !exists {
-		return sdk.WrapError(insertLog(db, logs), "cannot insert log")
+		return sdk.WrapError(insertLog(ctx, db, logs), "cannot insert log")
 	}
 
-	return sdk.WrapError(updateLog(db, logs), "cannot update log")
+	return sdk.WrapError(updateLog(ctx, db, logs), "cannot update log")
 }
 
 //AddServiceLog adds a service log
-func AddServiceLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.ServiceLog, maxLogSize int64) error {
+func AddServiceLog(ctx context.Context, db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.ServiceLog, maxLogSize int64) error {
 	if job != nil {
 		logs.WorkflowNodeJobRunID = job.ID
 		logs.WorkflowNodeRunID = job.WorkflowNodeRunID
@@ -599,20 +598,10 @@ func AddServiceLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.S
 	}
 
 	if !exists {
-		return sdk.WrapError(insertServiceLog(db, logs), "Cannot insert log")
+		return sdk.WrapError(insertServiceLog(ctx, db, logs), "Cannot insert log")
 	}
 
-	existingLogs, err := LoadServiceLog(db, logs.WorkflowNodeJobRunID, logs.ServiceRequirementName)
-	if err != nil {
-		return sdk.WrapError(err, "cannot load existing logs")
-	}
-
-	logbuf := bytes.NewBufferString(existingLogs.Val)
-	logbuf.WriteString(logs.Val)
-	existingLogs.Val = logbuf.String()
-	existingLogs.LastModified = logs.LastModified
-
-	return sdk.WrapError(updateServiceLog(db, existingLogs), "Cannot update log")
+	return sdk.WrapError(updateServiceLog(ctx, db, logs), "Cannot update log")
 }
 
 // RestartWorkflowNodeJob restart all workflow node job and update logs to indicate restart
@@ -621,25 +610,82 @@ func RestartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob s
 	ctx, end = observability.Span(ctx, "workflow.RestartWorkflowNodeJob")
 	defer end()
 
+	wNodeJob.Job.Reason = "Killed (Reason: Timeout)\n"
+	return replaceNodeJobRunInQueue(ctx, db, wNodeJob, "Worker timeout: job replaced in queue", wNodeJob.Queued)
+}
+
+// CanRetryNodeJobRun returns true if the retry policy of the job allows a new attempt
+// after the current one ended for given condition.
+func CanRetryNodeJobRun(wNodeJob sdk.WorkflowNodeJobRun, when string, exitCode int) bool {
+	if wNodeJob.Job.Action.Retry == nil {
+		return false
+	}
+	return wNodeJob.Job.Action.Retry.IsRetriable(int64(wNodeJob.Retry)+1, when, exitCode)
+}
+
+// RetryNodeJobRun replaces in queue a job which attempt ended for given condition. The job will be
+// visible in the queue after the backoff delay of its retry policy. Logs of previous attempts are kept.
+func RetryNodeJobRun(ctx context.Context, db gorp.SqlExecutor, wNodeJob sdk.WorkflowNodeJobRun, when string) error {
+	var end func()
+	ctx, end = observability.Span(ctx, "workflow.RetryNodeJobRun")
+	defer end()
+
+	retry := wNodeJob.Job.Action.Retry
+	if retry == nil {
+		return sdk.WithStack(fmt.Errorf("no retry policy for node job run %d", wNodeJob.ID))
+	}
+	attempt := int64(wNodeJob.Retry) + 1
+	backoff := retry.BackoffDuration(attempt)
+
+	infos := []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobRetry.ID, Args: []interface{}{attempt, retry.MaxAttempts, when, backoff.String()}},
+	}}
+	if err := AddSpawnInfosNodeJobRun(db, wNodeJob.ID, infos); err != nil {
+		return err
+	}
+
+	wNodeJob.Job.Reason = ""
+	separator := fmt.Sprintf("Attempt %d/%d failed (%s): job replaced in queue", attempt, retry.MaxAttempts, when)
+	return replaceNodeJobRunInQueue(ctx, db, wNodeJob, separator, time.Now().Add(backoff))
+}
+
+// RestartLostNodeJobRun replaces in queue a job which worker was lost. If the retry policy of the job handles
+// worker loss it is used, else the job is restarted at most maxRetry times. It returns false if the job
+// was not restarted.
+func RestartLostNodeJobRun(ctx context.Context, db gorp.SqlExecutor, wNodeJob sdk.WorkflowNodeJobRun) (bool, error) {
+	if retry := wNodeJob.Job.Action.Retry; retry != nil && sdk.IsInArray(sdk.RetryWhenWorkerLost, retry.When) {
+		if !CanRetryNodeJobRun(wNodeJob, sdk.RetryWhenWorkerLost, 0) {
+			return false, nil
+		}
+		return true, RetryNodeJobRun(ctx, db, wNodeJob, sdk.RetryWhenWorkerLost)
+	}
+
+	if wNodeJob.Retry >= maxRetry {
+		return false, nil
+	}
+	return true, RestartWorkflowNodeJob(ctx, db, wNodeJob)
+}
+
+// replaceNodeJobRunInQueue resets the steps of a job, appends given separator to their logs then replaces
+// the job in queue with given queued date.
+func replaceNodeJobRunInQueue(ctx context.Context, db gorp.SqlExecutor, wNodeJob sdk.WorkflowNodeJobRun, separator string, queued time.Time) error {
 	for iS := range wNodeJob.Job.StepStatus {
 		step := &wNodeJob.Job.StepStatus[iS]
 		if step.Status == sdk.StatusNeverBuilt || step.Status == sdk.StatusSkipped || step.Status == sdk.StatusDisabled {
 			continue
 		}
-		l, errL := LoadStepLogs(db, wNodeJob.ID, int64(step.StepOrder))
+		l, errL := LoadStepLogs(ctx, db, wNodeJob.ID, int64(step.StepOrder))
 		if errL != nil {
-			return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while load step logs")
+			return sdk.WrapError(errL, "error while load step logs")
 		}
-		wNodeJob.Job.Reason = "Killed (Reason: Timeout)\n"
 		step.Status = sdk.StatusWaiting
 		step.Done = time.Time{}
 		if l != nil { // log could be nil here
 			l.Done = nil
-			logbuf := bytes.NewBufferString(l.Val)
-			logbuf.WriteString("\n\n\n-=-=-=-=-=- Worker timeout: job replaced in queue -=-=-=-=-=-\n\n\n")
-			l.Val = logbuf.String()
-			if err := updateLog(db, l); err != nil {
-				return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while update step log")
+			l.Val = "\n\n\n-=-=-=-=-=- " + separator + " -=-=-=-=-=-\n\n\n"
+			if err := updateLog(ctx, db, l); err != nil {
+				return sdk.WrapError(err, "error while update step log")
 			}
 		}
 	}
@@ -651,17 +697,17 @@ func RestartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob s
 	//Synchronize struct but not in db
 	sync, errS := SyncNodeRunRunJob(ctx, db, nodeRun, wNodeJob)
 	if errS != nil {
-		return sdk.WrapError(errS, "RestartWorkflowNodeJob> error on sync nodeJobRun")
+		return sdk.WrapError(errS, "error on sync nodeJobRun")
 	}
 	if !sync {
-		log.Warning(ctx, "RestartWorkflowNodeJob> sync doesn't find a nodeJobRun")
+		log.Warning(ctx, "replaceNodeJobRunInQueue> sync doesn't find a nodeJobRun")
 	}
 
 	if errU := UpdateNodeRun(db, nodeRun); errU != nil {
-		return sdk.WrapError(errU, "RestartWorkflowNodeJob> Cannot update node run")
+		return sdk.WrapError(errU, "Cannot update node run")
 	}
 
-	if err := replaceWorkflowJobRunInQueue(db, wNodeJob); err != nil {
+	if err := replaceWorkflowJobRunInQueue(db, wNodeJob, queued); err != nil {
 		return sdk.WrapError(err, "Cannot replace workflow job in queue")
 	}
 
@@ -1,7 +1,9 @@
 package workflow
 
 import (
+	"context"
 	"database/sql"
+	"io"
 	"time"
 
 	"github.com/lib/pq"
@@ -15,7 +17,7 @@ import (
 // ExistsStepLog returns the size of step log if exists.
 func ExistsStepLog(db gorp.SqlExecutor, id int64, order int64) (bool, int64, error) {
 	query := `
-    SELECT octet_length(value) as size
+    SELECT octet_length(value) + storage_size as size
     FROM workflow_node_run_job_logs
     WHERE workflow_node_run_job_id = $1 AND step_order = $2
   `
@@ -32,15 +34,16 @@ func ExistsStepLog(db gorp.SqlExecutor, id int64, order int64) (bool, int64, err
 }
 
 //LoadStepLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job) for a specific step_order
-func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
+func LoadStepLogs(ctx context.Context, db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
 	log.Debug("LoadStepLogs> workflow_node_run_job_id = %d", id)
 	query := `
-		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value
+		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value, storage_first_chunk, storage_chunks
 		FROM workflow_node_run_job_logs
 		WHERE workflow_node_run_job_id = $1 AND step_order = $2`
 	logs := &sdk.Log{}
 	var s, m, d pq.NullTime
-	if err := db.QueryRow(query, id, order).Scan(&logs.ID, &logs.JobID, &logs.NodeRunID, &s, &m, &d, &logs.StepOrder, &logs.Val); err != nil {
+	var firstChunk, chunks int64
+	if err := db.QueryRow(query, id, order).Scan(&logs.ID, &logs.JobID, &logs.NodeRunID, &s, &m, &d, &logs.StepOrder, &logs.Val, &firstChunk, &chunks); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -56,13 +59,36 @@ func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error)
 	if d.Valid {
 		logs.Done = &d.Time
 	}
+
+	var err error
+	logs.Val, err = readLog(ctx, logs.Val, stepLogChunkFrom(logs, firstChunk), chunks)
+	if err != nil {
+		return nil, err
+	}
 	return logs, nil
 }
 
+// OpenStepLogs returns a reader on the content of logs for a job for a specific step_order.
+func OpenStepLogs(ctx context.Context, db gorp.SqlExecutor, id int64, order int64) (io.ReadCloser, error) {
+	query := `
+		SELECT workflow_node_run_id, value, storage_first_chunk, storage_chunks
+		FROM workflow_node_run_job_logs
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2`
+	logs := &sdk.Log{JobID: id, StepOrder: order}
+	var firstChunk, chunks int64
+	if err := db.QueryRow(query, id, order).Scan(&logs.NodeRunID, &logs.Val, &firstChunk, &chunks); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WithStack(err)
+	}
+	return openLog(ctx, logs.Val, stepLogChunkFrom(logs, firstChunk), chunks)
+}
+
 //LoadLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job)
-func LoadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
+func LoadLogs(ctx context.Context, db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
 	query := `
-		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value
+		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value, storage_first_chunk, storage_chunks
 		FROM workflow_node_run_job_logs
 		WHERE workflow_node_run_job_id = $1
 		ORDER BY id`
@@ -75,8 +101,9 @@ func LoadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
 	for rows.Next() {
 		l := &sdk.Log{}
 		var s, m, d pq.NullTime
+		var firstChunk, chunks int64
 
-		if err := rows.Scan(&l.ID, &l.JobID, &l.NodeRunID, &s, &m, &d, &l.StepOrder, &l.Val); err != nil {
+		if err := rows.Scan(&l.ID, &l.JobID, &l.NodeRunID, &s, &m, &d, &l.StepOrder, &l.Val, &firstChunk, &chunks); err != nil {
 			return nil, err
 		}
 
@@ -90,20 +117,95 @@ func LoadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
 			l.Done = &d.Time
 		}
 
+		l.Val, err = readLog(ctx, l.Val, stepLogChunkFrom(l, firstChunk), chunks)
+		if err != nil {
+			return nil, err
+		}
+
 		logs = append(logs, *l)
 	}
 	return logs, nil
 }
 
-func insertLog(db gorp.SqlExecutor, logs *sdk.Log) error {
+func stepLogChunk(logs *sdk.Log) LogChunk {
+	return LogChunk{NodeRunID: logs.NodeRunID, JobID: logs.JobID, StepOrder: logs.StepOrder}
+}
+
+func stepLogChunkFrom(logs *sdk.Log, index int64) LogChunk {
+	c := stepLogChunk(logs)
+	c.Index = index
+	return c
+}
+
+// CompactStepLogs replaces the chunks of a step log by a single one so readers don't have to fetch every
+// chunk flushed by the worker, it should be called when the step ends.
+func CompactStepLogs(ctx context.Context, db *gorp.DbMap, id int64, order int64) error {
+	if logStorage == nil {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "cannot start transaction")
+	}
+	defer tx.Rollback() // nolint
+
+	// Lock the log so no chunk is appended while compacting
+	logs := &sdk.Log{JobID: id, StepOrder: order}
+	var firstChunk, chunks int64
+	if err := tx.QueryRow(`
+		SELECT workflow_node_run_id, storage_first_chunk, storage_chunks
+		FROM workflow_node_run_job_logs
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2
+		FOR UPDATE`, id, order).Scan(&logs.NodeRunID, &firstChunk, &chunks); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return sdk.WithStack(err)
+	}
+	if chunks-firstChunk <= 1 {
+		return nil
+	}
+
+	first := stepLogChunkFrom(logs, firstChunk)
+	if err := compactLogChunks(ctx, first, chunks); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE workflow_node_run_job_logs SET
+			storage_first_chunk = $3,
+			storage_chunks = $3 + 1
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2`, id, order, chunks); err != nil {
+		return sdk.WithStack(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "cannot commit transaction")
+	}
+
+	deleteLogChunks(ctx, first, chunks)
+	return nil
+}
+
+func insertLog(ctx context.Context, db gorp.SqlExecutor, logs *sdk.Log) error {
+	value := logs.Val
+	if logStorage != nil {
+		value = ""
+	}
 	query := `
 		INSERT INTO workflow_node_run_job_logs (workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING ID `
-	return sdk.WithStack(db.QueryRow(query, logs.JobID, logs.NodeRunID, logs.Start, logs.LastModified, logs.Done, logs.StepOrder, logs.Val).Scan(&logs.ID))
+	if err := db.QueryRow(query, logs.JobID, logs.NodeRunID, logs.Start, logs.LastModified, logs.Done, logs.StepOrder, value).Scan(&logs.ID); err != nil {
+		return sdk.WithStack(err)
+	}
+	if logStorage != nil {
+		return appendStepLogChunk(ctx, db, logs)
+	}
+	return nil
 }
 
-func updateLog(db gorp.SqlExecutor, logs *sdk.Log) error {
+// updateLog updates the log and appends its value to the existing content.
+func updateLog(ctx context.Context, db gorp.SqlExecutor, logs *sdk.Log) error {
 	now := time.Now()
 	if logs.Start == nil {
 		logs.Start = &now
@@ -115,6 +217,11 @@ func updateLog(db gorp.SqlExecutor, logs *sdk.Log) error {
 		logs.Done = &now
 	}
 
+	value := logs.Val
+	if logStorage != nil {
+		value = ""
+	}
+
 	query := `
 		UPDATE workflow_node_run_job_logs set
 			workflow_node_run_id = $3,
@@ -124,8 +231,21 @@ func updateLog(db gorp.SqlExecutor, logs *sdk.Log) error {
 			value = value || $7
 		WHERE workflow_node_run_job_id = $1 AND step_order = $2`
 
-	if _, err := db.Exec(query, logs.JobID, logs.StepOrder, logs.NodeRunID, logs.Start, logs.LastModified, logs.Done, logs.Val); err != nil {
+	if _, err := db.Exec(query, logs.JobID, logs.StepOrder, logs.NodeRunID, logs.Start, logs.LastModified, logs.Done, value); err != nil {
 		return sdk.WithStack(err)
 	}
+	if logStorage != nil {
+		return appendStepLogChunk(ctx, db, logs)
+	}
 	return nil
 }
+
+func appendStepLogChunk(ctx context.Context, db gorp.SqlExecutor, logs *sdk.Log) error {
+	query := `
+		UPDATE workflow_node_run_job_logs set
+			storage_chunks = storage_chunks + 1,
+			storage_size = storage_size + $3
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2
+		RETURNING storage_chunks`
+	return storeLogChunk(ctx, db, query, stepLogChunk(logs), logs.Val, logs.JobID, logs.StepOrder)
+}
@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -10,17 +11,15 @@ import (
 	"github.com/ovh/cds/sdk"
 )
 
-// updateServiceLog Update a service log
-func updateServiceLog(db gorp.SqlExecutor, log *sdk.ServiceLog) error {
+// updateServiceLog Update a service log and appends its value to the existing content
+func updateServiceLog(ctx context.Context, db gorp.SqlExecutor, log *sdk.ServiceLog) error {
 	query := `
 	UPDATE requirement_service_logs
-		SET workflow_node_run_job_id = $2,
-				workflow_node_run_id = $3,
-				requirement_service_name = $4,
-				start = $5,
-				last_modified = $6,
-				value = $7
-		WHERE id = $1
+		SET workflow_node_run_id = $3,
+				start = $4,
+				last_modified = $5,
+				value = value || $6
+		WHERE workflow_node_run_job_id = $1 AND requirement_service_name = $2
 	`
 
 	var now = time.Now()
@@ -32,13 +31,22 @@ func updateServiceLog(db gorp.SqlExecutor, log *sdk.ServiceLog) error {
 		log.LastModified = &now
 	}
 
-	_, errU := db.Exec(query, log.ID, log.WorkflowNodeJobRunID, log.WorkflowNodeRunID, log.ServiceRequirementName, log.Start, log.LastModified, log.Val)
+	value := log.Val
+	if logStorage != nil {
+		value = ""
+	}
 
-	return sdk.WithStack(errU)
+	if _, err := db.Exec(query, log.WorkflowNodeJobRunID, log.ServiceRequirementName, log.WorkflowNodeRunID, log.Start, log.LastModified, value); err != nil {
+		return sdk.WithStack(err)
+	}
+	if logStorage != nil {
+		return appendServiceLogChunk(ctx, db, log)
+	}
+	return nil
 }
 
 // insertServiceLog insert service log into database
-func insertServiceLog(db gorp.SqlExecutor, log *sdk.ServiceLog) error {
+func insertServiceLog(ctx context.Context, db gorp.SqlExecutor, log *sdk.ServiceLog) error {
 	query := `
 	INSERT INTO requirement_service_logs
 		(workflow_node_run_job_id, workflow_node_run_id, requirement_service_name, start, last_modified, value)
@@ -54,13 +62,39 @@ func insertServiceLog(db gorp.SqlExecutor, log *sdk.ServiceLog) error {
 		log.LastModified = &now
 	}
 
-	return db.QueryRow(query, log.WorkflowNodeJobRunID, log.WorkflowNodeRunID, log.ServiceRequirementName, log.Start, log.LastModified, log.Val).Scan(&log.ID)
+	value := log.Val
+	if logStorage != nil {
+		value = ""
+	}
+
+	if err := db.QueryRow(query, log.WorkflowNodeJobRunID, log.WorkflowNodeRunID, log.ServiceRequirementName, log.Start, log.LastModified, value).Scan(&log.ID); err != nil {
+		return sdk.WithStack(err)
+	}
+	if logStorage != nil {
+		return appendServiceLogChunk(ctx, db, log)
+	}
+	return nil
+}
+
+func appendServiceLogChunk(ctx context.Context, db gorp.SqlExecutor, log *sdk.ServiceLog) error {
+	query := `
+	UPDATE requirement_service_logs
+		SET storage_chunks = storage_chunks + 1,
+				storage_size = storage_size + $3
+		WHERE workflow_node_run_job_id = $1 AND requirement_service_name = $2
+	RETURNING storage_chunks
+	`
+	return storeLogChunk(ctx, db, query, serviceLogChunk(log), log.Val, log.WorkflowNodeJobRunID, log.ServiceRequirementName)
+}
+
+func serviceLogChunk(log *sdk.ServiceLog) LogChunk {
+	return LogChunk{NodeRunID: log.WorkflowNodeRunID, JobID: log.WorkflowNodeJobRunID, ServiceName: log.ServiceRequirementName}
 }
 
 // ExistsServiceLog returns the size of service log if exists.
 func ExistsServiceLog(db gorp.SqlExecutor, nodeRunJobID int64, serviceName string) (bool, int64, error) {
 	query := `
-    SELECT octet_length(value) as size
+    SELECT octet_length(value) + storage_size as size
     FROM requirement_service_logs
     WHERE workflow_node_run_job_id = $1 AND requirement_service_name = $2
   `
@@ -77,15 +111,16 @@ func ExistsServiceLog(db gorp.SqlExecutor, nodeRunJobID int64, serviceName strin
 }
 
 // LoadServiceLog load logs for the given job and service name
-func LoadServiceLog(db gorp.SqlExecutor, nodeRunJobID int64, serviceName string) (*sdk.ServiceLog, error) {
+func LoadServiceLog(ctx context.Context, db gorp.SqlExecutor, nodeRunJobID int64, serviceName string) (*sdk.ServiceLog, error) {
 	query := `
-		SELECT id, workflow_node_run_job_id, workflow_node_run_id, requirement_service_name, start, last_modified, value
+		SELECT id, workflow_node_run_job_id, workflow_node_run_id, requirement_service_name, start, last_modified, value, storage_chunks
 			FROM requirement_service_logs
 		WHERE workflow_node_run_job_id = $1 AND requirement_service_name = $2
 	`
 	var log sdk.ServiceLog
 	var s, m pq.NullTime
-	err := db.QueryRow(query, nodeRunJobID, serviceName).Scan(&log.ID, &log.WorkflowNodeJobRunID, &log.WorkflowNodeRunID, &log.ServiceRequirementName, &s, &m, &log.Val)
+	var chunks int64
+	err := db.QueryRow(query, nodeRunJobID, serviceName).Scan(&log.ID, &log.WorkflowNodeJobRunID, &log.WorkflowNodeRunID, &log.ServiceRequirementName, &s, &m, &log.Val, &chunks)
 	if err != nil {
 		return nil, sdk.WithStack(err)
 	}
@@ -97,13 +132,18 @@ func LoadServiceLog(db gorp.SqlExecutor, nodeRunJobID int64, serviceName string)
 		log.LastModified = &m.Time
 	}
 
+	log.Val, err = readLog(ctx, log.Val, serviceLogChunk(&log), chunks)
+	if err != nil {
+		return nil, err
+	}
+
 	return &log, nil
 }
 
 // LoadServicesLogsByJob retrieves services logs for a run
-func LoadServicesLogsByJob(db gorp.SqlExecutor, nodeJobRunID int64) ([]sdk.ServiceLog, error) {
+func LoadServicesLogsByJob(ctx context.Context, db gorp.SqlExecutor, nodeJobRunID int64) ([]sdk.ServiceLog, error) {
 	query := `
-		SELECT id, workflow_node_run_job_id, workflow_node_run_id, requirement_service_name, start, last_modified, value
+		SELECT id, workflow_node_run_job_id, workflow_node_run_id, requirement_service_name, start, last_modified, value, storage_chunks
 			FROM requirement_service_logs
 		WHERE workflow_node_run_job_id = $1
 	`
@@ -117,8 +157,9 @@ func LoadServicesLogsByJob(db gorp.SqlExecutor, nodeJobRunID int64) ([]sdk.Servi
 	for rows.Next() {
 		var log sdk.ServiceLog
 		var s, m pq.NullTime
+		var chunks int64
 
-		errS := rows.Scan(&log.ID, &log.WorkflowNodeJobRunID, &log.WorkflowNodeRunID, &log.ServiceRequirementName, &s, &m, &log.Val)
+		errS := rows.Scan(&log.ID, &log.WorkflowNodeJobRunID, &log.WorkflowNodeRunID, &log.ServiceRequirementName, &s, &m, &log.Val, &chunks)
 		if errS != nil {
 			return nil, sdk.WithStack(errS)
 		}
@@ -130,6 +171,11 @@ func LoadServicesLogsByJob(db gorp.SqlExecutor, nodeJobRunID int64) ([]sdk.Servi
 			log.LastModified = &m.Time
 		}
 
+		log.Val, err = readLog(ctx, log.Val, serviceLogChunk(&log), chunks)
+		if err != nil {
+			return nil, err
+		}
+
 		logs = append(logs, log)
 	}
 
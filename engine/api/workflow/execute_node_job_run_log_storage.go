@@ -0,0 +1,246 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Log storage modes
+const (
+	LogStorageDatabase    = "database"
+	LogStorageObjectStore = "objectstore"
+)
+
+// LogStorage stores the content of step and service logs outside of the database.
+// Each flush of a log is stored as a new chunk, the number of chunks and their
+// size are kept in database with the log.
+type LogStorage interface {
+	StoreLogChunk(ctx context.Context, c LogChunk, data io.ReadCloser) error
+	FetchLogChunk(ctx context.Context, c LogChunk) (io.ReadCloser, error)
+	DeleteLogChunk(ctx context.Context, c LogChunk) error
+	DeleteNodeRunLogs(ctx context.Context, nodeRunID int64) error
+}
+
+// logStorage is nil when logs are stored in database
+var logStorage LogStorage
+
+// SetLogStorage sets the backend used to store new logs, logs are stored in database if nil.
+func SetLogStorage(s LogStorage) {
+	logStorage = s
+}
+
+// LogChunk is a part of a step or a service log stored outside of the database.
+type LogChunk struct {
+	NodeRunID   int64
+	JobID       int64
+	StepOrder   int64
+	ServiceName string
+	Index       int64
+}
+
+// GetName returns the name of the chunk object.
+func (c LogChunk) GetName() string {
+	if c.ServiceName != "" {
+		return fmt.Sprintf("%d-service-%s-%d", c.JobID, url.PathEscape(c.ServiceName), c.Index)
+	}
+	return fmt.Sprintf("%d-step-%d-%d", c.JobID, c.StepOrder, c.Index)
+}
+
+// GetPath returns the container of the chunk object, all the logs of a node run share the same container.
+func (c LogChunk) GetPath() string {
+	return logContainerPath(c.NodeRunID)
+}
+
+func logContainerPath(nodeRunID int64) string {
+	return fmt.Sprintf("logs-%d", nodeRunID)
+}
+
+// NewObjectStoreLogStorage returns a log storage that uses given objectstore driver.
+func NewObjectStoreLogStorage(driver objectstore.Driver) LogStorage {
+	return &objectStoreLogStorage{driver: driver}
+}
+
+type objectStoreLogStorage struct {
+	driver objectstore.Driver
+}
+
+func (s *objectStoreLogStorage) StoreLogChunk(ctx context.Context, c LogChunk, data io.ReadCloser) error {
+	_, err := s.driver.Store(c, data)
+	return sdk.WrapError(err, "cannot store log chunk %s/%s", c.GetPath(), c.GetName())
+}
+
+func (s *objectStoreLogStorage) FetchLogChunk(ctx context.Context, c LogChunk) (io.ReadCloser, error) {
+	r, err := s.driver.Fetch(ctx, c)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot fetch log chunk %s/%s", c.GetPath(), c.GetName())
+	}
+	return r, nil
+}
+
+func (s *objectStoreLogStorage) DeleteLogChunk(ctx context.Context, c LogChunk) error {
+	return sdk.WrapError(s.driver.Delete(ctx, c), "cannot delete log chunk %s/%s", c.GetPath(), c.GetName())
+}
+
+func (s *objectStoreLogStorage) DeleteNodeRunLogs(ctx context.Context, nodeRunID int64) error {
+	return sdk.WrapError(s.driver.DeleteContainer(ctx, logContainerPath(nodeRunID)), "cannot delete logs of node run %d", nodeRunID)
+}
+
+// chunksReader reads sequentially all the chunks of a log, chunks are fetched only when needed.
+type chunksReader struct {
+	ctx     context.Context
+	storage LogStorage
+	next    LogChunk
+	count   int64
+	current io.ReadCloser
+}
+
+func (r *chunksReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.next.Index >= r.count {
+				return 0, io.EOF
+			}
+			var err error
+			r.current, err = r.storage.FetchLogChunk(r.ctx, r.next)
+			if err != nil {
+				return 0, err
+			}
+			r.next.Index++
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			_ = r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *chunksReader) Close() error {
+	if r.current != nil {
+		return r.current.Close()
+	}
+	return nil
+}
+
+// openLog returns a reader on a log content, the part stored in database is read first then the chunks
+// from first.Index to count.
+func openLog(ctx context.Context, value string, first LogChunk, count int64) (io.ReadCloser, error) {
+	if first.Index >= count {
+		return ioutil.NopCloser(strings.NewReader(value)), nil
+	}
+	if logStorage == nil {
+		return nil, sdk.WithStack(fmt.Errorf("log storage is not configured, cannot read %d chunks of %s", count-first.Index, first.GetPath()))
+	}
+	chunks := &chunksReader{ctx: ctx, storage: logStorage, next: first, count: count}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(strings.NewReader(value), chunks), chunks}, nil
+}
+
+// readLog returns a log content, the part stored in database is read first.
+func readLog(ctx context.Context, value string, first LogChunk, count int64) (string, error) {
+	if first.Index >= count {
+		return value, nil
+	}
+	r, err := openLog(ctx, value, first, count)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close() // nolint
+	btes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", sdk.WithStack(err)
+	}
+	return string(btes), nil
+}
+
+// storeLogChunk increments the chunk count of a log then stores its new chunk.
+func storeLogChunk(ctx context.Context, db gorp.SqlExecutor, query string, c LogChunk, data string, args ...interface{}) error {
+	if data == "" {
+		return nil
+	}
+	if err := db.QueryRow(query, append(args, len(data))...).Scan(&c.Index); err != nil {
+		return sdk.WithStack(err)
+	}
+	c.Index-- // the query returns the new count of chunks
+	return logStorage.StoreLogChunk(ctx, c, ioutil.NopCloser(strings.NewReader(data)))
+}
+
+// compactLogChunks stores the content of the chunks from first.Index to count as a single chunk at index count.
+func compactLogChunks(ctx context.Context, first LogChunk, count int64) error {
+	r, err := openLog(ctx, "", first, count)
+	if err != nil {
+		return err
+	}
+	defer r.Close() // nolint
+	c := first
+	c.Index = count
+	return logStorage.StoreLogChunk(ctx, c, r)
+}
+
+// deleteLogChunks removes the chunks from first.Index to count from the log storage, errors are only logged.
+func deleteLogChunks(ctx context.Context, first LogChunk, count int64) {
+	for c := first; c.Index < count; c.Index++ {
+		if err := logStorage.DeleteLogChunk(ctx, c); err != nil {
+			log.Error(ctx, "deleteLogChunks> %v", err)
+		}
+	}
+}
+
+// DeleteNodeRunLogs removes the chunks of all the logs of a node run from the log storage.
+func DeleteNodeRunLogs(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) error {
+	var chunks []LogChunk
+
+	rows, err := db.Query(`
+		SELECT workflow_node_run_job_id, step_order, '', storage_first_chunk, storage_chunks
+		FROM workflow_node_run_job_logs
+		WHERE workflow_node_run_id = $1 AND storage_chunks > 0
+		UNION ALL
+		SELECT workflow_node_run_job_id, 0, requirement_service_name, 0, storage_chunks
+		FROM requirement_service_logs
+		WHERE workflow_node_run_id = $1 AND storage_chunks > 0`, nodeRunID)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer rows.Close() // nolint
+	for rows.Next() {
+		c := LogChunk{NodeRunID: nodeRunID}
+		var count int64
+		if err := rows.Scan(&c.JobID, &c.StepOrder, &c.ServiceName, &c.Index, &count); err != nil {
+			return sdk.WithStack(err)
+		}
+		for ; c.Index < count; c.Index++ {
+			chunks = append(chunks, c)
+		}
+	}
+
+	if len(chunks) == 0 {
+		return nil
+	}
+	if logStorage == nil {
+		return sdk.WithStack(fmt.Errorf("log storage is not configured, cannot delete %d log chunks of node run %d", len(chunks), nodeRunID))
+	}
+
+	for _, c := range chunks {
+		if err := logStorage.DeleteLogChunk(ctx, c); err != nil {
+			log.Error(ctx, "DeleteNodeRunLogs> %v", err)
+		}
+	}
+	return logStorage.DeleteNodeRunLogs(ctx, nodeRunID)
+}
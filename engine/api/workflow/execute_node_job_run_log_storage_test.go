@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+)
+
+func Test_openLog(t *testing.T) {
+	basedir, err := ioutil.TempDir("", "logs")
+	require.NoError(t, err)
+	defer os.RemoveAll(basedir) // nolint
+
+	driver, err := objectstore.Init(context.TODO(), objectstore.Config{
+		Kind:    objectstore.Filesystem,
+		Options: objectstore.ConfigOptions{Filesystem: objectstore.ConfigOptionsFilesystem{Basedir: basedir}},
+	})
+	require.NoError(t, err)
+
+	SetLogStorage(NewObjectStoreLogStorage(driver))
+	defer SetLogStorage(nil)
+
+	first := LogChunk{NodeRunID: 1, JobID: 2, StepOrder: 3}
+	for i, data := range []string{"hello ", "", "world"} {
+		c := first
+		c.Index = int64(i)
+		require.NoError(t, logStorage.StoreLogChunk(context.TODO(), c, ioutil.NopCloser(strings.NewReader(data))))
+	}
+
+	val, err := readLog(context.TODO(), "from database\n", first, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "from database\nhello world", val)
+
+	val, err = readLog(context.TODO(), "from database\n", first, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "from database\n", val)
+
+	// A missing chunk is an error
+	_, err = readLog(context.TODO(), "", first, 4)
+	require.Error(t, err)
+
+	// Compacted chunks are read from the new first chunk
+	require.NoError(t, compactLogChunks(context.TODO(), first, 3))
+	deleteLogChunks(context.TODO(), first, 3)
+	_, err = readLog(context.TODO(), "", first, 4)
+	require.Error(t, err)
+	compacted := first
+	compacted.Index = 3
+	val, err = readLog(context.TODO(), "from database\n", compacted, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "from database\nhello world", val)
+	val, err = readLog(context.TODO(), "from database\n", compacted, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "from database\n", val)
+
+	require.NoError(t, logStorage.DeleteNodeRunLogs(context.TODO(), first.NodeRunID))
+	_, err = readLog(context.TODO(), "", compacted, 4)
+	require.Error(t, err)
+}
@@ -308,68 +308,112 @@ func executeNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 			nodeName = node.Name
 		}
 
-		//Do we release a mutex ?
-		//Try to find one node run of the same node from the same workflow at status Waiting
-		if hasMutex {
-			_, next := observability.Span(ctx, "workflow.releaseMutex")
-
-			mutexQuery := `select workflow_node_run.id
-			from workflow_node_run
-			join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
-			join workflow on workflow.id = workflow_run.workflow_id
-			where workflow.id = $1
-			and workflow_node_run.workflow_node_name = $2
-			and workflow_node_run.status = $3
-			order by workflow_node_run.start asc
-			limit 1`
-			waitingRunID, errID := db.SelectInt(mutexQuery, updatedWorkflowRun.WorkflowID, nodeName, string(sdk.StatusWaiting))
-			if errID != nil && errID != sql.ErrNoRows {
-				log.Error(ctx, "workflow.execute> Unable to load mutex-locked workflow node run ID: %v", errID)
-				return report, nil
-			}
-			//If not more run is found, stop the loop
-			if waitingRunID == 0 {
-				return report, nil
-			}
-			waitingRun, errRun := LoadNodeRunByID(db, waitingRunID, LoadRunOptions{})
-			if errRun != nil && sdk.Cause(errRun) != sql.ErrNoRows {
-				log.Error(ctx, "workflow.execute> Unable to load mutex-locked workflow rnode un: %v", errRun)
-				return report, nil
-			}
-			//If not more run is found, stop the loop
-			if waitingRun == nil {
-				return report, nil
-			}
+		//Do we delete a preview environment ?
+		if err := completeEnvironmentPreviewTeardown(ctx, db, node, nr); err != nil {
+			return nil, sdk.WrapError(err, "unable to complete preview environment teardown for node run %d", nr.ID)
+		}
 
-			//Here we are loading another workflow run
-			workflowRun, errWRun := LoadRunByID(db, waitingRun.WorkflowRunID, LoadRunOptions{})
-			if errWRun != nil {
-				log.Error(ctx, "workflow.execute> Unable to load mutex-locked workflow rnode un: %v", errWRun)
-				return report, nil
-			}
-			AddWorkflowRunInfo(workflowRun, sdk.SpawnMsg{
-				ID:   sdk.MsgWorkflowNodeMutexRelease.ID,
-				Args: []interface{}{waitingRun.WorkflowNodeName},
-				Type: sdk.MsgWorkflowNodeMutexRelease.Type,
-			})
-
-			if err := UpdateWorkflowRun(ctx, db, workflowRun); err != nil {
-				return nil, sdk.WrapError(err, "unable to update workflow run %d after mutex release", workflowRun.ID)
+		//Do we release a lock ?
+		if nodeRunLockName(updatedWorkflowRun, node) != "" {
+			r, err := releaseNodeRunLocks(ctx, db, store, proj, nr.ID)
+			report.Merge(ctx, r)
+			if err != nil {
+				return nil, sdk.WrapError(err, "unable to release locks for node run %d", nr.ID)
 			}
+		}
 
-			log.Debug("workflow.execute> process the node run %d because mutex has been released", waitingRun.ID)
-			r, err := executeNodeRun(ctx, db, store, proj, waitingRun)
+		//Do we release a mutex ?
+		//Try to find one node run of the same node from the same workflow at status Waiting
+		if hasMutex {
+			r, err := executeNextNodeRunMutex(ctx, db, store, proj, updatedWorkflowRun.WorkflowID, nodeName)
 			report.Merge(ctx, r)
 			if err != nil {
-				return nil, sdk.WrapError(err, "unable to reprocess workflow")
+				return nil, err
 			}
-
-			next()
 		}
 	}
 	return report, nil
 }
 
+// executeNextNodeRunMutex executes the first node run of given node that is waiting for the mutex to be released.
+func executeNextNodeRunMutex(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, workflowID int64, nodeName string) (*ProcessorReport, error) {
+	_, next := observability.Span(ctx, "workflow.releaseMutex")
+	defer next()
+
+	report := new(ProcessorReport)
+
+	mutexQuery := `select workflow_node_run.id
+	from workflow_node_run
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	join workflow on workflow.id = workflow_run.workflow_id
+	where workflow.id = $1
+	and workflow_node_run.workflow_node_name = $2
+	and workflow_node_run.status = $3
+	order by workflow_node_run.start asc
+	limit 1`
+	waitingRunID, errID := db.SelectInt(mutexQuery, workflowID, nodeName, string(sdk.StatusWaiting))
+	if errID != nil && errID != sql.ErrNoRows {
+		log.Error(ctx, "workflow.execute> Unable to load mutex-locked workflow node run ID: %v", errID)
+		return report, nil
+	}
+	//If not more run is found, stop the loop
+	if waitingRunID == 0 {
+		return report, nil
+	}
+	waitingRun, errRun := LoadNodeRunByID(db, waitingRunID, LoadRunOptions{})
+	if errRun != nil && sdk.Cause(errRun) != sql.ErrNoRows {
+		log.Error(ctx, "workflow.execute> Unable to load mutex-locked workflow rnode un: %v", errRun)
+		return report, nil
+	}
+	//If not more run is found, stop the loop
+	if waitingRun == nil {
+		return report, nil
+	}
+
+	//The node run will be executed when its approval gate will be approved
+	approved, err := isNodeRunApproved(ctx, db, waitingRun.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !approved {
+		return report, nil
+	}
+
+	//Here we are loading another workflow run
+	workflowRun, errWRun := LoadRunByID(db, waitingRun.WorkflowRunID, LoadRunOptions{})
+	if errWRun != nil {
+		log.Error(ctx, "workflow.execute> Unable to load mutex-locked workflow rnode un: %v", errWRun)
+		return report, nil
+	}
+	AddWorkflowRunInfo(workflowRun, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeMutexRelease.ID,
+		Args: []interface{}{waitingRun.WorkflowNodeName},
+		Type: sdk.MsgWorkflowNodeMutexRelease.Type,
+	})
+
+	//The node run could need a lock held by another workflow
+	lockAcquired, err := acquireNodeRunLock(ctx, db, workflowRun, workflowRun.Workflow.WorkflowData.NodeByID(waitingRun.WorkflowNodeID), waitingRun)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to acquire lock for node run %d", waitingRun.ID)
+	}
+
+	if err := UpdateWorkflowRun(ctx, db, workflowRun); err != nil {
+		return nil, sdk.WrapError(err, "unable to update workflow run %d after mutex release", workflowRun.ID)
+	}
+	if !lockAcquired {
+		return report, nil
+	}
+
+	log.Debug("workflow.execute> process the node run %d because mutex has been released", waitingRun.ID)
+	r, err := executeNodeRun(ctx, db, store, proj, waitingRun)
+	report.Merge(ctx, r)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to reprocess workflow")
+	}
+
+	return report, nil
+}
+
 func checkRunOnlyFailedJobs(wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun) (*sdk.WorkflowNodeRun, error) {
 	var previousNR *sdk.WorkflowNodeRun
 	nrs, ok := wr.WorkflowNodeRuns[nr.WorkflowNodeID]
@@ -489,6 +533,7 @@ jobLoop:
 			},
 			Header:          nr.Header,
 			ContainsService: containsService,
+			Priority:        sdk.ParameterValue(nr.BuildParameters, sdk.JobPriorityParameter),
 		}
 		if wm != nil {
 			wjob.ModelType = wm.Type
@@ -812,6 +857,13 @@ func stopWorkflowNodePipeline(ctx context.Context, dbFunc func() *gorp.DbMap, st
 	if errU := UpdateNodeRun(tx, nodeRun); errU != nil {
 		return report, sdk.WrapError(errU, "stopWorkflowNodePipeline> Cannot update node run")
 	}
+
+	r, err := releaseNodeRunLocks(ctx, tx, store, proj, nodeRun.ID)
+	report.Merge(ctx, r)
+	if err != nil {
+		return report, sdk.WrapError(err, "stopWorkflowNodePipeline> Cannot release locks")
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, sdk.WrapError(err, "stopWorkflowNodePipeline> Cannot commit transaction")
 	}
@@ -0,0 +1,194 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// openNodeRunApproval creates the approval gate of a node run, the node run will wait for it to be approved.
+func openNodeRunApproval(ctx context.Context, db gorp.SqlExecutor, wr *sdk.WorkflowRun, n *sdk.Node, nr *sdk.WorkflowNodeRun) error {
+	a := sdk.WorkflowNodeRunApproval{
+		ProjectID:         wr.ProjectID,
+		WorkflowID:        wr.WorkflowID,
+		WorkflowName:      wr.Workflow.Name,
+		WorkflowRunID:     wr.ID,
+		WorkflowRunNumber: wr.Number,
+		WorkflowNodeRunID: nr.ID,
+		WorkflowNodeName:  n.Name,
+		MinApprovals:      n.Context.Approval.MinApprovals,
+		Groups:            n.Context.Approval.Groups,
+		Timeout:           n.Context.Approval.Timeout,
+	}
+	if err := insertNodeRunApproval(db, &a); err != nil {
+		return sdk.WrapError(err, "unable to open approval gate for node run %d", nr.ID)
+	}
+
+	log.Debug("Noderun %s processed but not executed because of approval gate", n.Name)
+	AddWorkflowRunInfo(wr, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeApproval.ID,
+		Args: []interface{}{n.Name, a.MinApprovals},
+		Type: sdk.MsgWorkflowNodeApproval.Type,
+	})
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return sdk.WrapError(err, "unable to update workflow run")
+	}
+	return nil
+}
+
+// isNodeRunApproved returns true if the node run has no approval gate or if its gate was approved.
+func isNodeRunApproved(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) (bool, error) {
+	a, err := LoadNodeRunApprovalByNodeRunID(ctx, db, nodeRunID)
+	if err != nil {
+		return false, err
+	}
+	return a == nil || a.Status == sdk.ApprovalStatusApproved, nil
+}
+
+// AddNodeRunApprovalDecision saves the decision of a user on the approval gate of a node run. The node run is executed
+// if it reached its min approvals and stopped if the decision is a rejection.
+func AddNodeRunApprovalDecision(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, nodeRunID int64, d *sdk.WorkflowNodeRunApprovalDecision) (*sdk.WorkflowNodeRunApproval, *ProcessorReport, error) {
+	ctx, end := observability.Span(ctx, "workflow.AddNodeRunApprovalDecision")
+	defer end()
+
+	a, err := LoadAndLockNodeRunApprovalByNodeRunID(ctx, db, nodeRunID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if a == nil {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no approval gate for node run %d", nodeRunID)
+	}
+	if a.Status != sdk.ApprovalStatusPending {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrForbidden, "approval gate of pipeline %s is already %s", a.WorkflowNodeName, a.Status)
+	}
+
+	d.ApprovalID = a.ID
+	if err := insertNodeRunApprovalDecision(db, d); err != nil {
+		return nil, nil, err
+	}
+	a.Decisions = append(a.Decisions, *d)
+	a.Status = a.ComputeStatus()
+	if err := updateNodeRunApproval(db, a); err != nil {
+		return nil, nil, err
+	}
+
+	var report *ProcessorReport
+	switch a.Status {
+	case sdk.ApprovalStatusApproved:
+		report, err = executeApprovedNodeRun(ctx, db, store, proj, a)
+	case sdk.ApprovalStatusRejected:
+		report, err = rejectNodeRun(ctx, db, store, proj, a, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeApprovalRejected.ID,
+			Args: []interface{}{a.WorkflowNodeName, d.Username},
+			Type: sdk.MsgWorkflowNodeApprovalRejected.Type,
+		})
+	default:
+		report = new(ProcessorReport)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, report, nil
+}
+
+// TimeoutNodeRunApproval rejects the approval gate of a node run that was not approved within its timeout.
+func TimeoutNodeRunApproval(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, nodeRunID int64) (*ProcessorReport, error) {
+	a, err := LoadAndLockNodeRunApprovalByNodeRunID(ctx, db, nodeRunID)
+	if err != nil {
+		return nil, err
+	}
+	// The gate could have been approved or rejected in the meantime
+	if a == nil || a.Status != sdk.ApprovalStatusPending {
+		return nil, nil
+	}
+
+	a.Status = sdk.ApprovalStatusRejected
+	if err := updateNodeRunApproval(db, a); err != nil {
+		return nil, err
+	}
+
+	return rejectNodeRun(ctx, db, store, proj, a, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeApprovalTimeout.ID,
+		Args: []interface{}{a.WorkflowNodeName, (time.Duration(a.Timeout) * time.Second).String()},
+		Type: sdk.MsgWorkflowNodeApprovalTimeout.Type,
+	})
+}
+
+func executeApprovedNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, a *sdk.WorkflowNodeRunApproval) (*ProcessorReport, error) {
+	nr, err := LoadNodeRunByID(db, a.WorkflowNodeRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load node run %d", a.WorkflowNodeRunID)
+	}
+	wr, err := LoadRunByID(db, nr.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load workflow run %d", nr.WorkflowRunID)
+	}
+	n := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID)
+	if n == nil {
+		return nil, sdk.WithStack(sdk.ErrWorkflowNodeNotFound)
+	}
+
+	AddWorkflowRunInfo(wr, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeApprovalApproved.ID,
+		Args: []interface{}{a.WorkflowNodeName},
+		Type: sdk.MsgWorkflowNodeApprovalApproved.Type,
+	})
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return nil, sdk.WrapError(err, "unable to update workflow run")
+	}
+
+	return executeNodeRunIfFree(ctx, db, store, proj, wr, n, nr)
+}
+
+// rejectNodeRun stops a node run waiting for its approval gate, then executes the next node run waiting for the mutex.
+func rejectNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, a *sdk.WorkflowNodeRunApproval, msg sdk.SpawnMsg) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
+	nr, err := LoadNodeRunByID(db, a.WorkflowNodeRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load node run %d", a.WorkflowNodeRunID)
+	}
+	if nr.Status != sdk.StatusWaiting {
+		return report, nil
+	}
+
+	stopWorkflowNodeRunStages(ctx, db, nr)
+	nr.Status = sdk.StatusStopped
+	nr.Done = time.Now()
+	if err := UpdateNodeRun(db, nr); err != nil {
+		return nil, sdk.WrapError(err, "unable to update node run %d", nr.ID)
+	}
+	report.Add(ctx, *nr)
+
+	wr, err := LoadRunByID(db, nr.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load workflow run %d", nr.WorkflowRunID)
+	}
+	AddWorkflowRunInfo(wr, msg)
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return nil, sdk.WrapError(err, "unable to update workflow run")
+	}
+
+	r, err := ResyncWorkflowRunStatus(ctx, db, wr)
+	report.Merge(ctx, r)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to resync workflow run status")
+	}
+
+	n := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID)
+	if n != nil && n.Context != nil && n.Context.Mutex {
+		r, err := executeNextNodeRunMutex(ctx, db, store, proj, wr.WorkflowID, n.Name)
+		report.Merge(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
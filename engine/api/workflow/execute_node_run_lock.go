@@ -0,0 +1,209 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// nodeRunLockName returns the name of the lock needed to run given node, from the node
+// context or from its environment.
+func nodeRunLockName(wr *sdk.WorkflowRun, n *sdk.Node) string {
+	if n == nil || n.Context == nil {
+		return ""
+	}
+	if n.Context.Lock != "" {
+		return n.Context.Lock
+	}
+	if n.Context.EnvironmentID != 0 {
+		return wr.Workflow.Environments[n.Context.EnvironmentID].Lock
+	}
+	return ""
+}
+
+// acquireNodeRunLock tries to acquire the lock needed by given node run. If the lock is held by another node run
+// the node run is queued and a spawn info is added on the workflow run.
+func acquireNodeRunLock(ctx context.Context, db gorp.SqlExecutor, wr *sdk.WorkflowRun, n *sdk.Node, nr *sdk.WorkflowNodeRun) (bool, error) {
+	name := nodeRunLockName(wr, n)
+	if name == "" {
+		return true, nil
+	}
+
+	if err := lockNodeRunLockName(db, wr.ProjectID, name); err != nil {
+		return false, err
+	}
+
+	l, err := loadNodeRunLockByNodeRunIDAndName(ctx, db, nr.ID, name)
+	if err != nil {
+		return false, err
+	}
+	if l == nil {
+		l = &sdk.WorkflowNodeRunLock{
+			ProjectID:         wr.ProjectID,
+			Name:              name,
+			WorkflowID:        wr.WorkflowID,
+			WorkflowName:      wr.Workflow.Name,
+			WorkflowRunID:     wr.ID,
+			WorkflowRunNumber: wr.Number,
+			WorkflowNodeRunID: nr.ID,
+			WorkflowNodeName:  nr.WorkflowNodeName,
+		}
+		if err := insertNodeRunLock(db, l); err != nil {
+			return false, err
+		}
+	}
+	if l.Held {
+		return true, nil
+	}
+
+	holder, _, err := loadNodeRunLockNextHolder(ctx, db, wr.ProjectID, name, nr.ID)
+	if err != nil {
+		return false, err
+	}
+	// The node run waits if the lock is held, or if an older node run waits for it
+	if holder != nil && holder.ID != l.ID {
+		log.Debug("acquireNodeRunLock> node run %d is waiting for lock %s held by node run %d", nr.ID, name, holder.WorkflowNodeRunID)
+		AddWorkflowRunInfo(wr, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeLock.ID,
+			Args: []interface{}{nr.WorkflowNodeName, name, holder.WorkflowName, holder.WorkflowRunNumber},
+			Type: sdk.MsgWorkflowNodeLock.Type,
+		})
+		return false, nil
+	}
+
+	l.Held = true
+	if err := updateNodeRunLock(db, l); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// releaseNodeRunLocks releases all the locks held or waited by given node run, then executes the next node runs
+// that were waiting for the released locks.
+func releaseNodeRunLocks(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, nodeRunID int64) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
+	ls, err := loadNodeRunLocksByNodeRunID(ctx, db, nodeRunID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ls {
+		if err := lockNodeRunLockName(db, ls[i].ProjectID, ls[i].Name); err != nil {
+			return nil, err
+		}
+		if err := deleteNodeRunLock(db, &ls[i]); err != nil {
+			return nil, err
+		}
+		if !ls[i].Held {
+			continue
+		}
+		r, err := executeNextNodeRunLock(ctx, db, store, proj, ls[i].Name)
+		report.Merge(ctx, r)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// ReleaseNodeRunLock force the release of given lock for a project, then executes the next node run
+// that was waiting for it.
+func ReleaseNodeRunLock(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, name string) (*ProcessorReport, error) {
+	if err := lockNodeRunLockName(db, proj.ID, name); err != nil {
+		return nil, err
+	}
+
+	holder, err := loadNodeRunLockHolder(ctx, db, proj.ID, name)
+	if err != nil {
+		return nil, err
+	}
+	if holder == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "lock %s is not held", name)
+	}
+	if holder.Held {
+		if err := deleteNodeRunLock(db, holder); err != nil {
+			return nil, err
+		}
+	}
+
+	return executeNextNodeRunLock(ctx, db, store, proj, name)
+}
+
+// loadNodeRunLockNextHolder returns the holder of given lock if it is held, else the first node run of its queue still
+// waiting for it with its node run. The queue entries of the node runs that were stopped or deleted while waiting are
+// removed, they would block the next node runs forever. The node run with given ID is processed by the caller, it is
+// considered waiting.
+func loadNodeRunLockNextHolder(ctx context.Context, db gorp.SqlExecutor, projectID int64, name string, processedNodeRunID int64) (*sdk.WorkflowNodeRunLock, *sdk.WorkflowNodeRun, error) {
+	for {
+		next, err := loadNodeRunLockHolder(ctx, db, projectID, name)
+		if err != nil || next == nil || next.Held {
+			return next, nil, err
+		}
+		if next.WorkflowNodeRunID == processedNodeRunID {
+			return next, nil, nil
+		}
+
+		waitingRun, err := LoadNodeRunByID(db, next.WorkflowNodeRunID, LoadRunOptions{})
+		if err != nil && sdk.Cause(err) != sql.ErrNoRows {
+			return nil, nil, sdk.WrapError(err, "unable to load node run %d waiting for lock %s", next.WorkflowNodeRunID, name)
+		}
+		if err == nil && waitingRun.Status == sdk.StatusWaiting {
+			return next, waitingRun, nil
+		}
+
+		log.Info(ctx, "loadNodeRunLockNextHolder> node run %d is no longer waiting for lock %s, removed from its queue", next.WorkflowNodeRunID, name)
+		if err := deleteNodeRunLock(db, next); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// executeNextNodeRunLock gives the lock to the first waiting node run and executes it.
+func executeNextNodeRunLock(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, name string) (*ProcessorReport, error) {
+	_, end := observability.Span(ctx, "workflow.executeNextNodeRunLock")
+	defer end()
+
+	report := new(ProcessorReport)
+
+	next, waitingRun, err := loadNodeRunLockNextHolder(ctx, db, proj.ID, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	// If the lock is free or already held, there is nothing to do
+	if next == nil || next.Held {
+		return report, nil
+	}
+
+	next.Held = true
+	if err := updateNodeRunLock(db, next); err != nil {
+		return nil, err
+	}
+
+	workflowRun, err := LoadRunByID(db, waitingRun.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load workflow run %d waiting for lock %s", waitingRun.WorkflowRunID, name)
+	}
+	AddWorkflowRunInfo(workflowRun, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeLockRelease.ID,
+		Args: []interface{}{waitingRun.WorkflowNodeName, name},
+		Type: sdk.MsgWorkflowNodeLockRelease.Type,
+	})
+	if err := UpdateWorkflowRun(ctx, db, workflowRun); err != nil {
+		return nil, sdk.WrapError(err, "unable to update workflow run %d after lock release", workflowRun.ID)
+	}
+
+	log.Debug("executeNextNodeRunLock> process the node run %d because lock %s has been released", waitingRun.ID, name)
+	r, err := executeNodeRun(ctx, db, store, proj, waitingRun)
+	report.Merge(ctx, r)
+	if err != nil {
+		return report, sdk.WrapError(err, "unable to execute node run %d", waitingRun.ID)
+	}
+	return report, nil
+}
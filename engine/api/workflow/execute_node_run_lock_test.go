@@ -0,0 +1,26 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_nodeRunLockName(t *testing.T) {
+	wr := &sdk.WorkflowRun{
+		Workflow: sdk.Workflow{
+			Environments: map[int64]sdk.Environment{
+				1: {ID: 1, Name: "production", Lock: "env-prod"},
+				2: {ID: 2, Name: "staging"},
+			},
+		},
+	}
+
+	assert.Equal(t, "", nodeRunLockName(wr, nil))
+	assert.Equal(t, "", nodeRunLockName(wr, &sdk.Node{Context: &sdk.NodeContext{}}))
+	assert.Equal(t, "", nodeRunLockName(wr, &sdk.Node{Context: &sdk.NodeContext{EnvironmentID: 2}}))
+	assert.Equal(t, "env-prod", nodeRunLockName(wr, &sdk.Node{Context: &sdk.NodeContext{EnvironmentID: 1}}))
+	assert.Equal(t, "deploy", nodeRunLockName(wr, &sdk.Node{Context: &sdk.NodeContext{EnvironmentID: 1, Lock: "deploy"}}))
+}
@@ -5,7 +5,7 @@ import (
 	"time"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
-
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -58,6 +58,7 @@ type NodeRun struct {
 	HookExecutionTimestamp sql.NullInt64  `db:"hook_execution_timestamp"`
 	ExecutionID            sql.NullString `db:"execution_id"`
 	Callback               sql.NullString `db:"callback"`
+	Outputs                sql.NullString `db:"outputs"`
 }
 
 // JobRun is a gorp wrapper around sdk.WorkflowNodeJobRun
@@ -79,6 +80,7 @@ type JobRun struct {
 	ContainsService           bool           `db:"contains_service"`
 	ModelType                 sql.NullString `db:"model_type"`
 	Header                    sql.NullString `db:"header"`
+	Priority                  string         `db:"priority"`
 }
 
 // ToJobRun transform the JobRun with data of the provided sdk.WorkflowNodeJobRun
@@ -102,6 +104,7 @@ func (j *JobRun) ToJobRun(jr *sdk.WorkflowNodeJobRun) (err error) {
 	j.Model = jr.Model
 	j.ModelType = sql.NullString{Valid: true, String: string(jr.ModelType)}
 	j.ContainsService = jr.ContainsService
+	j.Priority = jr.Priority
 	j.ExecGroups, err = gorpmapping.JSONToNullString(jr.ExecGroups)
 	if err != nil {
 		return sdk.WrapError(err, "column exec_groups")
@@ -131,6 +134,7 @@ func (j JobRun) WorkflowNodeRunJob() (sdk.WorkflowNodeJobRun, error) {
 		Done:              j.Done,
 		BookedBy:          j.BookedBy,
 		ContainsService:   j.ContainsService,
+		Priority:          j.Priority,
 	}
 	if err := gorpmapping.JSONNullString(j.Job, &jr.Job); err != nil {
 		return jr, sdk.WrapError(err, "column job")
@@ -198,6 +202,14 @@ type dbNodeHookData sdk.NodeHook
 
 type dbAsCodeEvents sdk.AsCodeEvent
 
+type dbNodeRunLock sdk.WorkflowNodeRunLock
+
+type dbNodeRunApproval sdk.WorkflowNodeRunApproval
+
+type dbNodeRunApprovalDecision sdk.WorkflowNodeRunApprovalDecision
+
+type dbEnvironmentPreview sdk.EnvironmentPreview
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(Workflow{}, "workflow", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Run{}, "workflow_run", true, "id"))
@@ -219,4 +231,10 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(dbNodeOutGoingHookData{}, "w_node_outgoing_hook", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeJoinData{}, "w_node_join", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbAsCodeEvents{}, "as_code_events", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbNodeRunLock{}, "workflow_node_run_lock", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbNodeRunApproval{}, "workflow_node_run_approval", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbNodeRunApprovalDecision{}, "workflow_node_run_approval_decision", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbEnvironmentPreview{}, "environment_preview", true, "id"))
+	secret.RegisterColumn(secret.Column{Table: "w_node_hook", Key: "id", Name: "config", JSON: true})
+	secret.RegisterColumn(secret.Column{Table: "workflow_notification", Key: "id", Name: "settings", JSON: true})
 }
@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-gorp/gorp"
 
@@ -12,6 +13,10 @@ import (
 
 const maxRetry = 3
 
+// timedOutJobGracePeriod is the delay given to a worker to send its result after the job timeout
+// before the API fails the job by itself.
+const timedOutJobGracePeriod = 5 * time.Minute
+
 // manageDeadJob restart all jobs which are building but without worker
 func manageDeadJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store) error {
 	db := DBFunc()
@@ -28,7 +33,13 @@ func manageDeadJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.S
 		}
 
 		if deadJob.Status == sdk.StatusBuilding {
-			if deadJob.Retry >= maxRetry {
+			restarted, err := RestartLostNodeJobRun(ctx, tx, deadJob)
+			if err != nil {
+				log.Warning(ctx, "manageDeadJob> Cannot restart node job run %d: %v", deadJob.ID, err)
+				_ = tx.Rollback()
+				continue
+			}
+			if !restarted {
 				if _, err := UpdateNodeJobRunStatus(ctx, tx, store, sdk.Project{}, &deadJob, sdk.StatusStopped); err != nil {
 					log.Error(ctx, "manageDeadJob> Cannot update node run job %d : %v", deadJob.ID, err)
 					_ = tx.Rollback()
@@ -40,12 +51,6 @@ func manageDeadJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.S
 					_ = tx.Rollback()
 					continue
 				}
-			} else {
-				if err := RestartWorkflowNodeJob(ctx, tx, deadJob); err != nil {
-					log.Warning(ctx, "manageDeadJob> Cannot restart node job run %d: %v", deadJob.ID, err)
-					_ = tx.Rollback()
-					continue
-				}
 			}
 		} else if sdk.StatusIsTerminated(deadJob.Status) {
 			if err := DeleteNodeJobRun(tx, deadJob.ID); err != nil {
@@ -62,3 +67,51 @@ func manageDeadJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.S
 
 	return nil
 }
+
+// manageTimedOutJob fails all jobs which are building since more than their timeout
+func manageTimedOutJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store) error {
+	db := DBFunc()
+	jobs, err := LoadTimedOutNodeJobRun(ctx, db, store, timedOutJobGracePeriod)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load timed out node job run")
+	}
+
+	for _, j := range jobs {
+		tx, err := db.Begin()
+		if err != nil {
+			log.Error(ctx, "manageTimedOutJob> cannot create transaction: %v", err)
+			continue
+		}
+
+		// lock the job to prevent concurrent update with the worker result
+		job, err := LoadAndLockNodeJobRunSkipLocked(ctx, tx, store, j.ID)
+		if err != nil {
+			log.Warning(ctx, "manageTimedOutJob> cannot lock node job run %d: %v", j.ID, err)
+			_ = tx.Rollback()
+			continue
+		}
+
+		infos := []sdk.SpawnInfo{{
+			RemoteTime: time.Now(),
+			Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobTimeout.ID, Args: []interface{}{job.Job.Action.TimeoutDuration().String()}},
+		}}
+		if err := AddSpawnInfosNodeJobRun(tx, job.ID, PrepareSpawnInfos(infos)); err != nil {
+			log.Error(ctx, "manageTimedOutJob> cannot save spawn info on node job run %d: %v", job.ID, err)
+			_ = tx.Rollback()
+			continue
+		}
+
+		job.Job.Reason = sdk.MsgSpawnInfoJobTimeout.Format[sdk.EN]
+		if _, err := UpdateNodeJobRunStatus(ctx, tx, store, sdk.Project{}, job, sdk.StatusFail); err != nil {
+			log.Error(ctx, "manageTimedOutJob> cannot update node job run %d: %v", job.ID, err)
+			_ = tx.Rollback()
+			continue
+		}
+
+		if err := tx.Commit(); err != nil {
+			log.Error(ctx, "manageTimedOutJob> cannot commit transaction: %v", err)
+		}
+	}
+
+	return nil
+}
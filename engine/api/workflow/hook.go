@@ -13,6 +13,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -121,6 +122,7 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 			previousHook, has := oldHooksByRef[h.Ref()]
 			if has {
 				h.UUID = previousHook.UUID
+				keepWebHookSecret(h, previousHook)
 				// If previous hook is the same, we do nothing
 				if h.Equals(previousHook) {
 					continue
@@ -129,6 +131,9 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 		} else if oldHooks != nil {
 			// search previous hook configuration by uuid
 			previousHook, has := oldHooks[h.UUID]
+			if has {
+				keepWebHookSecret(h, *previousHook)
+			}
 			// If previous hook is the same, we do nothing
 			if has && h.Equals(*previousHook) {
 				continue
@@ -139,6 +144,12 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 			h.UUID = sdk.UUID()
 		}
 
+		if h.HookModelName == sdk.RepositoryWebHookModelName && h.Config[sdk.HookConfigWebHookSecret].Value == "" {
+			if err := generateWebHookSecret(h); err != nil {
+				return err
+			}
+		}
+
 		if h.HookModelName == sdk.RepositoryWebHookModelName || h.HookModelName == sdk.GitPollerModelName || h.HookModelName == sdk.GerritHookModelName {
 			if wf.WorkflowData.Node.Context.ApplicationID == 0 || wf.Applications[wf.WorkflowData.Node.Context.ApplicationID].RepositoryFullname == "" || wf.Applications[wf.WorkflowData.Node.Context.ApplicationID].VCSServer == "" {
 				return sdk.NewErrorFrom(sdk.ErrForbidden, "cannot create a git poller or repository webhook on an application without a repository")
@@ -160,6 +171,21 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 	}
 
 	if len(hookToUpdate) > 0 {
+		// The hooks µservice needs the clear webhook secrets to check the signature of the incoming requests
+		encryptedSecrets := make(map[string]sdk.WorkflowNodeHookConfigValue)
+		for uuid, h := range hookToUpdate {
+			v, has := h.Config[sdk.HookConfigWebHookSecret]
+			if !has {
+				continue
+			}
+			encryptedSecrets[uuid] = v
+			h.Config = h.Config.Clone()
+			if err := DecryptWebHookSecret(&h); err != nil {
+				return err
+			}
+			hookToUpdate[uuid] = h
+		}
+
 		// Create hook on µservice
 		_, code, errHooks := services.NewClient(db, srvs).DoJSONRequest(ctx, http.MethodPost, "/task/bulk", hookToUpdate, &hookToUpdate)
 		if errHooks != nil || code >= 400 {
@@ -169,6 +195,9 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 		hooks := wf.WorkflowData.GetHooks()
 		for i := range hookToUpdate {
 			hooks[i].Config = hookToUpdate[i].Config
+			if v, has := encryptedSecrets[i]; has {
+				hooks[i].Config[sdk.HookConfigWebHookSecret] = v
+			}
 		}
 
 		// Create vcs configuration ( always after hook creation to have webhook URL) + update hook in DB
@@ -309,12 +338,18 @@ func createVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 
 	}
 
+	hookSecret, err := webHookSecret(*h)
+	if err != nil {
+		return err
+	}
+
 	// Prepare the hook that will be send to VCS
 	vcsHook := sdk.VCSHook{
 		Method:   "POST",
 		URL:      h.Config["webHookURL"].Value,
 		Events:   valueSplitted,
 		Workflow: true,
+		Secret:   hookSecret,
 	}
 	if err := client.CreateHook(ctx, h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "Cannot create hook on repository: %+v", vcsHook)
@@ -361,12 +396,18 @@ func updateVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 		valueSlitted = strings.Split(valueEvent, ";")
 	}
 
+	hookSecret, err := webHookSecret(*h)
+	if err != nil {
+		return err
+	}
+
 	vcsHook := sdk.VCSHook{
 		ID:       h.Config[sdk.HookConfigWebHookID].Value,
 		Method:   "POST",
 		URL:      h.Config["webHookURL"].Value,
 		Events:   valueSlitted,
 		Workflow: true,
+		Secret:   hookSecret,
 	}
 	if err := client.UpdateHook(ctx, h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "Cannot update hook on repository: %+v", vcsHook)
@@ -384,6 +425,64 @@ func updateVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 	return nil
 }
 
+// keepWebHookSecret copies the secret of the previous repository webhook, the secret is
+// never sent to users so it is missing on a workflow update.
+func keepWebHookSecret(h *sdk.NodeHook, previousHook sdk.NodeHook) {
+	if h.HookModelName != sdk.RepositoryWebHookModelName {
+		return
+	}
+	if _, has := h.Config[sdk.HookConfigWebHookSecret]; has {
+		return
+	}
+	if v, has := previousHook.Config[sdk.HookConfigWebHookSecret]; has {
+		h.Config[sdk.HookConfigWebHookSecret] = v
+	}
+}
+
+// generateWebHookSecret sets a new random secret on a repository webhook, it is stored encrypted in the hook config.
+func generateWebHookSecret(h *sdk.NodeHook) error {
+	clearSecret, err := sdk.GenerateHash()
+	if err != nil {
+		return err
+	}
+	encryptedSecret, err := secret.EncryptValue(clearSecret)
+	if err != nil {
+		return sdk.WrapError(err, "cannot encrypt webhook secret")
+	}
+	h.Config[sdk.HookConfigWebHookSecret] = sdk.WorkflowNodeHookConfigValue{
+		Value:        encryptedSecret,
+		Configurable: false,
+		Type:         sdk.HookConfigTypeString,
+	}
+	return nil
+}
+
+// webHookSecret returns the clear secret of a repository webhook or an empty string if the hook has no secret.
+func webHookSecret(h sdk.NodeHook) (string, error) {
+	v, has := h.Config[sdk.HookConfigWebHookSecret]
+	if !has || v.Value == "" {
+		return "", nil
+	}
+	clearSecret, err := secret.DecryptValue(v.Value)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot decrypt secret of webhook %s", h.UUID)
+	}
+	return clearSecret, nil
+}
+
+// DecryptWebHookSecret replaces the encrypted webhook secret of a hook config by its clear value.
+// The config should be cloned before if it is shared with a workflow.
+func DecryptWebHookSecret(h *sdk.NodeHook) error {
+	clearSecret, err := webHookSecret(*h)
+	if err != nil || clearSecret == "" {
+		return err
+	}
+	v := h.Config[sdk.HookConfigWebHookSecret]
+	v.Value = clearSecret
+	h.Config[sdk.HookConfigWebHookSecret] = v
+	return nil
+}
+
 // DefaultPayload returns the default payload for the workflow root
 func DefaultPayload(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, wf *sdk.Workflow) (interface{}, error) {
 	if wf.WorkflowData.Node.Context == nil || wf.WorkflowData.Node.Context.ApplicationID == 0 {
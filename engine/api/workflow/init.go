@@ -34,6 +34,9 @@ func Initialize(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Stor
 			if err := manageDeadJob(ctx, DBFunc, store); err != nil {
 				log.Warning(ctx, "workflow.manageDeadJob> Error on restartDeadJob : %v", err)
 			}
+			if err := manageTimedOutJob(ctx, DBFunc, store); err != nil {
+				log.Warning(ctx, "workflow.manageTimedOutJob> Error on manageTimedOutJob : %v", err)
+			}
 		case <-tickStop.C:
 			if err := stopRunsBlocked(ctx, db); err != nil {
 				log.Warning(ctx, "workflow.stopRunsBlocked> Error on stopRunsBlocked : %v", err)
@@ -155,6 +155,15 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 	// NODE CONTEXT BUILD PARAMETER
 	computeNodeContextBuildParameters(ctx, proj, wr, nr, n, runContext)
 
+	// PRIORITY of the jobs in the queue, given by the node or when it is run manually
+	priority := n.Context.Priority
+	if manual != nil && manual.Priority != "" {
+		priority = manual.Priority
+	}
+	if priority != "" {
+		sdk.ParameterAddOrSetValue(&nr.BuildParameters, sdk.JobPriorityParameter, sdk.StringParameter, priority)
+	}
+
 	// PARENT BUILD PARAMETER WITH git.*
 	if len(parents) > 0 {
 		_, next := observability.Span(ctx, "workflow.getParentParameters")
@@ -280,6 +289,19 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 		setValuesGitInBuildParameters(nr, *vcsInf)
 	}
 
+	// PREVIEW ENVIRONMENT
+	// Replace the environment of the node by its preview environment, the node run fails if it can't be created
+	if n.Context.PreviewEnvironment != nil && n.Context.EnvironmentID != 0 {
+		if err := resolvePreviewEnvironment(ctx, db, proj, wr, n, nr); err != nil {
+			log.Error(ctx, "processNode> unable to resolve preview environment for node %s: %v", n.Name, err)
+			AddWorkflowRunInfo(wr, sdk.SpawnMsg{
+				ID:   sdk.MsgWorkflowError.ID,
+				Args: []interface{}{sdk.Cause(err).Error()},
+				Type: sdk.MsgWorkflowError.Type,
+			})
+		}
+	}
+
 	// ADD TAG
 	// Tag VCS infos : add in tag only if it does not exist
 	if !wr.TagExists(tagGitRepository) {
@@ -342,6 +364,27 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 		return nil, false, sdk.WrapError(err, "unable to update workflow run")
 	}
 
+	//Check the context.approval to know if the node run has to be approved before being executed
+	if n.Context.Approval != nil && nr.Status == sdk.StatusWaiting {
+		if err := openNodeRunApproval(ctx, db, wr, n, nr); err != nil {
+			return nil, false, err
+		}
+		// The node run will be executed when the approval gate will be approved
+		return report, true, nil
+	}
+
+	r1, err := executeNodeRunIfFree(ctx, db, store, proj, wr, n, nr)
+	if err != nil {
+		return nil, false, err
+	}
+	report.Merge(ctx, r1)
+	return report, true, nil
+}
+
+// executeNodeRunIfFree executes the node run if its mutex and its lock are free.
+func executeNodeRunIfFree(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, wr *sdk.WorkflowRun, n *sdk.Node, nr *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
 	//Check the context.mutex to know if we are allowed to run it
 	if n.Context.Mutex {
 		//Check if there are previous waiting or builing workflownoderun
@@ -363,7 +406,7 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 		)`
 		nbMutex, err := db.SelectInt(mutexQuery, n.WorkflowID, nr.ID, n.Name, string(sdk.StatusWaiting), string(sdk.StatusBuilding))
 		if err != nil {
-			return nil, false, sdk.WrapError(err, "unable to check mutexes")
+			return nil, sdk.WrapError(err, "unable to check mutexes")
 		}
 		if nbMutex > 0 {
 			log.Debug("Noderun %s processed but not executed because of mutex", n.Name)
@@ -373,23 +416,37 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 				Type: sdk.MsgWorkflowNodeMutex.Type,
 			})
 			if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
-				return nil, false, sdk.WrapError(err, "unable to update workflow run")
+				return nil, sdk.WrapError(err, "unable to update workflow run")
 			}
 
 			// Mutex is locked, but it is as the workflow is ok to be run (conditions ok).
 			// it's ok exit without error
-			return report, true, nil
+			return report, nil
 		}
 		//Mutex is free, continue
 	}
 
+	//Check the context.lock to know if the named lock shared in the project is free
+	lockAcquired, err := acquireNodeRunLock(ctx, db, wr, n, nr)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to acquire lock")
+	}
+	if !lockAcquired {
+		log.Debug("Noderun %s processed but not executed because of lock", n.Name)
+		if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+			return nil, sdk.WrapError(err, "unable to update workflow run")
+		}
+		// Lock is held by another node run, the node run will be executed when the lock will be released
+		return report, nil
+	}
+
 	//Execute the node run !
-	r1, err := executeNodeRun(ctx, db, store, proj, nr)
+	r, err := executeNodeRun(ctx, db, store, proj, nr)
 	if err != nil {
-		return nil, false, sdk.WrapError(err, "unable to execute workflow run")
+		return nil, sdk.WrapError(err, "unable to execute workflow run")
 	}
-	report.Merge(ctx, r1)
-	return report, true, nil
+	report.Merge(ctx, r)
+	return report, nil
 }
 
 func getParentsStatus(wr *sdk.WorkflowRun, parents []*sdk.WorkflowNodeRun) string {
@@ -30,6 +30,11 @@ func getNodeJobRunParameters(db gorp.SqlExecutor, j sdk.Job, run *sdk.WorkflowNo
 		sdk.AddParameter(&params, k, sdk.StringParameter, s)
 	}
 
+	// add values of the matrix combination if the job was generated from a matrix
+	for _, p := range j.MatrixParameters() {
+		sdk.AddParameter(&params, p.Name, sdk.StringParameter, p.Value)
+	}
+
 	if errm.IsEmpty() {
 		return params, nil
 	}
@@ -186,8 +191,9 @@ func getParentParameters(w *sdk.WorkflowRun, nodeRuns []*sdk.WorkflowNodeRun) ([
 				continue
 			}
 
-			if param.Name == "payload" || strings.HasPrefix(param.Name, "cds.triggered") || strings.HasPrefix(param.Name, "cds.release") {
-				// keep p.Name as is
+			if param.Name == "payload" || strings.HasPrefix(param.Name, "cds.triggered") || strings.HasPrefix(param.Name, "cds.release") ||
+				strings.HasPrefix(param.Name, "cds.node.") {
+				// keep p.Name as is, node outputs are inherited from all the ancestors
 			} else if strings.HasPrefix(param.Name, "cds.") {
 				param.Name = strings.Replace(param.Name, "cds.", prefix, 1)
 			}
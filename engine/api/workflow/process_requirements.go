@@ -8,6 +8,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/resourceclass"
 	"github.com/ovh/cds/engine/api/workermodel"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/interpolate"
@@ -20,8 +21,11 @@ func processNodeJobRunRequirements(ctx context.Context, db gorp.SqlExecutor, j s
 	var requirements sdk.RequirementList
 	var errm sdk.MultiError
 	var containsService bool
-	var model string
+	var model, resourceClass string
 	var tmp = sdk.ParametersToMap(run.BuildParameters)
+	for _, p := range j.MatrixParameters() {
+		tmp[p.Name] = p.Value
+	}
 
 	pluginsRequirements := []sdk.Requirement{}
 	for i := range integrationPluginBinaries {
@@ -65,9 +69,28 @@ func processNodeJobRunRequirements(ctx context.Context, db gorp.SqlExecutor, j s
 			}
 		}
 
+		if v.Type == sdk.CPURequirement {
+			if _, err := sdk.ParseCPURequirement(value); err != nil {
+				errm.Append(err)
+				break
+			}
+		}
+
+		// The resource class is replaced by its cpu and memory requirements
+		if v.Type == sdk.ResourceClassRequirement {
+			resourceClass = value
+			continue
+		}
+
 		sdk.AddRequirement(&requirements, v.ID, name, v.Type, value)
 	}
 
+	if resourceClass != "" {
+		if err := processNodeJobRunRequirementsResourceClass(ctx, db, resourceClass, &requirements); err != nil {
+			errm.Append(err)
+		}
+	}
+
 	wm, err := processNodeJobRunRequirementsGetModel(ctx, db, model, execsGroupIDs)
 	if err != nil {
 		log.Error(ctx, "getNodeJobRunRequirements> error while getting worker model %s: %v", model, err)
@@ -118,6 +141,32 @@ func prepareRequirementsToNodeJobRunParameters(reqs sdk.RequirementList) []sdk.P
 	return params
 }
 
+// processNodeJobRunRequirementsResourceClass adds the cpu and memory requirements of given resource class,
+// a cpu or memory requirement set on the job is kept.
+func processNodeJobRunRequirementsResourceClass(ctx context.Context, db gorp.SqlExecutor, name string, requirements *sdk.RequirementList) error {
+	c, err := resourceclass.LoadByName(ctx, db, name)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return sdk.NewErrorFrom(sdk.ErrInvalidJobRequirement, "unknown resource class %q", name)
+	}
+
+	for _, r := range c.Requirements() {
+		var found bool
+		for _, jr := range *requirements {
+			if jr.Type == r.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			sdk.AddRequirement(requirements, 0, r.Name, r.Type, r.Value)
+		}
+	}
+	return nil
+}
+
 func processNodeJobRunRequirementsGetModel(ctx context.Context, db gorp.SqlExecutor, model string, execsGroupIDs []int64) (*sdk.Model, error) {
 	if model == "" {
 		return nil, nil
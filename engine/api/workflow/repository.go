@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"path"
 	"path/filepath"
 	"time"
 
@@ -132,8 +133,13 @@ func ReadCDSFiles(files map[string][]byte) (*tar.Reader, error) {
 	// Add some files to the archive.
 	for fname, fcontent := range files {
 		log.Debug("ReadCDSFiles> Reading %s", fname)
+		name := filepath.Base(fname)
+		// Composite actions are referenced by their path so the directory should be kept
+		if filepath.Base(filepath.Dir(fname)) == exportentities.CompositeActionsDirectory {
+			name = path.Join(exportentities.CompositeActionsDirectory, name)
+		}
 		hdr := &tar.Header{
-			Name: filepath.Base(fname),
+			Name: name,
 			Mode: 0600,
 			Size: int64(len(fcontent)),
 		}
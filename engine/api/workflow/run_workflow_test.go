@@ -632,14 +632,14 @@ queueRun:
 		assert.Len(t, secrets, 1)
 
 		//TestAddLog
-		assert.NoError(t, workflow.AddLog(db, j, &sdk.Log{
+		assert.NoError(t, workflow.AddLog(context.TODO(), db, j, &sdk.Log{
 			Val: "This is a log",
 		}, workflow.DefaultMaxLogSize))
 		if t.Failed() {
 			tx.Rollback()
 			t.FailNow()
 		}
-		assert.NoError(t, workflow.AddLog(db, j, &sdk.Log{
+		assert.NoError(t, workflow.AddLog(context.TODO(), db, j, &sdk.Log{
 			Val: "This is another log",
 		}, workflow.DefaultMaxLogSize))
 		if t.Failed() {
@@ -655,7 +655,7 @@ queueRun:
 			t.FailNow()
 		}
 
-		logs, err := workflow.LoadLogs(db, takenJob.ID)
+		logs, err := workflow.LoadLogs(context.TODO(), db, takenJob.ID)
 		assert.NoError(t, err)
 		if t.Failed() {
 			tx.Rollback()
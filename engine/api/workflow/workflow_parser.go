@@ -63,6 +63,11 @@ func ParseAndImport(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 		return nil, nil, errW
 	}
 
+	conditionMsgs, err := checkConditionsOperators(ctx, oldW, w)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Load deep pipelines if we come from workflow run ( so we have hook uuid ).
 	// We need deep pipelines to be able to run stages/jobs
 	if err := IsValid(ctx, store, db, w, proj, LoadOptions{DeepPipeline: opts.HookUUID != ""}); err != nil {
@@ -191,5 +196,32 @@ func ParseAndImport(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 		msgList = append(msgList, sdk.NewMessage(sdk.MsgWorkflowDeprecatedVersion, proj.Key, ew.GetName()))
 	}
 
+	msgList = append(msgList, conditionMsgs...)
+
 	return w, msgList, globalError
 }
+
+// checkConditionsOperators rejects the run conditions of a new workflow with an unknown operator. The conditions with
+// an unknown operator of an existing workflow are removed with a warning, they were ignored when checking the conditions.
+func checkConditionsOperators(ctx context.Context, oldW *sdk.Workflow, w *sdk.Workflow) ([]sdk.Message, error) {
+	var msgs []sdk.Message
+	for _, n := range w.WorkflowData.Array() {
+		if n.Context == nil {
+			continue
+		}
+		conditions := n.Context.Conditions.PlainConditions[:0]
+		for _, c := range n.Context.Conditions.PlainConditions {
+			if _, ok := sdk.WorkflowConditionsOperators[c.Operator]; ok {
+				conditions = append(conditions, c)
+				continue
+			}
+			if oldW == nil {
+				return nil, sdk.NewErrorFrom(sdk.ErrWorkflowConditionBadOperator, "invalid operator %q for condition on variable %s (node : %s)", c.Operator, c.Variable, n.Name)
+			}
+			log.Warning(ctx, "checkConditionsOperators> unknown operator %q for condition on variable %s of node %s in workflow %s", c.Operator, c.Variable, n.Name, w.Name)
+			msgs = append(msgs, sdk.NewMessage(sdk.MsgWorkflowConditionUnknownOperator, c.Operator, c.Variable, n.Name))
+		}
+		n.Context.Conditions.PlainConditions = conditions
+	}
+	return msgs, nil
+}
@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_checkConditionsOperators(t *testing.T) {
+	newWorkflow := func() *sdk.Workflow {
+		return &sdk.Workflow{
+			Name: "my-workflow",
+			WorkflowData: sdk.WorkflowData{
+				Node: sdk.Node{
+					Name: "build",
+					Context: &sdk.NodeContext{
+						Conditions: sdk.WorkflowNodeConditions{
+							PlainConditions: []sdk.WorkflowNodeCondition{
+								{Variable: "git.branch", Operator: "eq", Value: "master"},
+								{Variable: "cds.version", Operator: "greater", Value: "9"},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// an unknown operator is rejected for a new workflow
+	_, err := checkConditionsOperators(context.TODO(), nil, newWorkflow())
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWorkflowConditionBadOperator))
+
+	// it is removed with a warning for an existing workflow
+	w := newWorkflow()
+	msgs, err := checkConditionsOperators(context.TODO(), &sdk.Workflow{}, w)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, sdk.MsgWorkflowConditionUnknownOperator.ID, msgs[0].ID)
+	assert.Equal(t, []sdk.WorkflowNodeCondition{{Variable: "git.branch", Operator: "eq", Value: "master"}}, w.WorkflowData.Node.Context.Conditions.PlainConditions)
+}
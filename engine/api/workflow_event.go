@@ -51,6 +51,17 @@ func WorkflowSendEvent(ctx context.Context, db gorp.SqlExecutor, store cache.Sto
 		}
 
 		event.PublishWorkflowNodeRun(ctx, *nr, wr.Workflow, notification.GetUserWorkflowEvents(ctx, db, store, wr.Workflow, &previousNodeRun, *nr))
+
+		// Notify that the node run is waiting for approvals
+		if nr.Status == sdk.StatusWaiting {
+			a, err := workflow.LoadNodeRunApprovalByNodeRunID(ctx, db, nr.ID)
+			if err != nil {
+				log.Warning(ctx, "workflowSendEvent> Cannot load workflow node run approval: %v", err)
+			} else if a != nil && a.Status == sdk.ApprovalStatusPending && len(a.Decisions) == 0 {
+				event.PublishWorkflowNodeRunApproval(ctx, proj.Key, *wr, *a, nil)
+			}
+		}
+
 		e := &workflow.VCSEventMessenger{}
 		if err := e.SendVCSEvent(ctx, db, store, proj, *wr, wnr); err != nil {
 			log.Warning(ctx, "WorkflowSendEvent> Cannot send vcs notification")
@@ -27,6 +27,13 @@ func (api *API) getWorkflowHooksHandler() service.Handler {
 			return err
 		}
 
+		// The hooks µservice checks the signature of incoming repository webhooks with their secret
+		for i := range hooks {
+			if err := workflow.DecryptWebHookSecret(&hooks[i]); err != nil {
+				return err
+			}
+		}
+
 		return service.WriteJSON(w, hooks, http.StatusOK)
 	}
 }
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) getWorkflowPreviewEnvironmentsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		p, err := project.Load(api.mustDB(), api.Cache, key)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project")
+		}
+
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, *p, name, workflow.LoadOptions{Minimal: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow %s", name)
+		}
+
+		previews, err := workflow.LoadEnvironmentPreviewsByWorkflowID(ctx, api.mustDB(), wf.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, previews, http.StatusOK)
+	}
+}
+
+// deleteWorkflowPreviewEnvironmentsHandler tears down the preview environments created for a branch or a pull request,
+// it is called by the hooks service when a branch is deleted or a pull request is closed.
+func (api *API) deleteWorkflowPreviewEnvironmentsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		branch := r.FormValue("branch")
+		pullRequestID := r.FormValue("pullrequest")
+		if branch == "" && pullRequestID == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "a branch or a pull request should be given")
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project")
+		}
+
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, *p, name, workflow.LoadOptions{Minimal: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow %s", name)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		var previews []sdk.EnvironmentPreview
+		if pullRequestID != "" {
+			previews, err = workflow.LoadActiveEnvironmentPreviewsByPullRequest(ctx, tx, wf.ID, pullRequestID)
+		} else {
+			previews, err = workflow.LoadActiveEnvironmentPreviewsByBranch(ctx, tx, wf.ID, branch)
+		}
+		if err != nil {
+			return err
+		}
+
+		type teardown struct {
+			preview sdk.EnvironmentPreview
+			run     *sdk.WorkflowRun
+			node    *sdk.Node
+		}
+		teardowns := make([]teardown, 0, len(previews))
+		// Several previews can be torn down from the same workflow run, it is loaded once to keep all the changes
+		runs := make(map[int64]*sdk.WorkflowRun)
+		for i := range previews {
+			var node *sdk.Node
+			wr, ok := runs[previews[i].WorkflowRunNumber]
+			if !ok {
+				wr, err = workflow.LoadRun(ctx, tx, key, name, previews[i].WorkflowRunNumber, workflow.LoadRunOptions{})
+				if err != nil && !sdk.ErrorIs(err, sdk.ErrWorkflowNotFound) {
+					return sdk.WrapError(err, "cannot load workflow run %d", previews[i].WorkflowRunNumber)
+				}
+				runs[previews[i].WorkflowRunNumber] = wr
+			}
+			if wr != nil {
+				node, err = workflow.PrepareEnvironmentPreviewTeardown(ctx, tx, &previews[i], wr)
+				if err != nil {
+					return err
+				}
+			}
+			// Without teardown pipeline, the environment is deleted right away
+			if node == nil {
+				if err := workflow.DeleteEnvironmentPreview(ctx, tx, previews[i]); err != nil {
+					return err
+				}
+				continue
+			}
+			teardowns = append(teardowns, teardown{preview: previews[i], run: wr, node: node})
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		// Teardown pipelines are run one after the other as several previews can be torn down from the same workflow run
+		consumer := getAPIConsumer(ctx)
+		sdk.GoRoutine(context.Background(), fmt.Sprintf("api.teardownPreviewEnvironments-%s-%s", key, name), func(ctx context.Context) {
+			for _, t := range teardowns {
+				number := t.run.Number
+				t.run.Status = sdk.StatusWaiting
+				opts := &sdk.WorkflowRunPostHandlerOption{
+					Number:      &number,
+					FromNodeIDs: []int64{t.node.ID},
+					Manual: &sdk.WorkflowNodeRunManual{
+						Payload: map[string]string{
+							sdk.PreviewEnvironmentTeardownParameter: "true",
+							sdk.PreviewEnvironmentNameParameter:     t.preview.EnvironmentName,
+						},
+					},
+				}
+				log.Info(ctx, "teardownPreviewEnvironments> run pipeline %s of workflow %s/%s #%d to tear down %s", t.node.Name, key, name, number, t.preview.EnvironmentName)
+				api.initWorkflowRun(ctx, key, &t.run.Workflow, t.run, opts, consumer)
+			}
+		}, api.PanicDump())
+
+		return service.WriteJSON(w, previews, http.StatusOK)
+	}
+}
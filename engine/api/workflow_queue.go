@@ -14,6 +14,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/jobquota"
 	"github.com/ovh/cds/engine/api/metrics"
 	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/observability"
@@ -187,8 +188,16 @@ func (api *API) postBookWorkflowJobHandler() service.Handler {
 			return err
 		}
 
-		if _, err := workflow.BookNodeJobRun(ctx, api.Cache, id, s); err != nil {
+		job, err := workflow.LoadNodeJobRun(ctx, api.mustDB(), api.Cache, id)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load job nodeJobRunID: %d", id)
+		}
+
+		if err := jobquota.CheckAndBook(ctx, api.mustDB(), api.Cache, *job, func() error {
+			_, err := workflow.BookNodeJobRun(ctx, api.Cache, id, s)
 			return sdk.WrapError(err, "job already booked")
+		}); err != nil {
+			return err
 		}
 
 		return service.WriteJSON(w, nil, http.StatusOK)
@@ -302,7 +311,8 @@ func (api *API) postSpawnInfosWorkflowJobHandler() service.AsynchronousHandler {
 		}
 		defer tx.Rollback() // nolint
 
-		if _, err := workflow.LoadNodeJobRun(ctx, tx, api.Cache, id); err != nil {
+		job, err := workflow.LoadNodeJobRun(ctx, tx, api.Cache, id)
+		if err != nil {
 			if !sdk.ErrorIs(err, sdk.ErrWorkflowNodeRunJobNotFound) {
 				return err
 			}
@@ -312,14 +322,75 @@ func (api *API) postSpawnInfosWorkflowJobHandler() service.AsynchronousHandler {
 			return err
 		}
 
+		var report *workflow.ProcessorReport
+		if isSpawnError(s) && job.Status == sdk.StatusWaiting && job.Job.Action.Retry != nil &&
+			sdk.IsInArray(sdk.RetryWhenSpawnError, job.Job.Action.Retry.When) {
+			report, err = api.retryNodeJobRunOnSpawnError(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WithStack(err)
 		}
 
+		if report != nil {
+			proj, err := project.LoadProjectByNodeJobRunID(ctx, api.mustDB(), api.Cache, id)
+			if err != nil {
+				return sdk.WrapError(err, "cannot load project by nodeJobRunID: %d", id)
+			}
+			go WorkflowSendEvent(context.Background(), api.mustDB(), api.Cache, *proj, report)
+		}
+
 		return nil
 	}
 }
 
+func isSpawnError(infos []sdk.SpawnInfo) bool {
+	for _, i := range infos {
+		if i.Message.ID == sdk.MsgSpawnInfoHatcheryErrorSpawn.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// retryNodeJobRunOnSpawnError replaces in queue a job for which a hatchery failed to spawn a worker,
+// or fails it if there are no more attempts allowed by its retry policy.
+func (api *API) retryNodeJobRunOnSpawnError(ctx context.Context, tx gorp.SqlExecutor, id int64) (*workflow.ProcessorReport, error) {
+	job, err := workflow.LoadAndLockNodeJobRunSkipLocked(ctx, tx, api.Cache, id)
+	if err != nil {
+		// the job is being updated by someone else
+		log.Warning(ctx, "retryNodeJobRunOnSpawnError> cannot lock node job run %d: %v", id, err)
+		return nil, nil
+	}
+
+	if workflow.CanRetryNodeJobRun(*job, sdk.RetryWhenSpawnError, 0) {
+		if err := workflow.RetryNodeJobRun(ctx, tx, *job, sdk.RetryWhenSpawnError); err != nil {
+			return nil, sdk.WrapError(err, "cannot retry NodeJobRun %d", id)
+		}
+		retriedJob, err := workflow.LoadNodeJobRun(ctx, tx, api.Cache, id)
+		if err != nil {
+			return nil, err
+		}
+		report := new(workflow.ProcessorReport)
+		report.Add(ctx, *retriedJob)
+		return report, nil
+	}
+
+	proj, err := project.LoadProjectByNodeJobRunID(ctx, tx, api.Cache, id, project.LoadOptions.WithVariables)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load project by nodeJobRunID: %d", id)
+	}
+	job.Job.Reason = fmt.Sprintf("No worker could be spawned for the job after %d attempts", job.Retry+1)
+	report, err := workflow.UpdateNodeJobRunStatus(ctx, tx, api.Cache, *proj, job, sdk.StatusFail)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot update NodeJobRun %d status", id)
+	}
+	return report, nil
+}
+
 func (api *API) postWorkflowJobResultHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		id, err := requestVarInt(r, "permJobID")
@@ -466,6 +537,27 @@ func postJobResult(ctx context.Context, dbFunc func(context.Context) *gorp.DbMap
 		}
 	}
 
+	// Job outputs are saved as node outputs and added to build variables to be available
+	// in the next stages and in the child nodes
+	if len(res.Outputs) > 0 {
+		if node.Outputs == nil {
+			node.Outputs = make(map[string]string, len(res.Outputs))
+		}
+		for k, v := range res.Outputs {
+			log.Debug("postJobResult> managing output %s on node %d", k, node.ID)
+			node.Outputs[k] = v
+			name := sdk.NodeOutputVariable(node.WorkflowNodeName, k)
+			if p := sdk.ParameterFind(node.BuildParameters, name); p != nil {
+				p.Value = v
+			} else {
+				sdk.AddParameter(&node.BuildParameters, name, sdk.StringParameter, v)
+			}
+		}
+		if err := workflow.UpdateNodeRunOutputs(tx, node.ID, node.Outputs); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := workflow.UpdateNodeRunBuildParameters(tx, node.ID, node.BuildParameters); err != nil {
 		return nil, sdk.WrapError(err, "unable to update node run %d", node.ID)
 	}
@@ -481,9 +573,25 @@ func postJobResult(ctx context.Context, dbFunc func(context.Context) *gorp.DbMap
 	newDBFunc := func() *gorp.DbMap {
 		return dbFunc(context.Background())
 	}
-	report, err := workflow.UpdateNodeJobRunStatus(ctx, tx, store, *proj, job, res.Status)
-	if err != nil {
-		return nil, sdk.WrapError(err, "cannot update NodeJobRun %d status", job.ID)
+	var report *workflow.ProcessorReport
+	if res.Status == sdk.StatusFail && workflow.CanRetryNodeJobRun(*job, sdk.RetryWhenFailure, res.ExitCode) {
+		// The job will be replaced in queue according to its retry policy
+		log.Info(ctx, "postJobResult> retrying job %d after attempt %d failed with exit code %d", job.ID, job.Retry+1, res.ExitCode)
+		if err := workflow.RetryNodeJobRun(ctx, tx, *job, sdk.RetryWhenFailure); err != nil {
+			return nil, sdk.WrapError(err, "cannot retry NodeJobRun %d", job.ID)
+		}
+		retriedJob, err := workflow.LoadNodeJobRun(ctx, tx, store, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		report = new(workflow.ProcessorReport)
+		report.Add(ctx, *retriedJob)
+	} else {
+		var err error
+		report, err = workflow.UpdateNodeJobRunStatus(ctx, tx, store, *proj, job, res.Status)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot update NodeJobRun %d status", job.ID)
+		}
 	}
 
 	//Commit the transaction
@@ -546,7 +654,7 @@ func (api *API) postWorkflowJobLogsHandler() service.Handler {
 
 		log.Debug("postWorkflowJobLogsHandler> Logs: %+v", logs)
 
-		if err := workflow.AddLog(api.mustDB(), pbJob, &logs, api.Config.Log.StepMaxSize); err != nil {
+		if err := workflow.AddLog(ctx, api.mustDB(), pbJob, &logs, api.Config.Log.StepMaxSize); err != nil {
 			return err
 		}
 
@@ -585,7 +693,7 @@ func (api *API) postWorkflowJobServiceLogsHandler() service.AsynchronousHandler
 				continue
 			}
 
-			if err := workflow.AddServiceLog(db, nodeRunJob, &log, api.Config.Log.ServiceMaxSize); err != nil {
+			if err := workflow.AddServiceLog(ctx, db, nodeRunJob, &log, api.Config.Log.ServiceMaxSize); err != nil {
 				errorOccured = true
 				globalErr.Append(fmt.Errorf("postWorkflowJobServiceLogsHandler> %v", err))
 			}
@@ -675,6 +783,14 @@ func (api *API) postWorkflowJobStepStatusHandler() service.Handler {
 			return sdk.WrapError(err, "cannot commit transaction")
 		}
 
+		if sdk.StatusIsTerminated(step.Status) {
+			sdk.GoRoutine(api.Router.Background, "workflow.CompactStepLogs", func(ctx context.Context) {
+				if err := workflow.CompactStepLogs(ctx, api.mustDB(), id, int64(step.StepOrder)); err != nil {
+					log.Error(ctx, "postWorkflowJobStepStatusHandler> unable to compact logs of step %d of job %d: %v", step.StepOrder, id, err)
+				}
+			})
+		}
+
 		if nodeRun.ID == 0 {
 			nodeRunP, err := workflow.LoadNodeRunByID(api.mustDB(), nodeJobRun.WorkflowNodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
 			if err != nil {
@@ -772,6 +888,17 @@ func (api *API) getWorkflowJobQueueHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to load queue")
 		}
 
+		// Hatcheries only get the jobs that can be started without exceeding the job quotas, shared between the projects
+		if isHatchery(ctx) {
+			quotas, err := jobquota.LoadState(ctx, api.mustDB(), api.Cache)
+			if err != nil {
+				return err
+			}
+			queue := sdk.WorkflowQueue(quotas.Filter(jobs))
+			queue.SortFairShare(quotas.RunningJobsByProject(), time.Now())
+			jobs = queue
+		}
+
 		return service.WriteJSON(w, jobs, http.StatusOK)
 	}
 }
@@ -2,7 +2,12 @@ package api
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha512"
 	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
 	"mime"
 	"net/http"
 	"strconv"
@@ -224,6 +229,7 @@ func (api *API) postWorkflowJobArtifactHandler() service.Handler {
 			art.ProjectIntegrationID = &id
 		}
 
+		var receivedSHA512sum, receivedMD5sum string
 		files := m.File[fileName]
 		if len(files) == 1 {
 			file, err := files[0].Open()
@@ -232,13 +238,17 @@ func (api *API) postWorkflowJobArtifactHandler() service.Handler {
 				return sdk.WrapError(err, "cannot open file")
 			}
 
-			objectPath, err := storageDriver.Store(&art, file)
+			// the provenance is computed from the received content
+			h512, h5 := sha512.New(), md5.New()
+			objectPath, err := storageDriver.Store(&art, ioutil.NopCloser(io.TeeReader(file, io.MultiWriter(h512, h5))))
 			if err != nil {
 				file.Close()
 				return sdk.WrapError(err, "Cannot store artifact")
 			}
 			log.Debug("objectpath=%s\n", objectPath)
 			art.ObjectPath = objectPath
+			receivedSHA512sum = hex.EncodeToString(h512.Sum(nil))
+			receivedMD5sum = hex.EncodeToString(h5.Sum(nil))
 			file.Close()
 		}
 
@@ -247,6 +257,15 @@ func (api *API) postWorkflowJobArtifactHandler() service.Handler {
 			_ = storageDriver.Delete(ctx, &art)
 			return sdk.WrapError(err, "Cannot update workflow node run")
 		}
+
+		provenanceArt := art
+		if receivedSHA512sum != "" {
+			provenanceArt.SHA512sum = receivedSHA512sum
+			provenanceArt.MD5sum = receivedMD5sum
+		}
+		if err := api.storeArtifactProvenance(ctx, storageDriver, vars["permProjectKey"], *nodeRun, *nodeJobRun, &provenanceArt); err != nil {
+			log.Error(ctx, "cannot store provenance of artifact %s: %v", art.Name, err)
+		}
 		return nil
 	}
 }
@@ -303,6 +322,27 @@ func (api *API) postWorkflowJobArtifactWithTempURLCallbackHandler() service.Hand
 			return sdk.WrapError(err, "cannot update workflow node run")
 		}
 
+		nodeJobRun, err := workflow.LoadNodeJobRun(ctx, api.mustDB(), api.Cache, cachedArt.WorkflowNodeJobRunID)
+		if err != nil {
+			log.Error(ctx, "cannot load node job run %d to store provenance of artifact %s: %v", cachedArt.WorkflowNodeJobRunID, art.Name, err)
+			return nil
+		}
+		// the worker uploaded the artifact to the storage, the provenance is computed from the stored content
+		// in background to not download the artifact again during the worker request
+		projectKey := vars["permProjectKey"]
+		sdk.GoRoutine(api.Router.Background, "storeArtifactProvenance-"+art.Name, func(ctx context.Context) {
+			provenanceArt := art
+			var err error
+			provenanceArt.SHA512sum, provenanceArt.MD5sum, err = storedArtifactSums(ctx, storageDriver, &art)
+			if err != nil {
+				log.Error(ctx, "cannot store provenance of artifact %s: %v", art.Name, err)
+				return
+			}
+			if err := api.storeArtifactProvenance(ctx, storageDriver, projectKey, *nodeRun, *nodeJobRun, &provenanceArt); err != nil {
+				log.Error(ctx, "cannot store provenance of artifact %s: %v", art.Name, err)
+			}
+		})
+
 		return nil
 	}
 }
@@ -811,6 +811,11 @@ func (api *API) postWorkflowRunHandler() service.Handler {
 		if opts.Manual != nil && opts.Manual.OnlyFailedJobs && opts.Manual.Resync {
 			return sdk.WrapError(sdk.ErrWrongRequest, "You cannot resync workflow and run only failed jobs")
 		}
+		if opts.Manual != nil {
+			if err := sdk.IsValidJobPriority(opts.Manual.Priority); err != nil {
+				return err
+			}
+		}
 
 		// CHECK IF IT S AN EXISTING RUN
 		var lastRun *sdk.WorkflowRun
@@ -1258,7 +1263,7 @@ func (api *API) getWorkflowNodeRunJobServiceLogsHandler() service.Handler {
 		}
 		db := api.mustDB()
 
-		logsServices, err := workflow.LoadServicesLogsByJob(db, runJobID)
+		logsServices, err := workflow.LoadServicesLogsByJob(ctx, db, runJobID)
 		if err != nil {
 			return sdk.WrapError(err, "cannot load service logs for node run job id %d", runJobID)
 		}
@@ -1319,7 +1324,7 @@ func (api *API) getWorkflowNodeRunJobStepHandler() service.Handler {
 				stepOrder, runJobID, nodeRunID, number, workflowName, projectKey)
 		}
 
-		logs, errL := workflow.LoadStepLogs(api.mustDB(), runJobID, stepOrder)
+		logs, errL := workflow.LoadStepLogs(ctx, api.mustDB(), runJobID, stepOrder)
 		if errL != nil {
 			return sdk.WrapError(errL, "cannot load log for runJob %d on step %d", runJobID, stepOrder)
 		}
@@ -1339,6 +1344,64 @@ func (api *API) getWorkflowNodeRunJobStepHandler() service.Handler {
 	}
 }
 
+func (api *API) getWorkflowNodeRunJobStepLogsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		workflowName := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		nodeRunID, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+		runJobID, err := requestVarInt(r, "runJobId")
+		if err != nil {
+			return err
+		}
+		stepOrder, err := requestVarInt(r, "stepOrder")
+		if err != nil {
+			return err
+		}
+
+		// Check nodeRunID is link to workflow
+		nodeRun, err := workflow.LoadNodeRun(api.mustDB(), projectKey, workflowName, number, nodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot find nodeRun %d/%d for workflow %s in project %s", nodeRunID, number, workflowName, projectKey)
+		}
+
+		// Check runJobID is link to nodeRun
+		var found bool
+	stageLoop:
+		for _, s := range nodeRun.Stages {
+			for _, rj := range s.RunJobs {
+				if rj.ID == runJobID {
+					found = true
+					break stageLoop
+				}
+			}
+		}
+		if !found {
+			return sdk.WrapError(sdk.ErrStepNotFound, "cannot find job %d in nodeRun %d/%d for workflow %s in project %s",
+				runJobID, nodeRunID, number, workflowName, projectKey)
+		}
+
+		f, err := workflow.OpenStepLogs(ctx, api.mustDB(), runJobID, stepOrder)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load log for runJob %d on step %d", runJobID, stepOrder)
+		}
+		defer f.Close() // nolint
+
+		w.Header().Add("Content-Type", "text/plain; charset=utf-8")
+		if _, err := io.Copy(w, f); err != nil {
+			return sdk.WrapError(err, "cannot stream log for runJob %d on step %d", runJobID, stepOrder)
+		}
+		return nil
+	}
+}
+
 func (api *API) getWorkflowRunTagsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
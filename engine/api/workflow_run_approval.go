@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) getWorkflowNodeRunApprovalHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		id, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+
+		nodeRun, err := workflow.LoadNodeRun(api.mustDB(), key, name, number, id, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load node run %d", id)
+		}
+
+		a, err := workflow.LoadNodeRunApprovalByNodeRunID(ctx, api.mustDB(), nodeRun.ID)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no approval gate for pipeline %s", nodeRun.WorkflowNodeName)
+		}
+
+		return service.WriteJSON(w, a, http.StatusOK)
+	}
+}
+
+func (api *API) postWorkflowNodeRunApprovalHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		id, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+
+		consumer := getAPIConsumer(ctx)
+		if isWorker(ctx) || isService(ctx) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "only users can approve a pipeline")
+		}
+
+		var d sdk.WorkflowNodeRunApprovalDecision
+		if err := service.UnmarshalBody(r, &d); err != nil {
+			return err
+		}
+		d.AuthentifiedUserID = consumer.AuthentifiedUserID
+		d.Username = consumer.GetUsername()
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, project.LoadOptions.WithVariables, project.LoadOptions.WithIntegrations)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project")
+		}
+
+		nodeRun, err := workflow.LoadNodeRun(api.mustDB(), key, name, number, id, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load node run %d", id)
+		}
+		if nodeRun.Status != sdk.StatusWaiting {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "pipeline %s is not waiting for approval", nodeRun.WorkflowNodeName)
+		}
+
+		a, err := workflow.LoadNodeRunApprovalByNodeRunID(ctx, api.mustDB(), nodeRun.ID)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no approval gate for pipeline %s", nodeRun.WorkflowNodeName)
+		}
+
+		// If approver groups are set, the user should be member of one of them
+		if len(a.Groups) > 0 {
+			gs, err := group.LoadAllByNames(ctx, api.mustDB(), a.Groups)
+			if err != nil {
+				return err
+			}
+			if !gs.HasOneOf(consumer.GetGroupIDs()...) {
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "user %s is not member of the approver groups of pipeline %s", d.Username, nodeRun.WorkflowNodeName)
+			}
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		a, report, err := workflow.AddNodeRunApprovalDecision(ctx, tx, api.Cache, *p, nodeRun.ID, &d)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+
+		wr, err := workflow.LoadRunByID(api.mustDB(), nodeRun.WorkflowRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow run %d", nodeRun.WorkflowRunID)
+		}
+		event.PublishWorkflowNodeRunApproval(ctx, p.Key, *wr, *a, &d)
+
+		go WorkflowSendEvent(context.Background(), api.mustDB(), api.Cache, *p, report)
+
+		return service.WriteJSON(w, a, http.StatusOK)
+	}
+}
+
+// nodeRunApprovalTimeoutRoutine rejects the approval gates that were not approved within their timeout.
+func (api *API) nodeRunApprovalTimeoutRoutine(ctx context.Context) {
+	tick := time.NewTicker(30 * time.Second)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "Exiting nodeRunApprovalTimeoutRoutine: %v", ctx.Err())
+				return
+			}
+		case <-tick.C:
+			as, err := workflow.LoadTimedOutNodeRunApprovals(ctx, api.mustDB())
+			if err != nil {
+				log.Warning(ctx, "nodeRunApprovalTimeoutRoutine> unable to load timed out approvals: %v", err)
+				continue
+			}
+			for i := range as {
+				if err := api.timeoutNodeRunApproval(ctx, as[i]); err != nil {
+					log.Warning(ctx, "nodeRunApprovalTimeoutRoutine> unable to reject node run %d: %v", as[i].WorkflowNodeRunID, err)
+				}
+			}
+		}
+	}
+}
+
+func (api *API) timeoutNodeRunApproval(ctx context.Context, a sdk.WorkflowNodeRunApproval) error {
+	p, err := project.LoadByID(api.mustDB(), api.Cache, a.ProjectID, project.LoadOptions.WithVariables, project.LoadOptions.WithIntegrations)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project %d", a.ProjectID)
+	}
+
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return sdk.WrapError(err, "cannot start transaction")
+	}
+	defer tx.Rollback() // nolint
+
+	report, err := workflow.TimeoutNodeRunApproval(ctx, tx, api.Cache, *p, a.WorkflowNodeRunID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "cannot commit transaction")
+	}
+	if report == nil {
+		return nil
+	}
+
+	wr, err := workflow.LoadRunByID(api.mustDB(), a.WorkflowRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+	if err != nil {
+		return sdk.WrapError(err, "unable to load workflow run %d", a.WorkflowRunID)
+	}
+	a.Status = sdk.ApprovalStatusRejected
+	event.PublishWorkflowNodeRunApproval(ctx, p.Key, *wr, a, nil)
+
+	go WorkflowSendEvent(context.Background(), api.mustDB(), api.Cache, *p, report)
+
+	return nil
+}
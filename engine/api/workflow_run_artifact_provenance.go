@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// storeArtifactProvenance signs the provenance of an uploaded artifact and stores it next to the artifact.
+func (api *API) storeArtifactProvenance(ctx context.Context, storageDriver objectstore.Driver, projectKey string, nodeRun sdk.WorkflowNodeRun, nodeJobRun sdk.WorkflowNodeJobRun, art *sdk.WorkflowNodeRunArtifact) error {
+	proj, err := project.Load(api.mustDB(), api.Cache, projectKey)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project %s", projectKey)
+	}
+
+	k, err := project.LoadArtifactProvenanceKey(api.mustDB(), proj.ID)
+	if err != nil {
+		return err
+	}
+
+	p, err := workflow.SignArtifactProvenance(*k, workflow.NewArtifactProvenanceStatement(nodeRun, nodeJobRun, *art))
+	if err != nil {
+		return err
+	}
+
+	return workflow.StoreArtifactProvenance(storageDriver, art, *p)
+}
+
+// storedArtifactSums returns the SHA512 and MD5 sums of the content of an artifact in the storage, the sums
+// declared by the worker that uploaded it can't be trusted.
+func storedArtifactSums(ctx context.Context, storageDriver objectstore.Driver, art *sdk.WorkflowNodeRunArtifact) (string, string, error) {
+	r, err := storageDriver.Fetch(ctx, art)
+	if err != nil {
+		return "", "", sdk.WrapError(err, "cannot fetch artifact %s", art.Name)
+	}
+	defer r.Close()
+
+	h512, h5 := sha512.New(), md5.New()
+	if _, err := io.Copy(io.MultiWriter(h512, h5), r); err != nil {
+		return "", "", sdk.WrapError(err, "cannot read artifact %s", art.Name)
+	}
+	return hex.EncodeToString(h512.Sum(nil)), hex.EncodeToString(h5.Sum(nil)), nil
+}
+
+func (api *API) getWorkflowArtifactProvenanceHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		id, err := requestVarInt(r, "artifactId")
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load projet")
+		}
+
+		work, err := workflow.Load(ctx, api.mustDB(), api.Cache, *proj, name, workflow.LoadOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow")
+		}
+
+		art, err := workflow.LoadArtifactByIDs(api.mustDB(), work.ID, id)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load artifact")
+		}
+
+		integrationName := sdk.DefaultStorageIntegrationName
+		if art.ProjectIntegrationID != nil && *art.ProjectIntegrationID > 0 {
+			projectIntegration, err := integration.LoadProjectIntegrationByID(api.mustDB(), *art.ProjectIntegrationID, false)
+			if err != nil {
+				return sdk.WrapError(err, "cannot load project integration %s/%d", proj.Key, *art.ProjectIntegrationID)
+			}
+			integrationName = projectIntegration.Name
+		}
+
+		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, proj.Key, integrationName)
+		if err != nil {
+			return err
+		}
+
+		p, err := workflow.LoadArtifactProvenance(ctx, storageDriver, art)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, p, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowArtifactProvenanceKeyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load projet")
+		}
+
+		k, err := project.LoadArtifactProvenanceKey(api.mustDB(), proj.ID)
+		if err != nil {
+			return err
+		}
+		k.Private = ""
+
+		return service.WriteJSON(w, k, http.StatusOK)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/purge"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) workflowRunRetentionReport(ctx context.Context, r *http.Request) (*sdk.WorkflowRunRetentionReport, error) {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	name := vars["permWorkflowName"]
+
+	proj, err := project.Load(api.mustDB(), api.Cache, key)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load projet")
+	}
+
+	// applications are needed to get the branches of the repository
+	wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, *proj, name, workflow.LoadOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load workflow")
+	}
+
+	return purge.RunRetentionReport(ctx, api.mustDB(), api.Cache, *proj, *wf)
+}
+
+// getWorkflowRunRetentionHandler returns the runs that the run retention of the workflow keeps or purges,
+// nothing is purged.
+func (api *API) getWorkflowRunRetentionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		report, err := api.workflowRunRetentionReport(ctx, r)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, report, http.StatusOK)
+	}
+}
+
+// postWorkflowRunRetentionHandler applies the run retention of the workflow without waiting for the purge
+// and returns the purged runs.
+func (api *API) postWorkflowRunRetentionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		report, err := api.workflowRunRetentionReport(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		ids := report.PurgedRunIDs()
+		if len(ids) > 0 {
+			if err := workflow.MarkWorkflowRunsAsDelete(api.mustDB(), ids); err != nil {
+				return err
+			}
+			observability.Record(ctx, api.Metrics.WorkflowRunsMarkToDelete, int64(len(ids)))
+		}
+
+		return service.WriteJSON(w, report, http.StatusOK)
+	}
+}
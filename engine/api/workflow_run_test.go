@@ -2175,24 +2175,24 @@ func initGetWorkflowNodeRunJobTest(t *testing.T, api *API, db *gorp.DbMap) (*sdk
 	require.NoError(t, errUJ)
 
 	// Add log
-	require.NoError(t, workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
+	require.NoError(t, workflow.AddLog(context.TODO(), api.mustDB(), jobRun, &sdk.Log{
 		StepOrder: 1,
 		Val:       "1234567890",
 	}, 15))
 
 	// Add truncated log
-	require.NoError(t, workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
+	require.NoError(t, workflow.AddLog(context.TODO(), api.mustDB(), jobRun, &sdk.Log{
 		StepOrder: 1,
 		Val:       "1234567890",
 	}, 15))
 
 	// Add service log
-	require.NoError(t, workflow.AddServiceLog(api.mustDB(), jobRun, &sdk.ServiceLog{
+	require.NoError(t, workflow.AddServiceLog(context.TODO(), api.mustDB(), jobRun, &sdk.ServiceLog{
 		Val: "0987654321",
 	}, 15))
 
 	// Add truncated service log
-	require.NoError(t, workflow.AddServiceLog(api.mustDB(), jobRun, &sdk.ServiceLog{
+	require.NoError(t, workflow.AddServiceLog(context.TODO(), api.mustDB(), jobRun, &sdk.ServiceLog{
 		Val: "0987654321",
 	}, 15))
 
@@ -131,12 +131,15 @@ func configBootstrap(args []string) Configuration {
 			defaults.SetDefaults(&gitlab)
 			var gerrit vcs.GerritServerConfiguration
 			defaults.SetDefaults(&gerrit)
+			var gitea vcs.GiteaServerConfiguration
+			defaults.SetDefaults(&gitea)
 			conf.VCS.Servers = map[string]vcs.ServerConfiguration{
 				"github":         vcs.ServerConfiguration{URL: "https://github.com", Github: &github},
 				"bitbucket":      vcs.ServerConfiguration{URL: "https://mybitbucket.com", Bitbucket: &bitbucket},
 				"bitbucketcloud": vcs.ServerConfiguration{BitbucketCloud: &bitbucketcloud},
 				"gitlab":         vcs.ServerConfiguration{URL: "https://gitlab.com", Gitlab: &gitlab},
 				"gerrit":         vcs.ServerConfiguration{URL: "http://localhost:8080", Gerrit: &gerrit},
+				"gitea":          vcs.ServerConfiguration{URL: "https://gitea.com", Gitea: &gitea},
 			}
 			conf.VCS.Name = "cds-vcs-" + namesgenerator.GetRandomNameCDS(0)
 		case "repositories":
@@ -218,14 +218,11 @@ func (h *HatcheryKubernetes) WorkerModelsEnabled() ([]sdk.Model, error) {
 }
 
 // CanSpawn return wether or not hatchery can spawn model.
-// requirements are not supported
+// hostname requirements are not supported, services are run as sidecar containers of the worker
 func (h *HatcheryKubernetes) CanSpawn(ctx context.Context, model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
-	// Service and Hostname requirement are not supported
+	// Hostname requirement are not supported
 	for _, r := range requirements {
-		if r.Type == sdk.ServiceRequirement {
-			log.Debug("CanSpawn> Job %d has a service requirement. Kubernetes can't spawn a worker for this job", jobID)
-			return false
-		} else if r.Type == sdk.HostnameRequirement {
+		if r.Type == sdk.HostnameRequirement {
 			log.Debug("CanSpawn> Job %d has a hostname requirement. Kubernetes can't spawn a worker for this job", jobID)
 			return false
 		}
@@ -250,6 +247,7 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 	}
 
 	memory := int64(h.Config.DefaultMemory)
+	var cpu *resource.Quantity
 	for _, r := range spawnArgs.Requirements {
 		if r.Type == sdk.MemoryRequirement {
 			var err error
@@ -258,6 +256,13 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 				log.Warning(ctx, "spawnKubernetesDockerWorker> %s unable to parse memory requirement %d: %v", logJob, memory, err)
 				return err
 			}
+		} else if r.Type == sdk.CPURequirement {
+			q, err := resource.ParseQuantity(r.Value)
+			if err != nil {
+				log.Warning(ctx, "spawnKubernetesDockerWorker> %s unable to parse cpu requirement %s: %v", logJob, r.Value, err)
+				return sdk.WithStack(err)
+			}
+			cpu = &q
 		}
 	}
 
@@ -290,6 +295,7 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 	if spawnArgs.RegisterOnly {
 		cmd += " register"
 		memory = hatchery.MemoryRegisterContainer
+		cpu = nil
 	}
 
 	if spawnArgs.Model.ModelDocker.Envs == nil {
@@ -298,6 +304,9 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 	envsWm := map[string]string{}
 	envsWm["CDS_FORCE_EXIT"] = "1"
 	envsWm["CDS_MODEL_MEMORY"] = fmt.Sprintf("%d", memory)
+	if cpu != nil {
+		envsWm["CDS_MODEL_CPU"] = cpu.AsDec().String()
+	}
 	envsWm["CDS_API"] = udataParam.API
 	envsWm["CDS_TOKEN"] = udataParam.Token
 	envsWm["CDS_NAME"] = udataParam.Name
@@ -358,6 +367,14 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 		},
 	}
 
+	applyPodTemplate(&podSchema, spawnArgs.Model.ModelDocker.PodTemplate)
+
+	// The cpu requirement is requested and limited for the worker container
+	if cpu != nil {
+		podSchema.Spec.Containers[0].Resources.Requests[apiv1.ResourceCPU] = *cpu
+		podSchema.Spec.Containers[0].Resources.Limits = apiv1.ResourceList{apiv1.ResourceCPU: *cpu}
+	}
+
 	var services []sdk.Requirement
 	for _, req := range spawnArgs.Requirements {
 		if req.Type == sdk.ServiceRequirement {
@@ -392,15 +409,19 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 		}
 
 		if sm, ok := envm["CDS_SERVICE_MEMORY"]; ok {
+			// As for the swarm hatchery the memory is given in megabytes, a kubernetes quantity is also accepted (ex: 512Mi)
+			if _, err := strconv.ParseUint(sm, 10, 32); err == nil {
+				sm += "Mi"
+			}
 			mq, err := resource.ParseQuantity(sm)
 			if err != nil {
 				log.Warning(ctx, "hatchery> kubernetes> SpawnWorker> Unable to parse CDS_SERVICE_MEMORY value '%s': %s", sm, err)
-				continue
-			}
-			servContainer.Resources = apiv1.ResourceRequirements{
-				Requests: apiv1.ResourceList{
-					apiv1.ResourceMemory: mq,
-				},
+			} else {
+				servContainer.Resources = apiv1.ResourceRequirements{
+					Requests: apiv1.ResourceList{
+						apiv1.ResourceMemory: mq,
+					},
+				}
 			}
 			delete(envm, "CDS_SERVICE_MEMORY")
 		}
@@ -94,6 +94,8 @@ func TestHatcheryKubernetes_Status(t *testing.T) {
 		require.Equal(t, 2, len(podRequest.Spec.Containers))
 		require.Equal(t, "k8s-toto", podRequest.Spec.Containers[0].Name)
 		require.Equal(t, int64(4096), podRequest.Spec.Containers[0].Resources.Requests.Memory().Value())
+		require.Equal(t, int64(500), podRequest.Spec.Containers[0].Resources.Requests.Cpu().MilliValue())
+		require.Equal(t, int64(500), podRequest.Spec.Containers[0].Resources.Limits.Cpu().MilliValue())
 		require.Equal(t, "service-0-pg", podRequest.Spec.Containers[1].Name)
 		require.Equal(t, 1, len(podRequest.Spec.Containers[1].Env))
 		require.Equal(t, "PG_USERNAME", podRequest.Spec.Containers[1].Env[0].Name)
@@ -110,6 +112,10 @@ func TestHatcheryKubernetes_Status(t *testing.T) {
 				Name:  "mem",
 				Type:  sdk.MemoryRequirement,
 				Value: "4096",
+			}, {
+				Name:  "cpu",
+				Type:  sdk.CPURequirement,
+				Value: "0.5",
 			}, {
 				Name:  "pg",
 				Type:  sdk.ServiceRequirement,
@@ -120,3 +126,74 @@ func TestHatcheryKubernetes_Status(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, gock.IsDone())
 }
+
+func TestHatcheryKubernetes_SpawnWorkerWithPodTemplate(t *testing.T) {
+	defer gock.Off()
+	h := NewHatcheryKubernetesTest(t)
+
+	m := &sdk.Model{
+		Name: "model1",
+		Group: &sdk.Group{
+			Name: "group",
+		},
+		ModelDocker: sdk.ModelDocker{
+			PodTemplate: &sdk.ModelPodTemplate{
+				NodeSelector:       map[string]string{"disktype": "ssd"},
+				Tolerations:        []sdk.ModelPodToleration{{Key: "dedicated", Operator: "Equal", Value: "cds", Effect: "NoSchedule"}},
+				ServiceAccountName: "cds-worker",
+				Annotations:        map[string]string{"team": "ci"},
+				Volumes: []sdk.ModelPodVolume{
+					{Name: "cache", MountPath: "/cache", PersistentVolumeClaim: "cds-cache"},
+					{Name: "config", MountPath: "/etc/build", ConfigMap: "build-config", ReadOnly: true},
+				},
+			},
+		},
+	}
+
+	podResponse := v1.Pod{}
+	gock.New("http://lolcat.kube").Post("/api/v1/namespaces/hachibi/pods").Reply(http.StatusOK).JSON(podResponse)
+
+	var checkRequest gock.ObserverFunc = func(request *http.Request, mock gock.Mock) {
+		if request.Body == nil {
+			return
+		}
+		bodyContent, err := ioutil.ReadAll(request.Body)
+		assert.NoError(t, err)
+		var podRequest v1.Pod
+		require.NoError(t, json.Unmarshal(bodyContent, &podRequest))
+
+		require.Equal(t, map[string]string{"disktype": "ssd"}, podRequest.Spec.NodeSelector)
+		require.Len(t, podRequest.Spec.Tolerations, 1)
+		require.Equal(t, v1.TaintEffectNoSchedule, podRequest.Spec.Tolerations[0].Effect)
+		require.Equal(t, "cds-worker", podRequest.Spec.ServiceAccountName)
+		require.Equal(t, "ci", podRequest.Annotations["team"])
+		require.Len(t, podRequest.Spec.Volumes, 2)
+		require.Equal(t, "cds-cache", podRequest.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		require.Equal(t, "build-config", podRequest.Spec.Volumes[1].ConfigMap.Name)
+
+		require.Equal(t, 2, len(podRequest.Spec.Containers))
+		require.Len(t, podRequest.Spec.Containers[0].VolumeMounts, 2)
+		require.Equal(t, "/etc/build", podRequest.Spec.Containers[0].VolumeMounts[1].MountPath)
+		require.True(t, podRequest.Spec.Containers[0].VolumeMounts[1].ReadOnly)
+		require.Empty(t, podRequest.Spec.Containers[1].VolumeMounts)
+		require.Equal(t, int64(512*1024*1024), podRequest.Spec.Containers[1].Resources.Requests.Memory().Value())
+		require.Equal(t, []string{"worker", "mysql"}, podRequest.Spec.HostAliases[0].Hostnames)
+	}
+	gock.Observe(checkRequest)
+
+	reqs := []sdk.Requirement{{
+		Name:  "mysql",
+		Type:  sdk.ServiceRequirement,
+		Value: "mysql:5.7 MYSQL_ROOT_PASSWORD=secret CDS_SERVICE_MEMORY=512",
+	}}
+	require.True(t, h.CanSpawn(context.TODO(), m, 666, reqs))
+
+	err := h.SpawnWorker(context.TODO(), hatchery.SpawnArguments{
+		JobID:        666,
+		Model:        m,
+		WorkerName:   "k8s-toto",
+		Requirements: reqs,
+	})
+	require.NoError(t, err)
+	require.True(t, gock.IsDone())
+}
@@ -0,0 +1,66 @@
+package kubernetes
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// applyPodTemplate sets the pod template overrides of a worker model on the worker pod,
+// the volumes are mounted in the worker container.
+func applyPodTemplate(pod *apiv1.Pod, t *sdk.ModelPodTemplate) {
+	if t == nil {
+		return
+	}
+
+	if len(t.NodeSelector) > 0 {
+		pod.Spec.NodeSelector = make(map[string]string, len(t.NodeSelector))
+		for k, v := range t.NodeSelector {
+			pod.Spec.NodeSelector[k] = v
+		}
+	}
+
+	for _, tol := range t.Tolerations {
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, apiv1.Toleration{
+			Key:      tol.Key,
+			Operator: apiv1.TolerationOperator(tol.Operator),
+			Value:    tol.Value,
+			Effect:   apiv1.TaintEffect(tol.Effect),
+		})
+	}
+
+	if t.ServiceAccountName != "" {
+		pod.Spec.ServiceAccountName = t.ServiceAccountName
+	}
+
+	if len(t.Annotations) > 0 {
+		if pod.ObjectMeta.Annotations == nil {
+			pod.ObjectMeta.Annotations = make(map[string]string, len(t.Annotations))
+		}
+		for k, v := range t.Annotations {
+			pod.ObjectMeta.Annotations[k] = v
+		}
+	}
+
+	for _, v := range t.Volumes {
+		volume := apiv1.Volume{Name: v.Name}
+		switch {
+		case v.HostPath != "":
+			volume.HostPath = &apiv1.HostPathVolumeSource{Path: v.HostPath}
+		case v.ConfigMap != "":
+			volume.ConfigMap = &apiv1.ConfigMapVolumeSource{LocalObjectReference: apiv1.LocalObjectReference{Name: v.ConfigMap}}
+		case v.Secret != "":
+			volume.Secret = &apiv1.SecretVolumeSource{SecretName: v.Secret}
+		case v.PersistentVolumeClaim != "":
+			volume.PersistentVolumeClaim = &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: v.PersistentVolumeClaim, ReadOnly: v.ReadOnly}
+		default:
+			volume.EmptyDir = &apiv1.EmptyDirVolumeSource{}
+		}
+		pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, apiv1.VolumeMount{
+			Name:      v.Name,
+			MountPath: v.MountPath,
+			ReadOnly:  v.ReadOnly,
+		})
+	}
+}
@@ -249,6 +249,9 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 		memory = spawnArgs.Model.ModelDocker.Memory
 	}
 
+	//CPUs for the worker, no limit by default
+	var cpus float64
+
 	var network, networkAlias string
 	services := []string{}
 
@@ -261,6 +264,13 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 					log.Warning(ctx, "hatchery> swarm> SpawnWorker>Unable to parse memory requirement %d :%v", memory, err)
 					return err
 				}
+			} else if r.Type == sdk.CPURequirement {
+				var err error
+				cpus, err = sdk.ParseCPURequirement(r.Value)
+				if err != nil {
+					log.Warning(ctx, "hatchery> swarm> SpawnWorker>Unable to parse cpu requirement %s :%v", r.Value, err)
+					return err
+				}
 			} else if r.Type == sdk.ServiceRequirement {
 				//Create a network if not already created
 				if network == "" {
@@ -392,6 +402,9 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 	envsWm := map[string]string{}
 	envsWm["CDS_FORCE_EXIT"] = "1"
 	envsWm["CDS_MODEL_MEMORY"] = fmt.Sprintf("%d", memory)
+	if cpus > 0 {
+		envsWm["CDS_MODEL_CPU"] = strconv.FormatFloat(cpus, 'f', -1, 64)
+	}
 	envsWm["CDS_API"] = udataParam.API
 	envsWm["CDS_TOKEN"] = udataParam.Token
 	envsWm["CDS_NAME"] = udataParam.Name
@@ -428,6 +441,7 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 		cmd:          cmds,
 		labels:       labels,
 		memory:       memory,
+		cpus:         cpus,
 		dockerOpts:   *dockerOpts,
 		entryPoint:   []string{},
 		env:          envs,
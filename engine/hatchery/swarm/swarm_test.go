@@ -2,6 +2,7 @@ package swarm
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/h2non/gock.v1"
+	"io/ioutil"
 	"net/http"
 	"testing"
 	"time"
@@ -313,6 +315,22 @@ func TestHatcherySwarm_Spawn(t *testing.T) {
 	gock.New("https://lolcat.host").Post("/v6.66/containers/create").MatchParam("name", "swarmy-*").Reply(http.StatusOK).JSON(cWorker)
 	gock.New("https://lolcat.host").Post("/v6.66/containers/workerIDContainer/start").Reply(http.StatusOK).JSON(nil)
 
+	var checkRequest gock.ObserverFunc = func(request *http.Request, mock gock.Mock) {
+		if request.Body == nil || request.URL.Path != "/v6.66/containers/create" || request.URL.Query().Get("name") != "swarmy-worker1" {
+			return
+		}
+		bodyContent, err := ioutil.ReadAll(request.Body)
+		require.NoError(t, err)
+		var body struct {
+			HostConfig container.HostConfig
+		}
+		require.NoError(t, json.Unmarshal(bodyContent, &body))
+		require.Equal(t, int64(4096*1024*1024), body.HostConfig.Memory)
+		require.Equal(t, int64(1500000000), body.HostConfig.NanoCPUs)
+		require.Equal(t, int64(1536), body.HostConfig.CPUShares)
+	}
+	gock.Observe(checkRequest)
+
 	err := h.SpawnWorker(context.TODO(), hatchery.SpawnArguments{
 		JobID:      1,
 		Model:      &m,
@@ -323,6 +341,11 @@ func TestHatcherySwarm_Spawn(t *testing.T) {
 				Type:  sdk.MemoryRequirement,
 				Value: "4096",
 			},
+			{
+				Name:  "cpu",
+				Type:  sdk.CPURequirement,
+				Value: "1.5",
+			},
 			{
 				Name:  "pg",
 				Type:  sdk.ServiceRequirement,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/ovh/cds/sdk"
@@ -45,6 +46,14 @@ func (s *Service) generatePayloadFromBitbucketServerRequest(ctx context.Context,
 
 	projectKey := t.Config["project"].Value
 	workflowName := t.Config["workflow"].Value
+
+	// Preview environments of the pull request are torn down when it is closed
+	if request.PullRequest != nil {
+		switch request.EventKey {
+		case "pr:merged", "pr:declined", "pr:deleted":
+			s.teardownPreviewEnvironments(ctx, projectKey, workflowName, "", strconv.Itoa(request.PullRequest.ID))
+		}
+	}
 	for _, pushChange := range request.Changes {
 		if pushChange.Type == "DELETE" {
 			err := s.enqueueBranchDeletion(projectKey, workflowName, strings.TrimPrefix(pushChange.RefID, "refs/heads/"))
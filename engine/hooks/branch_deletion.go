@@ -13,6 +13,7 @@ func (s *Service) doBranchDeletionTaskExecution(t *sdk.TaskExecution) (*sdk.Work
 	projectKey := t.Config["project"].Value
 	workflowName := t.Config["workflow"].Value
 	branch := t.Config["branch"].Value
+	s.teardownPreviewEnvironments(context.Background(), projectKey, workflowName, branch, "")
 	err := s.Client.WorkflowRunsDeleteByBranch(projectKey, workflowName, branch)
 
 	return nil, sdk.WrapError(err, "cannot mark to delete workflow runs")
@@ -0,0 +1,154 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (s *Service) generatePayloadFromGiteaRequest(ctx context.Context, t *sdk.TaskExecution, event string) (map[string]interface{}, error) {
+	projectKey := t.Config["project"].Value
+	workflowName := t.Config["workflow"].Value
+
+	// Gitea sends a specific event when a branch is deleted
+	if event == "delete" {
+		var request GiteaDeleteEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &request); err != nil {
+			return nil, sdk.WrapError(err, "unable ro read gitea request: %s", string(t.WebHook.RequestBody))
+		}
+		if request.RefType == "branch" {
+			err := s.enqueueBranchDeletion(projectKey, workflowName, strings.TrimPrefix(request.Ref, "refs/heads/"))
+			return nil, sdk.WrapError(err, "cannot enqueue branch deletion")
+		}
+		return nil, nil
+	}
+
+	// Pull request events, reviews included, send the pull request in their payload
+	if strings.HasPrefix(event, "pull_request") {
+		return s.generatePayloadFromGiteaPullRequest(ctx, t, event)
+	}
+
+	var request GiteaPushEvent
+	if err := json.Unmarshal(t.WebHook.RequestBody, &request); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read gitea request: %s", string(t.WebHook.RequestBody))
+	}
+
+	// Branch deletion ( gitea return 0000000000000000000000000000000000000000 as git hash)
+	if request.After == "0000000000000000000000000000000000000000" {
+		if strings.HasPrefix(request.Ref, "refs/tags/") {
+			return nil, nil
+		}
+		err := s.enqueueBranchDeletion(projectKey, workflowName, strings.TrimPrefix(request.Ref, "refs/heads/"))
+		return nil, sdk.WrapError(err, "cannot enqueue branch deletion")
+	}
+
+	payload := make(map[string]interface{})
+	payload[GIT_EVENT] = event
+
+	if request.Ref != "" {
+		if !strings.HasPrefix(request.Ref, "refs/tags/") {
+			branch := strings.TrimPrefix(request.Ref, "refs/heads/")
+			payload[GIT_BRANCH] = branch
+			if err := s.stopBranchDeletionTask(ctx, branch); err != nil {
+				log.Error(ctx, "cannot stop branch deletion task for branch %s : %v", branch, err)
+			}
+		} else {
+			payload[GIT_TAG] = strings.TrimPrefix(request.Ref, "refs/tags/")
+		}
+	}
+	if request.Before != "" {
+		payload[GIT_HASH_BEFORE] = request.Before
+	}
+	if request.After != "" {
+		payload[GIT_HASH] = request.After
+		hashShort := request.After
+		if len(hashShort) >= 7 {
+			hashShort = hashShort[:7]
+		}
+		payload[GIT_HASH_SHORT] = hashShort
+	}
+
+	getPayloadFromGiteaRepository(payload, request.Repository)
+	getPayloadFromGiteaPusher(payload, request.Pusher)
+	getPayloadFromGiteaCommit(payload, request.HeadCommit, request.Commits)
+
+	for i := range request.Commits {
+		request.Commits[i].Added = nil
+		request.Commits[i].Removed = nil
+		request.Commits[i].Modified = nil
+	}
+	if request.HeadCommit != nil {
+		request.HeadCommit.Added = nil
+		request.HeadCommit.Removed = nil
+		request.HeadCommit.Modified = nil
+	}
+	getPayloadStringVariable(ctx, payload, request)
+
+	return payload, nil
+}
+
+func (s *Service) generatePayloadFromGiteaPullRequest(ctx context.Context, t *sdk.TaskExecution, event string) (map[string]interface{}, error) {
+	var request GiteaPullRequestEvent
+	if err := json.Unmarshal(t.WebHook.RequestBody, &request); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read gitea request: %s", string(t.WebHook.RequestBody))
+	}
+	if request.PullRequest == nil {
+		return nil, nil
+	}
+	pr := request.PullRequest
+
+	if request.Action == "closed" {
+		s.teardownPreviewEnvironments(ctx, t.Config["project"].Value, t.Config["workflow"].Value, "", strconv.Itoa(pr.Number))
+	}
+
+	payload := make(map[string]interface{})
+	payload[GIT_EVENT] = event
+	payload[PR_ID] = pr.Number
+	payload[PR_STATE] = pr.State
+	payload[PR_TITLE] = pr.Title
+	payload[GIT_BRANCH] = pr.Head.Ref
+	payload[GIT_HASH] = pr.Head.Sha
+	hashShort := pr.Head.Sha
+	if len(hashShort) >= 7 {
+		hashShort = hashShort[:7]
+	}
+	payload[GIT_HASH_SHORT] = hashShort
+
+	getPayloadFromGiteaRepository(payload, request.Repository)
+	getPayloadFromGiteaPusher(payload, request.Sender)
+	getPayloadStringVariable(ctx, payload, request)
+
+	return payload, nil
+}
+
+func getPayloadFromGiteaRepository(payload map[string]interface{}, repo *GiteaRepository) {
+	if repo == nil {
+		return
+	}
+	payload[GIT_REPOSITORY] = repo.FullName
+}
+
+func getPayloadFromGiteaPusher(payload map[string]interface{}, pusher *GiteaUser) {
+	if pusher == nil {
+		return
+	}
+	payload[GIT_AUTHOR] = pusher.Login
+	payload[GIT_AUTHOR_EMAIL] = pusher.Email
+	payload[CDS_TRIGGERED_BY_USERNAME] = pusher.Login
+	payload[CDS_TRIGGERED_BY_FULLNAME] = pusher.FullName
+	payload[CDS_TRIGGERED_BY_EMAIL] = pusher.Email
+}
+
+func getPayloadFromGiteaCommit(payload map[string]interface{}, headCommit *GiteaCommit, commits []GiteaCommit) {
+	if headCommit != nil {
+		payload[GIT_MESSAGE] = headCommit.Message
+		return
+	}
+	if len(commits) > 0 {
+		payload[GIT_MESSAGE] = commits[0].Message
+	}
+}
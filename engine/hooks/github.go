@@ -3,6 +3,7 @@ package hooks
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"strings"
 
 	"github.com/ovh/cds/sdk"
@@ -49,6 +50,24 @@ func (s *Service) generatePayloadFromGithubRequest(ctx context.Context, t *sdk.T
 		payload[GIT_HASH_SHORT] = hashShort
 	}
 
+	if request.PullRequest != nil {
+		pr := request.PullRequest
+		payload[PR_ID] = pr.Number
+		payload[PR_STATE] = pr.State
+		payload[PR_TITLE] = pr.Title
+		payload[GIT_BRANCH] = pr.Head.Ref
+		payload[GIT_HASH] = pr.Head.Sha
+		hashShort := pr.Head.Sha
+		if len(hashShort) >= 7 {
+			hashShort = hashShort[:7]
+		}
+		payload[GIT_HASH_SHORT] = hashShort
+		// the workflow is still triggered, e.g. to deploy on merge
+		if request.Action == "closed" {
+			s.teardownPreviewEnvironments(ctx, projectKey, workflowName, "", strconv.Itoa(pr.Number))
+		}
+	}
+
 	getPayloadFromRepository(payload, request.Repository)
 	getPayloadFromCommit(payload, request.HeadCommit)
 
@@ -64,6 +64,7 @@ func (s *Service) webhookHandler() service.Handler {
 		s.Dao.SaveTaskExecution(exec)
 
 		//Return the execution
+		hideTaskExecutionSecrets(exec)
 		return service.WriteJSON(w, exec, http.StatusOK)
 	}
 }
@@ -203,6 +204,7 @@ func (s *Service) getTasksHandler() service.Handler {
 			}
 			tasks[i].NbExecutionsTotal = len(m[t.UUID])
 			tasks[i].NbExecutionsTodo = nbTodo
+			hideTaskSecrets(&tasks[i])
 		}
 
 		for k, p := range sortParams {
@@ -274,6 +276,7 @@ func (s *Service) getTaskHandler() service.Handler {
 		}
 
 		t.Executions = execs
+		hideTaskSecrets(t)
 
 		return service.WriteJSON(w, t, http.StatusOK)
 	}
@@ -322,6 +325,7 @@ func (s *Service) getTaskExecutionsHandler() service.Handler {
 		sort.Slice(t.Executions, func(i, j int) bool {
 			return t.Executions[i].Timestamp > t.Executions[j].Timestamp
 		})
+		hideTaskSecrets(t)
 
 		return service.WriteJSON(w, t, http.StatusOK)
 	}
@@ -607,6 +611,7 @@ func (s *Service) getTaskExecutionHandler() service.Handler {
 
 		for _, e := range execs {
 			if strconv.FormatInt(e.Timestamp, 10) == timestamp {
+				hideTaskExecutionSecrets(&e)
 				return service.WriteJSON(w, e, http.StatusOK)
 			}
 		}
@@ -669,3 +674,38 @@ func (s *Service) postMaintenanceHandler() service.Handler {
 		return nil
 	}
 }
+
+// hideTaskSecrets removes the webhook secret from a task and its executions before sending them
+func hideTaskSecrets(t *sdk.Task) {
+	if _, has := t.Config[sdk.HookConfigWebHookSecret]; has {
+		t.Config = t.Config.Clone()
+		delete(t.Config, sdk.HookConfigWebHookSecret)
+	}
+	for i := range t.Executions {
+		hideTaskExecutionSecrets(&t.Executions[i])
+	}
+}
+
+// hideTaskExecutionSecrets removes the webhook secret from a task execution, the token sent by Gitlab is the secret too
+func hideTaskExecutionSecrets(e *sdk.TaskExecution) {
+	if _, has := e.Config[sdk.HookConfigWebHookSecret]; has {
+		e.Config = e.Config.Clone()
+		delete(e.Config, sdk.HookConfigWebHookSecret)
+	}
+	if e.WebHook == nil {
+		return
+	}
+	if _, has := e.WebHook.RequestHeader[GitlabTokenHeader]; has {
+		headers := make(map[string][]string, len(e.WebHook.RequestHeader))
+		for k, v := range e.WebHook.RequestHeader {
+			headers[k] = v
+		}
+		headers[GitlabTokenHeader] = []string{sdk.PasswordPlaceholder}
+		e.WebHook = &sdk.WebHookExecution{
+			RequestURL:    e.WebHook.RequestURL,
+			RequestBody:   e.WebHook.RequestBody,
+			RequestHeader: headers,
+			RequestMethod: e.WebHook.RequestMethod,
+		}
+	}
+}
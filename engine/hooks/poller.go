@@ -106,7 +106,14 @@ func (s *Service) doPollerTaskExecution(ctx context.Context, task *sdk.Task, tas
 		}
 
 		for _, pullRequestEvent := range events.PullRequestEvents {
+			if pullRequestEvent.Action == "closed" {
+				s.teardownPreviewEnvironments(ctx, taskExec.Config[sdk.HookConfigProject].Value, taskExec.Config[sdk.HookConfigWorkflow].Value, "", strconv.Itoa(pullRequestEvent.ID))
+			}
 			payload := fillPayload(ctx, pullRequestEvent.Head)
+			if pullRequestEvent.ID != 0 {
+				payload[PR_ID] = strconv.Itoa(pullRequestEvent.ID)
+				payload[PR_STATE] = pullRequestEvent.Action
+			}
 			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
 				WorkflowNodeHookUUID: task.UUID,
 				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
@@ -0,0 +1,16 @@
+package hooks
+
+import (
+	"context"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+// teardownPreviewEnvironments asks the API to tear down the preview environments of a workflow created for
+// a deleted branch or a closed pull request.
+func (s *Service) teardownPreviewEnvironments(ctx context.Context, projectKey, workflowName, branch, pullRequestID string) {
+	log.Debug("Hooks> Tearing down preview environments of %s/%s for branch %q and pull request %q", projectKey, workflowName, branch, pullRequestID)
+	if err := s.Client.WorkflowPreviewEnvironmentsTeardown(projectKey, workflowName, branch, pullRequestID); err != nil {
+		log.Error(ctx, "cannot tear down preview environments of %s/%s: %v", projectKey, workflowName, err)
+	}
+}
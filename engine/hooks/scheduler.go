@@ -105,6 +105,11 @@ func (s *Service) retryTaskExecutionsRoutine(ctx context.Context) error {
 						}
 					}
 					if e.NbErrors < s.Cfg.RetryError && e.LastError != "" {
+						// do not re-enqueue a repository webhook with an invalid signature, it will never work
+						// the execution is kept to show the error
+						if strings.Contains(e.LastError, errWebHookSignature) {
+							continue
+						}
 						// avoid re-enqueue if the lastError is about a git branch not found
 						// the branch was deleted from git repository, it will never work
 						if strings.Contains(e.LastError, "branchName parameter must be provided") {
@@ -30,9 +30,16 @@ const (
 
 	GithubHeader         = "X-Github-Event"
 	GitlabHeader         = "X-Gitlab-Event"
+	GiteaHeader          = "X-Gitea-Event"
 	BitbucketHeader      = "X-Event-Key"
 	BitbucketCloudHeader = "X-Event-Key_Cloud" // Fake header, do not use to fetch header, just to return custom header
 
+	GithubSignatureHeader       = "X-Hub-Signature-256"
+	GithubLegacySignatureHeader = "X-Hub-Signature"
+	GitlabTokenHeader           = "X-Gitlab-Token"
+	GiteaSignatureHeader        = "X-Gitea-Signature"
+	BitbucketSignatureHeader    = "X-Hub-Signature"
+
 	ConfigNumber    = "Number"
 	ConfigSubNumber = "SubNumber"
 	ConfigHookID    = "HookID"
@@ -0,0 +1,208 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func Test_getRepositoryHeaderGitea(t *testing.T) {
+	// Gitea sends both Gitea and Github headers
+	whe := &sdk.WebHookExecution{
+		RequestHeader: map[string][]string{
+			GiteaHeader:  {"push"},
+			GithubHeader: {"push"},
+		},
+	}
+	assert.Equal(t, GiteaHeader, getRepositoryHeader(whe, nil))
+	assert.Equal(t, "", getRepositoryHeader(whe, []string{"pull_request"}))
+
+	whe.RequestHeader[GiteaHeader] = []string{"delete"}
+	whe.RequestHeader[GithubHeader] = []string{"delete"}
+	assert.Equal(t, "", getRepositoryHeader(whe, nil))
+	assert.Equal(t, GiteaHeader, getRepositoryHeader(whe, []string{"push", "delete"}))
+}
+
+func Test_doWebHookExecutionGitea(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(giteaPushEvent),
+			RequestHeader: map[string][]string{
+				GiteaHeader:  {"push"},
+				GithubHeader: {"push"},
+			},
+			RequestURL: "",
+		},
+	}
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, "develop", hs[0].Payload["git.branch"])
+	assert.Equal(t, "gitea", hs[0].Payload["git.author"])
+	assert.Equal(t, "a random commit message\n", hs[0].Payload["git.message"])
+	assert.Equal(t, "bffeb74224043ba2feb48d137756c8a9331c449a", hs[0].Payload["git.hash"])
+	assert.Equal(t, "bffeb74", hs[0].Payload["git.hash.short"])
+	assert.Equal(t, "gitea/webhooks", hs[0].Payload["git.repository"])
+	assert.Equal(t, "push", hs[0].Payload["git.hook"])
+}
+
+func Test_doWebHookExecutionGiteaPullRequest(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: sdk.WorkflowNodeHookConfigValue{Value: "push;pull_request"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(giteaPullRequestEvent),
+			RequestHeader: map[string][]string{
+				GiteaHeader:  {"pull_request"},
+				GithubHeader: {"pull_request"},
+			},
+			RequestURL: "",
+		},
+	}
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, "feature/preview", hs[0].Payload["git.branch"])
+	assert.Equal(t, "2", hs[0].Payload["git.pr.id"])
+	assert.Equal(t, "open", hs[0].Payload["git.pr.state"])
+	assert.Equal(t, "Add a preview", hs[0].Payload["git.pr.title"])
+	assert.Equal(t, "4bfe49b56a4ebf8e8ef6b2d1d5bfca5f6e27e05c", hs[0].Payload["git.hash"])
+	assert.Equal(t, "4bfe49b", hs[0].Payload["git.hash.short"])
+	assert.Equal(t, "gitea/webhooks", hs[0].Payload["git.repository"])
+	assert.Equal(t, "gitea", hs[0].Payload["git.author"])
+	assert.Equal(t, "pull_request", hs[0].Payload["git.hook"])
+}
+
+var giteaPullRequestEvent = `{
+  "action": "opened",
+  "number": 2,
+  "pull_request": {
+    "id": 12,
+    "number": 2,
+    "title": "Add a preview",
+    "state": "open",
+    "merged": false,
+    "head": {
+      "label": "feature/preview",
+      "ref": "feature/preview",
+      "sha": "4bfe49b56a4ebf8e8ef6b2d1d5bfca5f6e27e05c"
+    },
+    "base": {
+      "label": "master",
+      "ref": "master",
+      "sha": "bffeb74224043ba2feb48d137756c8a9331c449a"
+    }
+  },
+  "repository": {
+    "id": 1,
+    "name": "webhooks",
+    "full_name": "gitea/webhooks",
+    "default_branch": "master"
+  },
+  "sender": {
+    "id": 1,
+    "login": "gitea",
+    "full_name": "Gitea",
+    "email": "someone@gitea.io",
+    "username": "gitea"
+  }
+}`
+
+var giteaPushEvent = `{
+  "secret": "3gEsCfjlV2ugRwgpU#w1*WaW*wa4NXgGmpCfkbG3",
+  "ref": "refs/heads/develop",
+  "before": "28e1879d029cb852e4844d9c718537df08844e03",
+  "after": "bffeb74224043ba2feb48d137756c8a9331c449a",
+  "compare_url": "http://localhost:3000/gitea/webhooks/compare/28e1879d029cb852e4844d9c718537df08844e03...bffeb74224043ba2feb48d137756c8a9331c449a",
+  "commits": [
+    {
+      "id": "bffeb74224043ba2feb48d137756c8a9331c449a",
+      "message": "a random commit message\n",
+      "url": "http://localhost:3000/gitea/webhooks/commit/bffeb74224043ba2feb48d137756c8a9331c449a",
+      "author": {
+        "name": "Gitea",
+        "email": "someone@gitea.io",
+        "username": "gitea"
+      },
+      "committer": {
+        "name": "Gitea",
+        "email": "someone@gitea.io",
+        "username": "gitea"
+      },
+      "timestamp": "2017-03-13T13:52:11-04:00",
+      "added": ["README.md"],
+      "removed": [],
+      "modified": []
+    }
+  ],
+  "head_commit": {
+    "id": "bffeb74224043ba2feb48d137756c8a9331c449a",
+    "message": "a random commit message\n",
+    "url": "http://localhost:3000/gitea/webhooks/commit/bffeb74224043ba2feb48d137756c8a9331c449a",
+    "author": {
+      "name": "Gitea",
+      "email": "someone@gitea.io",
+      "username": "gitea"
+    },
+    "committer": {
+      "name": "Gitea",
+      "email": "someone@gitea.io",
+      "username": "gitea"
+    },
+    "timestamp": "2017-03-13T13:52:11-04:00"
+  },
+  "repository": {
+    "id": 140,
+    "owner": {
+      "id": 1,
+      "login": "gitea",
+      "full_name": "Gitea",
+      "email": "someone@gitea.io",
+      "avatar_url": "https://localhost:3000/avatars/1",
+      "username": "gitea"
+    },
+    "name": "webhooks",
+    "full_name": "gitea/webhooks",
+    "description": "",
+    "private": false,
+    "fork": false,
+    "html_url": "http://localhost:3000/gitea/webhooks",
+    "ssh_url": "ssh://gitea@localhost:2222/gitea/webhooks.git",
+    "clone_url": "http://localhost:3000/gitea/webhooks.git",
+    "default_branch": "master"
+  },
+  "pusher": {
+    "id": 1,
+    "login": "gitea",
+    "full_name": "Gitea",
+    "email": "someone@gitea.io",
+    "avatar_url": "https://localhost:3000/avatars/1",
+    "username": "gitea"
+  },
+  "sender": {
+    "id": 1,
+    "login": "gitea",
+    "full_name": "Gitea",
+    "email": "someone@gitea.io",
+    "avatar_url": "https://localhost:3000/avatars/1",
+    "username": "gitea"
+  }
+}`
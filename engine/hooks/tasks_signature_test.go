@@ -0,0 +1,158 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func sign(newHash func() hash.Hash, secret string, body []byte) string {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body) // nolint
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func Test_checkRepositoryWebHookSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	secret := "my-secret"
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		headers map[string][]string
+		wantErr bool
+	}{
+		{
+			name:    "github valid signature",
+			secret:  secret,
+			header:  GithubHeader,
+			headers: map[string][]string{GithubSignatureHeader: {"sha256=" + sign(sha256.New, secret, body)}},
+		},
+		{
+			name:    "github legacy sha1 signature",
+			secret:  secret,
+			header:  GithubHeader,
+			headers: map[string][]string{GithubLegacySignatureHeader: {"sha1=" + sign(sha1.New, secret, body)}},
+		},
+		{
+			name:    "github wrong signature",
+			secret:  secret,
+			header:  GithubHeader,
+			headers: map[string][]string{GithubSignatureHeader: {"sha256=" + sign(sha256.New, "another-secret", body)}},
+			wantErr: true,
+		},
+		{
+			name:    "github unsigned request",
+			secret:  secret,
+			header:  GithubHeader,
+			wantErr: true,
+		},
+		{
+			name:    "gitlab valid token",
+			secret:  secret,
+			header:  GitlabHeader,
+			headers: map[string][]string{GitlabTokenHeader: {secret}},
+		},
+		{
+			name:    "gitlab wrong token",
+			secret:  secret,
+			header:  GitlabHeader,
+			headers: map[string][]string{GitlabTokenHeader: {"another-secret"}},
+			wantErr: true,
+		},
+		{
+			name:    "gitea valid signature",
+			secret:  secret,
+			header:  GiteaHeader,
+			headers: map[string][]string{GiteaSignatureHeader: {sign(sha256.New, secret, body)}},
+		},
+		{
+			name:    "gitea malformed signature",
+			secret:  secret,
+			header:  GiteaHeader,
+			headers: map[string][]string{GiteaSignatureHeader: {"not-an-hexadecimal-value"}},
+			wantErr: true,
+		},
+		{
+			name:    "bitbucket server valid signature",
+			secret:  secret,
+			header:  BitbucketHeader,
+			headers: map[string][]string{BitbucketSignatureHeader: {"sha256=" + sign(sha256.New, secret, body)}},
+		},
+		{
+			name:    "bitbucket cloud unsigned request",
+			secret:  secret,
+			header:  BitbucketCloudHeader,
+			wantErr: true,
+		},
+		{
+			name:   "webhook without secret",
+			header: GithubHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &sdk.TaskExecution{
+				UUID:   sdk.RandomString(10),
+				Type:   TypeRepoManagerWebHook,
+				Config: sdk.WorkflowNodeHookConfig{},
+				WebHook: &sdk.WebHookExecution{
+					RequestBody:   body,
+					RequestHeader: tt.headers,
+				},
+			}
+			if tt.secret != "" {
+				e.Config[sdk.HookConfigWebHookSecret] = sdk.WorkflowNodeHookConfigValue{Value: tt.secret}
+			}
+			err := checkRepositoryWebHookSignature(e, tt.header)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, strings.HasPrefix(err.Error(), errWebHookSignature), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_hideTaskSecrets(t *testing.T) {
+	config := sdk.WorkflowNodeHookConfig{
+		sdk.HookConfigProject:       {Value: "PROJ"},
+		sdk.HookConfigWebHookSecret: {Value: "my-secret"},
+	}
+	headers := map[string][]string{
+		GitlabHeader:      {"Push Hook"},
+		GitlabTokenHeader: {"my-secret"},
+	}
+	task := sdk.Task{
+		UUID:   sdk.RandomString(10),
+		Config: config,
+		Executions: []sdk.TaskExecution{{
+			Config:  config,
+			WebHook: &sdk.WebHookExecution{RequestHeader: headers},
+		}},
+	}
+
+	hideTaskSecrets(&task)
+
+	assert.NotContains(t, task.Config, sdk.HookConfigWebHookSecret)
+	assert.Equal(t, "PROJ", task.Config[sdk.HookConfigProject].Value)
+	assert.NotContains(t, task.Executions[0].Config, sdk.HookConfigWebHookSecret)
+	assert.Equal(t, []string{sdk.PasswordPlaceholder}, task.Executions[0].WebHook.RequestHeader[GitlabTokenHeader])
+	assert.Equal(t, []string{"Push Hook"}, task.Executions[0].WebHook.RequestHeader[GitlabHeader])
+
+	// the original values are not modified
+	assert.Equal(t, "my-secret", config[sdk.HookConfigWebHookSecret].Value)
+	assert.Equal(t, []string{"my-secret"}, headers[GitlabTokenHeader])
+}
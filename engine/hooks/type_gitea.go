@@ -0,0 +1,92 @@
+package hooks
+
+import "time"
+
+// GiteaPushEvent represents payload send by gitea on a push event
+type GiteaPushEvent struct {
+	Ref        string           `json:"ref"`
+	Before     string           `json:"before"`
+	After      string           `json:"after"`
+	CompareURL string           `json:"compare_url"`
+	Commits    []GiteaCommit    `json:"commits"`
+	HeadCommit *GiteaCommit     `json:"head_commit"`
+	Repository *GiteaRepository `json:"repository"`
+	Pusher     *GiteaUser       `json:"pusher"`
+	Sender     *GiteaUser       `json:"sender"`
+}
+
+// GiteaDeleteEvent represents payload send by gitea when a branch or a tag is deleted
+type GiteaDeleteEvent struct {
+	Ref        string           `json:"ref"`
+	RefType    string           `json:"ref_type"`
+	PusherType string           `json:"pusher_type"`
+	Repository *GiteaRepository `json:"repository"`
+	Sender     *GiteaUser       `json:"sender"`
+}
+
+// GiteaPullRequestEvent represents payload send by gitea on pull_request events, including reviews
+type GiteaPullRequestEvent struct {
+	Action      string            `json:"action"`
+	Number      int               `json:"number"`
+	PullRequest *GiteaPullRequest `json:"pull_request"`
+	Repository  *GiteaRepository  `json:"repository"`
+	Sender      *GiteaUser        `json:"sender"`
+}
+
+type GiteaPullRequest struct {
+	ID     int64             `json:"id"`
+	Number int               `json:"number"`
+	Title  string            `json:"title"`
+	State  string            `json:"state"`
+	Merged bool              `json:"merged"`
+	Head   GiteaPRBranchInfo `json:"head"`
+	Base   GiteaPRBranchInfo `json:"base"`
+	User   *GiteaUser        `json:"user"`
+}
+
+type GiteaPRBranchInfo struct {
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	Sha   string `json:"sha"`
+}
+
+type GiteaCommit struct {
+	ID        string          `json:"id"`
+	Message   string          `json:"message"`
+	URL       string          `json:"url"`
+	Author    GiteaCommitUser `json:"author"`
+	Committer GiteaCommitUser `json:"committer"`
+	Timestamp time.Time       `json:"timestamp"`
+	Added     []string        `json:"added,omitempty"`
+	Removed   []string        `json:"removed,omitempty"`
+	Modified  []string        `json:"modified,omitempty"`
+}
+
+type GiteaCommitUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+type GiteaUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+	Username  string `json:"username"`
+}
+
+type GiteaRepository struct {
+	ID            int64      `json:"id"`
+	Owner         *GiteaUser `json:"owner"`
+	Name          string     `json:"name"`
+	FullName      string     `json:"full_name"`
+	Description   string     `json:"description"`
+	Private       bool       `json:"private"`
+	Fork          bool       `json:"fork"`
+	HTMLURL       string     `json:"html_url"`
+	SSHURL        string     `json:"ssh_url"`
+	CloneURL      string     `json:"clone_url"`
+	DefaultBranch string     `json:"default_branch"`
+}
@@ -7,19 +7,34 @@ import (
 
 // GithubPushEvent represents payload send by github on a push event
 type GithubWebHookEvent struct {
-	Ref        string            `json:"ref"`
-	Before     string            `json:"before"`
-	After      string            `json:"after"`
-	Created    bool              `json:"created"`
-	Deleted    bool              `json:"deleted"`
-	Forced     bool              `json:"forced"`
-	BaseRef    interface{}       `json:"base_ref"`
-	Compare    string            `json:"compare"`
-	Commits    []GithubCommit    `json:"commits"`
-	HeadCommit *GithubCommit     `json:"head_commit"`
-	Repository *GithubRepository `json:"repository"`
-	Pusher     GithubOwner       `json:"pusher"`
-	Sender     GithubSender      `json:"sender"`
+	Ref         string             `json:"ref"`
+	Before      string             `json:"before"`
+	After       string             `json:"after"`
+	Created     bool               `json:"created"`
+	Deleted     bool               `json:"deleted"`
+	Forced      bool               `json:"forced"`
+	BaseRef     interface{}        `json:"base_ref"`
+	Compare     string             `json:"compare"`
+	Commits     []GithubCommit     `json:"commits"`
+	HeadCommit  *GithubCommit      `json:"head_commit"`
+	Repository  *GithubRepository  `json:"repository"`
+	Pusher      GithubOwner        `json:"pusher"`
+	Sender      GithubSender       `json:"sender"`
+	Action      string             `json:"action"`
+	PullRequest *GithubPullRequest `json:"pull_request"`
+}
+
+type GithubPullRequest struct {
+	Number int                  `json:"number"`
+	State  string               `json:"state"`
+	Title  string               `json:"title"`
+	Head   GithubPullRequestRef `json:"head"`
+	Base   GithubPullRequestRef `json:"base"`
+}
+
+type GithubPullRequestRef struct {
+	Ref string `json:"ref"`
+	Sha string `json:"sha"`
 }
 
 type GithubSender struct {
@@ -2,8 +2,14 @@ package hooks
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash"
 	"mime"
 	"net/http"
 	"net/url"
@@ -28,7 +34,13 @@ func (s *Service) doWebHookExecution(ctx context.Context, e *sdk.TaskExecution)
 }
 
 func getRepositoryHeader(whe *sdk.WebHookExecution, events []string) string {
-	if v, ok := whe.RequestHeader[GithubHeader]; ok && ((len(events) == 0 && v[0] == "push") || sdk.IsInArray(v[0], events)) {
+	// Gitea also sends Github headers, so it has to be checked first
+	if v, ok := whe.RequestHeader[GiteaHeader]; ok {
+		if (len(events) == 0 && v[0] == "push") || sdk.IsInArray(v[0], events) {
+			return GiteaHeader
+		}
+		return ""
+	} else if v, ok := whe.RequestHeader[GithubHeader]; ok && ((len(events) == 0 && v[0] == "push") || sdk.IsInArray(v[0], events)) {
 		return GithubHeader
 	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && ((len(events) == 0 && v[0] == "Push Hook") || sdk.IsInArray(v[0], events)) {
 		return GitlabHeader
@@ -50,7 +62,13 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 		events = strings.Split(t.Config[sdk.HookConfigEventFilter].Value, ";")
 	}
 
-	switch getRepositoryHeader(t.WebHook, events) {
+	repositoryHeader := getRepositoryHeader(t.WebHook, events)
+	if err := checkRepositoryWebHookSignature(t, repositoryHeader); err != nil {
+		log.Warning(ctx, "executeRepositoryWebHook> %s rejected: %v", t.UUID, err)
+		return nil, err
+	}
+
+	switch repositoryHeader {
 	case GithubHeader:
 		headerValue := t.WebHook.RequestHeader[GithubHeader][0]
 		payload, err := s.generatePayloadFromGithubRequest(ctx, t, headerValue)
@@ -60,6 +78,15 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 		if payload != nil {
 			payloads = append(payloads, payload)
 		}
+	case GiteaHeader:
+		headerValue := t.WebHook.RequestHeader[GiteaHeader][0]
+		payload, err := s.generatePayloadFromGiteaRequest(ctx, t, headerValue)
+		if err != nil {
+			return nil, err
+		}
+		if payload != nil {
+			payloads = append(payloads, payload)
+		}
 	case GitlabHeader:
 		headerValue := t.WebHook.RequestHeader[GitlabHeader][0]
 		payload, err := s.generatePayloadFromGitlabRequest(ctx, t, headerValue)
@@ -110,6 +137,62 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 	return hs, nil
 }
 
+// errWebHookSignature prefixes the errors of the repository webhook executions that are rejected, they are not retried
+const errWebHookSignature = "invalid webhook signature"
+
+// checkRepositoryWebHookSignature checks that the request was sent by the repository manager with the secret of the webhook.
+// Webhooks created before the secrets generation have no secret, their requests are not checked.
+func checkRepositoryWebHookSignature(t *sdk.TaskExecution, repositoryHeader string) error {
+	secret := t.Config[sdk.HookConfigWebHookSecret].Value
+	if secret == "" || repositoryHeader == "" {
+		return nil
+	}
+	headers := http.Header(t.WebHook.RequestHeader)
+
+	switch repositoryHeader {
+	case GitlabHeader:
+		token := headers.Get(GitlabTokenHeader)
+		if token == "" {
+			return fmt.Errorf("%s: missing header %s", errWebHookSignature, GitlabTokenHeader)
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return fmt.Errorf("%s: wrong token in header %s", errWebHookSignature, GitlabTokenHeader)
+		}
+		return nil
+	case GiteaHeader:
+		return checkHMACSignature(t.WebHook.RequestBody, secret, GiteaSignatureHeader, headers.Get(GiteaSignatureHeader), "", sha256.New)
+	case GithubHeader:
+		if signature := headers.Get(GithubSignatureHeader); signature != "" || headers.Get(GithubLegacySignatureHeader) == "" {
+			return checkHMACSignature(t.WebHook.RequestBody, secret, GithubSignatureHeader, signature, "sha256=", sha256.New)
+		}
+		// Old Github Enterprise versions only sign with sha1
+		return checkHMACSignature(t.WebHook.RequestBody, secret, GithubLegacySignatureHeader, headers.Get(GithubLegacySignatureHeader), "sha1=", sha1.New)
+	case BitbucketHeader, BitbucketCloudHeader:
+		return checkHMACSignature(t.WebHook.RequestBody, secret, BitbucketSignatureHeader, headers.Get(BitbucketSignatureHeader), "sha256=", sha256.New)
+	}
+	return nil
+}
+
+// checkHMACSignature checks the hexadecimal HMAC of the body given in a header with an optional prefix like "sha256="
+func checkHMACSignature(body []byte, secret, header, signature, prefix string, newHash func() hash.Hash) error {
+	if signature == "" {
+		return fmt.Errorf("%s: missing header %s", errWebHookSignature, header)
+	}
+	if !strings.HasPrefix(signature, prefix) {
+		return fmt.Errorf("%s: unsupported algorithm in header %s", errWebHookSignature, header)
+	}
+	given, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return fmt.Errorf("%s: malformed header %s", errWebHookSignature, header)
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body) // nolint
+	if !hmac.Equal(given, mac.Sum(nil)) {
+		return fmt.Errorf("%s: wrong signature in header %s", errWebHookSignature, header)
+	}
+	return nil
+}
+
 func executeWebHook(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
 	// Prepare a struct to send to CDS API
 	h := sdk.WorkflowNodeRunHookEvent{
@@ -29,6 +29,13 @@ func (s *Service) checkOrCreateFS(r *sdk.OperationRepo) error {
 		return sdk.WrapError(os.MkdirAll(path, os.FileMode(0700)), "checkOrCreateFS> unable to create directory %s", path)
 	}
 	if fi.IsDir() {
+		// Repositories used to be fully cloned in this directory, remove them to use a mirror instead
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			if err := os.RemoveAll(path); err != nil {
+				return sdk.WrapError(err, "checkOrCreateFS> unable to remove directory %s", path)
+			}
+			return sdk.WrapError(os.MkdirAll(path, os.FileMode(0700)), "checkOrCreateFS> unable to create directory %s", path)
+		}
 		return nil
 	}
 	r.Basedir = path
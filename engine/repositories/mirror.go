@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	mirrorDirName   = "mirror.git"
+	worktreeDirName = "worktree"
+)
+
+// sparseCheckoutPatterns are the only paths checked out in the worktrees, as code files are all in the .cds directory
+var sparseCheckoutPatterns = []string{"/.cds/"}
+
+// mirrorDir returns the path of the bare mirror of the repository
+func (s Service) mirrorDir(r *sdk.OperationRepo) string {
+	return filepath.Join(s.Cfg.Basedir, r.ID(), mirrorDirName)
+}
+
+// syncMirror creates the bare mirror of the repository or fetches it incrementally. It returns true if the mirror has been created.
+func (s *Service) syncMirror(ctx context.Context, r *sdk.OperationRepo) (bool, error) {
+	dir := s.mirrorDir(r)
+	u, err := remoteURL(r)
+	if err != nil {
+		return false, err
+	}
+
+	var created bool
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); os.IsNotExist(err) {
+		log.Info(ctx, "Repositories> syncMirror> creating mirror of %s into %s", r.URL, dir)
+		if err := os.RemoveAll(dir); err != nil {
+			return false, sdk.WithStack(err)
+		}
+		if err := os.MkdirAll(dir, os.FileMode(0700)); err != nil {
+			return false, sdk.WithStack(err)
+		}
+		if _, err := runGitCommand(ctx, dir, r, "init", "--bare"); err != nil {
+			return false, err
+		}
+		if _, err := runGitCommand(ctx, dir, r, "remote", "add", "origin", u); err != nil {
+			return false, err
+		}
+		if _, err := runGitCommand(ctx, dir, r, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+			return false, err
+		}
+		created = true
+	} else if _, err := runGitCommand(ctx, dir, r, "remote", "set-url", "origin", u); err != nil {
+		// Credentials could have changed since the mirror was created
+		return false, err
+	}
+
+	if _, err := runGitCommand(ctx, dir, r, "fetch", "--prune", "--tags", "origin"); err != nil {
+		return false, err
+	}
+	if created {
+		if _, err := runGitCommand(ctx, dir, r, "remote", "set-head", "origin", "--auto"); err != nil {
+			return false, err
+		}
+	}
+	return created, nil
+}
+
+// checkOrCreateWorktree adds a worktree of the mirror on the default branch, limited to the as code files.
+func (s *Service) checkOrCreateWorktree(ctx context.Context, r *sdk.OperationRepo) error {
+	if _, err := os.Stat(filepath.Join(r.Basedir, ".git")); err == nil {
+		return nil
+	}
+
+	dir := s.mirrorDir(r)
+	path, err := filepath.Abs(r.Basedir)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	log.Info(ctx, "Repositories> checkOrCreateWorktree> adding worktree of %s into %s", r.URL, path)
+
+	// Clean a previous worktree that could have been partially created
+	if err := os.RemoveAll(path); err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := runGitCommand(ctx, dir, r, "worktree", "prune"); err != nil {
+		return err
+	}
+
+	head, err := runGitCommand(ctx, dir, r, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err != nil {
+		return err
+	}
+	defaultBranch := strings.TrimPrefix(strings.TrimSpace(head), "origin/")
+
+	if _, err := runGitCommand(ctx, dir, r, "worktree", "add", "--no-checkout", "-B", defaultBranch, path, "origin/"+defaultBranch); err != nil {
+		return err
+	}
+	args := append([]string{"sparse-checkout", "set", "--no-cone"}, sparseCheckoutPatterns...)
+	if _, err := runGitCommand(ctx, path, r, args...); err != nil {
+		return err
+	}
+	if _, err := runGitCommand(ctx, path, r, "reset", "--hard"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// remoteURL returns the url of the repository, with the credentials for a http connection.
+func remoteURL(r *sdk.OperationRepo) (string, error) {
+	if r.RepositoryStrategy.ConnectionType == "ssh" || r.RepositoryStrategy.User == "" || r.RepositoryStrategy.Password == "" {
+		return r.URL, nil
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return "", sdk.WrapError(err, "invalid repository url")
+	}
+	u.User = url.UserPassword(r.RepositoryStrategy.User, r.RepositoryStrategy.Password)
+	return u.String(), nil
+}
+
+func runGitCommand(ctx context.Context, dir string, r *sdk.OperationRepo, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LANG=en_US")
+
+	if r.RepositoryStrategy.ConnectionType == "ssh" {
+		f, err := ioutil.TempFile("", "cds-repositories-key")
+		if err != nil {
+			return "", sdk.WithStack(err)
+		}
+		defer os.Remove(f.Name()) // nolint
+		if _, err := f.WriteString(r.RepositoryStrategy.SSHKeyContent); err != nil {
+			f.Close() // nolint
+			return "", sdk.WithStack(err)
+		}
+		if err := f.Close(); err != nil {
+			return "", sdk.WithStack(err)
+		}
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -i "+f.Name()+" -o StrictHostKeyChecking=no")
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	log.Debug("Repositories> runGitCommand> git %s", hidePassword(r, strings.Join(args, " ")))
+	if err := cmd.Run(); err != nil {
+		return "", sdk.WrapError(err, "git %s failed: %s", args[0], hidePassword(r, strings.TrimSpace(stderr.String())))
+	}
+	return stdout.String(), nil
+}
+
+func hidePassword(r *sdk.OperationRepo, s string) string {
+	if r.RepositoryStrategy.Password == "" {
+		return s
+	}
+	return strings.Replace(s, r.RepositoryStrategy.Password, "***", -1)
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSyncMirrorAndWorktree(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "cds-repositories")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp) // nolint
+
+	// Create a repository with as code files and other files
+	src := filepath.Join(tmp, "src")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, ".cds"), os.FileMode(0700)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, ".cds", "w.yml"), []byte("name: w"), os.FileMode(0600)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "main.go"), []byte("package main"), os.FileMode(0600)))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.email=cds@localhost", "-c", "user.name=cds", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = src
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	s := Service{Cfg: Configuration{Basedir: filepath.Join(tmp, "repositories")}}
+	r := s.Repo(sdk.Operation{URL: src})
+	require.NoError(t, s.checkOrCreateFS(r))
+
+	created, err := s.syncMirror(context.TODO(), r)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	created, err = s.syncMirror(context.TODO(), r)
+	require.NoError(t, err)
+	require.False(t, created, "mirror should be fetched")
+
+	require.NoError(t, s.checkOrCreateWorktree(context.TODO(), r))
+	_, err = os.Stat(filepath.Join(r.Basedir, ".cds", "w.yml"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(r.Basedir, "main.go"))
+	require.True(t, os.IsNotExist(err), "only the .cds directory should be checked out")
+
+	// Worktree already exists
+	require.NoError(t, s.checkOrCreateWorktree(context.TODO(), r))
+}
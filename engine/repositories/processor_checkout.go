@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -13,6 +14,11 @@ func (s *Service) processCheckout(ctx context.Context, op *sdk.Operation) error
 		return sdk.WrapError(err, "unable to process gitclone")
 	}
 
+	start := time.Now()
+	defer func() {
+		op.Timings.Checkout += time.Since(start).Milliseconds()
+	}()
+
 	if err := gitRepo.ResetHard("origin/" + currentBranch); err != nil {
 		log.Error(ctx, "Repositories> processCheckout> ResetHard> [%s] Error: %v", op.UUID, err)
 		return err
@@ -66,6 +72,6 @@ func (s *Service) processCheckout(ctx context.Context, op *sdk.Operation) error
 		}
 	}
 
-	log.Info(ctx, "Repositories> processCheckout> repository %s ready", op.URL)
+	log.Info(ctx, "Repositories> processCheckout> repository %s ready (clone: %dms, fetch: %dms)", op.URL, op.Timings.Clone, op.Timings.Fetch)
 	return nil
 }
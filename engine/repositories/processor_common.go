@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/fsamin/go-repo"
 
@@ -18,6 +19,30 @@ func (s *Service) processGitClone(ctx context.Context, op *sdk.Operation) (repo.
 		return gitRepo, "", "", err
 	}
 
+	if op.Timings == nil {
+		op.Timings = new(sdk.OperationTimings)
+	}
+
+	// Fetch the mirror of the repository, or create it
+	start := time.Now()
+	created, err := s.syncMirror(ctx, r)
+	if err != nil {
+		log.Error(ctx, "Repositories> processGitClone> syncMirror> [%s] error %v", op.UUID, err)
+		return gitRepo, "", "", err
+	}
+	if created {
+		op.Timings.Clone = time.Since(start).Milliseconds()
+	} else {
+		op.Timings.Fetch = time.Since(start).Milliseconds()
+	}
+
+	start = time.Now()
+	if err := s.checkOrCreateWorktree(ctx, r); err != nil {
+		log.Error(ctx, "Repositories> processGitClone> checkOrCreateWorktree> [%s] error %v", op.UUID, err)
+		return gitRepo, "", "", err
+	}
+	op.Timings.Checkout = time.Since(start).Milliseconds()
+
 	// Get the git repository
 	opts := []repo.Option{repo.WithVerbose()}
 	if op.RepositoryStrategy.ConnectionType == "ssh" {
@@ -27,14 +52,10 @@ func (s *Service) processGitClone(ctx context.Context, op *sdk.Operation) (repo.
 		opts = append(opts, repo.WithHTTPAuth(op.RepositoryStrategy.User, op.RepositoryStrategy.Password))
 	}
 
-	gitRepo, err := repo.New(r.Basedir, opts...)
+	gitRepo, err = repo.New(r.Basedir, opts...)
 	if err != nil {
-		log.Info(ctx, "Repositories> processGitClone> cloning %s into %s", r.URL, r.Basedir)
-		gitRepo, err = repo.Clone(r.Basedir, r.URL, opts...)
-		if err != nil {
-			log.Error(ctx, "Repositories> processGitClone> Clone> [%s] error %v", op.UUID, err)
-			return gitRepo, "", "", err
-		}
+		log.Error(ctx, "Repositories> processGitClone> repo.New> [%s] error %v", op.UUID, err)
+		return gitRepo, "", "", err
 	}
 
 	f, err := gitRepo.FetchURL()
@@ -44,7 +44,7 @@ type Configuration struct {
 func (s Service) Repo(op sdk.Operation) *sdk.OperationRepo {
 	r := new(sdk.OperationRepo)
 	r.URL = op.URL
-	r.Basedir = filepath.Join(s.Cfg.Basedir, r.ID())
+	r.Basedir = filepath.Join(s.Cfg.Basedir, r.ID(), worktreeDirName)
 	r.RepositoryStrategy = op.RepositoryStrategy
 	return r
 }
@@ -0,0 +1,27 @@
+-- +migrate Up
+ALTER TABLE "w_node_context" ADD COLUMN IF NOT EXISTS lock_name VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "environment" ADD COLUMN IF NOT EXISTS lock_name VARCHAR(256) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS "workflow_node_run_lock" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  name VARCHAR(256) NOT NULL,
+  workflow_id BIGINT NOT NULL,
+  workflow_name VARCHAR(256) NOT NULL,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_run_number BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  workflow_node_name VARCHAR(256) NOT NULL,
+  held BOOLEAN NOT NULL DEFAULT false,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_LOCK_PROJECT', 'workflow_node_run_lock', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_LOCK_WORKFLOW_NODE_RUN', 'workflow_node_run_lock', 'workflow_node_run', 'workflow_node_run_id', 'id');
+SELECT create_unique_index('workflow_node_run_lock', 'IDX_WORKFLOW_NODE_RUN_LOCK_NODE_RUN_NAME', 'workflow_node_run_id,name');
+SELECT create_index('workflow_node_run_lock', 'IDX_WORKFLOW_NODE_RUN_LOCK_PROJECT_NAME', 'project_id,name');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_node_run_lock";
+ALTER TABLE "w_node_context" DROP COLUMN lock_name;
+ALTER TABLE "environment" DROP COLUMN lock_name;
@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run_job_logs ADD COLUMN IF NOT EXISTS storage_chunks INT NOT NULL DEFAULT 0;
+ALTER TABLE workflow_node_run_job_logs ADD COLUMN IF NOT EXISTS storage_size BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE workflow_node_run_job_logs ADD COLUMN IF NOT EXISTS storage_first_chunk INT NOT NULL DEFAULT 0;
+ALTER TABLE requirement_service_logs ADD COLUMN IF NOT EXISTS storage_chunks INT NOT NULL DEFAULT 0;
+ALTER TABLE requirement_service_logs ADD COLUMN IF NOT EXISTS storage_size BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE workflow_node_run_job_logs DROP COLUMN IF EXISTS storage_chunks;
+ALTER TABLE workflow_node_run_job_logs DROP COLUMN IF EXISTS storage_size;
+ALTER TABLE workflow_node_run_job_logs DROP COLUMN IF EXISTS storage_first_chunk;
+ALTER TABLE requirement_service_logs DROP COLUMN IF EXISTS storage_chunks;
+ALTER TABLE requirement_service_logs DROP COLUMN IF EXISTS storage_size;
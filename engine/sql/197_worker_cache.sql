@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "worker_cache" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  integration_name VARCHAR(256) NOT NULL,
+  tag VARCHAR(256) NOT NULL,
+  size BIGINT NOT NULL DEFAULT 0,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_access TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  confirmed BOOLEAN NOT NULL DEFAULT false
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKER_CACHE_PROJECT', 'worker_cache', 'project', 'project_id', 'id');
+SELECT create_unique_index('worker_cache', 'IDX_WORKER_CACHE_PROJECT_INTEGRATION_TAG', 'project_id,integration_name,tag');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "worker_cache";
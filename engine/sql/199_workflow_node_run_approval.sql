@@ -0,0 +1,42 @@
+-- +migrate Up
+ALTER TABLE "w_node_context" ADD COLUMN IF NOT EXISTS approval JSONB;
+
+CREATE TABLE IF NOT EXISTS "workflow_node_run_approval" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  workflow_id BIGINT NOT NULL,
+  workflow_name VARCHAR(256) NOT NULL,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_run_number BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  workflow_node_name VARCHAR(256) NOT NULL,
+  min_approvals BIGINT NOT NULL,
+  approver_groups JSONB,
+  timeout BIGINT NOT NULL DEFAULT 0,
+  status VARCHAR(50) NOT NULL,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_APPROVAL_PROJECT', 'workflow_node_run_approval', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_APPROVAL_WORKFLOW_NODE_RUN', 'workflow_node_run_approval', 'workflow_node_run', 'workflow_node_run_id', 'id');
+SELECT create_unique_index('workflow_node_run_approval', 'IDX_WORKFLOW_NODE_RUN_APPROVAL_NODE_RUN', 'workflow_node_run_id');
+SELECT create_index('workflow_node_run_approval', 'IDX_WORKFLOW_NODE_RUN_APPROVAL_STATUS', 'status');
+
+CREATE TABLE IF NOT EXISTS "workflow_node_run_approval_decision" (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_node_run_approval_id BIGINT NOT NULL,
+  authentified_user_id VARCHAR(36) NOT NULL,
+  username VARCHAR(256) NOT NULL,
+  approved BOOLEAN NOT NULL,
+  comment TEXT NOT NULL DEFAULT '',
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_APPROVAL_DECISION_APPROVAL', 'workflow_node_run_approval_decision', 'workflow_node_run_approval', 'workflow_node_run_approval_id', 'id');
+SELECT create_unique_index('workflow_node_run_approval_decision', 'IDX_WORKFLOW_NODE_RUN_APPROVAL_DECISION_USER', 'workflow_node_run_approval_id,authentified_user_id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_node_run_approval_decision";
+DROP TABLE IF EXISTS "workflow_node_run_approval";
+ALTER TABLE "w_node_context" DROP COLUMN approval;
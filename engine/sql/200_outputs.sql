@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE action_edge ADD COLUMN IF NOT EXISTS step_id TEXT DEFAULT '';
+ALTER TABLE "action" ADD COLUMN IF NOT EXISTS outputs JSONB;
+ALTER TABLE workflow_node_run ADD COLUMN IF NOT EXISTS outputs JSONB;
+
+-- +migrate Down
+ALTER TABLE action_edge DROP COLUMN step_id;
+ALTER TABLE "action" DROP COLUMN outputs;
+ALTER TABLE workflow_node_run DROP COLUMN outputs;
@@ -0,0 +1,32 @@
+-- +migrate Up
+ALTER TABLE "w_node_context" ADD COLUMN IF NOT EXISTS preview_environment JSONB;
+
+CREATE TABLE IF NOT EXISTS "environment_preview" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  environment_id BIGINT NOT NULL,
+  environment_name VARCHAR(256) NOT NULL,
+  base_environment_id BIGINT NOT NULL,
+  workflow_id BIGINT NOT NULL,
+  workflow_name VARCHAR(256) NOT NULL,
+  workflow_run_number BIGINT NOT NULL,
+  workflow_node_name VARCHAR(256) NOT NULL,
+  repository VARCHAR(256) NOT NULL DEFAULT '',
+  branch VARCHAR(256) NOT NULL DEFAULT '',
+  pull_request_id VARCHAR(256) NOT NULL DEFAULT '',
+  teardown_pipeline VARCHAR(256) NOT NULL DEFAULT '',
+  status VARCHAR(50) NOT NULL,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_PREVIEW_PROJECT', 'environment_preview', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_PREVIEW_ENVIRONMENT', 'environment_preview', 'environment', 'environment_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_PREVIEW_WORKFLOW', 'environment_preview', 'workflow', 'workflow_id', 'id');
+SELECT create_unique_index('environment_preview', 'IDX_ENVIRONMENT_PREVIEW_ENVIRONMENT', 'environment_id');
+SELECT create_index('environment_preview', 'IDX_ENVIRONMENT_PREVIEW_BRANCH', 'workflow_id,branch');
+SELECT create_index('environment_preview', 'IDX_ENVIRONMENT_PREVIEW_PULL_REQUEST', 'workflow_id,pull_request_id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "environment_preview";
+ALTER TABLE "w_node_context" DROP COLUMN preview_environment;
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "job_quota" (
+  id BIGSERIAL PRIMARY KEY,
+  type VARCHAR(50) NOT NULL,
+  target VARCHAR(256) NOT NULL,
+  max_running_jobs BIGINT NOT NULL,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_unique_index('job_quota', 'IDX_JOB_QUOTA_TYPE_TARGET', 'type,target');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "job_quota";
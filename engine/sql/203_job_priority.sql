@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE "w_node_context" ADD COLUMN IF NOT EXISTS priority VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE "workflow_node_run_job" ADD COLUMN IF NOT EXISTS priority VARCHAR(50) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE "w_node_context" DROP COLUMN priority;
+ALTER TABLE "workflow_node_run_job" DROP COLUMN priority;
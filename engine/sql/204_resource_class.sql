@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "resource_class" (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(256) NOT NULL,
+  description TEXT,
+  cpu DOUBLE PRECISION NOT NULL,
+  memory BIGINT NOT NULL,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_unique_index('resource_class', 'IDX_RESOURCE_CLASS_NAME', 'name');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "resource_class";
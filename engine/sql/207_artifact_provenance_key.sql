@@ -0,0 +1,5 @@
+-- +migrate Up
+UPDATE project_key SET builtin = true WHERE name = 'proj-artifact-provenance';
+
+-- +migrate Down
+UPDATE project_key SET builtin = false WHERE name = 'proj-artifact-provenance';
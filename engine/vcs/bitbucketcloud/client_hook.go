@@ -31,6 +31,7 @@ func (client *bitbucketcloudClient) CreateHook(ctx context.Context, repo string,
 		Active:      true,
 		Events:      hook.Events,
 		URL:         hook.URL,
+		Secret:      hook.Secret,
 	}
 	b, err := json.Marshal(r)
 	if err != nil {
@@ -144,6 +145,7 @@ func (client *bitbucketcloudClient) UpdateHook(ctx context.Context, repo string,
 	}
 
 	bitbucketHook.Events = hook.Events
+	bitbucketHook.Secret = hook.Secret
 	b, err := json.Marshal(bitbucketHook)
 	if err != nil {
 		return sdk.WrapError(err, "cannot marshal body %+v", bitbucketHook)
@@ -19,6 +19,7 @@ type WebhookCreate struct {
 	URL         string   `json:"url"`
 	Active      bool     `json:"active"`
 	Events      []string `json:"events"`
+	Secret      string   `json:"secret,omitempty"`
 }
 
 type Webhook struct {
@@ -47,6 +48,7 @@ type Webhook struct {
 	Type   string   `json:"type"`
 	Events []string `json:"events"`
 	UUID   string   `json:"uuid"`
+	Secret string   `json:"secret,omitempty"`
 }
 
 type Webhooks struct {
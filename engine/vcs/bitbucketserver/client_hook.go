@@ -94,6 +94,9 @@ func (b *bitbucketClient) CreateHook(ctx context.Context, repo string, hook *sdk
 		Name:          repo,
 		Configuration: make(map[string]string),
 	}
+	if hook.Secret != "" {
+		request.Configuration["secret"] = hook.Secret
+	}
 
 	values, err := json.Marshal(&request)
 	if err != nil {
@@ -123,6 +126,12 @@ func (b *bitbucketClient) UpdateHook(ctx context.Context, repo string, hook *sdk
 	}
 
 	bitbucketHook.Events = hook.Events
+	if hook.Secret != "" {
+		if bitbucketHook.Configuration == nil {
+			bitbucketHook.Configuration = make(map[string]string)
+		}
+		bitbucketHook.Configuration["secret"] = hook.Secret
+	}
 
 	url := fmt.Sprintf("/projects/%s/repos/%s/webhooks/%d", project, slug, bitbucketHook.ID)
 
@@ -0,0 +1,68 @@
+package gitea
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Branches returns list of branches for a repo
+func (client *giteaClient) Branches(ctx context.Context, fullname string) ([]sdk.VCSBranch, error) {
+	repo, err := client.repoByFullname(ctx, fullname)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get repo by fullname")
+	}
+	path, err := repoPath(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []Branch
+	if err := getPaginated(ctx, nil, func(params url.Values) (int, error) {
+		var page []Branch
+		if err := client.do(ctx, "GET", path+"/branches", params, nil, &page); err != nil {
+			return 0, sdk.WrapError(err, "unable to get branches")
+		}
+		branches = append(branches, page...)
+		return len(page), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	branchesResult := make([]sdk.VCSBranch, 0, len(branches))
+	for _, b := range branches {
+		branchesResult = append(branchesResult, b.ToVCSBranch(repo.DefaultBranch))
+	}
+	return branchesResult, nil
+}
+
+// Branch returns only detail of a branch
+func (client *giteaClient) Branch(ctx context.Context, fullname, theBranch string) (*sdk.VCSBranch, error) {
+	repo, err := client.repoByFullname(ctx, fullname)
+	if err != nil {
+		return nil, err
+	}
+	path, err := repoPath(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var branch Branch
+	if err := client.do(ctx, "GET", path+"/branches/"+escapeRef(theBranch), nil, nil, &branch); err != nil {
+		return nil, sdk.WrapError(err, "unable to get branch %s", theBranch)
+	}
+
+	b := branch.ToVCSBranch(repo.DefaultBranch)
+	return &b, nil
+}
+
+// ToVCSBranch returns a sdk.VCSBranch from a Gitea branch
+func (b Branch) ToVCSBranch(defaultBranch string) sdk.VCSBranch {
+	return sdk.VCSBranch{
+		ID:           b.Name,
+		DisplayID:    b.Name,
+		LatestCommit: b.Commit.ID,
+		Default:      b.Name == defaultBranch,
+	}
+}
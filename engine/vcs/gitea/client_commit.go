@@ -0,0 +1,102 @@
+package gitea
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Commits returns the commits list on a branch between a commit SHA (since) until another commit SHA (until).
+// If until is empty, the last commit of the branch is used. If since is empty, only the last commits are returned.
+func (client *giteaClient) Commits(ctx context.Context, repo, theBranch, since, until string) ([]sdk.VCSCommit, error) {
+	head := until
+	if head == "" {
+		head = theBranch
+	}
+	if since != "" {
+		return client.CommitsBetweenRefs(ctx, repo, since, head)
+	}
+
+	path, err := repoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("sha", head)
+	params.Set("stat", "false")
+	params.Set("limit", strconv.Itoa(pageLimit))
+	var commits []Commit
+	if err := client.do(ctx, "GET", path+"/commits", params, nil, &commits); err != nil {
+		return nil, sdk.WrapError(err, "unable to get commits of %s", head)
+	}
+
+	commitsResult := make([]sdk.VCSCommit, 0, len(commits))
+	for _, c := range commits {
+		commitsResult = append(commitsResult, c.ToVCSCommit())
+	}
+	return commitsResult, nil
+}
+
+// Commit Get a single commit
+func (client *giteaClient) Commit(ctx context.Context, repo, hash string) (sdk.VCSCommit, error) {
+	path, err := repoPath(repo)
+	if err != nil {
+		return sdk.VCSCommit{}, err
+	}
+	var c Commit
+	if err := client.do(ctx, "GET", path+"/git/commits/"+url.PathEscape(hash), nil, nil, &c); err != nil {
+		return sdk.VCSCommit{}, sdk.WrapError(err, "unable to get commit %s", hash)
+	}
+	return c.ToVCSCommit(), nil
+}
+
+// CommitsBetweenRefs returns the commits reachable from head that are not reachable from base
+func (client *giteaClient) CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]sdk.VCSCommit, error) {
+	path, err := repoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+	if base == "" {
+		base = "HEAD"
+	}
+	if head == "" {
+		head = "HEAD"
+	}
+
+	var compare Compare
+	if err := client.do(ctx, "GET", path+"/compare/"+escapeRef(base)+"..."+escapeRef(head), nil, nil, &compare); err != nil {
+		return nil, sdk.WrapError(err, "unable to compare %s and %s", base, head)
+	}
+
+	commitsResult := make([]sdk.VCSCommit, 0, len(compare.Commits))
+	for _, c := range compare.Commits {
+		commitsResult = append(commitsResult, c.ToVCSCommit())
+	}
+	return commitsResult, nil
+}
+
+// ToVCSCommit returns a sdk.VCSCommit from a Gitea commit
+func (c Commit) ToVCSCommit() sdk.VCSCommit {
+	commit := sdk.VCSCommit{
+		Hash:      c.SHA,
+		Message:   c.Commit.Message,
+		URL:       c.HTMLURL,
+		Timestamp: c.Commit.Author.Date.Unix() * 1000,
+		Author: sdk.VCSAuthor{
+			Name:        c.Commit.Author.Name,
+			DisplayName: c.Commit.Author.Name,
+			Email:       c.Commit.Author.Email,
+		},
+	}
+	// the author is set only if the commit email matches a Gitea user
+	if c.Author != nil {
+		commit.Author.Name = c.Author.Login
+		if c.Author.FullName != "" {
+			commit.Author.DisplayName = c.Author.FullName
+		}
+		commit.Author.Avatar = c.Author.AvatarURL
+	}
+	return commit
+}
@@ -0,0 +1,34 @@
+package gitea
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// ListForks returns the forks of a repository
+func (client *giteaClient) ListForks(ctx context.Context, repo string) ([]sdk.VCSRepo, error) {
+	path, err := repoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []Repository
+	if err := getPaginated(ctx, nil, func(params url.Values) (int, error) {
+		var page []Repository
+		if err := client.do(ctx, "GET", path+"/forks", params, nil, &page); err != nil {
+			return 0, sdk.WrapError(err, "unable to get forks")
+		}
+		repos = append(repos, page...)
+		return len(page), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	responseRepos := make([]sdk.VCSRepo, 0, len(repos))
+	for _, repo := range repos {
+		responseRepos = append(responseRepos, repo.ToVCSRepo())
+	}
+	return responseRepos, nil
+}
@@ -0,0 +1,126 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (client *giteaClient) hookURL(u string) string {
+	if client.proxyURL == "" {
+		return u
+	}
+	lastIndexSlash := strings.LastIndex(u, "/")
+	if client.proxyURL[len(client.proxyURL)-1] == '/' {
+		lastIndexSlash++
+	}
+	return client.proxyURL + u[lastIndexSlash:]
+}
+
+// CreateHook creates a webhook that sends json payloads to CDS
+func (client *giteaClient) CreateHook(ctx context.Context, repo string, hook *sdk.VCSHook) error {
+	path, err := repoPath(repo)
+	if err != nil {
+		return err
+	}
+	hook.URL = client.hookURL(hook.URL)
+	if len(hook.Events) == 0 {
+		hook.Events = []string{"push"}
+	}
+
+	r := CreateHook{
+		Type: "gitea",
+		Config: map[string]string{
+			"url":          hook.URL,
+			"content_type": "json",
+		},
+		Events: hook.Events,
+		Active: true,
+	}
+	if hook.Secret != "" {
+		r.Config["secret"] = hook.Secret
+	}
+	var webhook Hook
+	if err := client.do(ctx, "POST", path+"/hooks", nil, r, &webhook); err != nil {
+		return sdk.WrapError(err, "unable to create webhook on %s", repo)
+	}
+	hook.ID = strconv.FormatInt(webhook.ID, 10)
+	return nil
+}
+
+func (client *giteaClient) getHooks(ctx context.Context, repo string) ([]Hook, error) {
+	path, err := repoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []Hook
+	if err := getPaginated(ctx, nil, func(params url.Values) (int, error) {
+		var page []Hook
+		if err := client.do(ctx, "GET", path+"/hooks", params, nil, &page); err != nil {
+			return 0, sdk.WrapError(err, "unable to get hooks")
+		}
+		webhooks = append(webhooks, page...)
+		return len(page), nil
+	}); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetHook returns the webhook of the repository that has given url
+func (client *giteaClient) GetHook(ctx context.Context, repo, webhookURL string) (sdk.VCSHook, error) {
+	hooks, err := client.getHooks(ctx, repo)
+	if err != nil {
+		return sdk.VCSHook{}, err
+	}
+	for _, h := range hooks {
+		if h.Config["url"] == webhookURL {
+			return sdk.VCSHook{
+				ID:          strconv.FormatInt(h.ID, 10),
+				Events:      h.Events,
+				URL:         h.Config["url"],
+				ContentType: h.Config["content_type"],
+				Disable:     !h.Active,
+			}, nil
+		}
+	}
+	return sdk.VCSHook{}, sdk.WithStack(sdk.ErrNotFound)
+}
+
+// UpdateHook updates the url and the events of a webhook
+func (client *giteaClient) UpdateHook(ctx context.Context, repo string, hook *sdk.VCSHook) error {
+	path, err := repoPath(repo)
+	if err != nil {
+		return err
+	}
+	hook.URL = client.hookURL(hook.URL)
+	if len(hook.Events) == 0 {
+		hook.Events = []string{"push"}
+	}
+
+	r := EditHook{
+		Config: map[string]string{
+			"url":          hook.URL,
+			"content_type": "json",
+		},
+		Events: hook.Events,
+		Active: true,
+	}
+	if hook.Secret != "" {
+		r.Config["secret"] = hook.Secret
+	}
+	return sdk.WrapError(client.do(ctx, "PATCH", fmt.Sprintf("%s/hooks/%s", path, url.PathEscape(hook.ID)), nil, r, nil), "unable to update webhook %s on %s", hook.ID, repo)
+}
+
+// DeleteHook deletes a webhook
+func (client *giteaClient) DeleteHook(ctx context.Context, repo string, hook sdk.VCSHook) error {
+	path, err := repoPath(repo)
+	if err != nil {
+		return err
+	}
+	return sdk.WrapError(client.do(ctx, "DELETE", fmt.Sprintf("%s/hooks/%s", path, url.PathEscape(hook.ID)), nil, nil, nil), "unable to delete webhook %s on %s", hook.ID, repo)
+}
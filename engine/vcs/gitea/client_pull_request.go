@@ -0,0 +1,127 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// PullRequest returns a pull request by its number
+func (client *giteaClient) PullRequest(ctx context.Context, fullname string, id int) (sdk.VCSPullRequest, error) {
+	path, err := repoPath(fullname)
+	if err != nil {
+		return sdk.VCSPullRequest{}, err
+	}
+	var pr PullRequest
+	if err := client.do(ctx, "GET", fmt.Sprintf("%s/pulls/%d", path, id), nil, nil, &pr); err != nil {
+		return sdk.VCSPullRequest{}, sdk.WrapError(err, "unable to get pull request %d", id)
+	}
+	return pr.ToVCSPullRequest(), nil
+}
+
+// PullRequests fetch all the opened pull request for a repository
+func (client *giteaClient) PullRequests(ctx context.Context, fullname string) ([]sdk.VCSPullRequest, error) {
+	path, err := repoPath(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var pullrequests []PullRequest
+	params := url.Values{}
+	params.Set("state", "open")
+	if err := getPaginated(ctx, params, func(params url.Values) (int, error) {
+		var page []PullRequest
+		if err := client.do(ctx, "GET", path+"/pulls", params, nil, &page); err != nil {
+			return 0, sdk.WrapError(err, "unable to get pull requests")
+		}
+		pullrequests = append(pullrequests, page...)
+		return len(page), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	responsePullRequest := make([]sdk.VCSPullRequest, 0, len(pullrequests))
+	for _, pr := range pullrequests {
+		responsePullRequest = append(responsePullRequest, pr.ToVCSPullRequest())
+	}
+	return responsePullRequest, nil
+}
+
+// PullRequestComment push a new comment on a pull request
+func (client *giteaClient) PullRequestComment(ctx context.Context, repo string, prRequest sdk.VCSPullRequestCommentRequest) error {
+	if client.DisableStatus {
+		log.Warning(ctx, "gitea.PullRequestComment>  ⚠ gitea statuses are disabled")
+		return nil
+	}
+	path, err := repoPath(repo)
+	if err != nil {
+		return err
+	}
+	payload := map[string]string{
+		"body": prRequest.Message,
+	}
+	// Pull requests share the comments of their issue
+	return sdk.WrapError(client.do(ctx, "POST", fmt.Sprintf("%s/issues/%d/comments", path, prRequest.ID), nil, payload, nil), "unable to comment pull request %d", prRequest.ID)
+}
+
+// PullRequestCreate creates a pull request
+func (client *giteaClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
+	path, err := repoPath(repo)
+	if err != nil {
+		return sdk.VCSPullRequest{}, err
+	}
+	payload := CreatePullRequest{
+		Title: pr.Title,
+		Head:  pr.Head.Branch.DisplayID,
+		Base:  pr.Base.Branch.DisplayID,
+	}
+	var prResponse PullRequest
+	if err := client.do(ctx, "POST", path+"/pulls", nil, payload, &prResponse); err != nil {
+		return sdk.VCSPullRequest{}, sdk.WrapError(err, "unable to create pull request")
+	}
+	return prResponse.ToVCSPullRequest(), nil
+}
+
+// ToVCSPullRequest returns a sdk.VCSPullRequest from a Gitea pull request
+func (pr PullRequest) ToVCSPullRequest() sdk.VCSPullRequest {
+	return sdk.VCSPullRequest{
+		ID:    pr.Number,
+		Title: pr.Title,
+		URL:   pr.HTMLURL,
+		Base: sdk.VCSPushEvent{
+			Repo:     pr.Base.Repo.FullName,
+			CloneURL: pr.Base.Repo.CloneURL,
+			Branch: sdk.VCSBranch{
+				ID:           pr.Base.Ref,
+				DisplayID:    pr.Base.Ref,
+				LatestCommit: pr.Base.Sha,
+			},
+			Commit: sdk.VCSCommit{
+				Hash: pr.Base.Sha,
+			},
+		},
+		Head: sdk.VCSPushEvent{
+			Repo:     pr.Head.Repo.FullName,
+			CloneURL: pr.Head.Repo.CloneURL,
+			Branch: sdk.VCSBranch{
+				ID:           pr.Head.Ref,
+				DisplayID:    pr.Head.Ref,
+				LatestCommit: pr.Head.Sha,
+			},
+			Commit: sdk.VCSCommit{
+				Hash: pr.Head.Sha,
+			},
+		},
+		User: sdk.VCSAuthor{
+			Name:        pr.User.Login,
+			DisplayName: pr.User.FullName,
+			Email:       pr.User.Email,
+			Avatar:      pr.User.AvatarURL,
+		},
+		Merged: pr.Merged,
+		Closed: pr.State == "closed",
+	}
+}
@@ -0,0 +1,62 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Release Create a release
+func (client *giteaClient) Release(ctx context.Context, fullname string, tagName string, title string, releaseNote string) (*sdk.VCSRelease, error) {
+	path, err := repoPath(fullname)
+	if err != nil {
+		return nil, err
+	}
+	req := CreateRelease{
+		TagName: tagName,
+		Name:    title,
+		Body:    releaseNote,
+	}
+	var release Release
+	if err := client.do(ctx, "POST", path+"/releases", nil, req, &release); err != nil {
+		return nil, sdk.WrapError(err, "cannot create release %s on %s", tagName, fullname)
+	}
+
+	return &sdk.VCSRelease{
+		ID:        release.ID,
+		UploadURL: fmt.Sprintf("%s%s/releases/%d/assets", client.apiURL, path, release.ID),
+	}, nil
+}
+
+// UploadReleaseFile Attach a file into the release
+func (client *giteaClient) UploadReleaseFile(ctx context.Context, repo string, releaseName string, uploadURL string, artifactName string, r io.ReadCloser) error {
+	defer r.Close() // nolint
+
+	// the file is sent as a multipart form, streamed through a pipe
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	go func() {
+		part, err := w.CreateFormFile("attachment", artifactName)
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = w.Close()
+		}
+		pw.CloseWithError(err) // nolint
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, uploadURL+"?name="+url.QueryEscape(artifactName), pr)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	return sdk.WrapError(client.send(ctx, req, nil), "unable to upload %s on release %s", artifactName, releaseName)
+}
@@ -0,0 +1,71 @@
+package gitea
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Repos list repositories that are accessible to the authenticated user
+func (client *giteaClient) Repos(ctx context.Context) ([]sdk.VCSRepo, error) {
+	var repos []Repository
+	if err := getPaginated(ctx, nil, func(params url.Values) (int, error) {
+		var page []Repository
+		if err := client.do(ctx, "GET", "/user/repos", params, nil, &page); err != nil {
+			return 0, sdk.WrapError(err, "unable to get repos")
+		}
+		repos = append(repos, page...)
+		return len(page), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	responseRepos := make([]sdk.VCSRepo, 0, len(repos))
+	for _, repo := range repos {
+		responseRepos = append(responseRepos, repo.ToVCSRepo())
+	}
+	return responseRepos, nil
+}
+
+// RepoByFullname Get only one repo
+func (client *giteaClient) RepoByFullname(ctx context.Context, fullname string) (sdk.VCSRepo, error) {
+	repo, err := client.repoByFullname(ctx, fullname)
+	if err != nil {
+		return sdk.VCSRepo{}, err
+	}
+	return repo.ToVCSRepo(), nil
+}
+
+func (client *giteaClient) repoByFullname(ctx context.Context, fullname string) (Repository, error) {
+	var repo Repository
+	path, err := repoPath(fullname)
+	if err != nil {
+		return repo, err
+	}
+	if err := client.do(ctx, "GET", path, nil, nil, &repo); err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return repo, sdk.WithStack(sdk.ErrRepoNotFound)
+		}
+		return repo, sdk.WrapError(err, "unable to get repo %s", fullname)
+	}
+	return repo, nil
+}
+
+func (client *giteaClient) GrantWritePermission(ctx context.Context, fullname string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// ToVCSRepo returns a sdk.VCSRepo from a Gitea repository
+func (repo Repository) ToVCSRepo() sdk.VCSRepo {
+	return sdk.VCSRepo{
+		ID:           strconv.FormatInt(repo.ID, 10),
+		Name:         repo.Name,
+		Slug:         repo.Name,
+		Fullname:     repo.FullName,
+		URL:          repo.HTMLURL,
+		HTTPCloneURL: repo.CloneURL,
+		SSHCloneURL:  repo.SSHURL,
+	}
+}
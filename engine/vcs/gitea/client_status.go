@@ -0,0 +1,157 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+type statusData struct {
+	pipName      string
+	desc         string
+	status       string
+	repoFullName string
+	hash         string
+	urlPipeline  string
+	context      string
+}
+
+//SetStatus Users with push access can create commit statuses for a given ref:
+func (client *giteaClient) SetStatus(ctx context.Context, event sdk.Event) error {
+	if client.DisableStatus {
+		log.Warning(ctx, "gitea.SetStatus>  ⚠ gitea statuses are disabled")
+		return nil
+	}
+
+	var data statusData
+	var err error
+	switch event.EventType {
+	case fmt.Sprintf("%T", sdk.EventRunWorkflowNode{}):
+		data, err = processEventWorkflowNodeRun(event, client.uiURL, client.DisableStatusDetail)
+	default:
+		log.Error(ctx, "gitea.SetStatus> Unknown event %v", event)
+		return nil
+	}
+	if err != nil {
+		return sdk.WrapError(err, "Cannot process Event")
+	}
+
+	if data.status == "" {
+		log.Debug("gitea.SetStatus> Do not process event for current status: %v", event)
+		return nil
+	}
+
+	path, err := repoPath(data.repoFullName)
+	if err != nil {
+		return err
+	}
+	s := CreateStatus{
+		State:       data.status,
+		TargetURL:   data.urlPipeline,
+		Description: data.desc,
+		Context:     data.context,
+	}
+	var resp Status
+	if err := client.do(ctx, "POST", path+"/statuses/"+url.PathEscape(data.hash), nil, s, &resp); err != nil {
+		return sdk.WrapError(err, "unable to create status on %s for %s", data.repoFullName, data.hash)
+	}
+
+	log.Debug("gitea.SetStatus> Status %d created at %v", resp.ID, resp.Created)
+	return nil
+}
+
+func (client *giteaClient) ListStatuses(ctx context.Context, repo string, ref string) ([]sdk.VCSCommitStatus, error) {
+	path, err := repoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	if err := getPaginated(ctx, nil, func(params url.Values) (int, error) {
+		var page []Status
+		if err := client.do(ctx, "GET", path+"/commits/"+escapeRef(ref)+"/statuses", params, nil, &page); err != nil {
+			return 0, sdk.WrapError(err, "unable to list statuses of %s", ref)
+		}
+		statuses = append(statuses, page...)
+		return len(page), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	vcsStatuses := make([]sdk.VCSCommitStatus, 0, len(statuses))
+	for _, s := range statuses {
+		if !strings.HasPrefix(s.Context, "CDS/") {
+			continue
+		}
+		vcsStatuses = append(vcsStatuses, sdk.VCSCommitStatus{
+			CreatedAt:  s.Created,
+			Decription: s.Context,
+			Ref:        ref,
+			State:      processGiteaState(s),
+		})
+	}
+	return vcsStatuses, nil
+}
+
+func processGiteaState(s Status) string {
+	switch s.State {
+	case "success":
+		return sdk.StatusSuccess
+	case "failure":
+		return sdk.StatusFail
+	case "error":
+		return sdk.StatusStopped
+	default:
+		return sdk.StatusBuilding
+	}
+}
+
+func processEventWorkflowNodeRun(event sdk.Event, cdsUIURL string, disabledStatusDetail bool) (statusData, error) {
+	data := statusData{}
+	var eventNR sdk.EventRunWorkflowNode
+	if err := json.Unmarshal(event.Payload, &eventNR); err != nil {
+		return data, sdk.WrapError(err, "cannot unmarshal payload")
+	}
+	//We only manage status Success, Failure and Stopped
+	if eventNR.Status == sdk.StatusChecking ||
+		eventNR.Status == sdk.StatusDisabled ||
+		eventNR.Status == sdk.StatusNeverBuilt ||
+		eventNR.Status == sdk.StatusSkipped ||
+		eventNR.Status == sdk.StatusUnknown ||
+		eventNR.Status == sdk.StatusWaiting {
+		return data, nil
+	}
+
+	switch eventNR.Status {
+	case sdk.StatusFail:
+		data.status = "failure"
+	case sdk.StatusSuccess:
+		data.status = "success"
+	case sdk.StatusStopped:
+		data.status = "error"
+	default:
+		data.status = "pending"
+	}
+	data.hash = eventNR.Hash
+	data.repoFullName = eventNR.RepositoryFullName
+	data.pipName = eventNR.NodeName
+
+	//CDS can avoid sending gitea target url in status, if it's disable
+	if !disabledStatusDetail {
+		data.urlPipeline = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d",
+			cdsUIURL,
+			event.ProjectKey,
+			event.WorkflowName,
+			eventNR.Number,
+		)
+	}
+
+	data.context = sdk.VCSCommitStatusDescription(event.ProjectKey, event.WorkflowName, eventNR)
+	data.desc = eventNR.NodeName + ": " + eventNR.Status
+	return data, nil
+}
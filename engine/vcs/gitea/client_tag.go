@@ -0,0 +1,39 @@
+package gitea
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Tags returns list of tags for a repo
+func (client *giteaClient) Tags(ctx context.Context, fullname string) ([]sdk.VCSTag, error) {
+	path, err := repoPath(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	if err := getPaginated(ctx, nil, func(params url.Values) (int, error) {
+		var page []Tag
+		if err := client.do(ctx, "GET", path+"/tags", params, nil, &page); err != nil {
+			return 0, sdk.WrapError(err, "unable to get tags")
+		}
+		tags = append(tags, page...)
+		return len(page), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	responseTags := make([]sdk.VCSTag, 0, len(tags))
+	for _, tag := range tags {
+		responseTags = append(responseTags, sdk.VCSTag{
+			Tag:     tag.Name,
+			Sha:     tag.ID,
+			Message: tag.Message,
+			Hash:    tag.Commit.SHA,
+		})
+	}
+	return responseTags, nil
+}
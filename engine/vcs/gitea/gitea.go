@@ -0,0 +1,52 @@
+package gitea
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// giteaClient is a Gitea (or Forgejo) wrapper for CDS vcs. interface
+type giteaClient struct {
+	OAuthToken          string
+	DisableStatus       bool
+	DisableStatusDetail bool
+	Cache               cache.Store
+	apiURL              string
+	uiURL               string
+	proxyURL            string
+}
+
+//giteaConsumer implements vcs.Server and it's used to instantiate a giteaClient
+type giteaConsumer struct {
+	ClientID                 string `json:"client-id"`
+	ClientSecret             string `json:"-"`
+	Cache                    cache.Store
+	URL                      string
+	AuthorizationCallbackURL string
+	uiURL                    string
+	proxyURL                 string
+	disableStatus            bool
+	disableStatusDetail      bool
+}
+
+//New creates a new GiteaConsumer
+func New(clientID, clientSecret, URL, callbackURL, uiURL, proxyURL string, store cache.Store, disableStatus, disableStatusDetail bool) sdk.VCSServer {
+	return &giteaConsumer{
+		ClientID:                 clientID,
+		ClientSecret:             clientSecret,
+		Cache:                    store,
+		URL:                      strings.TrimSuffix(URL, "/"),
+		AuthorizationCallbackURL: callbackURL,
+		uiURL:                    uiURL,
+		proxyURL:                 proxyURL,
+		disableStatus:            disableStatus,
+		disableStatusDetail:      disableStatusDetail,
+	}
+}
+
+func (c *giteaClient) GetAccessToken(_ context.Context) string {
+	return c.OAuthToken
+}
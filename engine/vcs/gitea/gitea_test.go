@@ -0,0 +1,137 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// giteaServer is a stand-in for a Gitea server that stores created hooks and statuses
+type giteaServer struct {
+	*httptest.Server
+	hooks    []CreateHook
+	statuses []CreateStatus
+}
+
+func newGiteaServer(t *testing.T) *giteaServer {
+	s := &giteaServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		token := AccessToken{TokenType: "bearer", ExpiresIn: 3600, RefreshToken: "refresh-token"}
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			assert.Equal(t, "the-code", r.Form.Get("code"))
+			token.AccessToken = "access-token"
+		case "refresh_token":
+			assert.Equal(t, "refresh-token", r.Form.Get("refresh_token"))
+			token.AccessToken = "refreshed-token"
+		}
+		_ = json.NewEncoder(w).Encode(token)
+	})
+	mux.HandleFunc("/api/v1/repos/fsamin/go-repo", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Repository{ID: 1, Name: "go-repo", FullName: "fsamin/go-repo", DefaultBranch: "master"})
+	})
+	mux.HandleFunc("/api/v1/repos/fsamin/go-repo/branches", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token refreshed-token", r.Header.Get("Authorization"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		var branches []Branch
+		// first page is full, second one is not
+		n := pageLimit
+		if page == 2 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			branches = append(branches, Branch{Name: fmt.Sprintf("branch-%d-%d", page, i)})
+		}
+		if page == 2 {
+			branches[0].Name = "master"
+			branches[0].Commit.ID = "2b8a4a9e"
+		}
+		_ = json.NewEncoder(w).Encode(branches)
+	})
+	mux.HandleFunc("/api/v1/repos/fsamin/go-repo/hooks", func(w http.ResponseWriter, r *http.Request) {
+		var h CreateHook
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&h))
+		s.hooks = append(s.hooks, h)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(Hook{ID: 42, Type: h.Type, Config: h.Config, Events: h.Events, Active: h.Active})
+	})
+	mux.HandleFunc("/api/v1/repos/fsamin/go-repo/statuses/2b8a4a9e", func(w http.ResponseWriter, r *http.Request) {
+		var st CreateStatus
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&st))
+		s.statuses = append(s.statuses, st)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(Status{ID: 1, State: st.State, Context: st.Context})
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func TestGitea(t *testing.T) {
+	srv := newGiteaServer(t)
+	defer srv.Close()
+
+	consumer := New("client-id", "client-secret", srv.URL, "http://cds/callback", "http://cds-ui", "https://myproxy.com", nil, false, false)
+
+	_, authorizeURL, err := consumer.AuthorizeRedirect(context.TODO())
+	require.NoError(t, err)
+	assert.Contains(t, authorizeURL, srv.URL+"/login/oauth/authorize?")
+
+	accessToken, refreshToken, err := consumer.AuthorizeToken(context.TODO(), "", "the-code")
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", accessToken)
+	assert.Equal(t, "refresh-token", refreshToken)
+
+	// the token was created two hours ago, it has to be refreshed
+	client, err := consumer.GetAuthorizedClient(context.TODO(), accessToken, refreshToken, time.Now().Add(-2*time.Hour).Unix())
+	require.NoError(t, err)
+	assert.Equal(t, "refreshed-token", client.GetAccessToken(context.TODO()))
+
+	branches, err := client.Branches(context.TODO(), "fsamin/go-repo")
+	require.NoError(t, err)
+	require.Len(t, branches, pageLimit+1)
+	defaultBranch := sdk.GetDefaultBranch(branches)
+	assert.Equal(t, "master", defaultBranch.DisplayID)
+	assert.Equal(t, "2b8a4a9e", defaultBranch.LatestCommit)
+
+	hook := sdk.VCSHook{URL: "http://cds-hooks/webhook/uuid", Secret: "my-secret"}
+	require.NoError(t, client.CreateHook(context.TODO(), "fsamin/go-repo", &hook))
+	assert.Equal(t, "42", hook.ID)
+	require.Len(t, srv.hooks, 1)
+	assert.Equal(t, "gitea", srv.hooks[0].Type)
+	assert.Equal(t, "https://myproxy.com/uuid", srv.hooks[0].Config["url"])
+	assert.Equal(t, "my-secret", srv.hooks[0].Config["secret"])
+	assert.Equal(t, []string{"push"}, srv.hooks[0].Events)
+
+	payload, _ := json.Marshal(sdk.EventRunWorkflowNode{
+		Number:             12,
+		NodeName:           "build",
+		Status:             sdk.StatusFail,
+		Hash:               "2b8a4a9e",
+		RepositoryFullName: "fsamin/go-repo",
+	})
+	require.NoError(t, client.SetStatus(context.TODO(), sdk.Event{
+		EventType:    fmt.Sprintf("%T", sdk.EventRunWorkflowNode{}),
+		ProjectKey:   "PROJ",
+		WorkflowName: "my-workflow",
+		Payload:      payload,
+	}))
+	require.Len(t, srv.statuses, 1)
+	assert.Equal(t, "failure", srv.statuses[0].State)
+	assert.Equal(t, "CDS/PROJ-my-workflow-build", srv.statuses[0].Context)
+	assert.Equal(t, "http://cds-ui/project/PROJ/workflow/my-workflow/run/12", srv.statuses[0].TargetURL)
+
+	_, err = client.RepoByFullname(context.TODO(), "fsamin/unknown")
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrRepoNotFound))
+}
@@ -0,0 +1,172 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Gitea http var
+var (
+	httpClient = cdsclient.NewHTTPClient(time.Second*30, false)
+)
+
+// pageLimit is the maximum number of items returned by Gitea API on each page
+const pageLimit = 50
+
+func (consumer *giteaConsumer) postForm(path string, data url.Values, headers map[string][]string) (int, []byte, error) {
+	body := strings.NewReader(data.Encode())
+
+	req, err := http.NewRequest(http.MethodPost, consumer.URL+path, body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for k, h := range headers {
+		for i := range h {
+			req.Header.Add(k, h[i])
+		}
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, err
+	}
+
+	if res.StatusCode >= 400 {
+		var oauthErr OAuthError
+		if err := json.Unmarshal(resBody, &oauthErr); err == nil && oauthErr.Error != "" {
+			return res.StatusCode, resBody, fmt.Errorf("%s: %s", oauthErr.Error, oauthErr.Description)
+		}
+	}
+
+	return res.StatusCode, resBody, nil
+}
+
+// do sends a request to the Gitea API and unmarshals the response body in v if not nil.
+func (client *giteaClient) do(ctx context.Context, method, path string, params url.Values, body interface{}, v interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return sdk.WrapError(err, "cannot marshal body %+v", body)
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	uri := client.apiURL + path
+	if len(params) > 0 {
+		uri += "?" + params.Encode()
+	}
+	req, err := http.NewRequest(method, uri, reader)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return client.send(ctx, req, v)
+}
+
+func (client *giteaClient) send(ctx context.Context, req *http.Request, v interface{}) error {
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("token %s", client.OAuthToken))
+
+	log.Debug("Gitea API>> Request %s %s", req.Method, req.URL.String())
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "HTTP Error")
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+
+	switch res.StatusCode {
+	case http.StatusNotFound:
+		return sdk.WithStack(sdk.ErrNotFound)
+	case http.StatusForbidden:
+		return sdk.WithStack(sdk.ErrForbidden)
+	case http.StatusUnauthorized:
+		return sdk.WithStack(sdk.ErrUnauthorized)
+	}
+	if res.StatusCode >= 400 {
+		log.Warning(ctx, "giteaClient.do> %s %s: %d %s", req.Method, req.URL.String(), res.StatusCode, string(resBody))
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "%s", errorAPI(resBody))
+	}
+
+	if v == nil || len(resBody) == 0 || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return sdk.WithStack(json.Unmarshal(resBody, v))
+}
+
+// getPaginated calls fn with the url values for each page of given list until a page is not full.
+func getPaginated(ctx context.Context, params url.Values, fn func(params url.Values) (int, error)) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("limit", strconv.Itoa(pageLimit))
+	for page := 1; ; page++ {
+		if ctx.Err() != nil {
+			return sdk.WithStack(ctx.Err())
+		}
+		params.Set("page", strconv.Itoa(page))
+		n, err := fn(params)
+		if err != nil {
+			return err
+		}
+		if n < pageLimit {
+			return nil
+		}
+	}
+}
+
+func splitFullname(fullname string) (string, string, error) {
+	t := strings.Split(fullname, "/")
+	if len(t) != 2 || t[0] == "" || t[1] == "" {
+		return "", "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid repository fullname %s", fullname)
+	}
+	return t[0], t[1], nil
+}
+
+// repoPath returns the API path of given repository
+func repoPath(fullname string) (string, error) {
+	owner, repo, err := splitFullname(fullname)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo)), nil
+}
+
+// escapeRef escapes a git reference to be used in a path, slashes are kept as Gitea handles them
+func escapeRef(ref string) string {
+	t := strings.Split(ref, "/")
+	for i := range t {
+		t[i] = url.PathEscape(t[i])
+	}
+	return strings.Join(t, "/")
+}
@@ -0,0 +1,127 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// accessTokenValidity is the default lifetime of Gitea OAuth2 access tokens
+const accessTokenValidity = time.Hour
+
+//AuthorizeRedirect returns the request token, the Authorize URL
+func (consumer *giteaConsumer) AuthorizeRedirect(ctx context.Context) (string, string, error) {
+	// See https://docs.gitea.io/en-us/oauth2-provider/
+	requestToken, err := sdk.GenerateHash()
+	if err != nil {
+		return "", "", err
+	}
+
+	val := url.Values{}
+	val.Add("client_id", consumer.ClientID)
+	val.Add("redirect_uri", consumer.AuthorizationCallbackURL)
+	val.Add("response_type", "code")
+	val.Add("state", requestToken)
+
+	authorizeURL := fmt.Sprintf("%s/login/oauth/authorize?%s", consumer.URL, val.Encode())
+	return requestToken, authorizeURL, nil
+}
+
+//AuthorizeToken returns the authorized token (and its refresh_token)
+//from the request token and the verifier got on authorize url
+func (consumer *giteaConsumer) AuthorizeToken(ctx context.Context, _, code string) (string, string, error) {
+	log.Debug("AuthorizeToken> Gitea send code %s", code)
+
+	params := url.Values{}
+	params.Add("client_id", consumer.ClientID)
+	params.Add("client_secret", consumer.ClientSecret)
+	params.Add("code", code)
+	params.Add("grant_type", "authorization_code")
+	params.Add("redirect_uri", consumer.AuthorizationCallbackURL)
+
+	return consumer.accessToken(params)
+}
+
+//RefreshToken returns the refreshed authorized token
+func (consumer *giteaConsumer) RefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
+	params := url.Values{}
+	params.Add("client_id", consumer.ClientID)
+	params.Add("client_secret", consumer.ClientSecret)
+	params.Add("refresh_token", refreshToken)
+	params.Add("grant_type", "refresh_token")
+
+	return consumer.accessToken(params)
+}
+
+func (consumer *giteaConsumer) accessToken(params url.Values) (string, string, error) {
+	headers := map[string][]string{}
+	headers["Accept"] = []string{"application/json"}
+
+	status, res, err := consumer.postForm("/login/oauth/access_token", params, headers)
+	if err != nil {
+		return "", "", err
+	}
+
+	if status < 200 || status >= 400 {
+		return "", "", fmt.Errorf("Gitea error (%d) %s ", status, string(res))
+	}
+
+	var resp AccessToken
+	if err := json.Unmarshal(res, &resp); err != nil {
+		return "", "", fmt.Errorf("Unable to parse gitea response (%d) %s ", status, string(res))
+	}
+
+	return resp.AccessToken, resp.RefreshToken, nil
+}
+
+type authorizedClient struct {
+	client  *giteaClient
+	expires time.Time
+}
+
+//keep client in memory, indexed by the access token given by CDS
+var (
+	instancesAuthorizedClient      = map[string]authorizedClient{}
+	instancesAuthorizedClientMutex sync.Mutex
+)
+
+//GetAuthorizedClient returns an authorized client, the access token is refreshed if it has expired
+func (consumer *giteaConsumer) GetAuthorizedClient(ctx context.Context, accessToken, refreshToken string, created int64) (sdk.VCSAuthorizedClient, error) {
+	instancesAuthorizedClientMutex.Lock()
+	defer instancesAuthorizedClientMutex.Unlock()
+
+	if c, ok := instancesAuthorizedClient[accessToken]; ok && c.expires.After(time.Now()) {
+		return c.client, nil
+	}
+
+	// keep a margin to not use a token that is about to expire
+	token := accessToken
+	expires := time.Unix(created, 0).Add(accessTokenValidity - time.Minute)
+	if expires.Before(time.Now()) {
+		newAccessToken, _, err := consumer.RefreshToken(ctx, refreshToken)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot refresh token")
+		}
+		token = newAccessToken
+		expires = time.Now().Add(accessTokenValidity - time.Minute)
+	}
+
+	c := &giteaClient{
+		OAuthToken:          token,
+		Cache:               consumer.Cache,
+		apiURL:              consumer.URL + "/api/v1",
+		uiURL:               consumer.uiURL,
+		proxyURL:            consumer.proxyURL,
+		DisableStatus:       consumer.disableStatus,
+		DisableStatusDetail: consumer.disableStatusDetail,
+	}
+	instancesAuthorizedClient[accessToken] = authorizedClient{client: c, expires: expires}
+
+	return c, nil
+}
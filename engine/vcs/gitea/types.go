@@ -0,0 +1,217 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AccessToken is the response of Gitea OAuth2 token endpoint
+type AccessToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// OAuthError match Gitea OAuth2 error format
+type OAuthError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// Error match Gitea API error format
+type Error struct {
+	Message string `json:"message"`
+	URL     string `json:"url"`
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("(gitea) %s", e.Message)
+}
+
+//errorAPI creates a new error
+func errorAPI(body []byte) error {
+	var res Error
+	if err := json.Unmarshal(body, &res); err != nil || res.Message == "" {
+		res.Message = string(body)
+	}
+	return res
+}
+
+// User represents a Gitea user
+type User struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// Repository represents a Gitea repository
+type Repository struct {
+	ID            int64  `json:"id"`
+	Owner         User   `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Description   string `json:"description"`
+	Private       bool   `json:"private"`
+	Fork          bool   `json:"fork"`
+	HTMLURL       string `json:"html_url"`
+	SSHURL        string `json:"ssh_url"`
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// PayloadUser represents the author or committer of a commit
+type PayloadUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	UserName string `json:"username"`
+}
+
+// PayloadCommit represents the last commit of a branch
+type PayloadCommit struct {
+	ID        string      `json:"id"`
+	Message   string      `json:"message"`
+	URL       string      `json:"url"`
+	Author    PayloadUser `json:"author"`
+	Committer PayloadUser `json:"committer"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Branch represents a Gitea branch
+type Branch struct {
+	Name      string        `json:"name"`
+	Commit    PayloadCommit `json:"commit"`
+	Protected bool          `json:"protected"`
+}
+
+// CommitUser represents the git author or committer of a commit
+type CommitUser struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// CommitMeta contains the sha of a commit
+type CommitMeta struct {
+	URL string `json:"url"`
+	SHA string `json:"sha"`
+}
+
+// Commit represents a Gitea commit
+type Commit struct {
+	URL     string `json:"url"`
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message   string     `json:"message"`
+		Author    CommitUser `json:"author"`
+		Committer CommitUser `json:"committer"`
+	} `json:"commit"`
+	Author    *User        `json:"author"`
+	Committer *User        `json:"committer"`
+	Parents   []CommitMeta `json:"parents"`
+}
+
+// Compare represents the result of the comparison of two refs
+type Compare struct {
+	TotalCommits int      `json:"total_commits"`
+	Commits      []Commit `json:"commits"`
+}
+
+// Tag represents a Gitea tag
+type Tag struct {
+	Name    string     `json:"name"`
+	Message string     `json:"message"`
+	ID      string     `json:"id"`
+	Commit  CommitMeta `json:"commit"`
+}
+
+// PRBranchInfo represents the base or the head of a pull request
+type PRBranchInfo struct {
+	Label string     `json:"label"`
+	Ref   string     `json:"ref"`
+	Sha   string     `json:"sha"`
+	Repo  Repository `json:"repo"`
+}
+
+// PullRequest represents a Gitea pull request
+type PullRequest struct {
+	ID      int64        `json:"id"`
+	Number  int          `json:"number"`
+	HTMLURL string       `json:"html_url"`
+	User    User         `json:"user"`
+	Title   string       `json:"title"`
+	Body    string       `json:"body"`
+	State   string       `json:"state"`
+	Merged  bool         `json:"merged"`
+	Head    PRBranchInfo `json:"head"`
+	Base    PRBranchInfo `json:"base"`
+}
+
+// CreatePullRequest is the body used to create a pull request
+type CreatePullRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+}
+
+// Hook represents a Gitea webhook
+type Hook struct {
+	ID     int64             `json:"id"`
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// CreateHook is the body used to create a webhook
+type CreateHook struct {
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// EditHook is the body used to update a webhook
+type EditHook struct {
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// Status represents a commit status
+type Status struct {
+	ID          int64     `json:"id"`
+	State       string    `json:"status"`
+	TargetURL   string    `json:"target_url"`
+	Description string    `json:"description"`
+	Context     string    `json:"context"`
+	Created     time.Time `json:"created_at"`
+}
+
+// CreateStatus is the body used to create a commit status
+type CreateStatus struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+// Release represents a Gitea release
+type Release struct {
+	ID      int64  `json:"id"`
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Body    string `json:"body"`
+	URL     string `json:"url"`
+}
+
+// CreateRelease is the body used to create a release
+type CreateRelease struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Body    string `json:"body"`
+}
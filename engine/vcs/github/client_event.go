@@ -251,10 +251,12 @@ func (g *githubClient) PullRequestEvents(ctx context.Context, fullname string, i
 
 	res := []sdk.VCSPullRequestEvent{}
 	for _, e := range events {
-		if e.Payload.PullRequest.State != "open" {
+		// Closed pull requests are kept to tear down their preview environments
+		if e.Payload.PullRequest.State != "open" && e.Payload.Action != "closed" {
 			continue
 		}
 		event := sdk.VCSPullRequestEvent{
+			ID:     e.Payload.PullRequest.Number,
 			Action: e.Payload.Action,
 			Repo:   e.Payload.PullRequest.Head.Repo.FullName,
 			Head: sdk.VCSPushEvent{
@@ -38,6 +38,8 @@ func (s *Service) getAllVCSServersHandler() service.Handler {
 				vcsType = "github"
 			} else if v.Gitlab != nil {
 				vcsType = "gitlab"
+			} else if v.Gitea != nil {
+				vcsType = "gitea"
 			}
 
 			servers[k] = sdk.VCSConfiguration{
@@ -70,6 +72,8 @@ func (s *Service) getVCSServersHandler() service.Handler {
 			s.Type = "github"
 		} else if cfg.Gitlab != nil {
 			s.Type = "gitlab"
+		} else if cfg.Gitea != nil {
+			s.Type = "gitea"
 		}
 		return service.WriteJSON(w, s, http.StatusOK)
 	}
@@ -207,6 +211,25 @@ func (s *Service) getVCSServersHooksHandler() service.Handler {
 				"Pipeline Hook",
 				"Job Hook",
 			}
+		case cfg.Gitea != nil:
+			res.WebhooksSupported = true
+			res.WebhooksDisabled = cfg.Gitea.DisableWebHooks
+			res.WebhooksIcon = sdk.GiteaIcon
+			// https://docs.gitea.io/en-us/webhooks/
+			res.Events = []string{
+				"push",
+				"create",
+				"delete",
+				"fork",
+				"issues",
+				"issue_comment",
+				"pull_request",
+				"pull_request_approved",
+				"pull_request_rejected",
+				"pull_request_comment",
+				"release",
+				"repository",
+			}
 		case cfg.Gerrit != nil:
 			res.WebhooksSupported = false
 			res.GerritHookDisabled = cfg.Gerrit.DisableGerritEvent
@@ -262,6 +285,8 @@ func (s *Service) getVCSServersPollingHandler() service.Handler {
 		case cfg.Gitlab != nil:
 			res.PollingSupported = false
 			res.PollingDisabled = cfg.Gitlab.DisablePolling
+		case cfg.Gitea != nil:
+			res.PollingSupported = false
 		}
 
 		return service.WriteJSON(w, res, http.StatusOK)
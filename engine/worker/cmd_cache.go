@@ -29,6 +29,16 @@ You can access to this cache from any workflow inside a project. You just have t
 
 For example if you need a different cache for each workflow so choose a tag scoped with your workflow name and workflow version (example of tag value: {{.cds.workflow}}-{{.cds.version}})
 
+A cache is immutable: pushing a cache with a tag that already exists is skipped. Use the flag --key-files to append
+to the tag a hash of the content of files describing your dependencies (go.sum, package-lock.json, pom.xml...), the
+cache will be pushed again only if these files change.
+
+When pulling, the flag --restore-keys gives an ordered list of tag prefixes used if there is no cache for the tag: the
+most recent cache which tag starts with the first matching prefix is restored. Restore keys only match tags made of
+letters, digits, '.', '_' and '-'. The step log shows if the cache was hit, restored from another tag or missed.
+
+The total size of the caches of a project is limited, the least recently used caches are deleted when the limit is reached.
+
 ## Use Case
 Java Developers often use maven to manage dependencies. The mvn install command could be long because all the maven dependencies have to be downloaded on a fresh CDS Job workspace.
 With the worker cache feature, you don't have to download the dependencies if they haven't been updated since the last run of the job.
@@ -41,10 +51,8 @@ Here, an example of a script inside a CDS Job using the cache feature:
 
 	#!/bin/bash
 
-	tag=($(md5sum pom.xml))
-
-	# download the cache of .m2/
-	if worker cache pull $tag; then
+	# download the cache of .m2/, or the most recent one if pom.xml was updated
+	if worker cache pull --key-files pom.xml --restore-keys m2- m2; then
 		echo ".m2/ getted from cache";
 	fi
 
@@ -53,8 +61,8 @@ Here, an example of a script inside a CDS Job using the cache feature:
 	# if they are not updated on upstream
 	mvn install
 
-	# put in cache the updated .m2/ directory
-	worker cache push $tag .m2/
+	# put in cache the updated .m2/ directory, skipped if a cache already exists for this pom.xml
+	worker cache push --key-files pom.xml m2 .m2/
 
     `,
 	}
@@ -63,7 +71,29 @@ Here, an example of a script inside a CDS Job using the cache feature:
 	return cmdCacheRoot
 }
 
-var cmdStorageIntegrationName string
+var (
+	cmdStorageIntegrationName string
+	cmdCacheKeyFiles          []string
+	cmdCacheRestoreKeys       []string
+)
+
+// cacheKey returns the given tag with the hash of the key files if any.
+func cacheKey(cmd, tag string) string {
+	if len(cmdCacheKeyFiles) == 0 {
+		return tag
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		sdk.Exit("worker cache %s > Cannot find working directory : %s", cmd, err)
+	}
+
+	hash, err := internal.HashFiles(cwd, cmdCacheKeyFiles)
+	if err != nil {
+		sdk.Exit("worker cache %s > Cannot compute hash of key files: %s", cmd, err)
+	}
+	return tag + "-" + hash
+}
 
 func cmdCachePush() *cobra.Command {
 	c := &cobra.Command{
@@ -76,11 +106,16 @@ Inside a project, you can create a cache from your worker with a tag (useful for
 
 You can use you storage integration: 
 	worker cache push --destination=MyStorageIntegration  <tagValue> dir/file
+
+A cache is immutable, if a cache already exists for the tag the push is skipped. To push a new cache when
+your dependencies change, append a hash of your dependency files to the tag:
+	worker cache push --key-files go.sum gomod $GOPATH/pkg/mod
 		`,
 		Example: "worker cache push {{.cds.workflow}}-{{.cds.version}} ./pathToUpload",
 		Run:     cachePushCmd(),
 	}
 	c.Flags().StringVar(&cmdStorageIntegrationName, "destination", "", "optional. Your storage integration name")
+	c.Flags().StringSliceVar(&cmdCacheKeyFiles, "key-files", nil, "optional. Glob patterns of files which content hash is appended to the tag")
 	return c
 }
 
@@ -110,8 +145,9 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache push > Cannot find working directory : %s", err)
 		}
 
+		tag := cacheKey("push", args[0])
 		c := sdk.Cache{
-			Tag:              args[0],
+			Tag:              tag,
 			Files:            files,
 			WorkingDirectory: cwd,
 			IntegrationName:  cmdStorageIntegrationName,
@@ -122,10 +158,10 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache push > internal error (%s)", errMarshal)
 		}
 
-		fmt.Printf("Worker cache push in progress... (tag: %s)\n", args[0])
+		fmt.Printf("Worker cache push in progress... (tag: %s)\n", tag)
 		req, errRequest := http.NewRequest(
 			"POST",
-			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/push", port, base64.RawURLEncoding.EncodeToString([]byte(args[0]))),
+			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/push", port, base64.RawURLEncoding.EncodeToString([]byte(tag))),
 			bytes.NewReader(data),
 		)
 		if errRequest != nil {
@@ -141,6 +177,11 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 		}
 		defer resp.Body.Close()
 
+		if resp.StatusCode == http.StatusConflict {
+			fmt.Printf("Worker cache push skipped, a cache already exists (tag: %s)\n", tag)
+			return
+		}
+
 		if resp.StatusCode >= 300 {
 			body, err := ioutil.ReadAll(resp.Body)
 			if err != nil {
@@ -150,7 +191,7 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("Error: http code %d : %v", resp.StatusCode, cdsError)
 		}
 
-		fmt.Printf("Worker cache push with success (tag: %s)\n", args[0])
+		fmt.Printf("Worker cache push with success (tag: %s)\n", tag)
 	}
 }
 
@@ -178,10 +219,17 @@ If you want to push a cache into a storage integration:
 
 	worker cache push latest --from=MyStorageIntegration {{.cds.workspace}}/pathToUpload
 
+If the cache was pushed with key files, pull it with the same key files. Restore keys are tag prefixes checked
+in order when there is no cache for the tag, the most recent cache matching the first of them is restored:
+
+	worker cache pull --key-files go.sum --restore-keys gomod- gomod
+
 		`,
 		Run: cachePullCmd(),
 	}
 	c.Flags().StringVar(&cmdStorageIntegrationName, "from", "", "optional. Your storage integration name")
+	c.Flags().StringSliceVar(&cmdCacheKeyFiles, "key-files", nil, "optional. Glob patterns of files which content hash is appended to the tag")
+	c.Flags().StringSliceVar(&cmdCacheRestoreKeys, "restore-keys", nil, "optional. Ordered tag prefixes to restore a cache if there is no cache for the tag")
 	return c
 }
 
@@ -206,14 +254,21 @@ func cachePullCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache pull > cannot get current path: %s", err)
 		}
 
-		fmt.Printf("Worker cache pull in progress... (tag: %s)\n", args[0])
+		tag := cacheKey("pull", args[0])
+		query := url.Values{
+			"path":        {dir},
+			"integration": {cmdStorageIntegrationName},
+			"restoreKey":  cmdCacheRestoreKeys,
+		}
+
+		fmt.Printf("Worker cache pull in progress... (tag: %s)\n", tag)
 		req, errRequest := http.NewRequest(
 			"GET",
-			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?path=%s&integration=%s", port, base64.RawURLEncoding.EncodeToString([]byte(args[0])), url.QueryEscape(dir), url.QueryEscape(cmdStorageIntegrationName)),
+			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?%s", port, base64.RawURLEncoding.EncodeToString([]byte(tag)), query.Encode()),
 			nil,
 		)
 		if errRequest != nil {
-			sdk.Exit("worker cache pull > cannot post worker cache pull with tag %s (Request): %s", args[0], errRequest)
+			sdk.Exit("worker cache pull > cannot post worker cache pull with tag %s (Request): %s", tag, errRequest)
 		}
 
 		client := http.DefaultClient
@@ -224,15 +279,29 @@ func cachePullCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache pull > cannot post worker cache pull (Do): %s", errDo)
 		}
 
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			sdk.Exit("cache pull HTTP error %v", err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			sdk.Exit("Worker cache miss (tag: %s)", tag)
+		}
+
 		if resp.StatusCode >= 300 {
-			body, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				sdk.Exit("cache pull HTTP error %v", err)
-			}
 			cdsError := sdk.DecodeError(body)
 			sdk.Exit("Error: %v", cdsError)
 		}
 
-		fmt.Printf("Worker cache pull with success (tag: %s)\n", args[0])
+		var c sdk.Cache
+		_ = json.Unmarshal(body, &c)
+		if c.Tag != "" && c.Tag != tag {
+			fmt.Printf("Worker cache restored from tag %s (tag: %s)\n", c.Tag, tag)
+			return
+		}
+
+		fmt.Printf("Worker cache hit, pull with success (tag: %s)\n", tag)
 	}
 }
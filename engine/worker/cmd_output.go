@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/engine/worker/internal"
+	"github.com/ovh/cds/sdk"
+)
+
+var cmdOutput = &cobra.Command{
+	Use:   "output",
+	Short: "worker output <name> <value>",
+	Long: `
+Inside a step script (https://ovh.github.io/cds/docs/actions/builtin-script/) of a step with an id, you can set an output of the step with the worker command:
+
+	worker output digest sha256:0123456789
+
+then, you can use the output in another step of the current job:
+
+	echo "{{.cds.steps.<step-id>.outputs.digest}}"
+
+## Job outputs
+
+The outputs of a job are computed from the outputs of its steps when the job ends:
+
+	jobs:
+	- job: build
+	  outputs:
+	    image_digest: "{{.cds.steps.docker.outputs.digest}}"
+	  steps:
+	  - id: docker
+	    script: worker output digest $(cat digest.txt)
+
+The job outputs are available in the next stages of the pipeline and in the next pipelines of the workflow
+with ` + "`{{.cds.node.<pipelineName>.outputs.image_digest}}`" + `, including in their run conditions.
+
+	`,
+	Run: outputCmd,
+}
+
+func outputCmd(cmd *cobra.Command, args []string) {
+	portS := os.Getenv(internal.WorkerServerPort)
+	if portS == "" {
+		sdk.Exit("%s not found, are you running inside a CDS worker job?\n", internal.WorkerServerPort)
+	}
+
+	port, err := strconv.Atoi(portS)
+	if err != nil {
+		sdk.Exit("cannot parse '%s' as a port number", portS)
+	}
+
+	if len(args) != 2 {
+		sdk.Exit("Wrong usage: See '%s'\n", cmd.Short)
+	}
+
+	v := sdk.Variable{
+		Name:  args[0],
+		Type:  sdk.StringVariable,
+		Value: args[1],
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		sdk.Exit("internal error (%s)\n", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/output", port), bytes.NewReader(data))
+	if err != nil {
+		sdk.Exit("cannot set output: %s\n", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		sdk.Exit("cannot set output: %s\n", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		var sdkErr sdk.Error
+		if err := json.Unmarshal(body, &sdkErr); err == nil && sdkErr.Message != "" {
+			sdk.Exit("cannot set output: %s\n", sdkErr.Message)
+		}
+		sdk.Exit("cannot set output: HTTP %d\n", resp.StatusCode)
+	}
+}
@@ -176,6 +176,8 @@ func writeScriptContent(ctx context.Context, script *script, fs afero.Fs, basedi
 func RunScriptAction(ctx context.Context, wk workerruntime.Runtime, a sdk.Action, secrets []sdk.Variable) (sdk.Result, error) {
 	chanRes := make(chan sdk.Result)
 	chanErr := make(chan error)
+	// exit code of the script, set before sending a command failure on chanErr
+	var exitCode int
 
 	workdir, err := workerruntime.WorkingDirectory(ctx)
 	if err != nil {
@@ -199,6 +201,7 @@ func RunScriptAction(ctx context.Context, wk workerruntime.Runtime, a sdk.Action
 
 		log.Info(ctx, "runScriptAction> Running command %s %s in %s", script.shell, strings.Trim(fmt.Sprint(script.opts), "[]"), script.dir)
 		cmd := exec.CommandContext(ctx, script.shell, script.opts...)
+		setProcessGroup(cmd)
 		res.Status = sdk.StatusUnknown
 
 		cmd.Dir = script.dir
@@ -260,9 +263,26 @@ func RunScriptAction(ctx context.Context, wk workerruntime.Runtime, a sdk.Action
 			chanErr <- fmt.Errorf("unable to start command: %v", err)
 		}
 
+		// If the context is cancelled (ex: step or job timeout), kill the whole process tree
+		// to be sure that no child process keeps the worker busy
+		cmdDone := make(chan struct{})
+		defer close(cmdDone)
+		go func() {
+			select {
+			case <-ctx.Done():
+				if err := killProcessTree(cmd); err != nil {
+					log.Warning(ctx, "runScriptAction> unable to kill process tree: %v", err)
+				}
+			case <-cmdDone:
+			}
+		}()
+
 		<-outchan
 		<-errchan
 		if err := cmd.Wait(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				exitCode = exitErr.ExitCode()
+			}
 			chanErr <- fmt.Errorf("command failure: %v", err)
 		}
 
@@ -279,6 +299,7 @@ func RunScriptAction(ctx context.Context, wk workerruntime.Runtime, a sdk.Action
 		return res, errors.New("CDS Worker execution canceled")
 	case res = <-chanRes:
 	case globalErr = <-chanErr:
+		res.ExitCode = exitCode
 	}
 
 	log.Info(ctx, "runScriptAction> %s %s", res.Status, res.Reason)
@@ -1,7 +1,9 @@
 package action
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -107,6 +109,25 @@ func TestRunScriptAction(t *testing.T) {
 	assert.Equal(t, sdk.StatusSuccess, res.Status)
 }
 
+func TestRunScriptActionWithTimeout(t *testing.T) {
+	wk, ctx := SetupTest(t)
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	t0 := time.Now()
+	_, err := RunScriptAction(ctx, wk,
+		sdk.Action{
+			Parameters: []sdk.Parameter{
+				{
+					Name:  "script",
+					Value: "sleep 30 &\nsleep 30",
+				},
+			},
+		}, nil)
+	assert.Error(t, err)
+	assert.True(t, time.Since(t0) < 10*time.Second, "script execution should have been cancelled")
+}
+
 func Test_writeScriptContent_windows(t *testing.T) {
 	sdk.GOOS = "windows"
 	defer func() {
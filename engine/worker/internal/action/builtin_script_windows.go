@@ -0,0 +1,19 @@
+// +build windows
+
+package action
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup does nothing on windows, the process tree is killed with taskkill.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessTree kills the command and all its children processes.
+func killProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
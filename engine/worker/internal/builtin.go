@@ -27,7 +27,8 @@ func init() {
 	mapBuiltinActions[sdk.InstallKeyAction] = action.RunInstallKey
 }
 
-func (w *CurrentWorker) runBuiltin(ctx context.Context, a sdk.Action, secrets []sdk.Variable) sdk.Result {
+// RunBuiltin runs a builtin action with the secrets of the current job.
+func (w *CurrentWorker) RunBuiltin(ctx context.Context, a sdk.Action) sdk.Result {
 	f, ok := mapBuiltinActions[a.Name]
 	if !ok {
 		res := sdk.Result{
@@ -40,7 +41,7 @@ func (w *CurrentWorker) runBuiltin(ctx context.Context, a sdk.Action, secrets []
 	}
 
 	log.Debug("running builin action %s %s", a.StepName, a.Name)
-	res, err := f(ctx, w, a, secrets)
+	res, err := f(ctx, w, a, w.currentJob.secrets)
 	if err != nil {
 		res.Status = sdk.StatusFail
 		res.Reason = err.Error()
@@ -50,11 +51,12 @@ func (w *CurrentWorker) runBuiltin(ctx context.Context, a sdk.Action, secrets []
 	return res
 }
 
-func (w *CurrentWorker) runGRPCPlugin(ctx context.Context, a sdk.Action) sdk.Result {
+// RunPlugin runs a plugin action with the parameters of the current job.
+func (w *CurrentWorker) RunPlugin(ctx context.Context, a sdk.Action) sdk.Result {
 	chanRes := make(chan sdk.Result, 1)
 	done := make(chan struct{})
 	sdk.GoRoutine(ctx, "runGRPCPlugin", func(ctx context.Context) {
-		action.RunGRPCPlugin(ctx, a.Name, w.currentJob.params, a, w, chanRes, done)
+		action.RunGRPCPlugin(ctx, a.Name, w.currentJob.Params, a, w, chanRes, done)
 	})
 
 	select {
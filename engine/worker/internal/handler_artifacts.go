@@ -32,12 +32,12 @@ func artifactsHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 		}
 
 		if reqArgs.Workflow == "" {
-			reqArgs.Workflow = sdk.ParameterValue(wk.currentJob.params, "cds.workflow")
+			reqArgs.Workflow = sdk.ParameterValue(wk.currentJob.Params, "cds.workflow")
 		}
 
 		if reqArgs.Number == 0 {
 			var errN error
-			buildNumberString := sdk.ParameterValue(wk.currentJob.params, "cds.run.number")
+			buildNumberString := sdk.ParameterValue(wk.currentJob.Params, "cds.run.number")
 			reqArgs.Number, errN = strconv.ParseInt(buildNumberString, 10, 64)
 			if errN != nil {
 				newError := sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("Cannot parse '%s' as run number: %s", buildNumberString, errN))
@@ -46,7 +46,7 @@ func artifactsHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 			}
 		}
 
-		projectKey := sdk.ParameterValue(wk.currentJob.params, "cds.project")
+		projectKey := sdk.ParameterValue(wk.currentJob.Params, "cds.project")
 		artifacts, err := wk.client.WorkflowRunArtifacts(projectKey, reqArgs.Workflow, reqArgs.Number)
 		if err != nil {
 			newError := sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("Cannot list artifacts with worker artifacts: %s", err))
@@ -3,6 +3,9 @@ package internal
 import (
 	"archive/tar"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,15 +13,76 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/gorilla/mux"
+	zglob "github.com/mattn/go-zglob"
 	"github.com/spf13/afero"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
+// HashFiles returns a hash of the content of the files matching given glob patterns, relative
+// to the working directory. It is used to compute cache tags that change with dependency files.
+func HashFiles(cwd string, patterns []string) (string, error) {
+	var files []string
+	for _, p := range patterns {
+		if !sdk.PathIsAbs(p) {
+			p = filepath.Join(cwd, p)
+		}
+		matches, err := zglob.Glob(p)
+		if err != nil && !os.IsNotExist(err) {
+			return "", sdk.WrapError(err, "cannot match files with pattern %s", p)
+		}
+		for _, m := range matches {
+			fi, err := os.Stat(m)
+			if err != nil {
+				return "", sdk.WithStack(err)
+			}
+			if !fi.IsDir() {
+				files = append(files, m)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no file matches %v", patterns)
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for i, f := range files {
+		if i > 0 && f == files[i-1] {
+			continue
+		}
+		rel, err := filepath.Rel(cwd, f)
+		if err != nil {
+			rel = f
+		}
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			return "", sdk.WithStack(err)
+		}
+		sum := sha256.Sum256(content)
+		fmt.Fprintf(h, "%s %s\n", hex.EncodeToString(sum[:]), filepath.ToSlash(rel))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cacheTag decodes a base64 encoded tag and returns the tag to send to the API: tags that match the name
+// pattern are sent as is so restore keys can match their prefix, others are sent encoded.
+func cacheTag(ref string) (name string, tag string) {
+	b, err := base64.RawURLEncoding.DecodeString(ref)
+	if err != nil {
+		return ref, ref
+	}
+	if !sdk.NamePatternRegex.MatchString(string(b)) {
+		return string(b), ref
+	}
+	return string(b), string(b)
+}
+
 func cachePushHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -67,9 +131,15 @@ func cachePushHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 			return
 		}
 
+		_, tag := cacheTag(vars["ref"])
 		var errPush error
 		for i := 0; i < 10; i++ {
-			if errPush = wk.client.WorkflowCachePush(projectKey, sdk.DefaultIfEmptyStorage(c.IntegrationName), vars["ref"], res, size); errPush == nil {
+			if errPush = wk.client.WorkflowCachePush(projectKey, sdk.DefaultIfEmptyStorage(c.IntegrationName), tag, res, size); errPush == nil {
+				return
+			}
+			// a cache is immutable and its size is limited, there is no need to retry
+			if sdk.ErrorIs(errPush, sdk.ErrAlreadyExist) || sdk.ErrorIs(errPush, sdk.ErrWrongRequest) {
+				writeError(w, r, errPush)
 				return
 			}
 			time.Sleep(3 * time.Second)
@@ -92,11 +162,37 @@ func cachePullHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 		integrationName := sdk.DefaultIfEmptyStorage(r.FormValue("integration"))
 		params := wk.currentJob.wJob.Parameters
 		projectKey := sdk.ParameterValue(params, "cds.project")
-		bts, err := wk.client.WorkflowCachePull(projectKey, integrationName, vars["ref"])
+
+		name, tag := cacheTag(vars["ref"])
+		restored := tag
+		if restoreKeys := r.Form["restoreKey"]; len(restoreKeys) > 0 {
+			c, err := wk.client.WorkflowCacheResolve(projectKey, integrationName, tag, restoreKeys)
+			if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				err = sdk.Error{
+					Message: "worker cache pull > Cannot resolve cache: " + err.Error(),
+					Status:  http.StatusInternalServerError,
+				}
+				writeError(w, r, err)
+				return
+			}
+			if c != nil {
+				restored = c.Tag
+			}
+		}
+
+		bts, err := wk.client.WorkflowCachePull(projectKey, integrationName, restored)
+		// caches pushed by previous workers were stored with an encoded tag
+		if sdk.ErrorIs(err, sdk.ErrNotFound) && restored == tag && tag != vars["ref"] {
+			bts, err = wk.client.WorkflowCachePull(projectKey, integrationName, vars["ref"])
+		}
 		if err != nil {
+			status := http.StatusInternalServerError
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				status = http.StatusNotFound
+			}
 			err = sdk.Error{
 				Message: "worker cache pull > Cannot pull cache: " + err.Error(),
-				Status:  http.StatusNotFound,
+				Status:  status,
 			}
 			writeError(w, r, err)
 			return
@@ -187,5 +283,10 @@ func cachePullHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 				_ = f.Close()
 			}
 		}
+
+		if restored == tag {
+			restored = name
+		}
+		writeJSON(w, sdk.Cache{Tag: restored}, http.StatusOK)
 	}
 }
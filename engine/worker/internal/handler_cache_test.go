@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "worker-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte("a"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "go.sum"), []byte("b"), 0644))
+
+	h1, err := HashFiles(dir, []string{"**/go.sum"})
+	require.NoError(t, err)
+	assert.Len(t, h1, 64)
+
+	// same files matched twice give the same hash
+	h2, err := HashFiles(dir, []string{"go.sum", "**/go.sum"})
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2)
+
+	h3, err := HashFiles(dir, []string{"go.sum"})
+	require.NoError(t, err)
+	assert.NotEqual(t, h1, h3)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "go.sum"), []byte("c"), 0644))
+	h4, err := HashFiles(dir, []string{"**/go.sum"})
+	require.NoError(t, err)
+	assert.NotEqual(t, h1, h4)
+
+	_, err = HashFiles(dir, []string{"package-lock.json"})
+	assert.Error(t, err)
+}
+
+func Test_cacheTag(t *testing.T) {
+	name, tag := cacheTag(base64.RawURLEncoding.EncodeToString([]byte("gomod-1a2b")))
+	assert.Equal(t, "gomod-1a2b", name)
+	assert.Equal(t, "gomod-1a2b", tag)
+
+	ref := base64.RawURLEncoding.EncodeToString([]byte("feat/my-branch"))
+	name, tag = cacheTag(ref)
+	assert.Equal(t, "feat/my-branch", name)
+	assert.Equal(t, ref, tag)
+}
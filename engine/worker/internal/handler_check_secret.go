@@ -34,7 +34,7 @@ func checkSecretHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc
 		sbtes := string(btes)
 
 		var varFound string
-		for _, p := range wk.currentJob.params {
+		for _, p := range wk.currentJob.Params {
 			if (p.Type == sdk.SecretVariable || p.Type == sdk.KeyVariable) && len(p.Value) >= sdk.SecretMinLength && strings.Contains(sbtes, p.Value) {
 				varFound = p.Name
 				break
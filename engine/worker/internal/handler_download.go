@@ -35,8 +35,8 @@ func downloadHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 			return
 		}
 
-		currentProject := sdk.ParameterValue(wk.currentJob.params, "cds.project")
-		currentWorkflow := sdk.ParameterValue(wk.currentJob.params, "cds.workflow")
+		currentProject := sdk.ParameterValue(wk.currentJob.Params, "cds.project")
+		currentWorkflow := sdk.ParameterValue(wk.currentJob.Params, "cds.workflow")
 		if reqArgs.Workflow == "" {
 			reqArgs.Workflow = currentWorkflow
 		}
@@ -45,7 +45,7 @@ func downloadHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 		if reqArgs.Number == 0 {
 			if reqArgs.Workflow == currentWorkflow {
 				var errN error
-				buildNumberString := sdk.ParameterValue(wk.currentJob.params, "cds.run.number")
+				buildNumberString := sdk.ParameterValue(wk.currentJob.Params, "cds.run.number")
 				reqArgs.Number, errN = strconv.ParseInt(buildNumberString, 10, 64)
 				if errN != nil {
 					newError := sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("Cannot parse '%s' as run number: %s", buildNumberString, errN))
@@ -72,7 +72,7 @@ func downloadHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 			}
 		}
 
-		projectKey := sdk.ParameterValue(wk.currentJob.params, "cds.project")
+		projectKey := sdk.ParameterValue(wk.currentJob.Params, "cds.project")
 		artifacts, err := wk.client.WorkflowRunArtifacts(projectKey, reqArgs.Workflow, reqArgs.Number)
 		if err != nil {
 			newError := sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("Cannot download artifacts with worker download: %s", err))
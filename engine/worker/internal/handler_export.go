@@ -28,7 +28,7 @@ func addBuildVarHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc
 		}
 		v.Name = "cds.build." + v.Name
 
-		wk.currentJob.newVariables = append(wk.currentJob.newVariables, v)
-		log.Debug("Variable %s added to %+v", v.Name, wk.currentJob.newVariables)
+		wk.currentJob.NewVariables = append(wk.currentJob.NewVariables, v)
+		log.Debug("Variable %s added to %+v", v.Name, wk.currentJob.NewVariables)
 	}
 }
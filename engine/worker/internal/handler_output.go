@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func addStepOutputHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			returnHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		var v sdk.Variable
+		if err := json.Unmarshal(data, &v); err != nil {
+			returnHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		if !sdk.NamePatternRegex.MatchString(v.Name) {
+			returnHTTPError(ctx, w, http.StatusBadRequest, fmt.Errorf("invalid output name %q, should match %s", v.Name, sdk.NamePattern))
+			return
+		}
+		if wk.currentJob.StepID == "" {
+			returnHTTPError(ctx, w, http.StatusBadRequest, fmt.Errorf("the current step has no id, set an id on the step to use its outputs"))
+			return
+		}
+		v.Name = sdk.StepOutputVariable(wk.currentJob.StepID, v.Name)
+
+		wk.currentJob.StepOutputs = append(wk.currentJob.StepOutputs, v)
+		log.Debug("Output %s added to %+v", v.Name, wk.currentJob.StepOutputs)
+	}
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_addStepOutputHandler(t *testing.T) {
+	var w = new(CurrentWorker)
+	h := addStepOutputHandler(context.TODO(), w)
+
+	post := func(v sdk.Variable) int {
+		btes, err := json.Marshal(v)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodPost, "/output", bytes.NewReader(btes)))
+		return rec.Code
+	}
+
+	// The step has no id
+	require.Equal(t, http.StatusBadRequest, post(sdk.Variable{Name: "digest", Value: "sha256:123"}))
+
+	w.currentJob.StepID = "docker"
+	require.Equal(t, http.StatusBadRequest, post(sdk.Variable{Name: "invalid name", Value: "sha256:123"}))
+	require.Equal(t, http.StatusOK, post(sdk.Variable{Name: "digest", Value: "sha256:123"}))
+	require.Len(t, w.currentJob.StepOutputs, 1)
+	require.Equal(t, "cds.steps.docker.outputs.digest", w.currentJob.StepOutputs[0].Name)
+
+	w.currentJob.MergeVariables()
+	p := sdk.ParameterFind(w.currentJob.Params, "cds.steps.docker.outputs.digest")
+	require.NotNil(t, p)
+	require.Equal(t, "sha256:123", p.Value)
+}
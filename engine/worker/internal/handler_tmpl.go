@@ -38,10 +38,10 @@ func tmplHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 		}
 
 		tmpvars := map[string]string{}
-		for _, v := range wk.currentJob.newVariables {
+		for _, v := range wk.currentJob.NewVariables {
 			tmpvars[v.Name] = v.Value
 		}
-		for _, v := range wk.currentJob.params {
+		for _, v := range wk.currentJob.Params {
 			tmpvars[v.Name] = v.Value
 		}
 
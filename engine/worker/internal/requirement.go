@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +25,7 @@ var requirementCheckFuncs = map[string]func(w *CurrentWorker, r sdk.Requirement)
 	sdk.PluginRequirement:        checkPluginRequirement,
 	sdk.ServiceRequirement:       checkServiceRequirement,
 	sdk.MemoryRequirement:        checkMemoryRequirement,
+	sdk.CPURequirement:           checkCPURequirement,
 	sdk.VolumeRequirement:        checkVolumeRequirement,
 	sdk.OSArchRequirement:        checkOSArchRequirement,
 }
@@ -183,6 +185,24 @@ func checkMemoryRequirement(w *CurrentWorker, r sdk.Requirement) (bool, error) {
 	return totalMemory >= (neededMemory*1024*1024)*90/100, nil
 }
 
+func checkCPURequirement(w *CurrentWorker, r sdk.Requirement) (bool, error) {
+	neededCPU, err := sdk.ParseCPURequirement(r.Value)
+	if err != nil {
+		return false, err
+	}
+
+	// The CPUs limit of a docker container is given by the hatchery
+	totalCPU := float64(runtime.NumCPU())
+	if cpuEnv := os.Getenv("CDS_MODEL_CPU"); w.model.Type == sdk.Docker && cpuEnv != "" {
+		totalCPU, err = strconv.ParseFloat(cpuEnv, 64)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return totalCPU >= neededCPU, nil
+}
+
 func checkVolumeRequirement(w *CurrentWorker, r sdk.Requirement) (bool, error) {
 	// volume are supported only for Model Docker
 	if w.model.Type != sdk.Docker {
@@ -64,6 +64,34 @@ func TestCheckHostnameRequirement(t *testing.T) {
 	}
 }
 
+func TestCheckCPURequirement(t *testing.T) {
+	os.Setenv("CDS_MODEL_CPU", "2")
+	defer os.Unsetenv("CDS_MODEL_CPU")
+
+	w := &CurrentWorker{model: sdk.Model{Type: sdk.Docker}}
+	r := sdk.Requirement{
+		Type:  sdk.CPURequirement,
+		Value: "1.5",
+	}
+
+	ok, err := checkRequirement(w, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement should be ok")
+	}
+
+	r.Value = "4"
+	ok, err = checkRequirement(w, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement should not be ok")
+	}
+}
+
 func TestNetworkAccessRequirement(t *testing.T) {
 	r := sdk.Requirement{
 		Type:  sdk.NetworkAccessRequirement,
@@ -13,10 +13,10 @@ import (
 
 	"github.com/spf13/afero"
 
+	"github.com/ovh/cds/engine/worker/internal/runner"
 	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
 
 	"github.com/ovh/cds/sdk"
-	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -42,258 +42,14 @@ func processJobParameter(parameters []sdk.Parameter, secrets []sdk.Variable) {
 	}
 }
 
-// ProcessActionVariables replaces all placeholders inside action recursively using
-// - parent parameters
-// - action build arguments
-// - Secrets from project, application and environment
-//
-// This function should be called ONLY from worker
-func (w *CurrentWorker) processActionVariables(a *sdk.Action, parent *sdk.Action, jobParameters []sdk.Parameter, secrets []sdk.Variable) error {
-	// replaces placeholder in parameters with ActionBuild variables
-	// replaces placeholder in parameters with Parent params
-	for i := range a.Parameters {
-		keepReplacing := true
-		for keepReplacing {
-			t := a.Parameters[i].Value
-
-			if parent != nil {
-				for _, p := range parent.Parameters {
-					a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-				}
-			}
-
-			for _, p := range jobParameters {
-				a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
-
-			for _, p := range secrets {
-				a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
-
-			// If parameters wasn't updated, consider it done
-			if a.Parameters[i].Value == t {
-				keepReplacing = false
-			}
-		}
-	}
-
-	// replaces placeholder in all children recursively
-	for i := range a.Actions {
-		if err := w.processActionVariables(&a.Actions[i], a, jobParameters, secrets); err != nil {
-			return nil
-		}
-	}
-
-	return nil
-}
-
-func (w *CurrentWorker) replaceVariablesPlaceholder(a *sdk.Action, params []sdk.Parameter) error {
-	tmp := sdk.ParametersToMap(params)
-	for i := range a.Parameters {
-		var err error
-		a.Parameters[i].Value, err = interpolate.Do(a.Parameters[i].Value, tmp)
-		if err != nil {
-			return sdk.WrapError(err, "Unable to interpolate action parameters")
-		}
-	}
-	return nil
+// UpdateStepStatus sends the status of a step of the current job to the API.
+func (w *CurrentWorker) UpdateStepStatus(ctx context.Context, stepOrder int, status string) error {
+	return w.updateStepStatus(ctx, w.currentJob.wJob.ID, stepOrder, status)
 }
 
-func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64, secrets []sdk.Variable) (sdk.Result, error) {
-	log.Info(ctx, "runJob> start job %s (%d)", a.Name, jobID)
-	defer func() { log.Info(ctx, "runJob> job %s (%d)", a.Name, jobID) }()
-
-	var jobResult = sdk.Result{
-		Status:  sdk.StatusSuccess,
-		BuildID: jobID,
-	}
-
-	var nDisabled, nCriticalFailed int
-	for jobStepIndex, step := range a.Actions {
-		ctx = workerruntime.SetStepOrder(ctx, jobStepIndex)
-		if err := w.updateStepStatus(ctx, jobID, jobStepIndex, sdk.StatusBuilding); err != nil {
-			jobResult.Status = sdk.StatusFail
-			jobResult.Reason = fmt.Sprintf("Cannot update step (%d) status (%s): %v", jobStepIndex, sdk.StatusBuilding, err)
-			return jobResult, err
-		}
-		var stepResult = sdk.Result{
-			Status:  sdk.StatusNeverBuilt,
-			BuildID: jobID,
-		}
-		if nCriticalFailed == 0 || step.AlwaysExecuted {
-			stepResult = w.runAction(ctx, step, jobID, secrets, step.Name)
-
-			// Check if all newVariables are in currentJob.params
-			// variable can be add in w.currentJob.newVariables by worker command export
-			for _, newVariableFromHandler := range w.currentJob.newVariables {
-				p := sdk.ParameterFind(w.currentJob.params, newVariableFromHandler.Name)
-				if p == nil {
-					w.currentJob.params = append(w.currentJob.params, newVariableFromHandler.ToParameter(""))
-				} else {
-					p.Value = newVariableFromHandler.Value
-				}
-			}
-
-			for _, newVariable := range stepResult.NewVariables {
-				// append the new variable from a step to the following steps
-				w.currentJob.params = append(w.currentJob.params, newVariable.ToParameter(""))
-				// Propagate new variables from step result to jobs result
-				w.currentJob.newVariables = append(w.currentJob.newVariables, newVariable)
-			}
-
-			switch stepResult.Status {
-			case sdk.StatusDisabled:
-				nDisabled++
-			case sdk.StatusFail:
-				if !step.Optional {
-					nCriticalFailed++
-				}
-			}
-		}
-		if err := w.updateStepStatus(ctx, jobID, jobStepIndex, stepResult.Status); err != nil {
-			jobResult.Status = sdk.StatusFail
-			jobResult.Reason = fmt.Sprintf("Cannot update step (%d) status (%s): %v", jobStepIndex, sdk.StatusBuilding, err)
-			return jobResult, err
-		}
-	}
-
-	// Propagate new variables from steps to jobs result
-	jobResult.NewVariables = w.currentJob.newVariables
-
-	//If all steps are disabled, set action status to disabled
-	jobResult.Status = sdk.StatusSuccess
-	if nDisabled >= len(a.Actions) {
-		jobResult.Status = sdk.StatusDisabled
-	}
-	if nCriticalFailed > 0 {
-		jobResult.Status = sdk.StatusFail
-	}
-	return jobResult, nil
-}
-
-func (w *CurrentWorker) runAction(ctx context.Context, a sdk.Action, jobID int64, secrets []sdk.Variable, actionName string) sdk.Result {
-	log.Info(ctx, "runAction> start action %s %s %d", a.StepName, actionName, jobID)
-	defer func() { log.Info(ctx, "runAction> end action %s %s run %d", a.StepName, actionName, jobID) }()
-
-	w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Starting step \"%s\"", actionName))
-	var t0 = time.Now()
-	defer func() {
-		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("End of step \"%s\" (%s)", actionName, sdk.Round(time.Since(t0), time.Second).String()))
-	}()
-
-	//If the action is disabled; skip it
-	if !a.Enabled || w.manualExit {
-		return sdk.Result{
-			Status:  sdk.StatusDisabled,
-			BuildID: jobID,
-		}
-	}
-
-	// Replace variable placeholder that may have been added by last step
-	if err := w.replaceVariablesPlaceholder(&a, w.currentJob.params); err != nil {
-		return sdk.Result{
-			Status:  sdk.StatusFail,
-			BuildID: jobID,
-			Reason:  err.Error(),
-		}
-	}
-
-	// ExpandEnv over all action parameters, avoid expending "CDS_*" env variables
-	if a.Name != sdk.ScriptAction {
-		var getFilteredEnv = func(s string) string {
-			if strings.HasPrefix(s, "CDS_") {
-				return s
-			}
-			return os.Getenv(s)
-		}
-		for i := range a.Parameters {
-			a.Parameters[i].Value = os.Expand(a.Parameters[i].Value, getFilteredEnv)
-		}
-	}
-
-	//If the action if a edge of the action tree; run it
-	switch a.Type {
-	case sdk.BuiltinAction:
-		return w.runBuiltin(ctx, a, secrets)
-	case sdk.PluginAction:
-		//Run the plugin
-		return w.runGRPCPlugin(ctx, a)
-	}
-
-	// There is is no children actions (action is empty) to do, success !
-	if len(a.Actions) == 0 {
-		return sdk.Result{
-			Status:  sdk.StatusSuccess,
-			BuildID: jobID,
-		}
-	}
-
-	//Run children actions
-	r, nDisabled := w.runSteps(ctx, a.Actions, a, jobID, secrets, actionName)
-	//If all steps are disabled, set action status to disabled
-	if nDisabled >= len(a.Actions) {
-		r.Status = sdk.StatusDisabled
-	}
-
-	return r
-}
-
-func (w *CurrentWorker) runSteps(ctx context.Context, steps []sdk.Action, a sdk.Action, jobID int64, secrets []sdk.Variable, stepName string) (sdk.Result, int) {
-	log.Info(ctx, "runSteps> start action steps %s %d len(steps):%d context=%p", stepName, jobID, len(steps), ctx)
-	defer func() {
-		log.Info(ctx, "runSteps> end action steps %s %d len(steps):%d context=%p (%s)", stepName, jobID, len(steps), ctx, ctx.Err())
-	}()
-	var criticalStepFailed bool
-	var nbDisabledChildren int
-
-	r := sdk.Result{
-		Status:  sdk.StatusFail,
-		BuildID: jobID,
-	}
-
-	for i, child := range steps {
-		childName := fmt.Sprintf("%s/%s-%d", stepName, child.Name, i+1)
-		if child.StepName != "" {
-			childName = "/" + child.StepName
-		}
-		if !child.Enabled || w.manualExit {
-			nbDisabledChildren++
-			continue
-		}
-
-		if !criticalStepFailed || child.AlwaysExecuted {
-			r = w.runAction(ctx, child, jobID, secrets, childName)
-			if r.Status != sdk.StatusSuccess && !child.Optional {
-				criticalStepFailed = true
-			}
-		} else if criticalStepFailed && !child.AlwaysExecuted {
-			r.Status = sdk.StatusNeverBuilt
-		}
-
-		// Check if all newVariables are in currentJob.params
-		// variable can be add in w.currentJob.newVariables by worker command export
-		for _, newVariableFromHandler := range w.currentJob.newVariables {
-			p := sdk.ParameterFind(w.currentJob.params, newVariableFromHandler.Name)
-			if p == nil {
-				w.currentJob.params = append(w.currentJob.params, newVariableFromHandler.ToParameter(""))
-			} else {
-				p.Value = newVariableFromHandler.Value
-			}
-		}
-
-		for _, newVariable := range r.NewVariables {
-			// append the new variable from a chile to the following children
-			w.currentJob.params = append(w.currentJob.params, newVariable.ToParameter(""))
-		}
-	}
-
-	if criticalStepFailed {
-		r.Status = sdk.StatusFail
-	} else {
-		r.Status = sdk.StatusSuccess
-	}
-
-	return r, nbDisabledChildren
+// Exited returns true if the worker was asked to exit, remaining steps are disabled.
+func (w *CurrentWorker) Exited() bool {
+	return w.manualExit
 }
 
 func (w *CurrentWorker) updateStepStatus(ctx context.Context, buildID int64, stepOrder int, status string) error {
@@ -515,13 +271,7 @@ func (w *CurrentWorker) ProcessJob(jobInfo sdk.WorkflowNodeJobRunData) (sdk.Resu
 
 	// REPLACE ALL VARIABLE EVEN SECRETS HERE
 	processJobParameter(jobParameters, jobInfo.Secrets)
-	if err := w.processActionVariables(&jobInfo.NodeJobRun.Job.Action, nil, jobParameters, jobInfo.Secrets); err != nil {
-		log.Warning(ctx, "processJob> Cannot process action %s parameters: %s", jobInfo.NodeJobRun.Job.Action.Name, err)
-		return sdk.Result{
-			Status: sdk.StatusFail,
-			Reason: fmt.Sprintf("Error: cannot process action %s parameters", jobInfo.NodeJobRun.Job.Action.Name),
-		}, err
-	}
+	runner.ProcessActionVariables(&jobInfo.NodeJobRun.Job.Action, nil, jobParameters, jobInfo.Secrets)
 
 	// Add secrets as string or password in ActionBuild.Args
 	// So they can be used by plugins
@@ -534,9 +284,9 @@ func (w *CurrentWorker) ProcessJob(jobInfo sdk.WorkflowNodeJobRunData) (sdk.Resu
 		jobParameters = append(jobParameters, p)
 	}
 
-	w.currentJob.params = jobParameters
+	w.currentJob.Params = jobParameters
 
-	res, err := w.runJob(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, jobInfo.Secrets)
+	res, err := runner.RunJob(ctx, w, &w.currentJob.Job, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID)
 
 	if len(res.NewVariables) > 0 {
 		log.Debug("processJob> new variables: %v", res.NewVariables)
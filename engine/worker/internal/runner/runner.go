@@ -0,0 +1,355 @@
+// Package runner runs the steps of a job. It is shared by the worker and by the local
+// execution of pipelines, that only differ by the way they run builtin and plugin actions.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/interpolate"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Executor runs the leaves of the action tree of a job.
+type Executor interface {
+	SendLog(ctx context.Context, level workerruntime.Level, s string)
+	// RunBuiltin runs a builtin action.
+	RunBuiltin(ctx context.Context, a sdk.Action) sdk.Result
+	// RunPlugin runs a plugin action.
+	RunPlugin(ctx context.Context, a sdk.Action) sdk.Result
+	// UpdateStepStatus is called when a step of the job starts and ends.
+	UpdateStepStatus(ctx context.Context, stepOrder int, status string) error
+	// Exited returns true if the job was stopped, remaining actions are disabled.
+	Exited() bool
+}
+
+// Job is the state of a running job.
+type Job struct {
+	// Params are the job parameters, updated with the variables and the outputs of the steps
+	Params []sdk.Parameter
+	// NewVariables are the variables exported by the steps, they are propagated to the job result
+	NewVariables []sdk.Variable
+	// StepID is the id of the running step, StepOutputs are the outputs set by the steps
+	StepID      string
+	StepOutputs []sdk.Variable
+}
+
+// MergeVariables adds the variables exported and the outputs set during the last step to the job
+// parameters, to be available in the following steps.
+func (j *Job) MergeVariables() {
+	for _, vs := range [][]sdk.Variable{j.NewVariables, j.StepOutputs} {
+		for _, v := range vs {
+			p := sdk.ParameterFind(j.Params, v.Name)
+			if p == nil {
+				j.Params = append(j.Params, v.ToParameter(""))
+			} else {
+				p.Value = v.Value
+			}
+		}
+	}
+}
+
+// ProcessActionVariables replaces all placeholders inside action recursively using
+// - parent parameters
+// - action build arguments
+// - Secrets from project, application and environment
+func ProcessActionVariables(a *sdk.Action, parent *sdk.Action, jobParameters []sdk.Parameter, secrets []sdk.Variable) {
+	// replaces placeholder in parameters with ActionBuild variables
+	// replaces placeholder in parameters with Parent params
+	for i := range a.Parameters {
+		keepReplacing := true
+		for keepReplacing {
+			t := a.Parameters[i].Value
+
+			if parent != nil {
+				for _, p := range parent.Parameters {
+					a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
+				}
+			}
+
+			for _, p := range jobParameters {
+				a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
+			}
+
+			for _, p := range secrets {
+				a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
+			}
+
+			// If parameters wasn't updated, consider it done
+			if a.Parameters[i].Value == t {
+				keepReplacing = false
+			}
+		}
+	}
+
+	// replaces placeholder in all children recursively
+	for i := range a.Actions {
+		ProcessActionVariables(&a.Actions[i], a, jobParameters, secrets)
+	}
+}
+
+type runner struct {
+	e     Executor
+	job   *Job
+	jobID int64
+}
+
+// RunJob runs the steps of the job action with given executor, job parameters should be set on given job state.
+func RunJob(ctx context.Context, e Executor, job *Job, a *sdk.Action, jobID int64) (sdk.Result, error) {
+	r := runner{e: e, job: job, jobID: jobID}
+	return r.runJob(ctx, a)
+}
+
+func (r *runner) runJob(ctx context.Context, a *sdk.Action) (sdk.Result, error) {
+	log.Info(ctx, "runJob> start job %s (%d)", a.Name, r.jobID)
+	defer func() { log.Info(ctx, "runJob> job %s (%d)", a.Name, r.jobID) }()
+
+	var jobResult = sdk.Result{
+		Status:  sdk.StatusSuccess,
+		BuildID: r.jobID,
+	}
+
+	// The job context is only used to run steps, so that logs and step statuses can
+	// still be sent to the API when the job timeout is reached
+	jobCtx, jobCancel := context.WithCancel(ctx)
+	if a.Timeout > 0 {
+		jobCtx, jobCancel = context.WithTimeout(ctx, a.TimeoutDuration())
+	}
+	defer jobCancel()
+
+	var nDisabled, nCriticalFailed int
+	var jobTimedOut bool
+	for jobStepIndex, step := range a.Actions {
+		ctx = workerruntime.SetStepOrder(ctx, jobStepIndex)
+		if err := r.e.UpdateStepStatus(ctx, jobStepIndex, sdk.StatusBuilding); err != nil {
+			jobResult.Status = sdk.StatusFail
+			jobResult.Reason = fmt.Sprintf("Cannot update step (%d) status (%s): %v", jobStepIndex, sdk.StatusBuilding, err)
+			return jobResult, err
+		}
+		var stepResult = sdk.Result{
+			Status:  sdk.StatusNeverBuilt,
+			BuildID: r.jobID,
+		}
+		if !jobTimedOut && (nCriticalFailed == 0 || step.AlwaysExecuted) {
+			r.job.StepID = step.StepID
+			stepResult = r.runActionWithTimeout(ctx, jobCtx, jobStepIndex, step)
+			r.job.StepID = ""
+			if jobCtx.Err() == context.DeadlineExceeded {
+				jobTimedOut = true
+				stepResult.Status = sdk.StatusFail
+				stepResult.Reason = fmt.Sprintf("Job timed out after %s", a.TimeoutDuration())
+				r.e.SendLog(ctx, workerruntime.LevelError, stepResult.Reason)
+			}
+
+			// variables can be added in the job state by the worker commands export and output
+			r.job.MergeVariables()
+
+			for _, newVariable := range stepResult.NewVariables {
+				// append the new variable from a step to the following steps
+				r.job.Params = append(r.job.Params, newVariable.ToParameter(""))
+				// Propagate new variables from step result to jobs result
+				r.job.NewVariables = append(r.job.NewVariables, newVariable)
+			}
+
+			switch stepResult.Status {
+			case sdk.StatusDisabled:
+				nDisabled++
+			case sdk.StatusFail:
+				if !step.Optional {
+					if nCriticalFailed == 0 {
+						jobResult.ExitCode = stepResult.ExitCode
+					}
+					nCriticalFailed++
+				}
+			}
+		}
+		if err := r.e.UpdateStepStatus(ctx, jobStepIndex, stepResult.Status); err != nil {
+			jobResult.Status = sdk.StatusFail
+			jobResult.Reason = fmt.Sprintf("Cannot update step (%d) status (%s): %v", jobStepIndex, sdk.StatusBuilding, err)
+			return jobResult, err
+		}
+	}
+
+	// Propagate new variables from steps to jobs result
+	jobResult.NewVariables = r.job.NewVariables
+
+	// Compute job outputs from the variables and the steps outputs
+	if len(a.Outputs) > 0 {
+		tmp := sdk.ParametersToMap(r.job.Params)
+		jobResult.Outputs = make(map[string]string, len(a.Outputs))
+		for name, value := range a.Outputs {
+			v, err := interpolate.Do(value, tmp)
+			if err != nil {
+				r.e.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("Unable to compute job output %s: %v", name, err))
+				continue
+			}
+			jobResult.Outputs[name] = v
+		}
+	}
+
+	//If all steps are disabled, set action status to disabled
+	jobResult.Status = sdk.StatusSuccess
+	if nDisabled >= len(a.Actions) {
+		jobResult.Status = sdk.StatusDisabled
+	}
+	if nCriticalFailed > 0 {
+		jobResult.Status = sdk.StatusFail
+	}
+	if jobTimedOut {
+		jobResult.Status = sdk.StatusFail
+		jobResult.Reason = fmt.Sprintf("Job timed out after %s", a.TimeoutDuration())
+	}
+	return jobResult, nil
+}
+
+// runActionWithTimeout runs given step with the job context, bounded by the step timeout if any.
+// Given ctx should be the step context that will be used to send logs.
+func (r *runner) runActionWithTimeout(ctx, jobCtx context.Context, stepOrder int, step sdk.Action) sdk.Result {
+	stepCtx := workerruntime.SetStepOrder(jobCtx, stepOrder)
+	if step.Timeout > 0 {
+		var cancel func()
+		stepCtx, cancel = context.WithTimeout(stepCtx, step.TimeoutDuration())
+		defer cancel()
+	}
+
+	res := r.runAction(stepCtx, step, step.Name)
+
+	if stepCtx.Err() == context.DeadlineExceeded && jobCtx.Err() == nil {
+		res.Status = sdk.StatusFail
+		res.Reason = fmt.Sprintf("Step timed out after %s", step.TimeoutDuration())
+		r.e.SendLog(ctx, workerruntime.LevelError, res.Reason)
+	}
+	return res
+}
+
+func (r *runner) runAction(ctx context.Context, a sdk.Action, actionName string) sdk.Result {
+	log.Info(ctx, "runAction> start action %s %s %d", a.StepName, actionName, r.jobID)
+	defer func() { log.Info(ctx, "runAction> end action %s %s run %d", a.StepName, actionName, r.jobID) }()
+
+	r.e.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Starting step \"%s\"", actionName))
+	var t0 = time.Now()
+	defer func() {
+		r.e.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("End of step \"%s\" (%s)", actionName, sdk.Round(time.Since(t0), time.Second).String()))
+	}()
+
+	//If the action is disabled; skip it
+	if !a.Enabled || r.e.Exited() {
+		return sdk.Result{
+			Status:  sdk.StatusDisabled,
+			BuildID: r.jobID,
+		}
+	}
+
+	// Replace variable placeholder that may have been added by last step
+	tmp := sdk.ParametersToMap(r.job.Params)
+	for i := range a.Parameters {
+		var err error
+		a.Parameters[i].Value, err = interpolate.Do(a.Parameters[i].Value, tmp)
+		if err != nil {
+			return sdk.Result{
+				Status:  sdk.StatusFail,
+				BuildID: r.jobID,
+				Reason:  sdk.WrapError(err, "Unable to interpolate action parameters").Error(),
+			}
+		}
+	}
+
+	// ExpandEnv over all action parameters, avoid expending "CDS_*" env variables
+	if a.Name != sdk.ScriptAction {
+		var getFilteredEnv = func(s string) string {
+			if strings.HasPrefix(s, "CDS_") {
+				return s
+			}
+			return os.Getenv(s)
+		}
+		for i := range a.Parameters {
+			a.Parameters[i].Value = os.Expand(a.Parameters[i].Value, getFilteredEnv)
+		}
+	}
+
+	//If the action if a edge of the action tree; run it
+	switch a.Type {
+	case sdk.BuiltinAction:
+		return r.e.RunBuiltin(ctx, a)
+	case sdk.PluginAction:
+		//Run the plugin
+		return r.e.RunPlugin(ctx, a)
+	}
+
+	// There is is no children actions (action is empty) to do, success !
+	if len(a.Actions) == 0 {
+		return sdk.Result{
+			Status:  sdk.StatusSuccess,
+			BuildID: r.jobID,
+		}
+	}
+
+	//Run children actions
+	res, nDisabled := r.runSteps(ctx, a.Actions, actionName)
+	//If all steps are disabled, set action status to disabled
+	if nDisabled >= len(a.Actions) {
+		res.Status = sdk.StatusDisabled
+	}
+
+	return res
+}
+
+func (r *runner) runSteps(ctx context.Context, steps []sdk.Action, stepName string) (sdk.Result, int) {
+	log.Info(ctx, "runSteps> start action steps %s %d len(steps):%d context=%p", stepName, r.jobID, len(steps), ctx)
+	defer func() {
+		log.Info(ctx, "runSteps> end action steps %s %d len(steps):%d context=%p (%s)", stepName, r.jobID, len(steps), ctx, ctx.Err())
+	}()
+	var criticalStepFailed bool
+	var nbDisabledChildren int
+	var exitCode int
+
+	res := sdk.Result{
+		Status:  sdk.StatusFail,
+		BuildID: r.jobID,
+	}
+
+	for i, child := range steps {
+		childName := fmt.Sprintf("%s/%s-%d", stepName, child.Name, i+1)
+		if child.StepName != "" {
+			childName = "/" + child.StepName
+		}
+		if !child.Enabled || r.e.Exited() {
+			nbDisabledChildren++
+			continue
+		}
+
+		if !criticalStepFailed || child.AlwaysExecuted {
+			res = r.runAction(ctx, child, childName)
+			if res.Status != sdk.StatusSuccess && !child.Optional {
+				if !criticalStepFailed {
+					exitCode = res.ExitCode
+				}
+				criticalStepFailed = true
+			}
+		} else if criticalStepFailed && !child.AlwaysExecuted {
+			res.Status = sdk.StatusNeverBuilt
+		}
+
+		// variables can be added in the job state by the worker commands export and output
+		r.job.MergeVariables()
+
+		for _, newVariable := range res.NewVariables {
+			// append the new variable from a chile to the following children
+			r.job.Params = append(r.job.Params, newVariable.ToParameter(""))
+		}
+	}
+
+	if criticalStepFailed {
+		res.Status = sdk.StatusFail
+		res.ExitCode = exitCode
+	} else {
+		res.Status = sdk.StatusSuccess
+	}
+
+	return res, nbDisabledChildren
+}
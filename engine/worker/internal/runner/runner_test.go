@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+type testExecutor struct {
+	job      *Job
+	logs     []string
+	statuses []string
+}
+
+func (e *testExecutor) SendLog(ctx context.Context, level workerruntime.Level, s string) {
+	e.logs = append(e.logs, s)
+}
+
+// RunBuiltin fails with the exit code given as parameter, or sets an output on the running step.
+func (e *testExecutor) RunBuiltin(ctx context.Context, a sdk.Action) sdk.Result {
+	if p := sdk.ParameterFind(a.Parameters, "exit"); p != nil {
+		return sdk.Result{Status: sdk.StatusFail, ExitCode: 2}
+	}
+	if p := sdk.ParameterFind(a.Parameters, "output"); p != nil {
+		e.job.StepOutputs = append(e.job.StepOutputs, sdk.Variable{Name: sdk.StepOutputVariable(e.job.StepID, "value"), Value: p.Value})
+	}
+	return sdk.Result{Status: sdk.StatusSuccess}
+}
+
+func (e *testExecutor) RunPlugin(ctx context.Context, a sdk.Action) sdk.Result {
+	return sdk.Result{Status: sdk.StatusSkipped}
+}
+
+func (e *testExecutor) UpdateStepStatus(ctx context.Context, stepOrder int, status string) error {
+	e.statuses = append(e.statuses, status)
+	return nil
+}
+
+func (e *testExecutor) Exited() bool { return false }
+
+func TestRunJob(t *testing.T) {
+	builtin := func(name string, params ...sdk.Parameter) sdk.Action {
+		return sdk.Action{Name: name, Type: sdk.BuiltinAction, Enabled: true, Parameters: params}
+	}
+	build := builtin("build", sdk.Parameter{Name: "output", Value: "{{.cds.version}}"})
+	build.StepID = "build"
+	group := sdk.Action{Name: "group", Type: sdk.DefaultAction, Enabled: true, Actions: []sdk.Action{
+		builtin("echo", sdk.Parameter{Name: "msg", Value: "{{.cds.steps.build.outputs.value}}"}),
+		builtin("fail", sdk.Parameter{Name: "exit", Value: "2"}),
+	}}
+	group.Actions[1].StepName = "tests"
+	job := sdk.Action{
+		Name:    "job",
+		Actions: []sdk.Action{build, group, builtin("never")},
+		Outputs: map[string]string{"version": "{{.cds.steps.build.outputs.value}}"},
+	}
+
+	state := &Job{Params: []sdk.Parameter{{Name: "cds.version", Value: "12"}}}
+	e := &testExecutor{job: state}
+	ProcessActionVariables(&job, nil, state.Params, nil)
+	res, err := RunJob(context.TODO(), e, state, &job, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, sdk.StatusFail, res.Status)
+	assert.Equal(t, 2, res.ExitCode)
+	assert.Equal(t, map[string]string{"version": "12"}, res.Outputs)
+	assert.Equal(t, "12", sdk.ParameterValue(state.Params, "cds.steps.build.outputs.value"))
+	assert.Equal(t, []string{
+		sdk.StatusBuilding, sdk.StatusSuccess,
+		sdk.StatusBuilding, sdk.StatusFail,
+		sdk.StatusBuilding, sdk.StatusNeverBuilt,
+	}, e.statuses)
+	assert.Contains(t, e.logs, `Starting step "group/echo-1"`)
+	assert.Contains(t, e.logs, `Starting step "/tests"`)
+}
@@ -29,7 +29,9 @@ func (w *CurrentWorker) Take(ctx context.Context, job sdk.WorkflowNodeJobRun) er
 	w.currentJob.wJob = &info.NodeJobRun
 	w.currentJob.secrets = info.Secrets
 	// Reset build variables
-	w.currentJob.newVariables = nil
+	w.currentJob.NewVariables = nil
+	w.currentJob.StepID = ""
+	w.currentJob.StepOutputs = nil
 
 	start := time.Now()
 
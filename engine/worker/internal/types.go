@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ovh/cds/engine/worker/internal/runner"
 	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
 
 	"github.com/spf13/afero"
@@ -37,11 +38,10 @@ type CurrentWorker struct {
 		model       string
 	}
 	currentJob struct {
-		wJob         *sdk.WorkflowNodeJobRun
-		newVariables []sdk.Variable
-		params       []sdk.Parameter
-		secrets      []sdk.Variable
-		context      context.Context
+		runner.Job
+		wJob    *sdk.WorkflowNodeJobRun
+		secrets []sdk.Variable
+		context context.Context
 	}
 	status struct {
 		Name   string `json:"name"`
@@ -72,7 +72,7 @@ func (wk *CurrentWorker) SetContext(c context.Context) {
 }
 
 func (wk *CurrentWorker) Parameters() []sdk.Parameter {
-	return wk.currentJob.params
+	return wk.currentJob.Params
 }
 
 func (wk *CurrentWorker) SendLog(ctx context.Context, level workerruntime.Level, s string) {
@@ -119,7 +119,7 @@ func (w *CurrentWorker) Environ() []string {
 	newEnv = append(newEnv, fmt.Sprintf("%s=%d", WorkerServerPort, w.HTTPPort()))
 
 	//set up environment variables from pipeline build job parameters
-	for _, p := range w.currentJob.params {
+	for _, p := range w.currentJob.Params {
 		// avoid put private key in environment var as it's a binary value
 		if strings.HasPrefix(p.Name, "cds.key.") && strings.HasSuffix(p.Name, ".priv") {
 			continue
@@ -136,7 +136,7 @@ func (w *CurrentWorker) Environ() []string {
 		newEnv = append(newEnv, fmt.Sprintf("%s=%s", envName, p.Value))
 	}
 
-	for _, p := range w.currentJob.newVariables {
+	for _, p := range w.currentJob.NewVariables {
 		envName := strings.Replace(p.Name, ".", "_", -1)
 		envName = strings.Replace(envName, "-", "_", -1)
 		envName = strings.ToUpper(envName)
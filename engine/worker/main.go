@@ -3,6 +3,7 @@ package main
 func main() {
 	cmd := cmdMain()
 	cmd.AddCommand(cmdExport)
+	cmd.AddCommand(cmdOutput)
 	cmd.AddCommand(cmdUpload())
 	cmd.AddCommand(cmdArtifacts())
 	cmd.AddCommand(cmdDownload())
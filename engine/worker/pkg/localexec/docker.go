@@ -0,0 +1,64 @@
+package localexec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// startContainer starts a detached container from given image. The base directory is
+// mounted on the same path so that scripts written by the script action can be run
+// in the container as they are.
+func startContainer(ctx context.Context, image, basedir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "docker", "run", "--detach", "--rm",
+		"--volume", basedir+":"+basedir,
+		"--entrypoint", "tail",
+		image, "-f", "/dev/null")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", sdk.WithStack(fmt.Errorf("cannot start container from image %s: %v: %s", image, err, strings.TrimSpace(stderr.String())))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func removeContainer(containerID string) error {
+	if err := exec.Command("docker", "rm", "--force", containerID).Run(); err != nil {
+		return sdk.WithStack(fmt.Errorf("cannot remove container %s: %v", containerID, err))
+	}
+	return nil
+}
+
+// containerScript rewrites the content of a script so that the script action runs it
+// in given container with the job environment.
+func containerScript(content, containerID, workdir string, env []string) string {
+	shell := []string{"/bin/sh", "-e"}
+	if strings.HasPrefix(content, "#!") {
+		t := strings.SplitN(content, "\n", 2)
+		shell = strings.Fields(strings.TrimPrefix(t[0], "#!"))
+		// the worker stops the script on first error for shells without options
+		if len(shell) == 1 && strings.HasSuffix(path.Base(shell[0]), "sh") {
+			shell = append(shell, "-e")
+		}
+		content = ""
+		if len(t) > 1 {
+			content = t[1]
+		}
+	}
+
+	args := []string{"docker", "exec", "--interactive", "--workdir", workdir}
+	for _, e := range env {
+		// only give the variable name, its value is read from the docker command environment
+		args = append(args, "--env", strings.SplitN(e, "=", 2)[0])
+	}
+	args = append(args, containerID)
+	args = append(args, shell...)
+
+	return "#!" + strings.Join(args, " ") + "\n" + content
+}
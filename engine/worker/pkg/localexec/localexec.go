@@ -0,0 +1,173 @@
+// Package localexec runs a pipeline on the local host, in the host shell or in a
+// docker container, using the builtin actions of the worker.
+package localexec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/internal/action"
+	"github.com/ovh/cds/engine/worker/internal/runner"
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+// BuiltInAction defines builtin action signature.
+type BuiltInAction func(context.Context, workerruntime.Runtime, sdk.Action, []sdk.Variable) (sdk.Result, error)
+
+// mapBuiltinActions contains the builtin actions that can be run without CDS API.
+var mapBuiltinActions = map[string]BuiltInAction{
+	sdk.ScriptAction:              action.RunScriptAction,
+	sdk.GitCloneAction:            action.RunGitClone,
+	sdk.CheckoutApplicationAction: action.RunCheckoutApplication,
+}
+
+// ActionResolver returns a non builtin action from its group and name.
+type ActionResolver func(groupName, name string) (*sdk.Action, error)
+
+// Options for a local execution.
+type Options struct {
+	// Workspace is the directory where jobs working directories are created.
+	Workspace string
+	// Image is the docker image used to run script steps, host shell is used if empty.
+	Image string
+	// Parameters given to all jobs, ie. project variables.
+	Parameters []sdk.Parameter
+	// ResolveAction is used to load non builtin actions, they fail if nil.
+	ResolveAction ActionResolver
+	// Output receives the steps logs.
+	Output io.Writer
+}
+
+// Run executes all the stages of given pipeline in order. Jobs of a stage are run one
+// after the other, next stages are skipped if a job fails.
+func Run(ctx context.Context, pip sdk.Pipeline, opts Options) (sdk.Result, error) {
+	if opts.Image != "" && runtime.GOOS == "windows" {
+		return sdk.Result{}, sdk.NewErrorFrom(sdk.ErrWrongRequest, "docker execution is not available on windows")
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	workspace, err := filepath.Abs(opts.Workspace)
+	if err != nil {
+		return sdk.Result{}, sdk.WithStack(err)
+	}
+
+	stages := make([]sdk.Stage, len(pip.Stages))
+	copy(stages, pip.Stages)
+	sort.Slice(stages, func(i, j int) bool { return stages[i].BuildOrder < stages[j].BuildOrder })
+
+	res := sdk.Result{Status: sdk.StatusSuccess}
+	var jobIndex int
+	for _, s := range stages {
+		for _, j := range s.Jobs {
+			jobIndex++
+			prefix := fmt.Sprintf("[%s]", j.Action.Name)
+			if !s.Enabled || !j.Enabled {
+				fmt.Fprintf(opts.Output, "%s job disabled\n", prefix)
+				continue
+			}
+			if res.Status != sdk.StatusSuccess {
+				fmt.Fprintf(opts.Output, "%s job skipped\n", prefix)
+				continue
+			}
+
+			jobRes, err := runJob(ctx, pip, s, j, filepath.Join(workspace, fmt.Sprintf("%d", jobIndex)), prefix, opts)
+			if err != nil {
+				return jobRes, err
+			}
+			fmt.Fprintf(opts.Output, "%s job %s\n", prefix, jobRes.Status)
+			if jobRes.Status == sdk.StatusFail {
+				res = jobRes
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func runJob(ctx context.Context, pip sdk.Pipeline, s sdk.Stage, j sdk.Job, basedir, prefix string, opts Options) (sdk.Result, error) {
+	fs := afero.NewOsFs()
+	if err := fs.MkdirAll(filepath.Join(basedir, "workspace"), os.FileMode(0755)); err != nil {
+		return sdk.Result{}, sdk.WithStack(err)
+	}
+	r := &localRuntime{
+		basedir: afero.NewBasePathFs(fs, basedir),
+		prefix:  prefix,
+		out:     opts.Output,
+		mutex:   new(sync.Mutex),
+	}
+	wd, err := r.basedir.Open("workspace")
+	if err != nil {
+		return sdk.Result{}, sdk.WithStack(err)
+	}
+	defer wd.Close() // nolint
+	ctx = workerruntime.SetWorkingDirectory(ctx, wd)
+	ctx = workerruntime.SetJobID(ctx, 0)
+
+	wdAbs := filepath.Join(basedir, "workspace")
+	params := append([]sdk.Parameter{}, opts.Parameters...)
+	params = append(params, j.MatrixParameters()...)
+	sdk.AddParameter(&params, "cds.pipeline", sdk.StringParameter, pip.Name)
+	sdk.AddParameter(&params, "cds.stage", sdk.StringParameter, s.Name)
+	sdk.AddParameter(&params, "cds.job", sdk.StringParameter, j.Action.Name)
+	sdk.AddParameter(&params, "cds.workspace", sdk.StringParameter, wdAbs)
+	sdk.AddParameter(&params, "cds.worker", sdk.StringParameter, r.Name())
+	r.job.Params = params
+
+	if opts.Image != "" {
+		r.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Starting container from image %s", opts.Image))
+		r.containerID, err = startContainer(ctx, opts.Image, basedir)
+		if err != nil {
+			return sdk.Result{}, err
+		}
+		defer func() {
+			if err := removeContainer(r.containerID); err != nil {
+				r.SendLog(ctx, workerruntime.LevelWarn, err.Error())
+			}
+		}()
+	}
+
+	a := j.Action
+	if err := resolveAction(&a, opts.ResolveAction); err != nil {
+		return sdk.Result{Status: sdk.StatusFail, Reason: err.Error()}, nil
+	}
+	runner.ProcessActionVariables(&a, nil, r.job.Params, nil)
+
+	return runner.RunJob(ctx, r, &r.job, &a, 0)
+}
+
+// resolveAction loads recursively the children of non builtin actions.
+func resolveAction(a *sdk.Action, resolve ActionResolver) error {
+	for i := range a.Actions {
+		child := &a.Actions[i]
+		if child.Type == "" {
+			if resolve == nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "cannot load action %s without CDS API", child.Name)
+			}
+			var groupName string
+			if child.Group != nil {
+				groupName = child.Group.Name
+			}
+			loaded, err := resolve(groupName, child.Name)
+			if err != nil {
+				return sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrWrongRequest, "cannot load action %s", child.Name))
+			}
+			child.Type = loaded.Type
+			child.Actions = loaded.Actions
+			child.Parameters = sdk.ParametersMerge(loaded.Parameters, child.Parameters)
+		}
+		if err := resolveAction(child, resolve); err != nil {
+			return err
+		}
+	}
+	return nil
+}
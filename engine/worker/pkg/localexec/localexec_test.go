@@ -0,0 +1,77 @@
+package localexec
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func TestRun(t *testing.T) {
+	workspace, err := ioutil.TempDir("", "localexec")
+	require.NoError(t, err)
+	defer os.RemoveAll(workspace) // nolint
+
+	payload, err := exportentities.ParsePipeline(exportentities.FormatYAML, []byte(`version: v1.0
+name: build
+stages:
+- compile
+- package
+jobs:
+- job: compile
+  stage: compile
+  steps:
+  - name: hello
+    script:
+    - echo "hello {{.cds.proj.name}}"
+    - echo "from $CDS_JOB"
+  - name: unknown
+    artifactUpload:
+      path: ./*
+      tag: "{{.cds.version}}"
+- job: package
+  stage: package
+  steps:
+  - script: exit 1
+  - script: echo "never built"
+  - script: echo "always executed"
+    always_executed: true
+`))
+	require.NoError(t, err)
+	pip, err := payload.Pipeline()
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	res, err := Run(context.TODO(), *pip, Options{
+		Workspace:  workspace,
+		Parameters: []sdk.Parameter{{Name: "cds.proj.name", Type: sdk.StringParameter, Value: "world"}},
+		Output:     &out,
+	})
+	require.NoError(t, err)
+	t.Log(out.String())
+
+	assert.Equal(t, sdk.StatusFail, res.Status)
+	assert.Contains(t, out.String(), "[compile] hello world")
+	assert.Contains(t, out.String(), "[compile] from compile")
+	assert.Contains(t, out.String(), "[compile] [WARN] builtin action Artifact Upload is not available locally, step skipped")
+	assert.Contains(t, out.String(), "[compile] job Success")
+	assert.NotContains(t, out.String(), "never built")
+	assert.Contains(t, out.String(), "[package] always executed")
+	assert.Contains(t, out.String(), "[package] job Fail")
+}
+
+func Test_containerScript(t *testing.T) {
+	assert.Equal(t, "#!docker exec --interactive --workdir /tmp/1/workspace --env CDS_JOB abc /bin/sh -e\necho hello",
+		containerScript("echo hello", "abc", "/tmp/1/workspace", []string{"CDS_JOB=compile"}))
+	assert.Equal(t, "#!docker exec --interactive --workdir /tmp/1/workspace abc /bin/bash -e\necho hello",
+		containerScript("#!/bin/bash\necho hello", "abc", "/tmp/1/workspace", nil))
+	assert.Equal(t, "#!docker exec --interactive --workdir /tmp/1/workspace abc /usr/bin/env python3\nprint('hello')",
+		containerScript("#!/usr/bin/env python3\nprint('hello')", "abc", "/tmp/1/workspace", nil))
+}
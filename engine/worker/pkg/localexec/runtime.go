@@ -0,0 +1,141 @@
+package localexec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/internal/runner"
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+// localRuntime implements workerruntime.Runtime to run builtin actions on the
+// local host, without any CDS API.
+type localRuntime struct {
+	basedir afero.Fs
+	job     runner.Job
+	prefix  string
+	out     io.Writer
+	mutex   *sync.Mutex
+	// containerID is the docker container used to run the scripts, the host shell is used if empty
+	containerID string
+}
+
+var _ workerruntime.Runtime = new(localRuntime)
+var _ runner.Executor = new(localRuntime)
+
+func (r *localRuntime) Name() string { return "local" }
+
+func (r *localRuntime) Register(ctx context.Context) error { return nil }
+
+func (r *localRuntime) Take(ctx context.Context, job sdk.WorkflowNodeJobRun) error { return nil }
+
+func (r *localRuntime) ProcessJob(job sdk.WorkflowNodeJobRunData) (sdk.Result, error) {
+	return sdk.Result{}, sdk.WithStack(fmt.Errorf("cannot process a job from CDS API locally"))
+}
+
+func (r *localRuntime) Unregister(ctx context.Context) error { return nil }
+
+// SendLog prints given log line on the output, prefixed by the job and step name.
+func (r *localRuntime) SendLog(ctx context.Context, level workerruntime.Level, s string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	s = strings.TrimRight(s, "\n")
+	if level == workerruntime.LevelInfo {
+		fmt.Fprintf(r.out, "%s %s\n", r.prefix, s)
+		return
+	}
+	fmt.Fprintf(r.out, "%s [%s] %s\n", r.prefix, level, s)
+}
+
+func (r *localRuntime) InstallKey(key sdk.Variable) (*workerruntime.KeyResponse, error) {
+	return nil, sdk.WithStack(fmt.Errorf("cannot install key %s: keys are not available locally", key.Name))
+}
+
+func (r *localRuntime) InstallKeyTo(key sdk.Variable, destinationPath string) (*workerruntime.KeyResponse, error) {
+	return r.InstallKey(key)
+}
+
+// Client returns nil as there is no worker session on local execution,
+// actions that need it are not run locally.
+func (r *localRuntime) Client() cdsclient.WorkerInterface { return nil }
+
+func (r *localRuntime) BaseDir() afero.Fs { return r.basedir }
+
+// Environ returns the host environment and the job parameters as environment
+// variables, the same way the worker does.
+func (r *localRuntime) Environ() []string {
+	env := []string{"CI=1"}
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, "CDS_") {
+			continue
+		}
+		env = append(env, e)
+	}
+	return append(env, r.jobEnviron()...)
+}
+
+func (r *localRuntime) jobEnviron() []string {
+	var env []string
+	for _, p := range r.job.Params {
+		if p.Type == sdk.KeyParameter && !strings.HasSuffix(p.Name, ".pub") {
+			continue
+		}
+		env = append(env, sdk.EnvVartoENV(p)...)
+
+		envName := strings.Replace(p.Name, ".", "_", -1)
+		envName = strings.Replace(envName, "-", "_", -1)
+		envName = strings.ToUpper(envName)
+		env = append(env, fmt.Sprintf("%s=%s", envName, p.Value))
+	}
+	return env
+}
+
+func (r *localRuntime) Blur(i interface{}) error { return nil }
+
+func (r *localRuntime) HTTPPort() int32 { return 0 }
+
+func (r *localRuntime) Parameters() []sdk.Parameter { return r.job.Params }
+
+// RunBuiltin runs the builtin actions available without CDS API, others are skipped.
+func (r *localRuntime) RunBuiltin(ctx context.Context, a sdk.Action) sdk.Result {
+	f, ok := mapBuiltinActions[a.Name]
+	if !ok {
+		r.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("builtin action %s is not available locally, step skipped", a.Name))
+		return sdk.Result{Status: sdk.StatusSkipped}
+	}
+	if a.Name == sdk.ScriptAction && r.containerID != "" {
+		if p := sdk.ParameterFind(a.Parameters, "script"); p != nil {
+			wd, _ := workerruntime.WorkingDirectory(ctx)
+			workdir, _ := r.basedir.(*afero.BasePathFs).RealPath(wd.Name())
+			p.Value = containerScript(p.Value, r.containerID, workdir, r.jobEnviron())
+		}
+	}
+	res, err := f(ctx, r, a, nil)
+	if err != nil {
+		res.Status = sdk.StatusFail
+		res.Reason = err.Error()
+		r.SendLog(ctx, workerruntime.LevelError, res.Reason)
+	}
+	return res
+}
+
+// RunPlugin skips the plugin actions that need CDS API.
+func (r *localRuntime) RunPlugin(ctx context.Context, a sdk.Action) sdk.Result {
+	r.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("plugin %s is not available locally, step skipped", a.Name))
+	return sdk.Result{Status: sdk.StatusSkipped}
+}
+
+// UpdateStepStatus does nothing as step statuses are only sent to CDS API.
+func (r *localRuntime) UpdateStepStatus(ctx context.Context, stepOrder int, status string) error {
+	return nil
+}
+
+func (r *localRuntime) Exited() bool { return false }
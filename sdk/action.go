@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	json "encoding/json"
 	"fmt"
+	"time"
 )
 
 // Action type
@@ -39,15 +40,20 @@ func NewAction(name string) *Action {
 
 // Action is the base element of CDS pipeline
 type Action struct {
-	ID          int64  `json:"id" yaml:"-" db:"id"`
-	GroupID     *int64 `json:"group_id,omitempty" yaml:"-" db:"group_id"`
-	Name        string `json:"name" db:"name"`
-	Type        string `json:"type" yaml:"-" db:"type"`
-	Description string `json:"description" yaml:"desc,omitempty" db:"description"`
-	Enabled     bool   `json:"enabled" yaml:"-" db:"enabled"`
-	Deprecated  bool   `json:"deprecated" yaml:"-" db:"deprecated"`
+	ID          int64        `json:"id" yaml:"-" db:"id"`
+	GroupID     *int64       `json:"group_id,omitempty" yaml:"-" db:"group_id"`
+	Name        string       `json:"name" db:"name"`
+	Type        string       `json:"type" yaml:"-" db:"type"`
+	Description string       `json:"description" yaml:"desc,omitempty" db:"description"`
+	Enabled     bool         `json:"enabled" yaml:"-" db:"enabled"`
+	Deprecated  bool         `json:"deprecated" yaml:"-" db:"deprecated"`
+	Timeout     int64        `json:"timeout,omitempty" yaml:"-" db:"timeout"` // in seconds, zero means no timeout
+	Retry       *ActionRetry `json:"retry,omitempty" yaml:"-" db:"retry"`
+	// Outputs are the outputs of a job, computed from its steps outputs when the job ends
+	Outputs ActionOutputs `json:"outputs,omitempty" yaml:"-" db:"outputs"`
 	// aggregates from action_edge
 	StepName       string `json:"step_name,omitempty" yaml:"step_name,omitempty" db:"-"`
+	StepID         string `json:"step_id,omitempty" yaml:"step_id,omitempty" db:"-"`
 	Optional       bool   `json:"optional" yaml:"-" db:"-"`
 	AlwaysExecuted bool   `json:"always_executed" yaml:"-" db:"-"`
 	// aggregates
@@ -126,6 +132,16 @@ func (a Action) IsValid() error {
 		return NewErrorFrom(ErrWrongRequest, "invalid name for action")
 	}
 
+	if a.Timeout < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid timeout for action")
+	}
+
+	if a.Retry != nil {
+		if err := a.Retry.IsValid(); err != nil {
+			return err
+		}
+	}
+
 	for i := range a.Parameters {
 		if err := a.Parameters[i].IsValid(); err != nil {
 			return err
@@ -136,10 +152,17 @@ func (a Action) IsValid() error {
 		return err
 	}
 
+	if err := a.IsValidOutputs(); err != nil {
+		return err
+	}
+
 	for i := range a.Actions {
 		if a.Actions[i].ID == 0 {
 			return NewErrorFrom(ErrWrongRequest, "invalid action id for child")
 		}
+		if a.Actions[i].Timeout < 0 {
+			return NewErrorFrom(ErrWrongRequest, "invalid timeout for child")
+		}
 		for j := range a.Actions[i].Parameters {
 			if err := a.Actions[i].Parameters[j].IsValid(); err != nil {
 				return err
@@ -150,6 +173,32 @@ func (a Action) IsValid() error {
 	return nil
 }
 
+// IsValidOutputs returns an error if the outputs names or the steps ids of the action are not valid.
+func (a Action) IsValidOutputs() error {
+	for name := range a.Outputs {
+		if !NamePatternRegex.MatchString(name) {
+			return NewErrorFrom(ErrWrongRequest, "invalid output name %q, should match %s", name, NamePattern)
+		}
+	}
+
+	stepIDs := make(map[string]struct{}, len(a.Actions))
+	for i := range a.Actions {
+		id := a.Actions[i].StepID
+		if id == "" {
+			continue
+		}
+		if !NamePatternRegex.MatchString(id) {
+			return NewErrorFrom(ErrWrongRequest, "invalid step id %q, should match %s", id, NamePattern)
+		}
+		if _, ok := stepIDs[id]; ok {
+			return NewErrorFrom(ErrWrongRequest, "step id %q should be unique in job %s", id, a.Name)
+		}
+		stepIDs[id] = struct{}{}
+	}
+
+	return nil
+}
+
 // FlattenRequirements returns all requirements for an action and its children.
 func (a *Action) FlattenRequirements() RequirementList {
 	if !a.Enabled {
@@ -191,6 +240,140 @@ func (a *Action) FlattenRequirements() RequirementList {
 	return rs
 }
 
+// TimeoutDuration returns action's timeout as a duration, zero if no timeout set.
+func (a Action) TimeoutDuration() time.Duration {
+	return time.Duration(a.Timeout) * time.Second
+}
+
+// Retry conditions for a job.
+const (
+	RetryWhenFailure    = "failure"
+	RetryWhenWorkerLost = "worker_lost"
+	RetryWhenSpawnError = "spawn_error"
+)
+
+// maxRetryBackoff is the maximum delay between two attempts of a job.
+const maxRetryBackoff = time.Hour
+
+// ActionRetry is the retry policy of a job.
+type ActionRetry struct {
+	MaxAttempts int64 `json:"max_attempts"`
+	// Backoff is the delay in seconds before the second attempt, it is doubled for each following attempt
+	Backoff int64 `json:"backoff,omitempty"`
+	// When lists the conditions to retry the job, failure by default
+	When []string `json:"when,omitempty"`
+	// ExitCodes restricts the retry on failure to the jobs which step exited with one of these codes
+	ExitCodes []int `json:"exit_codes,omitempty"`
+}
+
+// Value returns driver.Value from action retry.
+func (r ActionRetry) Value() (driver.Value, error) {
+	j, err := json.Marshal(r)
+	return j, WrapError(err, "cannot marshal ActionRetry")
+}
+
+// Scan action retry.
+func (r *ActionRetry) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, r), "cannot unmarshal ActionRetry")
+}
+
+// IsValid returns an error if the retry policy is not valid.
+func (r ActionRetry) IsValid() error {
+	if r.MaxAttempts < 1 {
+		return NewErrorFrom(ErrWrongRequest, "invalid max attempts for retry, should be greater than 0")
+	}
+	if r.Backoff < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid backoff for retry")
+	}
+	for _, w := range r.When {
+		switch w {
+		case RetryWhenFailure, RetryWhenWorkerLost, RetryWhenSpawnError:
+		default:
+			return NewErrorFrom(ErrWrongRequest, "invalid retry condition %q, should be one of %s, %s or %s", w, RetryWhenFailure, RetryWhenWorkerLost, RetryWhenSpawnError)
+		}
+	}
+	return nil
+}
+
+// IsRetriable returns true if a job that ended its given attempt for given condition should be retried.
+// The exit code is only checked for a failure.
+func (r ActionRetry) IsRetriable(attempt int64, when string, exitCode int) bool {
+	if attempt >= r.MaxAttempts {
+		return false
+	}
+
+	whens := r.When
+	if len(whens) == 0 {
+		whens = []string{RetryWhenFailure}
+	}
+	if !IsInArray(when, whens) {
+		return false
+	}
+
+	if when != RetryWhenFailure || len(r.ExitCodes) == 0 {
+		return true
+	}
+	for _, c := range r.ExitCodes {
+		if c == exitCode {
+			return true
+		}
+	}
+	return false
+}
+
+// BackoffDuration returns the delay to wait before the attempt following given one.
+func (r ActionRetry) BackoffDuration(attempt int64) time.Duration {
+	d := time.Duration(r.Backoff) * time.Second
+	for i := int64(1); i < attempt && d > 0 && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	return d
+}
+
+// ActionOutputs are the outputs of a job by name, the values are interpolated with the job variables when the job ends.
+type ActionOutputs map[string]string
+
+// Value returns driver.Value from action outputs.
+func (o ActionOutputs) Value() (driver.Value, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+	j, err := json.Marshal(o)
+	return j, WrapError(err, "cannot marshal ActionOutputs")
+}
+
+// Scan action outputs.
+func (o *ActionOutputs) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, o), "cannot unmarshal ActionOutputs")
+}
+
+// StepOutputVariable returns the name of the variable that contains an output of a step.
+func StepOutputVariable(stepID, name string) string {
+	return "cds.steps." + stepID + ".outputs." + name
+}
+
+// NodeOutputVariable returns the name of the variable that contains an output of a workflow node.
+func NodeOutputVariable(nodeName, name string) string {
+	return "cds.node." + nodeName + ".outputs." + name
+}
+
 // Parameter add given parameter to Action
 func (a *Action) Parameter(p Parameter) *Action {
 	a.Parameters = append(a.Parameters, p)
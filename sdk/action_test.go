@@ -2,6 +2,7 @@ package sdk_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -36,3 +37,26 @@ func TestWorkflowRunTag(t *testing.T) {
 	assert.Equal(t, "hostname1", rs[2].Value)
 	assert.Equal(t, "service2", rs[3].Value)
 }
+
+func TestActionRetry(t *testing.T) {
+	r := sdk.ActionRetry{MaxAttempts: 3, Backoff: 30, ExitCodes: []int{137}}
+
+	assert.True(t, r.IsRetriable(1, sdk.RetryWhenFailure, 137))
+	assert.True(t, r.IsRetriable(2, sdk.RetryWhenFailure, 137))
+	assert.False(t, r.IsRetriable(3, sdk.RetryWhenFailure, 137), "no more attempt")
+	assert.False(t, r.IsRetriable(1, sdk.RetryWhenFailure, 1), "exit code not retriable")
+	assert.False(t, r.IsRetriable(1, sdk.RetryWhenWorkerLost, 0), "failure is the only default condition")
+
+	r.When = []string{sdk.RetryWhenWorkerLost}
+	assert.True(t, r.IsRetriable(1, sdk.RetryWhenWorkerLost, 0))
+	assert.False(t, r.IsRetriable(1, sdk.RetryWhenFailure, 137))
+
+	assert.Equal(t, 30*time.Second, r.BackoffDuration(1))
+	assert.Equal(t, time.Minute, r.BackoffDuration(2))
+	assert.Equal(t, 2*time.Minute, r.BackoffDuration(3))
+	assert.Equal(t, time.Hour, r.BackoffDuration(20))
+
+	assert.NoError(t, r.IsValid())
+	assert.Error(t, sdk.ActionRetry{}.IsValid())
+	assert.Error(t, sdk.ActionRetry{MaxAttempts: 2, When: []string{"always"}}.IsValid())
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/afero"
 )
@@ -22,9 +23,26 @@ type Cache struct {
 	TmpURL          string `json:"tmp_url"`
 	SecretKey       string `json:"secret_key"`
 	IntegrationName string `json:"integration_name"`
+	Size            int64  `json:"size,omitempty"`
 
 	Files            []string `json:"files"`
 	WorkingDirectory string   `json:"working_directory"`
+	// KeyFiles are glob patterns of the files whose content hash is appended to the tag
+	KeyFiles []string `json:"key_files,omitempty"`
+	// RestoreKeys are ordered tag prefixes used to restore a cache when no cache exists for the tag
+	RestoreKeys []string `json:"restore_keys,omitempty"`
+}
+
+// WorkerCache is the index entry of a cache pushed by workers for a project.
+type WorkerCache struct {
+	ID              int64     `json:"id" db:"id"`
+	ProjectID       int64     `json:"project_id" db:"project_id"`
+	IntegrationName string    `json:"integration_name" db:"integration_name"`
+	Tag             string    `json:"tag" db:"tag"`
+	Size            int64     `json:"size" db:"size"`
+	Created         time.Time `json:"created" db:"created"`
+	LastAccess      time.Time `json:"last_access" db:"last_access"`
+	Confirmed       bool      `json:"confirmed" db:"confirmed"`
 }
 
 //GetName returns the name the artifact
@@ -38,6 +38,45 @@ func (c *client) AdminCDSMigrationReset(id int64) error {
 	return err
 }
 
+func (c *client) AdminJobQuotas() ([]sdk.JobQuota, error) {
+	qs := []sdk.JobQuota{}
+	if _, err := c.GetJSON(context.Background(), "/admin/quotas", &qs); err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+func (c *client) AdminJobQuotaSet(q sdk.JobQuota) error {
+	_, err := c.PostJSON(context.Background(), "/admin/quotas", q, nil)
+	return err
+}
+
+func (c *client) AdminJobQuotaDelete(quotaType, target string) error {
+	q := url.Values{}
+	q.Set("type", quotaType)
+	q.Set("target", target)
+	_, err := c.DeleteJSON(context.Background(), "/admin/quotas?"+q.Encode(), nil)
+	return err
+}
+
+func (c *client) ResourceClassList() ([]sdk.ResourceClass, error) {
+	cs := []sdk.ResourceClass{}
+	if _, err := c.GetJSON(context.Background(), "/resourceclass", &cs); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+func (c *client) ResourceClassSet(rc sdk.ResourceClass) error {
+	_, err := c.PostJSON(context.Background(), "/resourceclass", rc, nil)
+	return err
+}
+
+func (c *client) ResourceClassDelete(name string) error {
+	_, err := c.DeleteJSON(context.Background(), "/resourceclass/"+url.PathEscape(name), nil)
+	return err
+}
+
 func (c *client) AdminCDSMigrationList() ([]sdk.Migration, error) {
 	var migrations []sdk.Migration
 	if _, err := c.GetJSON(context.Background(), "/admin/cds/migration", &migrations); err != nil {
@@ -184,3 +223,16 @@ func (c *client) AdminDatabaseRollAllEncryptedEntities() error {
 	}
 	return nil
 }
+
+func (c *client) AdminDatabaseRotationEntities() ([]string, error) {
+	var res []string
+	_, err := c.GetJSON(context.Background(), "/admin/database/rotation", &res)
+	return res, err
+}
+
+func (c *client) AdminDatabaseRotateEntity(e, from string, limit int64) (sdk.DatabaseKeyRotation, error) {
+	var res sdk.DatabaseKeyRotation
+	path := fmt.Sprintf("/admin/database/rotation/%s?limit=%d&from=%s", e, limit, url.QueryEscape(from))
+	_, err := c.PostJSON(context.Background(), path, nil, &res)
+	return res, err
+}
@@ -0,0 +1,21 @@
+package cdsclient
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ProjectLockList(projectKey string) ([]sdk.WorkflowNodeRunLock, error) {
+	ls := []sdk.WorkflowNodeRunLock{}
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/lock", &ls); err != nil {
+		return nil, err
+	}
+	return ls, nil
+}
+
+func (c *client) ProjectLockRelease(projectKey string, lockName string) error {
+	_, _, _, err := c.Request(context.Background(), "DELETE", "/project/"+projectKey+"/lock/"+url.QueryEscape(lockName), nil)
+	return err
+}
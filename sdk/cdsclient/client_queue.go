@@ -38,7 +38,7 @@ func shrinkQueue(queue *sdk.WorkflowQueue, nbJobsToKeep int) time.Time {
 	// we keep 2x this number
 	nbJobsToKeep = nbJobsToKeep * 2
 
-	queue.Sort()
+	// the queue is already sorted by the API to share the workers between the projects
 
 	if len(*queue) > nbJobsToKeep {
 		newQueue := (*queue)[:nbJobsToKeep]
@@ -104,8 +104,10 @@ func (c *client) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJ
 						continue
 					}
 
-					// push the job in the channel
-					if job.Status == sdk.StatusWaiting && job.BookedBy.Name == "" {
+					// push the job in the channel, a job replaced in queue by its retry policy
+					// will be fetched by polling once its backoff delay is over. The job quotas are not
+					// applied on events, they are checked by the API when the job is booked
+					if job.Status == sdk.StatusWaiting && job.BookedBy.Name == "" && !job.Queued.After(time.Now()) {
 						job.Header["SSE"] = "true"
 						jobs <- *job
 					}
@@ -205,6 +207,14 @@ func (c *client) QueueCountWorkflowNodeJobRun(since *time.Time, until *time.Time
 	return countWJobs, err
 }
 
+func (c *client) QueueJobQuotas() ([]sdk.JobQuotaUsage, error) {
+	usage := []sdk.JobQuotaUsage{}
+	if _, err := c.GetJSON(context.Background(), "/queue/workflows/quotas", &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
 func (c *client) QueueTakeJob(ctx context.Context, job sdk.WorkflowNodeJobRun) (*sdk.WorkflowNodeJobRunData, error) {
 	path := fmt.Sprintf("/queue/workflows/%d/take", job.ID)
 	var info sdk.WorkflowNodeJobRunData
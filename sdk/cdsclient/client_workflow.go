@@ -97,6 +97,30 @@ func (c *client) WorkflowRunsDeleteByBranch(projectKey string, workflowName stri
 	return nil
 }
 
+func (c *client) WorkflowPreviewEnvironments(projectKey string, workflowName string) ([]sdk.EnvironmentPreview, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/previews", projectKey, workflowName)
+	var previews []sdk.EnvironmentPreview
+	if _, err := c.GetJSON(context.Background(), url, &previews); err != nil {
+		return nil, err
+	}
+	return previews, nil
+}
+
+func (c *client) WorkflowPreviewEnvironmentsTeardown(projectKey string, workflowName string, branch string, pullRequestID string) error {
+	q := url.Values{}
+	if branch != "" {
+		q.Set("branch", branch)
+	}
+	if pullRequestID != "" {
+		q.Set("pullrequest", pullRequestID)
+	}
+	path := fmt.Sprintf("/project/%s/workflows/%s/previews?%s", projectKey, workflowName, q.Encode())
+	if _, err := c.DeleteJSON(context.Background(), path, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *client) WorkflowRunResync(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/resync", projectKey, workflowName, number)
 	var run sdk.WorkflowRun
@@ -214,6 +238,48 @@ func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, workflowName
 	return err
 }
 
+func (c *client) WorkflowNodeRunArtifactProvenance(projectKey string, workflowName string, artifactID int64) (*sdk.ArtifactProvenance, error) {
+	var p sdk.ArtifactProvenance
+	url := fmt.Sprintf("/project/%s/workflows/%s/artifact/%d/provenance", projectKey, workflowName, artifactID)
+	if _, err := c.GetJSON(context.Background(), url, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (c *client) WorkflowArtifactProvenanceKey(projectKey string, workflowName string) (*sdk.ProjectKey, error) {
+	var k sdk.ProjectKey
+	url := fmt.Sprintf("/project/%s/workflows/%s/artifacts/provenance/key", projectKey, workflowName)
+	if _, err := c.GetJSON(context.Background(), url, &k); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (c *client) WorkflowArtifactRetention(projectKey string, workflowName string) (*sdk.WorkflowArtifactRetentionReport, error) {
+	var report sdk.WorkflowArtifactRetentionReport
+	url := fmt.Sprintf("/project/%s/workflows/%s/artifacts/retention", projectKey, workflowName)
+	if _, err := c.GetJSON(context.Background(), url, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (c *client) WorkflowRunRetention(projectKey string, workflowName string, dryRun bool) (*sdk.WorkflowRunRetentionReport, error) {
+	var report sdk.WorkflowRunRetentionReport
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/retention", projectKey, workflowName)
+	if dryRun {
+		if _, err := c.GetJSON(context.Background(), url, &report); err != nil {
+			return nil, err
+		}
+		return &report, nil
+	}
+	if _, err := c.PostJSON(context.Background(), url, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
 func (c *client) WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/release", projectKey, workflowName, runNumber, nodeRunID)
 	btes, _ := json.Marshal(release)
@@ -313,6 +379,24 @@ func (c *client) WorkflowNodeStop(projectKey string, workflowName string, number
 	return nodeRun, nil
 }
 
+func (c *client) WorkflowNodeRunApprovalGet(projectKey string, workflowName string, number, nodeRunID int64) (*sdk.WorkflowNodeRunApproval, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/approval", projectKey, workflowName, number, nodeRunID)
+	var a sdk.WorkflowNodeRunApproval
+	if _, err := c.GetJSON(context.Background(), url, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (c *client) WorkflowNodeRunApprove(projectKey string, workflowName string, number, nodeRunID int64, decision sdk.WorkflowNodeRunApprovalDecision) (*sdk.WorkflowNodeRunApproval, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/approval", projectKey, workflowName, number, nodeRunID)
+	var a sdk.WorkflowNodeRunApproval
+	if _, err := c.PostJSON(context.Background(), url, &decision, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
 func (c *client) WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
 	store := new(sdk.ArtifactsStore)
 	uri := fmt.Sprintf("/project/%s/storage/%s", projectKey, integrationName)
@@ -332,12 +416,17 @@ func (c *client) workflowCachePushDirectUpload(projectKey, integrationName, ref
 	}
 
 	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s", projectKey, integrationName, ref)
-	_, _, code, err := c.Stream(context.Background(), "POST", uri, tarContent, true, mods...)
+	res, _, code, err := c.Stream(context.Background(), "POST", uri, tarContent, true, mods...)
 	if err != nil {
 		return err
 	}
+	defer res.Close()
 
 	if code >= 400 {
+		body, _ := ioutil.ReadAll(res)
+		if err := sdk.DecodeError(body); err != nil {
+			return err
+		}
 		return fmt.Errorf("HTTP Code %d", code)
 	}
 
@@ -346,7 +435,7 @@ func (c *client) workflowCachePushDirectUpload(projectKey, integrationName, ref
 
 func (c *client) workflowCachePushIndirectUpload(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
 	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s/url", projectKey, integrationName, ref)
-	cacheObj := sdk.Cache{}
+	cacheObj := sdk.Cache{Size: int64(size)}
 	code, err := c.PostJSON(context.Background(), uri, cacheObj, &cacheObj)
 	if err != nil {
 		return err
@@ -356,7 +445,19 @@ func (c *client) workflowCachePushIndirectUpload(projectKey, integrationName, re
 		return fmt.Errorf("HTTP Code %d", code)
 	}
 
-	return c.workflowCachePushIndirectUploadPost(cacheObj.TmpURL, tarContent, size)
+	if err := c.workflowCachePushIndirectUploadPost(cacheObj.TmpURL, tarContent, size); err != nil {
+		return err
+	}
+
+	// confirm the upload, the cache can't be restored before
+	code, err = c.PostJSON(context.Background(), uri+"/callback", nil, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 400 {
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+	return nil
 }
 
 func (c *client) workflowCachePushIndirectUploadPost(url string, tarContent io.Reader, size int) error {
@@ -433,7 +534,7 @@ func (c *client) WorkflowCachePull(projectKey, integrationName, ref string) (io.
 
 	if code >= 400 {
 		if code == 404 {
-			return nil, fmt.Errorf("Cache not found")
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "cache not found")
 		}
 		return nil, fmt.Errorf("HTTP Code %d", code)
 	}
@@ -446,6 +547,19 @@ func (c *client) WorkflowCachePull(projectKey, integrationName, ref string) (io.
 	return bytes.NewBuffer(body), nil
 }
 
+func (c *client) WorkflowCacheResolve(projectKey, integrationName, ref string, restoreKeys []string) (*sdk.WorkerCache, error) {
+	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s/resolve", projectKey, integrationName, ref)
+	if len(restoreKeys) > 0 {
+		uri += "?" + url.Values{"restoreKey": restoreKeys}.Encode()
+	}
+
+	var cache sdk.WorkerCache
+	if _, err := c.GetJSON(context.Background(), uri, &cache); err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
 func (c *client) WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error) {
 	url := fmt.Sprintf("/project/%s/workflow/%s/templateInstance", projectKey, workflowName)
 
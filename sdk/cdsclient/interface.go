@@ -43,9 +43,17 @@ type Admin interface {
 	AdminDatabaseListEncryptedEntities() ([]string, error)
 	AdminDatabaseRollEncryptedEntity(e string) error
 	AdminDatabaseRollAllEncryptedEntities() error
+	AdminDatabaseRotationEntities() ([]string, error)
+	AdminDatabaseRotateEntity(e, from string, limit int64) (sdk.DatabaseKeyRotation, error)
 	AdminCDSMigrationList() ([]sdk.Migration, error)
 	AdminCDSMigrationCancel(id int64) error
 	AdminCDSMigrationReset(id int64) error
+	AdminJobQuotas() ([]sdk.JobQuota, error)
+	AdminJobQuotaSet(q sdk.JobQuota) error
+	AdminJobQuotaDelete(quotaType, target string) error
+	ResourceClassList() ([]sdk.ResourceClass, error)
+	ResourceClassSet(c sdk.ResourceClass) error
+	ResourceClassDelete(name string) error
 	Services() ([]sdk.Service, error)
 	ServicesByName(name string) (*sdk.Service, error)
 	ServiceDelete(name string) error
@@ -216,6 +224,8 @@ type ProjectClient interface {
 	ProjectIntegrationDelete(projectKey string, integrationName string) error
 	ProjectRepositoryManagerList(projectKey string) ([]sdk.ProjectVCSServer, error)
 	ProjectRepositoryManagerDelete(projectKey string, repoManagerName string, force bool) error
+	ProjectLockList(projectKey string) ([]sdk.WorkflowNodeRunLock, error)
+	ProjectLockRelease(projectKey string, lockName string) error
 }
 
 // ProjectKeysClient exposes project keys related functions
@@ -239,6 +249,7 @@ type ProjectVariablesClient interface {
 type QueueClient interface {
 	QueueWorkflowNodeJobRun(status ...string) ([]sdk.WorkflowNodeJobRun, error)
 	QueueCountWorkflowNodeJobRun(since *time.Time, until *time.Time, modelType string, ratioService *int) (sdk.WorkflowNodeJobRunCount, error)
+	QueueJobQuotas() ([]sdk.JobQuotaUsage, error)
 	QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJobRun, errs chan<- error, delay time.Duration, modelType string, ratioService *int) error
 	QueueTakeJob(ctx context.Context, job sdk.WorkflowNodeJobRun) (*sdk.WorkflowNodeJobRunData, error)
 	QueueJobBook(ctx context.Context, id int64) error
@@ -302,6 +313,8 @@ type WorkflowClient interface {
 	WorkflowGroupDelete(projectKey, name, groupName string) error
 	WorkflowRunGet(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowRunsDeleteByBranch(projectKey string, workflowName string, branch string) error
+	WorkflowPreviewEnvironments(projectKey string, workflowName string) ([]sdk.EnvironmentPreview, error)
+	WorkflowPreviewEnvironmentsTeardown(projectKey string, workflowName string, branch string, pullRequestID string) error
 	WorkflowRunResync(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowRunSearch(projectKey string, offset, limit int64, filter ...Filter) ([]sdk.WorkflowRun, error)
 	WorkflowRunList(projectKey string, workflowName string, offset, limit int64) ([]sdk.WorkflowRun, error)
@@ -312,13 +325,20 @@ type WorkflowClient interface {
 	WorkflowRunNumberSet(projectKey string, workflowName string, number int64) error
 	WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowNodeStop(projectKey string, workflowName string, number, fromNodeID int64) (*sdk.WorkflowNodeRun, error)
+	WorkflowNodeRunApprovalGet(projectKey string, workflowName string, number, nodeRunID int64) (*sdk.WorkflowNodeRunApproval, error)
+	WorkflowNodeRunApprove(projectKey string, workflowName string, number, nodeRunID int64, decision sdk.WorkflowNodeRunApprovalDecision) (*sdk.WorkflowNodeRunApproval, error)
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
+	WorkflowNodeRunArtifactProvenance(projectKey string, name string, artifactID int64) (*sdk.ArtifactProvenance, error)
+	WorkflowArtifactProvenanceKey(projectKey string, name string) (*sdk.ProjectKey, error)
+	WorkflowArtifactRetention(projectKey string, name string) (*sdk.WorkflowArtifactRetentionReport, error)
+	WorkflowRunRetention(projectKey string, name string, dryRun bool) (*sdk.WorkflowRunRetentionReport, error)
 	WorkflowNodeRunJobStep(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int) (*sdk.BuildState, error)
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
 	WorkflowAllHooksList() ([]sdk.NodeHook, error)
 	WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error
 	WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error)
+	WorkflowCacheResolve(projectKey, integrationName, ref string, restoreKeys []string) (*sdk.WorkerCache, error)
 	WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error)
 	WorkflowTransformAsCode(projectKey, workflowName string) (*sdk.Operation, error)
 	WorkflowTransformAsCodeFollow(projectKey, workflowName string, ope *sdk.Operation) error
@@ -385,6 +405,7 @@ type WorkerInterface interface {
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error)
 	WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error
 	WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error)
+	WorkflowCacheResolve(projectKey, integrationName, ref string, restoreKeys []string) (*sdk.WorkerCache, error)
 	WorkflowRunSearch(projectKey string, offset, limit int64, filter ...Filter) ([]sdk.WorkflowRun, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
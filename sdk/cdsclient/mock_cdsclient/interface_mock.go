@@ -342,6 +342,36 @@ func (mr *MockAdminMockRecorder) AdminDatabaseRollAllEncryptedEntities() *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseRollAllEncryptedEntities", reflect.TypeOf((*MockAdmin)(nil).AdminDatabaseRollAllEncryptedEntities))
 }
 
+// AdminDatabaseRotationEntities mocks base method
+func (m *MockAdmin) AdminDatabaseRotationEntities() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDatabaseRotationEntities")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDatabaseRotationEntities indicates an expected call of AdminDatabaseRotationEntities
+func (mr *MockAdminMockRecorder) AdminDatabaseRotationEntities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseRotationEntities", reflect.TypeOf((*MockAdmin)(nil).AdminDatabaseRotationEntities))
+}
+
+// AdminDatabaseRotateEntity mocks base method
+func (m *MockAdmin) AdminDatabaseRotateEntity(e, from string, limit int64) (sdk.DatabaseKeyRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDatabaseRotateEntity", e, from, limit)
+	ret0, _ := ret[0].(sdk.DatabaseKeyRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDatabaseRotateEntity indicates an expected call of AdminDatabaseRotateEntity
+func (mr *MockAdminMockRecorder) AdminDatabaseRotateEntity(e, from, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseRotateEntity", reflect.TypeOf((*MockAdmin)(nil).AdminDatabaseRotateEntity), e, from, limit)
+}
+
 // AdminCDSMigrationList mocks base method
 func (m *MockAdmin) AdminCDSMigrationList() ([]sdk.Migration, error) {
 	m.ctrl.T.Helper()
@@ -385,6 +415,92 @@ func (mr *MockAdminMockRecorder) AdminCDSMigrationReset(id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminCDSMigrationReset", reflect.TypeOf((*MockAdmin)(nil).AdminCDSMigrationReset), id)
 }
 
+// AdminJobQuotas mocks base method
+func (m *MockAdmin) AdminJobQuotas() ([]sdk.JobQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminJobQuotas")
+	ret0, _ := ret[0].([]sdk.JobQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminJobQuotas indicates an expected call of AdminJobQuotas
+func (mr *MockAdminMockRecorder) AdminJobQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminJobQuotas", reflect.TypeOf((*MockAdmin)(nil).AdminJobQuotas))
+}
+
+// AdminJobQuotaSet mocks base method
+func (m *MockAdmin) AdminJobQuotaSet(q sdk.JobQuota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminJobQuotaSet", q)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminJobQuotaSet indicates an expected call of AdminJobQuotaSet
+func (mr *MockAdminMockRecorder) AdminJobQuotaSet(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminJobQuotaSet", reflect.TypeOf((*MockAdmin)(nil).AdminJobQuotaSet), q)
+}
+
+// AdminJobQuotaDelete mocks base method
+func (m *MockAdmin) AdminJobQuotaDelete(quotaType, target string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminJobQuotaDelete", quotaType, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminJobQuotaDelete indicates an expected call of AdminJobQuotaDelete
+func (mr *MockAdminMockRecorder) AdminJobQuotaDelete(quotaType, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminJobQuotaDelete", reflect.TypeOf((*MockAdmin)(nil).AdminJobQuotaDelete), quotaType, target)
+}
+
+// ResourceClassList mocks base method
+func (m *MockAdmin) ResourceClassList() ([]sdk.ResourceClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceClassList")
+	ret0, _ := ret[0].([]sdk.ResourceClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResourceClassList indicates an expected call of ResourceClassList
+func (mr *MockAdminMockRecorder) ResourceClassList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceClassList", reflect.TypeOf((*MockAdmin)(nil).ResourceClassList))
+}
+
+// ResourceClassSet mocks base method
+func (m *MockAdmin) ResourceClassSet(c sdk.ResourceClass) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceClassSet", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResourceClassSet indicates an expected call of ResourceClassSet
+func (mr *MockAdminMockRecorder) ResourceClassSet(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceClassSet", reflect.TypeOf((*MockAdmin)(nil).ResourceClassSet), c)
+}
+
+// ResourceClassDelete mocks base method
+func (m *MockAdmin) ResourceClassDelete(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceClassDelete", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResourceClassDelete indicates an expected call of ResourceClassDelete
+func (mr *MockAdminMockRecorder) ResourceClassDelete(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceClassDelete", reflect.TypeOf((*MockAdmin)(nil).ResourceClassDelete), name)
+}
+
 // Services mocks base method
 func (m *MockAdmin) Services() ([]sdk.Service, error) {
 	m.ctrl.T.Helper()
@@ -2677,6 +2793,35 @@ func (mr *MockProjectClientMockRecorder) ProjectRepositoryManagerDelete(projectK
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectRepositoryManagerDelete", reflect.TypeOf((*MockProjectClient)(nil).ProjectRepositoryManagerDelete), projectKey, repoManagerName, force)
 }
 
+// ProjectLockList mocks base method
+func (m *MockProjectClient) ProjectLockList(projectKey string) ([]sdk.WorkflowNodeRunLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectLockList", projectKey)
+	ret0, _ := ret[0].([]sdk.WorkflowNodeRunLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectLockList indicates an expected call of ProjectLockList
+func (mr *MockProjectClientMockRecorder) ProjectLockList(projectKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectLockList", reflect.TypeOf((*MockProjectClient)(nil).ProjectLockList), projectKey)
+}
+
+// ProjectLockRelease mocks base method
+func (m *MockProjectClient) ProjectLockRelease(projectKey, lockName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectLockRelease", projectKey, lockName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectLockRelease indicates an expected call of ProjectLockRelease
+func (mr *MockProjectClientMockRecorder) ProjectLockRelease(projectKey, lockName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectLockRelease", reflect.TypeOf((*MockProjectClient)(nil).ProjectLockRelease), projectKey, lockName)
+}
+
 // MockProjectKeysClient is a mock of ProjectKeysClient interface
 type MockProjectKeysClient struct {
 	ctrl     *gomock.Controller
@@ -2910,6 +3055,21 @@ func (mr *MockQueueClientMockRecorder) QueueCountWorkflowNodeJobRun(since, until
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueCountWorkflowNodeJobRun", reflect.TypeOf((*MockQueueClient)(nil).QueueCountWorkflowNodeJobRun), since, until, modelType, ratioService)
 }
 
+// QueueJobQuotas mocks base method
+func (m *MockQueueClient) QueueJobQuotas() ([]sdk.JobQuotaUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobQuotas")
+	ret0, _ := ret[0].([]sdk.JobQuotaUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueJobQuotas indicates an expected call of QueueJobQuotas
+func (mr *MockQueueClientMockRecorder) QueueJobQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobQuotas", reflect.TypeOf((*MockQueueClient)(nil).QueueJobQuotas))
+}
+
 // QueuePolling mocks base method
 func (m *MockQueueClient) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJobRun, errs chan<- error, delay time.Duration, modelType string, ratioService *int) error {
 	m.ctrl.T.Helper()
@@ -3691,6 +3851,35 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowRunsDeleteByBranch(projectKey,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunsDeleteByBranch", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowRunsDeleteByBranch), projectKey, workflowName, branch)
 }
 
+// WorkflowPreviewEnvironments mocks base method
+func (m *MockWorkflowClient) WorkflowPreviewEnvironments(projectKey, workflowName string) ([]sdk.EnvironmentPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowPreviewEnvironments", projectKey, workflowName)
+	ret0, _ := ret[0].([]sdk.EnvironmentPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowPreviewEnvironments indicates an expected call of WorkflowPreviewEnvironments
+func (mr *MockWorkflowClientMockRecorder) WorkflowPreviewEnvironments(projectKey, workflowName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowPreviewEnvironments", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowPreviewEnvironments), projectKey, workflowName)
+}
+
+// WorkflowPreviewEnvironmentsTeardown mocks base method
+func (m *MockWorkflowClient) WorkflowPreviewEnvironmentsTeardown(projectKey, workflowName, branch, pullRequestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowPreviewEnvironmentsTeardown", projectKey, workflowName, branch, pullRequestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WorkflowPreviewEnvironmentsTeardown indicates an expected call of WorkflowPreviewEnvironmentsTeardown
+func (mr *MockWorkflowClientMockRecorder) WorkflowPreviewEnvironmentsTeardown(projectKey, workflowName, branch, pullRequestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowPreviewEnvironmentsTeardown", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowPreviewEnvironmentsTeardown), projectKey, workflowName, branch, pullRequestID)
+}
+
 // WorkflowRunResync mocks base method
 func (m *MockWorkflowClient) WorkflowRunResync(projectKey, workflowName string, number int64) (*sdk.WorkflowRun, error) {
 	m.ctrl.T.Helper()
@@ -3845,6 +4034,36 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowNodeStop(projectKey, workflowN
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeStop", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeStop), projectKey, workflowName, number, fromNodeID)
 }
 
+// WorkflowNodeRunApprovalGet mocks base method
+func (m *MockWorkflowClient) WorkflowNodeRunApprovalGet(projectKey, workflowName string, number, nodeRunID int64) (*sdk.WorkflowNodeRunApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunApprovalGet", projectKey, workflowName, number, nodeRunID)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunApprovalGet indicates an expected call of WorkflowNodeRunApprovalGet
+func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunApprovalGet(projectKey, workflowName, number, nodeRunID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunApprovalGet", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunApprovalGet), projectKey, workflowName, number, nodeRunID)
+}
+
+// WorkflowNodeRunApprove mocks base method
+func (m *MockWorkflowClient) WorkflowNodeRunApprove(projectKey, workflowName string, number, nodeRunID int64, decision sdk.WorkflowNodeRunApprovalDecision) (*sdk.WorkflowNodeRunApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunApprove", projectKey, workflowName, number, nodeRunID, decision)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunApprove indicates an expected call of WorkflowNodeRunApprove
+func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunApprove(projectKey, workflowName, number, nodeRunID, decision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunApprove", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunApprove), projectKey, workflowName, number, nodeRunID, decision)
+}
+
 // WorkflowNodeRun mocks base method
 func (m *MockWorkflowClient) WorkflowNodeRun(projectKey, name string, number, nodeRunID int64) (*sdk.WorkflowNodeRun, error) {
 	m.ctrl.T.Helper()
@@ -3874,6 +4093,66 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunArtifactDownload(projec
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunArtifactDownload", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunArtifactDownload), projectKey, name, a, w)
 }
 
+// WorkflowNodeRunArtifactProvenance mocks base method
+func (m *MockWorkflowClient) WorkflowNodeRunArtifactProvenance(projectKey, name string, artifactID int64) (*sdk.ArtifactProvenance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunArtifactProvenance", projectKey, name, artifactID)
+	ret0, _ := ret[0].(*sdk.ArtifactProvenance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunArtifactProvenance indicates an expected call of WorkflowNodeRunArtifactProvenance
+func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunArtifactProvenance(projectKey, name, artifactID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunArtifactProvenance", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunArtifactProvenance), projectKey, name, artifactID)
+}
+
+// WorkflowArtifactProvenanceKey mocks base method
+func (m *MockWorkflowClient) WorkflowArtifactProvenanceKey(projectKey, name string) (*sdk.ProjectKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowArtifactProvenanceKey", projectKey, name)
+	ret0, _ := ret[0].(*sdk.ProjectKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowArtifactProvenanceKey indicates an expected call of WorkflowArtifactProvenanceKey
+func (mr *MockWorkflowClientMockRecorder) WorkflowArtifactProvenanceKey(projectKey, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowArtifactProvenanceKey", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowArtifactProvenanceKey), projectKey, name)
+}
+
+// WorkflowArtifactRetention mocks base method
+func (m *MockWorkflowClient) WorkflowArtifactRetention(projectKey, name string) (*sdk.WorkflowArtifactRetentionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowArtifactRetention", projectKey, name)
+	ret0, _ := ret[0].(*sdk.WorkflowArtifactRetentionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowArtifactRetention indicates an expected call of WorkflowArtifactRetention
+func (mr *MockWorkflowClientMockRecorder) WorkflowArtifactRetention(projectKey, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowArtifactRetention", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowArtifactRetention), projectKey, name)
+}
+
+// WorkflowRunRetention mocks base method
+func (m *MockWorkflowClient) WorkflowRunRetention(projectKey, name string, dryRun bool) (*sdk.WorkflowRunRetentionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowRunRetention", projectKey, name, dryRun)
+	ret0, _ := ret[0].(*sdk.WorkflowRunRetentionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowRunRetention indicates an expected call of WorkflowRunRetention
+func (mr *MockWorkflowClientMockRecorder) WorkflowRunRetention(projectKey, name, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunRetention", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowRunRetention), projectKey, name, dryRun)
+}
+
 // WorkflowNodeRunJobStep mocks base method
 func (m *MockWorkflowClient) WorkflowNodeRunJobStep(projectKey, workflowName string, number, nodeRunID, job int64, step int) (*sdk.BuildState, error) {
 	m.ctrl.T.Helper()
@@ -3947,6 +4226,21 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowCachePull(projectKey, integrat
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePull", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowCachePull), projectKey, integrationName, ref)
 }
 
+// WorkflowCacheResolve mocks base method
+func (m *MockWorkflowClient) WorkflowCacheResolve(projectKey, integrationName, ref string, restoreKeys []string) (*sdk.WorkerCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowCacheResolve", projectKey, integrationName, ref, restoreKeys)
+	ret0, _ := ret[0].(*sdk.WorkerCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowCacheResolve indicates an expected call of WorkflowCacheResolve
+func (mr *MockWorkflowClientMockRecorder) WorkflowCacheResolve(projectKey, integrationName, ref, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCacheResolve", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowCacheResolve), projectKey, integrationName, ref, restoreKeys)
+}
+
 // WorkflowTemplateInstanceGet mocks base method
 func (m *MockWorkflowClient) WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error) {
 	m.ctrl.T.Helper()
@@ -4821,6 +5115,36 @@ func (mr *MockInterfaceMockRecorder) AdminDatabaseRollAllEncryptedEntities() *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseRollAllEncryptedEntities", reflect.TypeOf((*MockInterface)(nil).AdminDatabaseRollAllEncryptedEntities))
 }
 
+// AdminDatabaseRotationEntities mocks base method
+func (m *MockInterface) AdminDatabaseRotationEntities() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDatabaseRotationEntities")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDatabaseRotationEntities indicates an expected call of AdminDatabaseRotationEntities
+func (mr *MockInterfaceMockRecorder) AdminDatabaseRotationEntities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseRotationEntities", reflect.TypeOf((*MockInterface)(nil).AdminDatabaseRotationEntities))
+}
+
+// AdminDatabaseRotateEntity mocks base method
+func (m *MockInterface) AdminDatabaseRotateEntity(e, from string, limit int64) (sdk.DatabaseKeyRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDatabaseRotateEntity", e, from, limit)
+	ret0, _ := ret[0].(sdk.DatabaseKeyRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDatabaseRotateEntity indicates an expected call of AdminDatabaseRotateEntity
+func (mr *MockInterfaceMockRecorder) AdminDatabaseRotateEntity(e, from, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseRotateEntity", reflect.TypeOf((*MockInterface)(nil).AdminDatabaseRotateEntity), e, from, limit)
+}
+
 // AdminCDSMigrationList mocks base method
 func (m *MockInterface) AdminCDSMigrationList() ([]sdk.Migration, error) {
 	m.ctrl.T.Helper()
@@ -4864,6 +5188,92 @@ func (mr *MockInterfaceMockRecorder) AdminCDSMigrationReset(id interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminCDSMigrationReset", reflect.TypeOf((*MockInterface)(nil).AdminCDSMigrationReset), id)
 }
 
+// AdminJobQuotas mocks base method
+func (m *MockInterface) AdminJobQuotas() ([]sdk.JobQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminJobQuotas")
+	ret0, _ := ret[0].([]sdk.JobQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminJobQuotas indicates an expected call of AdminJobQuotas
+func (mr *MockInterfaceMockRecorder) AdminJobQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminJobQuotas", reflect.TypeOf((*MockInterface)(nil).AdminJobQuotas))
+}
+
+// AdminJobQuotaSet mocks base method
+func (m *MockInterface) AdminJobQuotaSet(q sdk.JobQuota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminJobQuotaSet", q)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminJobQuotaSet indicates an expected call of AdminJobQuotaSet
+func (mr *MockInterfaceMockRecorder) AdminJobQuotaSet(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminJobQuotaSet", reflect.TypeOf((*MockInterface)(nil).AdminJobQuotaSet), q)
+}
+
+// AdminJobQuotaDelete mocks base method
+func (m *MockInterface) AdminJobQuotaDelete(quotaType, target string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminJobQuotaDelete", quotaType, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminJobQuotaDelete indicates an expected call of AdminJobQuotaDelete
+func (mr *MockInterfaceMockRecorder) AdminJobQuotaDelete(quotaType, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminJobQuotaDelete", reflect.TypeOf((*MockInterface)(nil).AdminJobQuotaDelete), quotaType, target)
+}
+
+// ResourceClassList mocks base method
+func (m *MockInterface) ResourceClassList() ([]sdk.ResourceClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceClassList")
+	ret0, _ := ret[0].([]sdk.ResourceClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResourceClassList indicates an expected call of ResourceClassList
+func (mr *MockInterfaceMockRecorder) ResourceClassList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceClassList", reflect.TypeOf((*MockInterface)(nil).ResourceClassList))
+}
+
+// ResourceClassSet mocks base method
+func (m *MockInterface) ResourceClassSet(c sdk.ResourceClass) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceClassSet", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResourceClassSet indicates an expected call of ResourceClassSet
+func (mr *MockInterfaceMockRecorder) ResourceClassSet(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceClassSet", reflect.TypeOf((*MockInterface)(nil).ResourceClassSet), c)
+}
+
+// ResourceClassDelete mocks base method
+func (m *MockInterface) ResourceClassDelete(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceClassDelete", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResourceClassDelete indicates an expected call of ResourceClassDelete
+func (mr *MockInterfaceMockRecorder) ResourceClassDelete(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceClassDelete", reflect.TypeOf((*MockInterface)(nil).ResourceClassDelete), name)
+}
+
 // Services mocks base method
 func (m *MockInterface) Services() ([]sdk.Service, error) {
 	m.ctrl.T.Helper()
@@ -6562,6 +6972,35 @@ func (mr *MockInterfaceMockRecorder) ProjectRepositoryManagerDelete(projectKey,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectRepositoryManagerDelete", reflect.TypeOf((*MockInterface)(nil).ProjectRepositoryManagerDelete), projectKey, repoManagerName, force)
 }
 
+// ProjectLockList mocks base method
+func (m *MockInterface) ProjectLockList(projectKey string) ([]sdk.WorkflowNodeRunLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectLockList", projectKey)
+	ret0, _ := ret[0].([]sdk.WorkflowNodeRunLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectLockList indicates an expected call of ProjectLockList
+func (mr *MockInterfaceMockRecorder) ProjectLockList(projectKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectLockList", reflect.TypeOf((*MockInterface)(nil).ProjectLockList), projectKey)
+}
+
+// ProjectLockRelease mocks base method
+func (m *MockInterface) ProjectLockRelease(projectKey, lockName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectLockRelease", projectKey, lockName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectLockRelease indicates an expected call of ProjectLockRelease
+func (mr *MockInterfaceMockRecorder) ProjectLockRelease(projectKey, lockName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectLockRelease", reflect.TypeOf((*MockInterface)(nil).ProjectLockRelease), projectKey, lockName)
+}
+
 // QueueWorkflowNodeJobRun mocks base method
 func (m *MockInterface) QueueWorkflowNodeJobRun(status ...string) ([]sdk.WorkflowNodeJobRun, error) {
 	m.ctrl.T.Helper()
@@ -6596,6 +7035,21 @@ func (mr *MockInterfaceMockRecorder) QueueCountWorkflowNodeJobRun(since, until,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueCountWorkflowNodeJobRun", reflect.TypeOf((*MockInterface)(nil).QueueCountWorkflowNodeJobRun), since, until, modelType, ratioService)
 }
 
+// QueueJobQuotas mocks base method
+func (m *MockInterface) QueueJobQuotas() ([]sdk.JobQuotaUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobQuotas")
+	ret0, _ := ret[0].([]sdk.JobQuotaUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueJobQuotas indicates an expected call of QueueJobQuotas
+func (mr *MockInterfaceMockRecorder) QueueJobQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobQuotas", reflect.TypeOf((*MockInterface)(nil).QueueJobQuotas))
+}
+
 // QueuePolling mocks base method
 func (m *MockInterface) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJobRun, errs chan<- error, delay time.Duration, modelType string, ratioService *int) error {
 	m.ctrl.T.Helper()
@@ -7328,6 +7782,35 @@ func (mr *MockInterfaceMockRecorder) WorkflowRunsDeleteByBranch(projectKey, work
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunsDeleteByBranch", reflect.TypeOf((*MockInterface)(nil).WorkflowRunsDeleteByBranch), projectKey, workflowName, branch)
 }
 
+// WorkflowPreviewEnvironments mocks base method
+func (m *MockInterface) WorkflowPreviewEnvironments(projectKey, workflowName string) ([]sdk.EnvironmentPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowPreviewEnvironments", projectKey, workflowName)
+	ret0, _ := ret[0].([]sdk.EnvironmentPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowPreviewEnvironments indicates an expected call of WorkflowPreviewEnvironments
+func (mr *MockInterfaceMockRecorder) WorkflowPreviewEnvironments(projectKey, workflowName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowPreviewEnvironments", reflect.TypeOf((*MockInterface)(nil).WorkflowPreviewEnvironments), projectKey, workflowName)
+}
+
+// WorkflowPreviewEnvironmentsTeardown mocks base method
+func (m *MockInterface) WorkflowPreviewEnvironmentsTeardown(projectKey, workflowName, branch, pullRequestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowPreviewEnvironmentsTeardown", projectKey, workflowName, branch, pullRequestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WorkflowPreviewEnvironmentsTeardown indicates an expected call of WorkflowPreviewEnvironmentsTeardown
+func (mr *MockInterfaceMockRecorder) WorkflowPreviewEnvironmentsTeardown(projectKey, workflowName, branch, pullRequestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowPreviewEnvironmentsTeardown", reflect.TypeOf((*MockInterface)(nil).WorkflowPreviewEnvironmentsTeardown), projectKey, workflowName, branch, pullRequestID)
+}
+
 // WorkflowRunResync mocks base method
 func (m *MockInterface) WorkflowRunResync(projectKey, workflowName string, number int64) (*sdk.WorkflowRun, error) {
 	m.ctrl.T.Helper()
@@ -7482,6 +7965,36 @@ func (mr *MockInterfaceMockRecorder) WorkflowNodeStop(projectKey, workflowName,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeStop", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeStop), projectKey, workflowName, number, fromNodeID)
 }
 
+// WorkflowNodeRunApprovalGet mocks base method
+func (m *MockInterface) WorkflowNodeRunApprovalGet(projectKey, workflowName string, number, nodeRunID int64) (*sdk.WorkflowNodeRunApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunApprovalGet", projectKey, workflowName, number, nodeRunID)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunApprovalGet indicates an expected call of WorkflowNodeRunApprovalGet
+func (mr *MockInterfaceMockRecorder) WorkflowNodeRunApprovalGet(projectKey, workflowName, number, nodeRunID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunApprovalGet", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunApprovalGet), projectKey, workflowName, number, nodeRunID)
+}
+
+// WorkflowNodeRunApprove mocks base method
+func (m *MockInterface) WorkflowNodeRunApprove(projectKey, workflowName string, number, nodeRunID int64, decision sdk.WorkflowNodeRunApprovalDecision) (*sdk.WorkflowNodeRunApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunApprove", projectKey, workflowName, number, nodeRunID, decision)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunApprove indicates an expected call of WorkflowNodeRunApprove
+func (mr *MockInterfaceMockRecorder) WorkflowNodeRunApprove(projectKey, workflowName, number, nodeRunID, decision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunApprove", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunApprove), projectKey, workflowName, number, nodeRunID, decision)
+}
+
 // WorkflowNodeRun mocks base method
 func (m *MockInterface) WorkflowNodeRun(projectKey, name string, number, nodeRunID int64) (*sdk.WorkflowNodeRun, error) {
 	m.ctrl.T.Helper()
@@ -7511,6 +8024,66 @@ func (mr *MockInterfaceMockRecorder) WorkflowNodeRunArtifactDownload(projectKey,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunArtifactDownload", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunArtifactDownload), projectKey, name, a, w)
 }
 
+// WorkflowNodeRunArtifactProvenance mocks base method
+func (m *MockInterface) WorkflowNodeRunArtifactProvenance(projectKey, name string, artifactID int64) (*sdk.ArtifactProvenance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunArtifactProvenance", projectKey, name, artifactID)
+	ret0, _ := ret[0].(*sdk.ArtifactProvenance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunArtifactProvenance indicates an expected call of WorkflowNodeRunArtifactProvenance
+func (mr *MockInterfaceMockRecorder) WorkflowNodeRunArtifactProvenance(projectKey, name, artifactID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunArtifactProvenance", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunArtifactProvenance), projectKey, name, artifactID)
+}
+
+// WorkflowArtifactProvenanceKey mocks base method
+func (m *MockInterface) WorkflowArtifactProvenanceKey(projectKey, name string) (*sdk.ProjectKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowArtifactProvenanceKey", projectKey, name)
+	ret0, _ := ret[0].(*sdk.ProjectKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowArtifactProvenanceKey indicates an expected call of WorkflowArtifactProvenanceKey
+func (mr *MockInterfaceMockRecorder) WorkflowArtifactProvenanceKey(projectKey, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowArtifactProvenanceKey", reflect.TypeOf((*MockInterface)(nil).WorkflowArtifactProvenanceKey), projectKey, name)
+}
+
+// WorkflowArtifactRetention mocks base method
+func (m *MockInterface) WorkflowArtifactRetention(projectKey, name string) (*sdk.WorkflowArtifactRetentionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowArtifactRetention", projectKey, name)
+	ret0, _ := ret[0].(*sdk.WorkflowArtifactRetentionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowArtifactRetention indicates an expected call of WorkflowArtifactRetention
+func (mr *MockInterfaceMockRecorder) WorkflowArtifactRetention(projectKey, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowArtifactRetention", reflect.TypeOf((*MockInterface)(nil).WorkflowArtifactRetention), projectKey, name)
+}
+
+// WorkflowRunRetention mocks base method
+func (m *MockInterface) WorkflowRunRetention(projectKey, name string, dryRun bool) (*sdk.WorkflowRunRetentionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowRunRetention", projectKey, name, dryRun)
+	ret0, _ := ret[0].(*sdk.WorkflowRunRetentionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowRunRetention indicates an expected call of WorkflowRunRetention
+func (mr *MockInterfaceMockRecorder) WorkflowRunRetention(projectKey, name, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunRetention", reflect.TypeOf((*MockInterface)(nil).WorkflowRunRetention), projectKey, name, dryRun)
+}
+
 // WorkflowNodeRunJobStep mocks base method
 func (m *MockInterface) WorkflowNodeRunJobStep(projectKey, workflowName string, number, nodeRunID, job int64, step int) (*sdk.BuildState, error) {
 	m.ctrl.T.Helper()
@@ -7584,6 +8157,21 @@ func (mr *MockInterfaceMockRecorder) WorkflowCachePull(projectKey, integrationNa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePull", reflect.TypeOf((*MockInterface)(nil).WorkflowCachePull), projectKey, integrationName, ref)
 }
 
+// WorkflowCacheResolve mocks base method
+func (m *MockInterface) WorkflowCacheResolve(projectKey, integrationName, ref string, restoreKeys []string) (*sdk.WorkerCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowCacheResolve", projectKey, integrationName, ref, restoreKeys)
+	ret0, _ := ret[0].(*sdk.WorkerCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowCacheResolve indicates an expected call of WorkflowCacheResolve
+func (mr *MockInterfaceMockRecorder) WorkflowCacheResolve(projectKey, integrationName, ref, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCacheResolve", reflect.TypeOf((*MockInterface)(nil).WorkflowCacheResolve), projectKey, integrationName, ref, restoreKeys)
+}
+
 // WorkflowTemplateInstanceGet mocks base method
 func (m *MockInterface) WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error) {
 	m.ctrl.T.Helper()
@@ -8084,6 +8672,21 @@ func (mr *MockWorkerInterfaceMockRecorder) QueueCountWorkflowNodeJobRun(since, u
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueCountWorkflowNodeJobRun", reflect.TypeOf((*MockWorkerInterface)(nil).QueueCountWorkflowNodeJobRun), since, until, modelType, ratioService)
 }
 
+// QueueJobQuotas mocks base method
+func (m *MockWorkerInterface) QueueJobQuotas() ([]sdk.JobQuotaUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobQuotas")
+	ret0, _ := ret[0].([]sdk.JobQuotaUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueJobQuotas indicates an expected call of QueueJobQuotas
+func (mr *MockWorkerInterfaceMockRecorder) QueueJobQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobQuotas", reflect.TypeOf((*MockWorkerInterface)(nil).QueueJobQuotas))
+}
+
 // QueuePolling mocks base method
 func (m *MockWorkerInterface) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJobRun, errs chan<- error, delay time.Duration, modelType string, ratioService *int) error {
 	m.ctrl.T.Helper()
@@ -8563,6 +9166,21 @@ func (mr *MockWorkerInterfaceMockRecorder) WorkflowCachePull(projectKey, integra
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePull", reflect.TypeOf((*MockWorkerInterface)(nil).WorkflowCachePull), projectKey, integrationName, ref)
 }
 
+// WorkflowCacheResolve mocks base method
+func (m *MockWorkerInterface) WorkflowCacheResolve(projectKey, integrationName, ref string, restoreKeys []string) (*sdk.WorkerCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowCacheResolve", projectKey, integrationName, ref, restoreKeys)
+	ret0, _ := ret[0].(*sdk.WorkerCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowCacheResolve indicates an expected call of WorkflowCacheResolve
+func (mr *MockWorkerInterfaceMockRecorder) WorkflowCacheResolve(projectKey, integrationName, ref, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCacheResolve", reflect.TypeOf((*MockWorkerInterface)(nil).WorkflowCacheResolve), projectKey, integrationName, ref, restoreKeys)
+}
+
 // WorkflowRunSearch mocks base method
 func (m *MockWorkerInterface) WorkflowRunSearch(projectKey string, offset, limit int64, filter ...cdsclient.Filter) ([]sdk.WorkflowRun, error) {
 	m.ctrl.T.Helper()
@@ -18,3 +18,12 @@ type CanonicalFormUsage struct {
 }
 
 type CanonicalFormUsageResume map[string][]CanonicalFormUsage
+
+// DatabaseKeyRotation is the progress of the rotation of the encryption and signature keys for a database entity.
+type DatabaseKeyRotation struct {
+	Entity string `json:"entity" cli:"entity,key"`
+	Total  int64  `json:"total" cli:"total"`
+	Done   int64  `json:"done" cli:"done"`
+	// Next is the last rolled primary key, it's empty when all the entity was rolled
+	Next string `json:"next,omitempty" cli:"next"`
+}
@@ -15,6 +15,7 @@ type Environment struct {
 	Keys           []EnvironmentKey `json:"keys"`
 	Usage          *Usage           `json:"usage,omitempty"`
 	FromRepository string           `json:"from_repository,omitempty"`
+	Lock           string           `json:"lock,omitempty" yaml:"lock,omitempty"`
 }
 
 // EnvironmentVariableAudit represents an audit on an environment variable
@@ -102,6 +102,19 @@ type EventRunWorkflowOutgoingHook struct {
 	WorkflowRunNumber *int64 `json:"workflow_run_number,omitempty"`
 }
 
+// EventRunWorkflowNodeApproval contains event data for the approval gate of a workflow node run
+type EventRunWorkflowNodeApproval struct {
+	ID           int64  `json:"id"`
+	NodeRunID    int64  `json:"node_run_id"`
+	NodeName     string `json:"node_name"`
+	Status       string `json:"status"`
+	MinApprovals int64  `json:"min_approvals"`
+	Approvals    int64  `json:"approvals"`
+	Username     string `json:"username,omitempty"`
+	Approved     bool   `json:"approved,omitempty"`
+	Comment      string `json:"comment,omitempty"`
+}
+
 // EventRunWorkflowJob contains event data for a workflow job node run
 type EventRunWorkflowJob struct {
 	ID     int64  `json:"id,omitempty"`
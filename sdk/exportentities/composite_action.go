@@ -0,0 +1,55 @@
+package exportentities
+
+import (
+	"path"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// CompositeActionsDirectory is the directory of the .cds folder that contains the composite actions of a repository.
+const CompositeActionsDirectory = "actions"
+
+// CompositeAction is a list of steps stored as code in the repository, it can be used as a step by a pipeline
+// or another composite action with a relative path (ie. ./actions/build.yml).
+type CompositeAction struct {
+	Version     string                           `json:"version,omitempty" yaml:"version,omitempty"`
+	Name        string                           `json:"name,omitempty" yaml:"name,omitempty"`
+	Description string                           `json:"description,omitempty" yaml:"description,omitempty"`
+	Inputs      map[string]CompositeActionInput  `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	Outputs     map[string]CompositeActionOutput `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Steps       []Step                           `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
+
+// CompositeActionInput is a typed input of a composite action, it is required if it has no default value.
+type CompositeActionInput struct {
+	Type        string  `json:"type,omitempty" yaml:"type,omitempty"`
+	Default     *string `json:"default,omitempty" yaml:"default,omitempty"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// CompositeActionOutput is exported as a build variable when the steps of the composite action are done.
+type CompositeActionOutput struct {
+	Value       string `json:"value,omitempty" yaml:"value,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Actions returns the steps of the composite action as actions.
+func (a CompositeAction) Actions() ([]sdk.Action, error) {
+	return computeSteps(a.Steps)
+}
+
+// IsCompositeActionPath returns true if given custom step name is a relative path to a composite action.
+func IsCompositeActionPath(name string) bool {
+	return strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../")
+}
+
+// CompositeActionPath returns the path of a composite action relative to the .cds folder, from a step
+// reference and the directory of the file that contains the step. It returns false if the path is outside the .cds folder.
+func CompositeActionPath(dir, ref string) (string, bool) {
+	p := path.Join(dir, ref)
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", false
+	}
+	return p, true
+}
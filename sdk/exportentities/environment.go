@@ -9,6 +9,7 @@ type Environment struct {
 	Name   string                   `json:"name" yaml:"name" jsonschema_description:"The name of the environment."`
 	Values map[string]VariableValue `json:"values,omitempty" yaml:"values,omitempty"`
 	Keys   map[string]KeyValue      `json:"keys,omitempty" yaml:"keys,omitempty"`
+	Lock   string                   `json:"lock,omitempty" yaml:"lock,omitempty" jsonschema_description:"The name of the lock shared by all the pipelines that use the environment.\nhttps://ovh.github.io/cds/docs/concepts/workflow/lock."`
 }
 
 //NewEnvironment returns an Environment from an sdk.Environment pointer
@@ -16,6 +17,7 @@ func NewEnvironment(e sdk.Environment, keys []EncryptedKey) Environment {
 	env := Environment{
 		Name:   e.Name,
 		Values: make(map[string]VariableValue, len(e.Variables)),
+		Lock:   e.Lock,
 	}
 	for _, v := range e.Variables {
 		env.Values[v.Name] = VariableValue{
@@ -37,6 +39,7 @@ func NewEnvironment(e sdk.Environment, keys []EncryptedKey) Environment {
 func (e *Environment) Environment() (env *sdk.Environment) {
 	env = new(sdk.Environment)
 	env.Name = e.Name
+	env.Lock = e.Lock
 	env.Variables = make([]sdk.Variable, len(e.Values))
 	var i int
 	for k, v := range e.Values {
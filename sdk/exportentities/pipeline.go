@@ -2,7 +2,10 @@ package exportentities
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -40,16 +43,36 @@ type Stage struct {
 
 // Job represents exported sdk.Job
 type Job struct {
-	Name           string        `json:"job,omitempty" yaml:"job,omitempty" jsonschema_description:"The name of the job."`
-	Stage          string        `json:"stage,omitempty" yaml:"stage,omitempty" jsonschema_description:"The name of the stage for the job."`
-	Description    string        `json:"description,omitempty" yaml:"description,omitempty" jsonschema_description:"The description of the job."`
-	Enabled        *bool         `json:"enabled,omitempty" yaml:"enabled,omitempty" jsonschema_description:"Job is enabled by default, you can set this option to disable a job."`
-	Steps          []Step        `json:"steps,omitempty" yaml:"steps,omitempty" jsonschema_description:"The list of steps for the job."`
-	Requirements   []Requirement `json:"requirements,omitempty" yaml:"requirements,omitempty" jsonschema_description:"The list of requirements for the jobs."`
-	Optional       *bool         `json:"optional,omitempty" yaml:"optional,omitempty" jsonschema_description:"Set this option to ignore job's errors."`
-	AlwaysExecuted *bool         `json:"always_executed,omitempty" yaml:"always_executed,omitempty" jsonschema_description:"Set this option to execute the job even if a previous step failed."`
+	Name           string            `json:"job,omitempty" yaml:"job,omitempty" jsonschema_description:"The name of the job."`
+	Stage          string            `json:"stage,omitempty" yaml:"stage,omitempty" jsonschema_description:"The name of the stage for the job."`
+	Description    string            `json:"description,omitempty" yaml:"description,omitempty" jsonschema_description:"The description of the job."`
+	Enabled        *bool             `json:"enabled,omitempty" yaml:"enabled,omitempty" jsonschema_description:"Job is enabled by default, you can set this option to disable a job."`
+	Steps          []Step            `json:"steps,omitempty" yaml:"steps,omitempty" jsonschema_description:"The list of steps for the job."`
+	Requirements   []Requirement     `json:"requirements,omitempty" yaml:"requirements,omitempty" jsonschema_description:"The list of requirements for the jobs."`
+	Optional       *bool             `json:"optional,omitempty" yaml:"optional,omitempty" jsonschema_description:"Set this option to ignore job's errors."`
+	AlwaysExecuted *bool             `json:"always_executed,omitempty" yaml:"always_executed,omitempty" jsonschema_description:"Set this option to execute the job even if a previous step failed."`
+	Timeout        string            `json:"timeout,omitempty" yaml:"timeout,omitempty" jsonschema_description:"Maximum duration of the job (ex: 30m, 1h30m), the job will be stopped and set as failed after it."`
+	Retry          *JobRetry         `json:"retry,omitempty" yaml:"retry,omitempty" jsonschema_description:"Retry policy of the job."`
+	Outputs        map[string]string `json:"outputs,omitempty" yaml:"outputs,omitempty" jsonschema_description:"Outputs of the job, computed from the outputs of its steps (ex: {{.cds.steps.build.outputs.digest}})."`
+	Matrix         JobMatrix         `json:"matrix,omitempty" yaml:"matrix,omitempty" jsonschema_description:"Values for each matrix key, a job will be generated for each combination of values."`
 }
 
+// JobRetry represents an exported sdk.ActionRetry
+type JobRetry struct {
+	MaxAttempts int64    `json:"max_attempts" yaml:"max_attempts" jsonschema_description:"Maximum number of attempts of the job, including the first one."`
+	Backoff     string   `json:"backoff,omitempty" yaml:"backoff,omitempty" jsonschema_description:"Delay before the second attempt (ex: 30s, 5m), doubled for each following attempt."`
+	When        []string `json:"when,omitempty" yaml:"when,omitempty" jsonschema_description:"Conditions to retry the job: failure, worker_lost or spawn_error (default: failure)."`
+	ExitCodes   []int    `json:"exit_codes,omitempty" yaml:"exit_codes,omitempty" jsonschema_description:"Retry a failed job only if a step exited with one of these codes."`
+}
+
+// JobMatrix represents the values for each key of a job matrix.
+type JobMatrix map[string][]string
+
+// MaxJobMatrixCombinations is the maximum number of jobs that can be generated from a job matrix.
+const MaxJobMatrixCombinations = 64
+
+var jobMatrixKeyPattern = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
 // Requirement represents an exported sdk.Requirement
 type Requirement struct {
 	Binary            string             `json:"binary,omitempty" yaml:"binary,omitempty"`
@@ -59,6 +82,8 @@ type Requirement struct {
 	Plugin            string             `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 	Service           ServiceRequirement `json:"service,omitempty" yaml:"service,omitempty"`
 	Memory            string             `json:"memory,omitempty" yaml:"memory,omitempty"`
+	CPU               string             `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	ResourceClass     string             `json:"resource-class,omitempty" yaml:"resource-class,omitempty"`
 	OSArchRequirement string             `json:"os-architecture,omitempty" yaml:"os-architecture,omitempty"`
 }
 
@@ -91,12 +116,28 @@ func NewPipelineV1(pip sdk.Pipeline) (p PipelineV1) {
 	}
 
 	for _, s := range pip.Stages {
+		// index of exported jobs generated from a matrix for current stage
+		matrixJobs := make(map[string]int)
 		for _, j := range s.Jobs {
+			values := j.MatrixValues()
+			if len(values) > 0 {
+				if i, ok := matrixJobs[j.MatrixName()]; ok {
+					p.Jobs[i].Matrix.add(values)
+					continue
+				}
+			}
+
 			jo := newJob(j)
 			if len(pip.Stages) > 1 {
 				jo.Stage = s.Name
 			}
 			jo.Name = j.Action.Name
+			if len(values) > 0 {
+				jo.Name = j.MatrixName()
+				jo.Matrix = JobMatrix{}
+				jo.Matrix.add(values)
+				matrixJobs[jo.Name] = len(p.Jobs)
+			}
 			p.Jobs = append(p.Jobs, jo)
 		}
 	}
@@ -153,6 +194,10 @@ func newRequirements(req []sdk.Requirement) []Requirement {
 			res = append(res, Requirement{OSArchRequirement: r.Value})
 		case sdk.MemoryRequirement:
 			res = append(res, Requirement{Memory: r.Value})
+		case sdk.CPURequirement:
+			res = append(res, Requirement{CPU: r.Value})
+		case sdk.ResourceClassRequirement:
+			res = append(res, Requirement{ResourceClass: r.Value})
 		}
 	}
 	return res
@@ -166,9 +211,120 @@ func newJob(j sdk.Job) Job {
 	jo.Steps = newSteps(j.Action)
 	jo.Description = j.Action.Description
 	jo.Requirements = newRequirements(j.Action.Requirements)
+	jo.Timeout = newTimeout(j.Action.Timeout)
+	jo.Retry = newJobRetry(j.Action.Retry)
+	if len(j.Action.Outputs) > 0 {
+		jo.Outputs = j.Action.Outputs
+	}
 	return jo
 }
 
+func newJobRetry(r *sdk.ActionRetry) *JobRetry {
+	if r == nil {
+		return nil
+	}
+	return &JobRetry{
+		MaxAttempts: r.MaxAttempts,
+		Backoff:     newTimeout(r.Backoff),
+		When:        r.When,
+		ExitCodes:   r.ExitCodes,
+	}
+}
+
+func computeJobRetry(r *JobRetry) (*sdk.ActionRetry, error) {
+	if r == nil {
+		return nil, nil
+	}
+	backoff, err := computeTimeout(r.Backoff)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given retry backoff %q, should be a duration greater than 1s (ex: 30s, 5m)", r.Backoff)
+	}
+	retry := sdk.ActionRetry{
+		MaxAttempts: r.MaxAttempts,
+		Backoff:     backoff,
+		When:        r.When,
+		ExitCodes:   r.ExitCodes,
+	}
+	if err := retry.IsValid(); err != nil {
+		return nil, err
+	}
+	return &retry, nil
+}
+
+// newTimeout returns a human readable duration (ex: 1h30m) for given number of seconds.
+func newTimeout(seconds int64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	s := (time.Duration(seconds) * time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// computeTimeout returns a number of seconds for given duration (ex: 1h30m).
+func computeTimeout(timeout string) (int64, error) {
+	if timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d < time.Second {
+		return 0, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given timeout %q, should be a duration greater than 1s (ex: 30m, 1h30m)", timeout)
+	}
+	return int64(d / time.Second), nil
+}
+
+// add given combination values to the matrix, keeping values order.
+func (m JobMatrix) add(values map[string]string) {
+	for k, v := range values {
+		if !sdk.IsInArray(v, m[k]) {
+			m[k] = append(m[k], v)
+		}
+	}
+}
+
+// combinations returns all the combinations of values for the matrix of given job.
+func (m JobMatrix) combinations(jobName string) ([]map[string]string, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := []map[string]string{{}}
+	for _, k := range keys {
+		if !jobMatrixKeyPattern.MatchString(k) {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid matrix key %q for job %s, should match %s", k, jobName, jobMatrixKeyPattern.String())
+		}
+		if len(m[k]) == 0 {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid matrix key %q for job %s, at least one value is required", k, jobName)
+		}
+		next := make([]map[string]string, 0, len(res)*len(m[k]))
+		for _, c := range res {
+			for _, v := range m[k] {
+				if v == "" {
+					return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid empty value for matrix key %q for job %s", k, jobName)
+				}
+				n := make(map[string]string, len(c)+1)
+				for ck, cv := range c {
+					n[ck] = cv
+				}
+				n[k] = v
+				next = append(next, n)
+			}
+		}
+		if len(next) > MaxJobMatrixCombinations {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "too many combinations for matrix of job %s, the maximum is %d", jobName, MaxJobMatrixCombinations)
+		}
+		res = next
+	}
+	return res, nil
+}
+
 func newJobs(jobs []sdk.Job) map[string]Job {
 	res := map[string]Job{}
 	for i := range jobs {
@@ -210,6 +366,14 @@ func computeJobRequirements(req []Requirement) []sdk.Requirement {
 			name = "memory"
 			val = r.Memory
 			tpe = sdk.MemoryRequirement
+		} else if r.CPU != "" {
+			name = "cpu"
+			val = r.CPU
+			tpe = sdk.CPURequirement
+		} else if r.ResourceClass != "" {
+			name = "resource-class"
+			val = r.ResourceClass
+			tpe = sdk.ResourceClassRequirement
 		} else if r.Model != "" {
 			name = "model"
 			val = r.Model
@@ -256,6 +420,18 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 	job.Action.Enabled = job.Enabled
 	job.Action.Requirements = computeJobRequirements(j.Requirements)
 
+	timeout, err := computeTimeout(j.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	job.Action.Timeout = timeout
+
+	retry, err := computeJobRetry(j.Retry)
+	if err != nil {
+		return nil, err
+	}
+	job.Action.Retry = retry
+
 	//Compute steps for the jobs
 	children, err := computeSteps(j.Steps)
 	if err != nil {
@@ -263,9 +439,54 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 	}
 	job.Action.Actions = children
 
+	if len(j.Outputs) > 0 {
+		job.Action.Outputs = j.Outputs
+	}
+	if err := job.Action.IsValidOutputs(); err != nil {
+		return nil, err
+	}
+
 	return &job, nil
 }
 
+// computeJobs returns the list of jobs for given exported job, a job is generated for
+// each combination of values if a matrix is set.
+func computeJobs(j Job) ([]sdk.Job, error) {
+	job, err := computeJob(j.Name, j)
+	if err != nil {
+		return nil, err
+	}
+	if len(j.Matrix) == 0 {
+		return []sdk.Job{*job}, nil
+	}
+
+	combinations, err := j.Matrix.combinations(j.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]sdk.Job, len(combinations))
+	for i, c := range combinations {
+		mj := *job
+		mj.Action.Name = sdk.NewMatrixJobName(j.Name, c)
+		mj.Action.Actions = append([]sdk.Action(nil), job.Action.Actions...)
+		mj.Action.Requirements = append(sdk.RequirementList(nil), job.Action.Requirements...)
+		mj.Action.Parameters = make([]sdk.Parameter, 0, len(c))
+		for k, v := range c {
+			mj.Action.Parameters = append(mj.Action.Parameters, sdk.Parameter{
+				Name:  sdk.JobMatrixParameterPrefix + k,
+				Type:  sdk.StringParameter,
+				Value: v,
+			})
+		}
+		sort.Slice(mj.Action.Parameters, func(i, j int) bool {
+			return mj.Action.Parameters[i].Name < mj.Action.Parameters[j].Name
+		})
+		res[i] = mj
+	}
+	return res, nil
+}
+
 //Pipeline returns a sdk.Pipeline entity
 func (p PipelineV1) Pipeline() (pip *sdk.Pipeline, err error) {
 	pip = new(sdk.Pipeline)
@@ -332,11 +553,11 @@ func (p PipelineV1) Pipeline() (pip *sdk.Pipeline, err error) {
 			}
 		}
 
-		job, err := computeJob(j.Name, j)
+		jobs, err := computeJobs(j)
 		if err != nil {
 			return pip, err
 		}
-		s.Jobs = append(s.Jobs, *job)
+		s.Jobs = append(s.Jobs, jobs...)
 	}
 
 	pip.Stages = make([]sdk.Stage, len(mapStages))
@@ -596,6 +596,158 @@ jobs:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions[0].Parameters, 1)
 }
 
+func Test_ImportPipelineWithTimeouts(t *testing.T) {
+	in := `name: build-all-images
+jobs:
+- job: build
+  timeout: 1h30m
+  steps:
+  - script: sleep 10
+    timeout: 10m
+  - script: sleep 10
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	assert.Equal(t, int64(5400), p.Stages[0].Jobs[0].Action.Timeout)
+	assert.Equal(t, int64(600), p.Stages[0].Jobs[0].Action.Actions[0].Timeout)
+	assert.Equal(t, int64(0), p.Stages[0].Jobs[0].Action.Actions[1].Timeout)
+
+	exported := exportentities.NewPipelineV1(*p)
+	assert.Equal(t, "1h30m", exported.Jobs[0].Timeout)
+	assert.Equal(t, "10m", exported.Jobs[0].Steps[0].Timeout)
+	assert.Equal(t, "", exported.Jobs[0].Steps[1].Timeout)
+
+	payload.Jobs[0].Timeout = "forever"
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithRetry(t *testing.T) {
+	in := `name: build-all-images
+jobs:
+- job: build
+  retry:
+    max_attempts: 3
+    backoff: 30s
+    when: [failure, worker_lost]
+    exit_codes: [1, 137]
+  steps:
+  - script: make
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	retry := p.Stages[0].Jobs[0].Action.Retry
+	test.NotNil(t, retry)
+	assert.Equal(t, int64(3), retry.MaxAttempts)
+	assert.Equal(t, int64(30), retry.Backoff)
+	assert.Equal(t, []string{sdk.RetryWhenFailure, sdk.RetryWhenWorkerLost}, retry.When)
+	assert.Equal(t, []int{1, 137}, retry.ExitCodes)
+
+	exported := exportentities.NewPipelineV1(*p)
+	test.NotNil(t, exported.Jobs[0].Retry)
+	assert.Equal(t, *payload.Jobs[0].Retry, *exported.Jobs[0].Retry)
+
+	payload.Jobs[0].Retry.When = []string{"always"}
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+
+	payload.Jobs[0].Retry = &exportentities.JobRetry{}
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithOutputs(t *testing.T) {
+	in := `name: build-all-images
+jobs:
+- job: build
+  outputs:
+    image_digest: "{{.cds.steps.docker.outputs.digest}}"
+  steps:
+  - id: docker
+    script: worker output digest $(cat digest.txt)
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0].Action
+	assert.Equal(t, sdk.ActionOutputs{"image_digest": "{{.cds.steps.docker.outputs.digest}}"}, job.Outputs)
+	assert.Equal(t, "docker", job.Actions[0].StepID)
+
+	exported := exportentities.NewPipelineV1(*p)
+	assert.Equal(t, payload.Jobs[0].Outputs, exported.Jobs[0].Outputs)
+	assert.Equal(t, "docker", exported.Jobs[0].Steps[0].ID)
+
+	payload.Jobs[0].Steps = append(payload.Jobs[0].Steps, payload.Jobs[0].Steps[0])
+	_, err = payload.Pipeline()
+	assert.Error(t, err, "step ids should be unique")
+
+	payload.Jobs[0].Steps = payload.Jobs[0].Steps[:1]
+	payload.Jobs[0].Outputs = map[string]string{"image digest": "{{.cds.steps.docker.outputs.digest}}"}
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithMatrix(t *testing.T) {
+	in := `name: build-all-images
+jobs:
+- job: build
+  matrix:
+    os: [linux, windows]
+    go: ["1.12", "1.13"]
+  requirements:
+  - model: go{{.cds.matrix.go}}-{{.cds.matrix.os}}
+  steps:
+  - script: GOOS={{.cds.matrix.os}} go build
+- job: package
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	test.Equal(t, 1, len(p.Stages))
+	test.Equal(t, 5, len(p.Stages[0].Jobs))
+	assert.Equal(t, "build (go=1.12, os=linux)", p.Stages[0].Jobs[0].Action.Name)
+	assert.Equal(t, "build (go=1.12, os=windows)", p.Stages[0].Jobs[1].Action.Name)
+	assert.Equal(t, "build (go=1.13, os=linux)", p.Stages[0].Jobs[2].Action.Name)
+	assert.Equal(t, "build (go=1.13, os=windows)", p.Stages[0].Jobs[3].Action.Name)
+	assert.Equal(t, "package", p.Stages[0].Jobs[4].Action.Name)
+	assert.Equal(t, map[string]string{"go": "1.13", "os": "linux"}, p.Stages[0].Jobs[2].MatrixValues())
+	assert.Equal(t, "build", p.Stages[0].Jobs[2].MatrixName())
+	assert.Nil(t, p.Stages[0].Jobs[4].MatrixValues())
+
+	exported := exportentities.NewPipelineV1(*p)
+	test.Equal(t, 2, len(exported.Jobs))
+	assert.Equal(t, "build", exported.Jobs[0].Name)
+	assert.Equal(t, exportentities.JobMatrix{"go": {"1.12", "1.13"}, "os": {"linux", "windows"}}, exported.Jobs[0].Matrix)
+	assert.Equal(t, "package", exported.Jobs[1].Name)
+	assert.Nil(t, exported.Jobs[1].Matrix)
+
+	payload.Jobs[0].Matrix["os"] = nil
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+
+	payload.Jobs[0].Matrix = exportentities.JobMatrix{"invalid key": {"value"}}
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
 func Test_ImportPipelineWithOneStageAndRunConditions(t *testing.T) {
 	in := `version: v1.0
 name: echo
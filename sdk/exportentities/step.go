@@ -15,6 +15,7 @@ import (
 func newStep(act sdk.Action) Step {
 	s := Step{
 		Name: act.StepName,
+		ID:   act.StepID,
 	}
 
 	if !act.Enabled {
@@ -26,6 +27,7 @@ func newStep(act sdk.Action) Step {
 	if act.AlwaysExecuted {
 		s.AlwaysExecuted = &sdk.True
 	}
+	s.Timeout = newTimeout(act.Timeout)
 
 	switch act.Type {
 	case sdk.BuiltinAction:
@@ -323,9 +325,11 @@ type StepDeploy string
 type Step struct {
 	// common step data
 	Name           string `json:"name,omitempty" yaml:"name,omitempty" jsonschema_description:"The name for this step."`
+	ID             string `json:"id,omitempty" yaml:"id,omitempty" jsonschema_description:"The id of the step, used to read its outputs (ex: {{.cds.steps.<id>.outputs.<name>}})."`
 	Enabled        *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 	Optional       *bool  `json:"optional,omitempty" yaml:"optional,omitempty"`
 	AlwaysExecuted *bool  `json:"always_executed,omitempty" yaml:"always_executed,omitempty"`
+	Timeout        string `json:"timeout,omitempty" yaml:"timeout,omitempty" jsonschema_description:"Maximum duration of the step (ex: 10m), the step will be stopped and set as failed after it."`
 	// step specific data, only one option should be set
 	StepCustom       `json:"-" yaml:",inline"`
 	Script           interface{}           `json:"script,omitempty" yaml:"script,omitempty" jsonschema:"oneof_type=string;array,oneof_required=actionScript" jsonschema_description:"Script.\nhttps://ovh.github.io/cds/docs/actions/builtin-script"`
@@ -503,10 +507,16 @@ func (s Step) toAction() (*sdk.Action, error) {
 	}
 
 	a.StepName = s.Name
+	a.StepID = s.ID
 	a.Enabled = s.Enabled == nil || *s.Enabled == sdk.True // enabled is true by default
 	a.Optional = s.Optional != nil && *s.Optional == sdk.True
 	a.AlwaysExecuted = s.AlwaysExecuted != nil && *s.AlwaysExecuted == sdk.True
 
+	a.Timeout, err = computeTimeout(s.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
 	return &a, nil
 }
 
@@ -756,7 +766,7 @@ func (s Step) asAction() sdk.Action {
 	}
 
 	splitted := strings.Split(name, "/")
-	if len(splitted) == 2 {
+	if len(splitted) == 2 && !IsCompositeActionPath(name) {
 		a.Name = splitted[1]
 		a.Group = &sdk.Group{Name: splitted[0]}
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/fsamin/go-dump"
@@ -22,29 +23,49 @@ type Workflow struct {
 	Hooks    map[string][]HookEntry `json:"hooks,omitempty" yaml:"hooks,omitempty" jsonschema_description:"Workflow hooks list."`
 
 	// extra workflow data
-	Permissions   map[string]int      `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the workflow (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
-	Metadata      map[string]string   `json:"metadata,omitempty" yaml:"metadata,omitempty"`
-	PurgeTags     []string            `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty"`
-	Notifications []NotificationEntry `json:"notifications,omitempty" yaml:"notifications,omitempty"` // This is used when the workflow have only one pipeline
-	HistoryLength *int64              `json:"history_length,omitempty" yaml:"history_length,omitempty"`
+	Permissions       map[string]int                 `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the workflow (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
+	Metadata          map[string]string              `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	PurgeTags         []string                       `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty"`
+	Notifications     []NotificationEntry            `json:"notifications,omitempty" yaml:"notifications,omitempty"` // This is used when the workflow have only one pipeline
+	HistoryLength     *int64                         `json:"history_length,omitempty" yaml:"history_length,omitempty"`
+	RunRetention      *sdk.WorkflowRunRetention      `json:"run_retention,omitempty" yaml:"run_retention,omitempty" jsonschema_description:"The rules to purge the runs, replaces the history length and the purge tags.\nhttps://ovh.github.io/cds/docs/concepts/workflow/run-retention"`
+	ArtifactRetention *sdk.WorkflowArtifactRetention `json:"artifact_retention,omitempty" yaml:"artifact_retention,omitempty" jsonschema_description:"The retention policy of the artifacts.\nhttps://ovh.github.io/cds/docs/concepts/workflow/artifact-retention"`
 }
 
 // NodeEntry represents a node as code
 type NodeEntry struct {
-	ID                     int64                  `json:"-" yaml:"-"`
-	DependsOn              []string               `json:"depends_on,omitempty" yaml:"depends_on,omitempty" jsonschema_description:"Names of the parent nodes, can be pipelines, forks or joins."`
-	Conditions             *ConditionEntry        `json:"conditions,omitempty" yaml:"conditions,omitempty" jsonschema_description:"Conditions to run this node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/run-conditions."`
-	When                   []string               `json:"when,omitempty" yaml:"when,omitempty" jsonschema_description:"Set manual and status condition (ex: 'success')."` //This is used only for manual and success condition
-	PipelineName           string                 `json:"pipeline,omitempty" yaml:"pipeline,omitempty" jsonschema_description:"The name of a pipeline used for pipeline node."`
-	ApplicationName        string                 `json:"application,omitempty" yaml:"application,omitempty" jsonschema_description:"The application to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
-	EnvironmentName        string                 `json:"environment,omitempty" yaml:"environment,omitempty" jsonschema_description:"The environment to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
-	ProjectIntegrationName string                 `json:"integration,omitempty" yaml:"integration,omitempty" jsonschema_description:"The integration to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
-	OneAtATime             *bool                  `json:"one_at_a_time,omitempty" yaml:"one_at_a_time,omitempty" jsonschema_description:"Set to true if you want to limit the execution of this node to one at a time."`
-	Payload                map[string]interface{} `json:"payload,omitempty" yaml:"payload,omitempty"`
-	Parameters             map[string]string      `json:"parameters,omitempty" yaml:"parameters,omitempty" jsonschema_description:"List of parameters for the workflow."`
-	OutgoingHookModelName  string                 `json:"trigger,omitempty" yaml:"trigger,omitempty"`
-	OutgoingHookConfig     map[string]string      `json:"config,omitempty" yaml:"config,omitempty"`
-	Permissions            map[string]int         `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the node (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
+	ID                     int64                    `json:"-" yaml:"-"`
+	DependsOn              []string                 `json:"depends_on,omitempty" yaml:"depends_on,omitempty" jsonschema_description:"Names of the parent nodes, can be pipelines, forks or joins."`
+	Conditions             *ConditionEntry          `json:"conditions,omitempty" yaml:"conditions,omitempty" jsonschema_description:"Conditions to run this node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/run-conditions."`
+	When                   []string                 `json:"when,omitempty" yaml:"when,omitempty" jsonschema_description:"Set manual and status condition (ex: 'success')."` //This is used only for manual and success condition
+	PipelineName           string                   `json:"pipeline,omitempty" yaml:"pipeline,omitempty" jsonschema_description:"The name of a pipeline used for pipeline node."`
+	ApplicationName        string                   `json:"application,omitempty" yaml:"application,omitempty" jsonschema_description:"The application to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
+	EnvironmentName        string                   `json:"environment,omitempty" yaml:"environment,omitempty" jsonschema_description:"The environment to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
+	ProjectIntegrationName string                   `json:"integration,omitempty" yaml:"integration,omitempty" jsonschema_description:"The integration to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
+	OneAtATime             *bool                    `json:"one_at_a_time,omitempty" yaml:"one_at_a_time,omitempty" jsonschema_description:"Set to true if you want to limit the execution of this node to one at a time."`
+	Lock                   string                   `json:"lock,omitempty" yaml:"lock,omitempty" jsonschema_description:"The name of a lock shared by all the workflows of the project, only one node that use the lock can run at a time.\nhttps://ovh.github.io/cds/docs/concepts/workflow/lock"`
+	Priority               string                   `json:"priority,omitempty" yaml:"priority,omitempty" jsonschema_description:"Priority of the jobs of this node in the queue: high, normal or low.\nhttps://ovh.github.io/cds/docs/concepts/workflow/priority"`
+	Approval               *ApprovalEntry           `json:"approval,omitempty" yaml:"approval,omitempty" jsonschema_description:"Approvals required before running this node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/approval"`
+	PreviewEnvironment     *PreviewEnvironmentEntry `json:"preview_environment,omitempty" yaml:"preview_environment,omitempty" jsonschema_description:"Preview environment cloned from the environment of the node for each branch or pull request.\nhttps://ovh.github.io/cds/docs/concepts/workflow/preview-environment"`
+	Payload                map[string]interface{}   `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Parameters             map[string]string        `json:"parameters,omitempty" yaml:"parameters,omitempty" jsonschema_description:"List of parameters for the workflow."`
+	OutgoingHookModelName  string                   `json:"trigger,omitempty" yaml:"trigger,omitempty"`
+	OutgoingHookConfig     map[string]string        `json:"config,omitempty" yaml:"config,omitempty"`
+	Permissions            map[string]int           `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the node (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
+}
+
+// ApprovalEntry represents the approval gate of a node as code
+type ApprovalEntry struct {
+	MinApprovals int64    `json:"min_approvals" yaml:"min_approvals" jsonschema_description:"Number of approvals required to run the node."`
+	Groups       []string `json:"groups,omitempty" yaml:"groups,omitempty" jsonschema_description:"Names of the groups which members can approve, any user with execute permission can if empty."`
+	Timeout      string   `json:"timeout,omitempty" yaml:"timeout,omitempty" jsonschema_description:"Delay after which the node run is rejected if not approved (ex: 30m, 24h)."`
+}
+
+// PreviewEnvironmentEntry represents the preview environment of a node as code
+type PreviewEnvironmentEntry struct {
+	Name             string            `json:"name" yaml:"name" jsonschema_description:"Name of the preview environment, interpolated with the build parameters (ex: pr-{{.git.pr.id}})."`
+	Variables        map[string]string `json:"variables,omitempty" yaml:"variables,omitempty" jsonschema_description:"Variables overriding the ones of the environment of the node, values are interpolated with the build parameters."`
+	TeardownPipeline string            `json:"teardown_pipeline,omitempty" yaml:"teardown_pipeline,omitempty" jsonschema_description:"Name of the pipeline run when the pull request is closed or the branch is deleted."`
 }
 
 type ConditionEntry struct {
@@ -52,10 +73,11 @@ type ConditionEntry struct {
 	LuaScript       string                `json:"script,omitempty" yaml:"script,omitempty"`
 }
 
-//WorkflowNodeCondition represents a condition to trigger ot not a pipeline in a workflow. Operator can be =, !=, regex
+//WorkflowNodeCondition represents a condition to trigger ot not a pipeline in a workflow. Operator can be =, !=, regex,
+// in, not_in, contains or a typed comparison (ex: gt_number, le_semver, lt_date).
 type PlainConditionEntry struct {
 	Variable string `json:"variable" yaml:"variable"`
-	Operator string `json:"operator" yaml:"operator"`
+	Operator string `json:"operator" yaml:"operator" jsonschema_description:"Operator used to compare the variable with the value.\nhttps://ovh.github.io/cds/docs/concepts/workflow/run-conditions."`
 	Value    string `json:"value" yaml:"value"`
 }
 
@@ -91,6 +113,8 @@ func NewWorkflow(ctx context.Context, w sdk.Workflow, version string, opts ...Ex
 	}
 
 	exportedWorkflow.PurgeTags = w.PurgeTags
+	exportedWorkflow.RunRetention = w.RunRetention
+	exportedWorkflow.ArtifactRetention = w.ArtifactRetention
 
 	nodes := w.WorkflowData.Array()
 
@@ -229,6 +253,24 @@ func craftNodeEntry(w sdk.Workflow, n sdk.Node) (NodeEntry, error) {
 		if n.Context.Mutex {
 			entry.OneAtATime = &n.Context.Mutex
 		}
+		entry.Lock = n.Context.Lock
+		entry.Priority = n.Context.Priority
+
+		if n.Context.Approval != nil {
+			entry.Approval = &ApprovalEntry{
+				MinApprovals: n.Context.Approval.MinApprovals,
+				Groups:       n.Context.Approval.Groups,
+			}
+			entry.Approval.Timeout = newApprovalTimeout(n.Context.Approval.Timeout)
+		}
+
+		if n.Context.PreviewEnvironment != nil {
+			entry.PreviewEnvironment = &PreviewEnvironmentEntry{
+				Name:             n.Context.PreviewEnvironment.Name,
+				Variables:        n.Context.PreviewEnvironment.Variables,
+				TeardownPipeline: n.Context.PreviewEnvironment.TeardownPipeline,
+			}
+		}
 
 		if n.Context.HasDefaultPayload() {
 			enc := dump.NewDefaultEncoder()
@@ -347,6 +389,8 @@ func (w Workflow) GetWorkflow() (*sdk.Workflow, error) {
 		return nil, sdk.WrapError(err, "Unable to check dependencies")
 	}
 	wf.PurgeTags = w.PurgeTags
+	wf.RunRetention = w.RunRetention
+	wf.ArtifactRetention = w.ArtifactRetention
 	if len(w.Metadata) > 0 {
 		wf.Metadata = make(map[string]string, len(w.Metadata))
 		for k, v := range w.Metadata {
@@ -591,6 +635,8 @@ func (e *NodeEntry) getNode(name string) (*sdk.Node, error) {
 			EnvironmentName:        e.EnvironmentName,
 			ProjectIntegrationName: e.ProjectIntegrationName,
 			Mutex:                  mutex,
+			Lock:                   e.Lock,
+			Priority:               e.Priority,
 		},
 	}
 
@@ -662,6 +708,43 @@ func (e *NodeEntry) getNode(name string) (*sdk.Node, error) {
 		node.Context.Mutex = *e.OneAtATime
 	}
 
+	if err := sdk.IsValidJobPriority(e.Priority); err != nil {
+		return nil, err
+	}
+
+	if e.Approval != nil {
+		approval := sdk.WorkflowNodeApproval{
+			MinApprovals: e.Approval.MinApprovals,
+			Groups:       e.Approval.Groups,
+		}
+		if e.Approval.Timeout != "" {
+			timeout, err := time.ParseDuration(e.Approval.Timeout)
+			if err != nil || timeout < time.Second {
+				return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given approval timeout %q, should be a duration greater than 1s (ex: 30m, 24h) (node : %s)", e.Approval.Timeout, name)
+			}
+			approval.Timeout = int64(timeout.Seconds())
+		}
+		if err := approval.IsValid(); err != nil {
+			return nil, err
+		}
+		node.Context.Approval = &approval
+	}
+
+	if e.PreviewEnvironment != nil {
+		if e.EnvironmentName == "" {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "preview environment needs an environment to clone (node : %s)", name)
+		}
+		preview := sdk.WorkflowNodePreviewEnvironment{
+			Name:             e.PreviewEnvironment.Name,
+			Variables:        e.PreviewEnvironment.Variables,
+			TeardownPipeline: e.PreviewEnvironment.TeardownPipeline,
+		}
+		if err := preview.IsValid(); err != nil {
+			return nil, err
+		}
+		node.Context.PreviewEnvironment = &preview
+	}
+
 	if e.OutgoingHookModelName != "" {
 		node.Type = sdk.NodeTypeOutGoingHook
 		config := sdk.WorkflowNodeHookConfig{}
@@ -678,6 +761,21 @@ func (e *NodeEntry) getNode(name string) (*sdk.Node, error) {
 	return node, nil
 }
 
+// newApprovalTimeout returns the approval timeout as a duration (ex: 1h30m).
+func newApprovalTimeout(seconds int64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	s := (time.Duration(seconds) * time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
 func (w *Workflow) processHooks(n *sdk.Node, wf *sdk.Workflow) {
 	var addHooks = func(hooks []HookEntry) {
 		for _, h := range hooks {
@@ -120,7 +120,52 @@ func Test_processNotificationValues(t *testing.T) {
 		want    sdk.WorkflowNotification
 		wantErr bool
 	}{
-		// TODO: Add test cases.
+		{
+			name: "slack notification without webhook",
+			args: args{
+				notif: v2.NotificationEntry{
+					Type: sdk.SlackUserNotification,
+				},
+			},
+			want: sdk.WorkflowNotification{
+				Type: sdk.SlackUserNotification,
+				Settings: sdk.UserNotificationSettings{
+					OnFailure:    sdk.UserNotificationAlways,
+					OnSuccess:    sdk.UserNotificationChange,
+					OnStart:      &v2.False,
+					SendToAuthor: &v2.True,
+					SendToGroups: &v2.False,
+					Template:     &sdk.UserNotificationTemplateChat,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "mattermost notification with default values",
+			args: args{
+				notif: v2.NotificationEntry{
+					Type: sdk.MattermostUserNotification,
+					Settings: &sdk.UserNotificationSettings{
+						OnSuccess:  sdk.UserNotificationNever,
+						WebhookURL: "https://mattermost.local/hooks/xxx",
+						Channel:    "town-square",
+					},
+				},
+			},
+			want: sdk.WorkflowNotification{
+				Type: sdk.MattermostUserNotification,
+				Settings: sdk.UserNotificationSettings{
+					OnFailure:    sdk.UserNotificationAlways,
+					OnSuccess:    sdk.UserNotificationNever,
+					OnStart:      &v2.False,
+					SendToAuthor: &v2.True,
+					SendToGroups: &v2.False,
+					Template:     &sdk.UserNotificationTemplateChat,
+					WebhookURL:   "https://mattermost.local/hooks/xxx",
+					Channel:      "town-square",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -213,6 +258,29 @@ notifications:
         Details : {{.cds.buildURL}}
         Triggered by : {{.cds.triggered_by.username}}
         Branch : {{.git.branch}}
+`,
+		},
+		{
+			name: "one pipeline with slack and teams notifs",
+			yaml: `name: test-notif-chat
+version: v2.0
+workflow:
+  test:
+    pipeline: test
+notifications:
+- type: slack
+  pipelines:
+  - test
+  settings:
+    on_success: never
+    webhook_url: '**********'
+    channel: '#builds'
+- type: teams
+  pipelines:
+  - test
+  settings:
+    on_failure: change
+    webhook_url: '**********'
 `,
 		},
 		{
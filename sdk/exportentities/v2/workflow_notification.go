@@ -75,6 +75,11 @@ func craftNotificationEntry(ctx context.Context, w sdk.Workflow, notif sdk.Workf
 		}
 	}
 
+	// The webhook url is stored encrypted, it is never exported
+	if entry.Settings.WebhookURL != "" {
+		entry.Settings.WebhookURL = sdk.PasswordPlaceholder
+	}
+
 	// Finally if settings are all default, lets skip it
 	if entry.Settings.OnFailure == "" &&
 		entry.Settings.OnStart == nil &&
@@ -82,7 +87,9 @@ func craftNotificationEntry(ctx context.Context, w sdk.Workflow, notif sdk.Workf
 		len(entry.Settings.Recipients) == 0 &&
 		entry.Settings.SendToAuthor == nil &&
 		entry.Settings.SendToGroups == nil &&
-		entry.Settings.Template == nil {
+		entry.Settings.Template == nil &&
+		entry.Settings.WebhookURL == "" &&
+		entry.Settings.Channel == "" {
 		entry.Settings = nil
 	}
 
@@ -141,6 +148,9 @@ func ProcessNotificationValues(notif NotificationEntry) (sdk.WorkflowNotificatio
 	} else {
 		n.Settings = *notif.Settings
 	}
+	if sdk.IsChatUserNotification(n.Type) && n.Settings.WebhookURL == "" {
+		return n, fmt.Errorf("Error: wrong usage: webhook_url is mandatory for %s notification", n.Type)
+	}
 	//Default values
 	if n.Settings.OnFailure == "" {
 		n.Settings.OnFailure = sdk.UserNotificationAlways
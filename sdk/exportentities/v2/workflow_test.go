@@ -973,6 +973,68 @@ workflow:
     - success
     pipeline: env
     one_at_a_time: true
+`,
+		},
+		{
+			name: "Workflow with approval gate",
+			yaml: `name: myapproval
+version: v2.0
+workflow:
+  build:
+    pipeline: build
+  deploy:
+    depends_on:
+    - build
+    when:
+    - success
+    pipeline: deploy
+    approval:
+      min_approvals: 2
+      groups:
+      - ops
+      timeout: 24h
+`,
+		},
+		{
+			name: "Workflow with priority",
+			yaml: `name: mypriority
+version: v2.0
+workflow:
+  build:
+    pipeline: build
+    priority: low
+  deploy:
+    depends_on:
+    - build
+    when:
+    - success
+    pipeline: deploy
+    priority: high
+`,
+		},
+		{
+			name: "Workflow with preview environment",
+			yaml: `name: mypreview
+version: v2.0
+workflow:
+  deploy:
+    pipeline: deploy
+    environment: staging
+    preview_environment:
+      name: pr-{{.git.pr.id}}
+      variables:
+        url: https://pr-{{.git.pr.id}}.example.com
+      teardown_pipeline: teardown
+  teardown:
+    depends_on:
+    - deploy
+    conditions:
+      check:
+      - variable: cds.preview.teardown
+        operator: eq
+        value: "true"
+    pipeline: teardown
+    environment: staging
 `,
 		},
 		{
@@ -6,24 +6,25 @@ import (
 
 // WorkerModel is the as code format of a worker model
 type WorkerModel struct {
-	Name          string            `json:"name" yaml:"name"`
-	Group         string            `json:"group" yaml:"group"`
-	Communication string            `json:"communication,omitempty" yaml:"communication,omitempty"`
-	Image         string            `json:"image" yaml:"image"`
-	Registry      string            `json:"registry,omitempty" yaml:"registry,omitempty"`
-	Username      string            `json:"username,omitempty" yaml:"username,omitempty"`
-	Password      string            `json:"password,omitempty" yaml:"password,omitempty"`
-	Description   string            `json:"description" yaml:"description"`
-	Type          string            `json:"type" yaml:"type"`
-	Flavor        string            `json:"flavor,omitempty" yaml:"flavor,omitempty"`
-	Envs          map[string]string `json:"envs,omitempty" yaml:"envs,omitempty"`
-	PatternName   string            `json:"pattern_name,omitempty" yaml:"pattern_name,omitempty"`
-	Shell         string            `json:"shell,omitempty" yaml:"shell,omitempty"`
-	PreCmd        string            `json:"pre_cmd,omitempty" yaml:"pre_cmd,omitempty"`
-	Cmd           string            `json:"cmd,omitempty" yaml:"cmd,omitempty"`
-	PostCmd       string            `json:"post_cmd,omitempty" yaml:"post_cmd,omitempty"`
-	Restricted    bool              `json:"restricted,omitempty" yaml:"restricted,omitempty"`
-	IsDeprecated  bool              `json:"is_deprecated,omitempty" yaml:"is_deprecated,omitempty"`
+	Name          string                `json:"name" yaml:"name"`
+	Group         string                `json:"group" yaml:"group"`
+	Communication string                `json:"communication,omitempty" yaml:"communication,omitempty"`
+	Image         string                `json:"image" yaml:"image"`
+	Registry      string                `json:"registry,omitempty" yaml:"registry,omitempty"`
+	Username      string                `json:"username,omitempty" yaml:"username,omitempty"`
+	Password      string                `json:"password,omitempty" yaml:"password,omitempty"`
+	Description   string                `json:"description" yaml:"description"`
+	Type          string                `json:"type" yaml:"type"`
+	Flavor        string                `json:"flavor,omitempty" yaml:"flavor,omitempty"`
+	Envs          map[string]string     `json:"envs,omitempty" yaml:"envs,omitempty"`
+	PatternName   string                `json:"pattern_name,omitempty" yaml:"pattern_name,omitempty"`
+	Shell         string                `json:"shell,omitempty" yaml:"shell,omitempty"`
+	PreCmd        string                `json:"pre_cmd,omitempty" yaml:"pre_cmd,omitempty"`
+	Cmd           string                `json:"cmd,omitempty" yaml:"cmd,omitempty"`
+	PostCmd       string                `json:"post_cmd,omitempty" yaml:"post_cmd,omitempty"`
+	Restricted    bool                  `json:"restricted,omitempty" yaml:"restricted,omitempty"`
+	IsDeprecated  bool                  `json:"is_deprecated,omitempty" yaml:"is_deprecated,omitempty"`
+	PodTemplate   *sdk.ModelPodTemplate `json:"pod_template,omitempty" yaml:"pod_template,omitempty"`
 }
 
 type WorkerModelOption func(sdk.Model, *WorkerModel) error
@@ -40,6 +41,7 @@ func loadWorkerModelWithoutAdminFields(_ sdk.Model, wm *WorkerModel) error {
 	wm.Cmd = ""
 	wm.PostCmd = ""
 	wm.Envs = nil
+	wm.PodTemplate = nil
 	return nil
 }
 
@@ -62,6 +64,7 @@ func NewWorkerModel(wm sdk.Model, opts ...WorkerModelOption) WorkerModel {
 		model.Image = wm.ModelDocker.Image
 		model.Cmd = wm.ModelDocker.Cmd
 		model.Envs = wm.ModelDocker.Envs
+		model.PodTemplate = wm.ModelDocker.PodTemplate
 		if wm.ModelDocker.Private {
 			model.Registry = wm.ModelDocker.Registry
 			model.Username = wm.ModelDocker.Username
@@ -100,10 +103,11 @@ func (wm WorkerModel) GetWorkerModel() sdk.Model {
 	switch wm.Type {
 	case sdk.Docker:
 		model.ModelDocker = sdk.ModelDocker{
-			Shell: wm.Shell,
-			Image: wm.Image,
-			Cmd:   wm.Cmd,
-			Envs:  wm.Envs,
+			Shell:       wm.Shell,
+			Image:       wm.Image,
+			Cmd:         wm.Cmd,
+			Envs:        wm.Envs,
+			PodTemplate: wm.PodTemplate,
 		}
 		if wm.Username != "" || wm.Registry != "" || wm.Password != "" {
 			model.ModelDocker.Registry = wm.Registry
@@ -21,6 +21,8 @@ type WorkflowComponents struct {
 	Applications []Application
 	Pipelines    []PipelineV1
 	Environments []Environment
+	// Actions are the composite actions of the workflow, by path relative to the .cds folder
+	Actions map[string]CompositeAction
 }
 
 func (w WorkflowComponents) ToRaw() (WorkflowComponentsRaw, error) {
@@ -196,6 +198,17 @@ func UntarWorkflowComponents(ctx context.Context, tr *tar.Reader) (WorkflowCompo
 
 		b := buff.Bytes()
 		switch {
+		case strings.HasPrefix(hdr.Name, CompositeActionsDirectory+"/"):
+			var a CompositeAction
+			if err := UnmarshalStrict(b, format, &a); err != nil {
+				log.Error(ctx, "ExtractWorkflowFromTar> Unable to unmarshal composite action %s: %v", hdr.Name, err)
+				mError.Append(fmt.Errorf("unable to unmarshal composite action %s: %v", hdr.Name, err))
+				continue
+			}
+			if res.Actions == nil {
+				res.Actions = make(map[string]CompositeAction)
+			}
+			res.Actions[hdr.Name] = a
 		case strings.Contains(hdr.Name, ".app."):
 			var app Application
 			if err := Unmarshal(b, format, &app); err != nil {
@@ -1,5 +1,15 @@
 package sdk
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JobMatrixParameterPrefix is the prefix of job's parameters that contains the values
+// of the matrix combination used to generate the job.
+const JobMatrixParameterPrefix = "cds.matrix."
+
 // Job is the element of a stage
 type Job struct {
 	PipelineActionID int64                  `json:"pipeline_action_id"`
@@ -18,3 +28,59 @@ func (j Job) IsValid() error {
 
 	return j.Action.IsValid()
 }
+
+// MatrixValues returns the matrix combination values for a job generated from a matrix, nil otherwise.
+func (j Job) MatrixValues() map[string]string {
+	var res map[string]string
+	for _, p := range j.Action.Parameters {
+		if !strings.HasPrefix(p.Name, JobMatrixParameterPrefix) {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]string)
+		}
+		res[strings.TrimPrefix(p.Name, JobMatrixParameterPrefix)] = p.Value
+	}
+	return res
+}
+
+// MatrixParameters returns the job's parameters that contains matrix values.
+func (j Job) MatrixParameters() []Parameter {
+	var res []Parameter
+	for _, p := range j.Action.Parameters {
+		if strings.HasPrefix(p.Name, JobMatrixParameterPrefix) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// MatrixName returns the name of the job without the matrix combination suffix.
+func (j Job) MatrixName() string {
+	values := j.MatrixValues()
+	if len(values) == 0 {
+		return j.Action.Name
+	}
+	return strings.TrimSuffix(j.Action.Name, " "+matrixSuffix(values))
+}
+
+// NewMatrixJobName returns the name of a job generated for given matrix combination (ex: build (go=1.13, os=linux)).
+func NewMatrixJobName(name string, values map[string]string) string {
+	if len(values) == 0 {
+		return name
+	}
+	return name + " " + matrixSuffix(values)
+}
+
+func matrixSuffix(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	vs := make([]string, len(keys))
+	for i, k := range keys {
+		vs[i] = fmt.Sprintf("%s=%s", k, values[k])
+	}
+	return "(" + strings.Join(vs, ", ") + ")"
+}
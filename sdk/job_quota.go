@@ -0,0 +1,67 @@
+package sdk
+
+import (
+	"strings"
+	"time"
+)
+
+// Job quota types.
+const (
+	JobQuotaTypeProject     = "project"
+	JobQuotaTypeGroup       = "group"
+	JobQuotaTypeWorkerModel = "worker_model"
+)
+
+// JobQuotaTypes is the list of the available job quota types.
+var JobQuotaTypes = []string{JobQuotaTypeProject, JobQuotaTypeGroup, JobQuotaTypeWorkerModel}
+
+// JobQuota limits the number of jobs running at the same time for a project (target is the project key),
+// a group (target is the group name, it applies to the jobs it can execute) or a worker model (target is
+// the model path, ex: shared.infra/debian).
+type JobQuota struct {
+	ID             int64     `json:"id" db:"id"`
+	Type           string    `json:"type" db:"type" cli:"type,key"`
+	Target         string    `json:"target" db:"target" cli:"target,key"`
+	MaxRunningJobs int64     `json:"max_running_jobs" db:"max_running_jobs" cli:"max_running_jobs"`
+	Created        time.Time `json:"created" db:"created"`
+	LastModified   time.Time `json:"last_modified" db:"last_modified"`
+}
+
+// IsValid returns an error if the job quota is not valid.
+func (q JobQuota) IsValid() error {
+	if !IsInArray(q.Type, JobQuotaTypes) {
+		return NewErrorFrom(ErrWrongRequest, "invalid job quota type %q, should be one of %s", q.Type, strings.Join(JobQuotaTypes, ", "))
+	}
+	if q.Target == "" {
+		return NewErrorFrom(ErrWrongRequest, "invalid empty target for job quota")
+	}
+	if q.MaxRunningJobs < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid max running jobs %d for job quota", q.MaxRunningJobs)
+	}
+	return nil
+}
+
+// JobQuotaUsage is the number of running and waiting jobs for a job quota.
+type JobQuotaUsage struct {
+	Type           string `json:"type" cli:"type,key"`
+	Target         string `json:"target" cli:"target,key"`
+	MaxRunningJobs int64  `json:"max_running_jobs" cli:"max_running_jobs"`
+	RunningJobs    int64  `json:"running_jobs" cli:"running_jobs"`
+	WaitingJobs    int64  `json:"waiting_jobs" cli:"waiting_jobs"`
+}
+
+// WorkerModelPathFromRequirements returns the path of the worker model required by a job (ex: shared.infra/debian),
+// a model given without group is a shared.infra model.
+func WorkerModelPathFromRequirements(reqs RequirementList) string {
+	for _, r := range reqs {
+		if r.Type != ModelRequirement {
+			continue
+		}
+		name := strings.Split(r.Value, " ")[0]
+		if !strings.Contains(name, "/") {
+			name = SharedInfraGroupName + "/" + name
+		}
+		return name
+	}
+	return ""
+}
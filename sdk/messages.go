@@ -68,12 +68,20 @@ var (
 	MsgSpawnInfoWorkerForJob               = &Message{"MsgSpawnInfoWorkerForJob", trad{FR: "Ce worker %s a été créé pour lancer ce job", EN: "This worker %s was created to take this action"}, nil, RunInfoTypInfo}
 	MsgSpawnInfoWorkerForJobError          = &Message{"MsgSpawnInfoWorkerForJobError", trad{FR: "⚠ Ce worker %s a été créé pour lancer ce job, mais ne possède pas tous les pré-requis. Vérifiez que les prérequis suivants:%s", EN: "⚠ This worker %s was created to take this action, but does not have all prerequisites. Please verify the following prerequisites:%s"}, nil, RunInfoTypeError}
 	MsgSpawnInfoJobError                   = &Message{"MsgSpawnInfoJobError", trad{FR: "⚠ Impossible de lancer ce job : %s", EN: "⚠ Unable to run this job: %s"}, nil, RunInfoTypInfo}
+	MsgSpawnInfoJobTimeout                 = &Message{"MsgSpawnInfoJobTimeout", trad{FR: "⚠ Le job a été arrêté car il a dépassé son délai d'exécution de %s", EN: "⚠ Job has been stopped because it exceeded its timeout of %s"}, nil, RunInfoTypeError}
+	MsgSpawnInfoJobRetry                   = &Message{"MsgSpawnInfoJobRetry", trad{FR: "⚠ La tentative %v/%v du job a échoué (%s), le job sera relancé dans %s", EN: "⚠ Attempt %v/%v of the job failed (%s), the job will be retried in %s"}, nil, RunInfoTypeWarning}
 	MsgWorkflowStarting                    = &Message{"MsgWorkflowStarting", trad{FR: "Le workflow %s#%s a été démarré", EN: "Workflow %s#%s has been started"}, nil, RunInfoTypInfo}
 	MsgWorkflowError                       = &Message{"MsgWorkflowError", trad{FR: "⚠ Une erreur est survenue: %v", EN: "⚠ An error has occurred: %v"}, nil, RunInfoTypeError}
 	MsgWorkflowConditionError              = &Message{"MsgWorkflowConditionError", trad{FR: "Les conditions de lancement ne sont pas respectées.", EN: "Run conditions aren't ok."}, nil, RunInfoTypInfo}
 	MsgWorkflowNodeStop                    = &Message{"MsgWorkflowNodeStop", trad{FR: "Le pipeline a été arrété par %s", EN: "The pipeline has been stopped by %s"}, nil, RunInfoTypInfo}
 	MsgWorkflowNodeMutex                   = &Message{"MsgWorkflowNodeMutex", trad{FR: "Le pipeline %s est mis en attente tant qu'il est en cours sur un autre run", EN: "The pipeline %s is waiting while it's running on another run"}, nil, RunInfoTypInfo}
 	MsgWorkflowNodeMutexRelease            = &Message{"MsgWorkflowNodeMutexRelease", trad{FR: "Lancement du pipeline %s", EN: "Triggering pipeline %s"}, nil, RunInfoTypInfo}
+	MsgWorkflowNodeLock                    = &Message{"MsgWorkflowNodeLock", trad{FR: "Le pipeline %s est en attente du verrou %s détenu par le workflow %s #%d", EN: "The pipeline %s is waiting for lock %s held by workflow %s #%d"}, nil, RunInfoTypInfo}
+	MsgWorkflowNodeLockRelease             = &Message{"MsgWorkflowNodeLockRelease", trad{FR: "Lancement du pipeline %s, le verrou %s a été libéré", EN: "Triggering pipeline %s, lock %s has been released"}, nil, RunInfoTypInfo}
+	MsgWorkflowNodeApproval                = &Message{"MsgWorkflowNodeApproval", trad{FR: "Le pipeline %s est en attente de %v approbation(s)", EN: "The pipeline %s is waiting for %v approval(s)"}, nil, RunInfoTypInfo}
+	MsgWorkflowNodeApprovalApproved        = &Message{"MsgWorkflowNodeApprovalApproved", trad{FR: "Lancement du pipeline %s, il a été approuvé", EN: "Triggering pipeline %s, it has been approved"}, nil, RunInfoTypInfo}
+	MsgWorkflowNodeApprovalRejected        = &Message{"MsgWorkflowNodeApprovalRejected", trad{FR: "Le pipeline %s a été rejeté par %s", EN: "The pipeline %s has been rejected by %s"}, nil, RunInfoTypeWarning}
+	MsgWorkflowNodeApprovalTimeout         = &Message{"MsgWorkflowNodeApprovalTimeout", trad{FR: "Le pipeline %s a été rejeté car il n'a pas été approuvé dans le délai de %s", EN: "The pipeline %s has been rejected because it was not approved within %s"}, nil, RunInfoTypeWarning}
 	MsgWorkflowImportedUpdated             = &Message{"MsgWorkflowImportedUpdated", trad{FR: "Le workflow %s a été mis à jour", EN: "Workflow %s has been updated"}, nil, RunInfoTypInfo}
 	MsgWorkflowImportedInserted            = &Message{"MsgWorkflowImportedInserted", trad{FR: "Le workflow %s a été créé", EN: "Workflow %s has been created"}, nil, RunInfoTypInfo}
 	MsgSpawnInfoHatcheryCannotStartJob     = &Message{"MsgSpawnInfoHatcheryCannotStart", trad{FR: "Aucune hatchery n'a pu démarrer de worker respectant vos pré-requis de job, merci de les vérifier.", EN: "No hatchery can spawn a worker corresponding your job's requirements. Please check your job's requirements."}, nil, RunInfoTypeWarning}
@@ -88,6 +96,7 @@ var (
 	MsgWorkflowErrorUnknownKey             = &Message{"MsgWorkflowErrorUnknownKey", trad{FR: "La clé '%s' est incorrecte ou n'existe pas", EN: "The key '%s' is incorrect or doesn't exist"}, nil, RunInfoTypeError}
 	MsgWorkflowErrorBadVCSStrategy         = &Message{"MsgWorkflowErrorBadVCSStrategy", trad{FR: "Vos informations vcs_* sont incorrectes", EN: "Your vcs_* fields are incorrects"}, nil, RunInfoTypeError}
 	MsgWorkflowDeprecatedVersion           = &Message{"MsgWorkflowDeprecatedVersion", trad{FR: "La configuration yaml de votre workflow est dans un format déprécié. Exportez le avec la CLI `cdsctl workflow export %s %s`", EN: "The yaml workflow configuration format is deprecated. Export your workflow with CLI `cdsctl workflow export %s %s`"}, nil, RunInfoTypeWarning}
+	MsgWorkflowConditionUnknownOperator    = &Message{"MsgWorkflowConditionUnknownOperator", trad{FR: "L'opérateur inconnu %q de la condition sur la variable %s du pipeline %s est ignoré", EN: "The unknown operator %q of the condition on variable %s of pipeline %s is ignored"}, nil, RunInfoTypeWarning}
 )
 
 // Messages contains all sdk Messages
@@ -140,12 +149,20 @@ var Messages = map[string]*Message{
 	MsgSpawnInfoWorkerForJob.ID:               MsgSpawnInfoWorkerForJob,
 	MsgSpawnInfoWorkerForJobError.ID:          MsgSpawnInfoWorkerForJobError,
 	MsgSpawnInfoJobError.ID:                   MsgSpawnInfoJobError,
+	MsgSpawnInfoJobTimeout.ID:                 MsgSpawnInfoJobTimeout,
+	MsgSpawnInfoJobRetry.ID:                   MsgSpawnInfoJobRetry,
 	MsgWorkflowStarting.ID:                    MsgWorkflowStarting,
 	MsgWorkflowError.ID:                       MsgWorkflowError,
 	MsgWorkflowConditionError.ID:              MsgWorkflowConditionError,
 	MsgWorkflowNodeStop.ID:                    MsgWorkflowNodeStop,
 	MsgWorkflowNodeMutex.ID:                   MsgWorkflowNodeMutex,
 	MsgWorkflowNodeMutexRelease.ID:            MsgWorkflowNodeMutexRelease,
+	MsgWorkflowNodeLock.ID:                    MsgWorkflowNodeLock,
+	MsgWorkflowNodeLockRelease.ID:             MsgWorkflowNodeLockRelease,
+	MsgWorkflowNodeApproval.ID:                MsgWorkflowNodeApproval,
+	MsgWorkflowNodeApprovalApproved.ID:        MsgWorkflowNodeApprovalApproved,
+	MsgWorkflowNodeApprovalRejected.ID:        MsgWorkflowNodeApprovalRejected,
+	MsgWorkflowNodeApprovalTimeout.ID:         MsgWorkflowNodeApprovalTimeout,
 	MsgWorkflowImportedUpdated.ID:             MsgWorkflowImportedUpdated,
 	MsgWorkflowImportedInserted.ID:            MsgWorkflowImportedInserted,
 	MsgSpawnInfoHatcheryCannotStartJob.ID:     MsgSpawnInfoHatcheryCannotStartJob,
@@ -160,6 +177,7 @@ var Messages = map[string]*Message{
 	MsgWorkflowErrorUnknownKey.ID:             MsgWorkflowErrorUnknownKey,
 	MsgWorkflowErrorBadVCSStrategy.ID:         MsgWorkflowErrorBadVCSStrategy,
 	MsgWorkflowDeprecatedVersion.ID:           MsgWorkflowDeprecatedVersion,
+	MsgWorkflowConditionUnknownOperator.ID:    MsgWorkflowConditionUnknownOperator,
 }
 
 //Message represent a struc format translated messages
@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"bytes"
+	"strings"
 	"text/template"
 	"time"
 
@@ -14,8 +15,39 @@ const (
 	EmailUserNotification  = "email"
 	JabberUserNotification = "jabber"
 	VCSUserNotification    = "vcs"
+
+	SlackUserNotification      = "slack"
+	MattermostUserNotification = "mattermost"
+	TeamsUserNotification      = "teams"
 )
 
+// IsChatUserNotification returns true if given notification type is sent to a chat webhook
+func IsChatUserNotification(t string) bool {
+	switch t {
+	case SlackUserNotification, MattermostUserNotification, TeamsUserNotification:
+		return true
+	}
+	return false
+}
+
+// HideNotificationsWebhookURL replaces the webhook urls of the chat notifications by a placeholder, the placeholder
+// is replaced by the stored url when the workflow is updated.
+func (w *Workflow) HideNotificationsWebhookURL() {
+	for i := range w.Notifications {
+		if IsChatUserNotification(w.Notifications[i].Type) && w.Notifications[i].Settings.WebhookURL != "" {
+			w.Notifications[i].Settings.WebhookURL = PasswordPlaceholder
+		}
+	}
+}
+
+// CheckChatWebhookURL returns an error if given chat webhook url is not an http or https url.
+func CheckChatWebhookURL(u string) error {
+	if !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+		return NewErrorFrom(ErrWrongRequest, "chat webhook url must be an http or https url")
+	}
+	return nil
+}
+
 //const
 const (
 	UserNotificationAlways = "always"
@@ -42,6 +74,10 @@ type UserNotificationSettings struct {
 	Recipients   []string                  `json:"recipients,omitempty" yaml:"recipients,omitempty"`
 	Template     *UserNotificationTemplate `json:"template,omitempty" yaml:"template,omitempty"`
 	Conditions   WorkflowNodeConditions    `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+
+	// For chat notifications
+	WebhookURL string `json:"webhook_url,omitempty" yaml:"webhook_url,omitempty"`
+	Channel    string `json:"channel,omitempty" yaml:"channel,omitempty"` // Slack and Mattermost only, default is the webhook channel
 }
 
 // UserNotificationTemplate is the notification content
@@ -80,9 +116,18 @@ Branch : {{.git.branch | default "n/a"}}`,
 		Body:    `{{.cds.buildURL}}`,
 	}
 
+	UserNotificationTemplateChat = UserNotificationTemplate{
+		Subject: "{{.cds.project}}/{{.cds.workflow}}#{{.cds.version}} {{.cds.status}}",
+		Body: `Pipeline {{.cds.node}} on branch {{.git.branch | default "n/a"}} triggered by {{.cds.triggered_by.username}}
+{{.cds.buildURL}}`,
+	}
+
 	UserNotificationTemplateMap = map[string]UserNotificationTemplate{
-		EmailUserNotification:  UserNotificationTemplateEmail,
-		JabberUserNotification: UserNotificationTemplateJabber,
+		EmailUserNotification:      UserNotificationTemplateEmail,
+		JabberUserNotification:     UserNotificationTemplateJabber,
+		SlackUserNotification:      UserNotificationTemplateChat,
+		MattermostUserNotification: UserNotificationTemplateChat,
+		TeamsUserNotification:      UserNotificationTemplateChat,
 		VCSUserNotification: UserNotificationTemplate{
 			Body: DefaultWorkflowNodeRunReport,
 		},
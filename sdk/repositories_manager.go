@@ -120,6 +120,7 @@ type VCSDeleteEvent struct {
 
 //VCSPullRequestEvent represents a push events for polling
 type VCSPullRequestEvent struct {
+	ID     int          `json:"id"`
 	Action string       `json:"action"` // opened | closed
 	URL    string       `json:"url"`
 	Repo   string       `json:"repo"`
@@ -141,6 +142,7 @@ type VCSHook struct {
 	Disable     bool     `json:"disable"`
 	InsecureSSL bool     `json:"insecure_ssl"`
 	Workflow    bool     `json:"workflow"`
+	Secret      string   `json:"secret,omitempty"`
 }
 
 // VCSCommitStatus represents a status on a VCS repository
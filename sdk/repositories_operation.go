@@ -17,6 +17,7 @@ type Operation struct {
 	Status             OperationStatus          `json:"status"`
 	Error              string                   `json:"error,omitempty"`
 	RepositoryInfo     *OperationRepositoryInfo `json:"repository_info,omitempty"`
+	Timings            *OperationTimings        `json:"timings,omitempty"`
 	Date               *time.Time               `json:"date,omitempty"`
 	User               struct {
 		Username string `json:"username"  db:"-" cli:"-"`
@@ -38,6 +39,13 @@ type OperationRepositoryInfo struct {
 	DefaultBranch string `json:"default_branch,omitempty"`
 }
 
+// OperationTimings contains the durations in milliseconds of the git commands run for an operation
+type OperationTimings struct {
+	Clone    int64 `json:"clone_ms,omitempty"`
+	Fetch    int64 `json:"fetch_ms,omitempty"`
+	Checkout int64 `json:"checkout_ms,omitempty"`
+}
+
 // OperationLoadFiles represents files loading from a globbing pattern
 type OperationLoadFiles struct {
 	Pattern string            `json:"pattern,omitempty"`
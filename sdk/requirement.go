@@ -3,6 +3,8 @@ package sdk
 import (
 	"context"
 	"net"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -21,6 +23,10 @@ const (
 	ServiceRequirement = "service"
 	//MemoryRequirement set memory limit on a container
 	MemoryRequirement = "memory"
+	// CPURequirement set the number of CPUs (ex: 2 or 0.5) requested and limited for a container
+	CPURequirement = "cpu"
+	// ResourceClassRequirement refers to an admin-defined resource class, replaced by its cpu and memory requirements when the job is queued
+	ResourceClassRequirement = "resource-class"
 	// VolumeRequirement set Volume limit on a container
 	VolumeRequirement = "volume"
 	// OSArchRequirement checks the 'dist' of a worker eg {GOOS}/{GOARCH}
@@ -66,13 +72,20 @@ func (l RequirementList) IsValid() error {
 	}
 
 	// check that only one model requirement and hostname exists
-	nbModel, nbHostname := 0, 0
+	nbModel, nbHostname, nbCPU, nbResourceClass := 0, 0, 0, 0
 	for i := range l {
 		switch l[i].Type {
 		case ModelRequirement:
 			nbModel++
 		case HostnameRequirement:
 			nbHostname++
+		case CPURequirement:
+			nbCPU++
+			if _, err := ParseCPURequirement(l[i].Value); err != nil {
+				return err
+			}
+		case ResourceClassRequirement:
+			nbResourceClass++
 		}
 	}
 	if nbModel > 1 {
@@ -81,10 +94,29 @@ func (l RequirementList) IsValid() error {
 	if nbHostname > 1 {
 		return WithStack(ErrInvalidJobRequirementDuplicateHostname)
 	}
+	if nbCPU > 1 {
+		return NewErrorFrom(ErrInvalidJobRequirement, "only one cpu requirement is allowed")
+	}
+	if nbResourceClass > 1 {
+		return NewErrorFrom(ErrInvalidJobRequirement, "only one resource class requirement is allowed")
+	}
 
 	return nil
 }
 
+// ParseCPURequirement returns the number of CPUs given in a cpu requirement value. A value that
+// contains a variable is only checked when the job is queued, 0 is returned for it.
+func ParseCPURequirement(value string) (float64, error) {
+	if strings.Contains(value, "{{") {
+		return 0, nil
+	}
+	cpu, err := strconv.ParseFloat(value, 64)
+	if err != nil || cpu <= 0 {
+		return 0, NewErrorFrom(ErrInvalidJobRequirement, "invalid cpu requirement %q, should be a positive number of CPUs", value)
+	}
+	return cpu, nil
+}
+
 var (
 	// AvailableRequirementsType List of all requirements
 	AvailableRequirementsType = []string{
@@ -95,6 +127,8 @@ var (
 		PluginRequirement,
 		ServiceRequirement,
 		MemoryRequirement,
+		CPURequirement,
+		ResourceClassRequirement,
 		VolumeRequirement,
 		OSArchRequirement,
 	}
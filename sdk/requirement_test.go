@@ -100,3 +100,53 @@ func TestRequirementListDeduplicate(t *testing.T) {
 		})
 	}
 }
+
+func TestRequirementListIsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		l       RequirementList
+		wantErr bool
+	}{
+		{
+			name: "cpu",
+			l:    RequirementList{{Name: "cpu", Type: CPURequirement, Value: "0.5"}},
+		},
+		{
+			name: "cpu with variable",
+			l:    RequirementList{{Name: "cpu", Type: CPURequirement, Value: "{{.cds.env.cpu}}"}},
+		},
+		{
+			name:    "invalid cpu",
+			l:       RequirementList{{Name: "cpu", Type: CPURequirement, Value: "two"}},
+			wantErr: true,
+		},
+		{
+			name:    "negative cpu",
+			l:       RequirementList{{Name: "cpu", Type: CPURequirement, Value: "-1"}},
+			wantErr: true,
+		},
+		{
+			name: "two cpu",
+			l: RequirementList{
+				{Name: "cpu", Type: CPURequirement, Value: "1"},
+				{Name: "cpu2", Type: CPURequirement, Value: "2"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "two resource classes",
+			l: RequirementList{
+				{Name: "small", Type: ResourceClassRequirement, Value: "small"},
+				{Name: "large", Type: ResourceClassRequirement, Value: "large"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.l.IsValid(); (err != nil) != tt.wantErr {
+				t.Errorf("RequirementList.IsValid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package sdk
+
+import (
+	"strconv"
+	"time"
+)
+
+// ResourceClass is an admin-defined set of resources (ex: small, large) that a job can require with
+// a resource-class requirement instead of giving its cpu and memory requirements.
+type ResourceClass struct {
+	ID           int64     `json:"id" db:"id"`
+	Name         string    `json:"name" db:"name" cli:"name,key"`
+	Description  string    `json:"description" db:"description" cli:"description"`
+	CPU          float64   `json:"cpu" db:"cpu" cli:"cpu"`
+	Memory       int64     `json:"memory" db:"memory" cli:"memory"`
+	Created      time.Time `json:"created" db:"created"`
+	LastModified time.Time `json:"last_modified" db:"last_modified"`
+}
+
+// IsValid returns an error if the resource class is not valid.
+func (c ResourceClass) IsValid() error {
+	if !NamePatternRegex.MatchString(c.Name) {
+		return NewErrorFrom(ErrWrongRequest, "invalid resource class name %q, should match %s", c.Name, NamePattern)
+	}
+	if c.CPU <= 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid cpu %v for resource class, should be a positive number of CPUs", c.CPU)
+	}
+	if c.Memory <= 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid memory %d for resource class, should be a positive number of megabytes", c.Memory)
+	}
+	return nil
+}
+
+// Requirements returns the cpu and memory requirements of the resource class.
+func (c ResourceClass) Requirements() RequirementList {
+	return RequirementList{
+		{Name: "cpu", Type: CPURequirement, Value: strconv.FormatFloat(c.CPU, 'f', -1, 64)},
+		{Name: "memory", Type: MemoryRequirement, Value: strconv.FormatInt(c.Memory, 10)},
+	}
+}
@@ -112,6 +112,11 @@ func (m Model) IsValidType() error {
 		if m.PatternName == "" && (m.ModelDocker.Cmd == "" || m.ModelDocker.Shell == "") {
 			return WrapError(ErrWrongRequest, "invalid worker model command or shell command")
 		}
+		if m.ModelDocker.PodTemplate != nil {
+			if err := m.ModelDocker.PodTemplate.IsValid(); err != nil {
+				return err
+			}
+		}
 	case Openstack:
 		if m.ModelVirtualMachine.Image == "" {
 			return WrapError(ErrWrongRequest, "invalid worker model image")
@@ -163,6 +168,8 @@ type ModelDocker struct {
 	Envs     map[string]string `json:"envs,omitempty"`
 	Shell    string            `json:"shell,omitempty"`
 	Cmd      string            `json:"cmd,omitempty"`
+	// PodTemplate is only used by the kubernetes hatchery, it can be set only by an administrator
+	PodTemplate *ModelPodTemplate `json:"pod_template,omitempty"`
 }
 
 // ModelPattern represent patterns for users and admin when creating a worker model
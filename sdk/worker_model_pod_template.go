@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"path"
+	"regexp"
+)
+
+var podTemplateVolumeNameRegexp = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+
+// ModelPodTemplate overrides the pod spec of the workers spawned by the kubernetes hatchery for a docker model.
+type ModelPodTemplate struct {
+	NodeSelector       map[string]string    `json:"node_selector,omitempty" yaml:"node_selector,omitempty"`
+	Tolerations        []ModelPodToleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+	ServiceAccountName string               `json:"service_account_name,omitempty" yaml:"service_account_name,omitempty"`
+	Annotations        map[string]string    `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Volumes            []ModelPodVolume     `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+}
+
+// ModelPodToleration allows the worker pods to be scheduled on nodes with a matching taint.
+type ModelPodToleration struct {
+	Key      string `json:"key,omitempty" yaml:"key,omitempty"`
+	Operator string `json:"operator,omitempty" yaml:"operator,omitempty"`
+	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect   string `json:"effect,omitempty" yaml:"effect,omitempty"`
+}
+
+// ModelPodVolume is a volume mounted in the worker container. Its source is a host path, a config map,
+// a secret, a persistent volume claim or an empty dir.
+type ModelPodVolume struct {
+	Name                  string `json:"name" yaml:"name"`
+	MountPath             string `json:"mount_path" yaml:"mount_path"`
+	ReadOnly              bool   `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+	HostPath              string `json:"host_path,omitempty" yaml:"host_path,omitempty"`
+	ConfigMap             string `json:"config_map,omitempty" yaml:"config_map,omitempty"`
+	Secret                string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	PersistentVolumeClaim string `json:"persistent_volume_claim,omitempty" yaml:"persistent_volume_claim,omitempty"`
+	EmptyDir              bool   `json:"empty_dir,omitempty" yaml:"empty_dir,omitempty"`
+}
+
+// IsValid returns an error if the pod template is not valid.
+func (t ModelPodTemplate) IsValid() error {
+	for _, tol := range t.Tolerations {
+		if tol.Operator != "" && tol.Operator != "Exists" && tol.Operator != "Equal" {
+			return NewErrorFrom(ErrWrongRequest, "invalid toleration operator %q, should be Exists or Equal", tol.Operator)
+		}
+		if tol.Effect != "" && tol.Effect != "NoSchedule" && tol.Effect != "PreferNoSchedule" && tol.Effect != "NoExecute" {
+			return NewErrorFrom(ErrWrongRequest, "invalid toleration effect %q, should be NoSchedule, PreferNoSchedule or NoExecute", tol.Effect)
+		}
+	}
+
+	names := make(map[string]struct{}, len(t.Volumes))
+	for _, v := range t.Volumes {
+		if !podTemplateVolumeNameRegexp.MatchString(v.Name) {
+			return NewErrorFrom(ErrWrongRequest, "invalid volume name %q, should match %s", v.Name, podTemplateVolumeNameRegexp.String())
+		}
+		if _, ok := names[v.Name]; ok {
+			return NewErrorFrom(ErrWrongRequest, "duplicate volume name %q", v.Name)
+		}
+		names[v.Name] = struct{}{}
+		if !path.IsAbs(v.MountPath) {
+			return NewErrorFrom(ErrWrongRequest, "invalid mount path %q for volume %s, should be an absolute path", v.MountPath, v.Name)
+		}
+
+		var sources int
+		for _, s := range []bool{v.HostPath != "", v.ConfigMap != "", v.Secret != "", v.PersistentVolumeClaim != "", v.EmptyDir} {
+			if s {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return NewErrorFrom(ErrWrongRequest, "volume %s should have one source: host_path, config_map, secret, persistent_volume_claim or empty_dir", v.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelPodTemplateIsValid(t *testing.T) {
+	tmpl := ModelPodTemplate{
+		Tolerations: []ModelPodToleration{{Key: "dedicated", Operator: "Equal", Value: "cds", Effect: "NoSchedule"}},
+		Volumes: []ModelPodVolume{
+			{Name: "cache", MountPath: "/cache", EmptyDir: true},
+			{Name: "docker-sock", MountPath: "/var/run/docker.sock", HostPath: "/var/run/docker.sock"},
+		},
+	}
+	assert.NoError(t, tmpl.IsValid())
+
+	tmpl.Tolerations[0].Operator = "Like"
+	assert.Error(t, tmpl.IsValid())
+	tmpl.Tolerations[0].Operator = "Exists"
+
+	tmpl.Volumes[1].Name = "cache"
+	assert.Error(t, tmpl.IsValid(), "duplicate volume name")
+	tmpl.Volumes[1].Name = "Docker_Sock"
+	assert.Error(t, tmpl.IsValid(), "invalid volume name")
+	tmpl.Volumes[1].Name = "docker-sock"
+
+	tmpl.Volumes[0].MountPath = "cache"
+	assert.Error(t, tmpl.IsValid(), "relative mount path")
+	tmpl.Volumes[0].MountPath = "/cache"
+
+	tmpl.Volumes[0].Secret = "my-secret"
+	assert.Error(t, tmpl.IsValid(), "two sources")
+}
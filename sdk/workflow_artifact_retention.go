@@ -0,0 +1,108 @@
+package sdk
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// WorkflowArtifactRetention is the retention policy applied by the purge to the artifacts of a workflow,
+// independently of the run history. An artifact is kept if one of the rules matches, others are deleted.
+type WorkflowArtifactRetention struct {
+	// KeepLastPerBranch keeps the artifacts of the last runs with artifacts of each branch
+	KeepLastPerBranch int64 `json:"keep_last_per_branch,omitempty" yaml:"keep_last_per_branch,omitempty"`
+	// KeepDays keeps the artifacts uploaded since given number of days
+	KeepDays int64 `json:"keep_days,omitempty" yaml:"keep_days,omitempty"`
+	// KeepTags keeps the artifacts of the runs with one of the tags, given as name=value where value can be a pattern (ex: environment=prod*)
+	KeepTags []string `json:"keep_tags,omitempty" yaml:"keep_tags,omitempty"`
+	// KeepReleases keeps forever the artifacts built from a git tag
+	KeepReleases bool `json:"keep_releases,omitempty" yaml:"keep_releases,omitempty"`
+	// DryRun only reports the artifacts that would be deleted
+	DryRun bool `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+}
+
+// IsValid returns an error if the artifact retention policy is not valid.
+func (r WorkflowArtifactRetention) IsValid() error {
+	if r.KeepLastPerBranch < 0 {
+		return NewErrorFrom(ErrWorkflowInvalid, "invalid artifact retention keep_last_per_branch %d", r.KeepLastPerBranch)
+	}
+	if r.KeepDays < 0 {
+		return NewErrorFrom(ErrWorkflowInvalid, "invalid artifact retention keep_days %d", r.KeepDays)
+	}
+	for _, t := range r.KeepTags {
+		name, value, ok := splitRetentionTag(t)
+		if !ok || name == "" {
+			return NewErrorFrom(ErrWorkflowInvalid, "invalid artifact retention tag %q, should be name=value", t)
+		}
+		if _, err := path.Match(value, ""); err != nil {
+			return NewErrorFrom(ErrWorkflowInvalid, "invalid artifact retention tag pattern %q", t)
+		}
+	}
+	// a policy without rule would delete all the artifacts
+	if r.KeepLastPerBranch == 0 && r.KeepDays == 0 && len(r.KeepTags) == 0 && !r.KeepReleases {
+		return NewErrorFrom(ErrWorkflowInvalid, "artifact retention should have at least one keep rule")
+	}
+	return nil
+}
+
+// MatchTag returns the keep tag matching one of given run tags.
+func (r WorkflowArtifactRetention) MatchTag(tags []WorkflowRunTag) (string, bool) {
+	for _, t := range r.KeepTags {
+		name, value, _ := splitRetentionTag(t)
+		for _, tag := range tags {
+			if tag.Tag != name {
+				continue
+			}
+			if ok, _ := path.Match(value, tag.Value); ok {
+				return t, true
+			}
+		}
+	}
+	return "", false
+}
+
+func splitRetentionTag(t string) (string, string, bool) {
+	i := strings.Index(t, "=")
+	if i < 0 {
+		return "", "", false
+	}
+	return t[:i], t[i+1:], true
+}
+
+// WorkflowArtifactRetentionItem is an artifact of a workflow with the result of the retention policy.
+type WorkflowArtifactRetentionItem struct {
+	ArtifactID    int64            `json:"artifact_id" cli:"-"`
+	Name          string           `json:"name" cli:"name"`
+	Tag           string           `json:"tag" cli:"tag"`
+	Size          int64            `json:"size" cli:"size"`
+	Created       time.Time        `json:"created" cli:"created"`
+	WorkflowRunID int64            `json:"workflow_run_id" cli:"-"`
+	RunNumber     int64            `json:"run_number" cli:"run"`
+	RunStatus     string           `json:"run_status" cli:"-"`
+	Branch        string           `json:"branch" cli:"branch"`
+	GitTag        string           `json:"git_tag,omitempty" cli:"git_tag"`
+	RunTags       []WorkflowRunTag `json:"run_tags,omitempty" cli:"-"`
+	Keep          bool             `json:"keep" cli:"keep"`
+	Reason        string           `json:"reason,omitempty" cli:"reason"`
+}
+
+// WorkflowArtifactRetentionReport lists the artifacts of a workflow that are kept or deleted by its retention policy.
+type WorkflowArtifactRetentionReport struct {
+	ProjectKey   string                          `json:"project_key"`
+	WorkflowName string                          `json:"workflow_name"`
+	Retention    WorkflowArtifactRetention       `json:"retention"`
+	Items        []WorkflowArtifactRetentionItem `json:"items"`
+}
+
+// DeletedSize returns the number and the total size of the artifacts to delete.
+func (r WorkflowArtifactRetentionReport) DeletedSize() (int, int64) {
+	var n int
+	var size int64
+	for _, i := range r.Items {
+		if !i.Keep {
+			n++
+			size += i.Size
+		}
+	}
+	return n, size
+}
@@ -3,35 +3,72 @@ package sdk
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/blang/semver"
 
 	"github.com/ovh/cds/sdk/interpolate"
 )
 
 // WorkflowData conditions operator
 const (
-	WorkflowConditionsOperatorEquals             = "eq"
-	WorkflowConditionsOperatorNotEquals          = "ne"
-	WorkflowConditionsOperatorLessThan           = "lt"
-	WorkflowConditionsOperatorLessOrEqualThan    = "le"
-	WorkflowConditionsOperatorGreaterThan        = "gt"
-	WorkflowConditionsOperatorGreaterOrEqualThan = "ge"
-	WorkflowConditionsOperatorRegex              = "regex"
+	WorkflowConditionsOperatorEquals                   = "eq"
+	WorkflowConditionsOperatorNotEquals                = "ne"
+	WorkflowConditionsOperatorLessThan                 = "lt"
+	WorkflowConditionsOperatorLessOrEqualThan          = "le"
+	WorkflowConditionsOperatorGreaterThan              = "gt"
+	WorkflowConditionsOperatorGreaterOrEqualThan       = "ge"
+	WorkflowConditionsOperatorRegex                    = "regex"
+	WorkflowConditionsOperatorIn                       = "in"
+	WorkflowConditionsOperatorNotIn                    = "not_in"
+	WorkflowConditionsOperatorContains                 = "contains"
+	WorkflowConditionsOperatorNumberLessThan           = "lt_number"
+	WorkflowConditionsOperatorNumberLessOrEqualThan    = "le_number"
+	WorkflowConditionsOperatorNumberGreaterThan        = "gt_number"
+	WorkflowConditionsOperatorNumberGreaterOrEqualThan = "ge_number"
+	WorkflowConditionsOperatorSemverLessThan           = "lt_semver"
+	WorkflowConditionsOperatorSemverLessOrEqualThan    = "le_semver"
+	WorkflowConditionsOperatorSemverGreaterThan        = "gt_semver"
+	WorkflowConditionsOperatorSemverGreaterOrEqualThan = "ge_semver"
+	WorkflowConditionsOperatorDateLessThan             = "lt_date"
+	WorkflowConditionsOperatorDateLessOrEqualThan      = "le_date"
+	WorkflowConditionsOperatorDateGreaterThan          = "gt_date"
+	WorkflowConditionsOperatorDateGreaterOrEqualThan   = "ge_date"
 )
 
 // WorkflowData conditions operator
 var (
 	WorkflowConditionsOperators = map[string]string{
-		WorkflowConditionsOperatorEquals:             "=",
-		WorkflowConditionsOperatorNotEquals:          "!=",
-		WorkflowConditionsOperatorLessThan:           "<",
-		WorkflowConditionsOperatorLessOrEqualThan:    "<=",
-		WorkflowConditionsOperatorGreaterThan:        ">",
-		WorkflowConditionsOperatorGreaterOrEqualThan: ">=",
-		WorkflowConditionsOperatorRegex:              "match",
+		WorkflowConditionsOperatorEquals:                   "=",
+		WorkflowConditionsOperatorNotEquals:                "!=",
+		WorkflowConditionsOperatorLessThan:                 "<",
+		WorkflowConditionsOperatorLessOrEqualThan:          "<=",
+		WorkflowConditionsOperatorGreaterThan:              ">",
+		WorkflowConditionsOperatorGreaterOrEqualThan:       ">=",
+		WorkflowConditionsOperatorRegex:                    "match",
+		WorkflowConditionsOperatorIn:                       "in",
+		WorkflowConditionsOperatorNotIn:                    "not in",
+		WorkflowConditionsOperatorContains:                 "contains",
+		WorkflowConditionsOperatorNumberLessThan:           "< (number)",
+		WorkflowConditionsOperatorNumberLessOrEqualThan:    "<= (number)",
+		WorkflowConditionsOperatorNumberGreaterThan:        "> (number)",
+		WorkflowConditionsOperatorNumberGreaterOrEqualThan: ">= (number)",
+		WorkflowConditionsOperatorSemverLessThan:           "< (semver)",
+		WorkflowConditionsOperatorSemverLessOrEqualThan:    "<= (semver)",
+		WorkflowConditionsOperatorSemverGreaterThan:        "> (semver)",
+		WorkflowConditionsOperatorSemverGreaterOrEqualThan: ">= (semver)",
+		WorkflowConditionsOperatorDateLessThan:             "< (date)",
+		WorkflowConditionsOperatorDateLessOrEqualThan:      "<= (date)",
+		WorkflowConditionsOperatorDateGreaterThan:          "> (date)",
+		WorkflowConditionsOperatorDateGreaterOrEqualThan:   ">= (date)",
 	}
 )
 
+// Supported formats for values compared with date operators.
+var workflowConditionsDateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
 //WorkflowCheckConditions checks conditions given a list of parameters
 func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parameter) (bool, error) {
 	if len(conditions) == 0 {
@@ -54,33 +91,139 @@ func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parame
 			return false, fmt.Errorf("Unable to interpolate %s (%v)", cond.Value, err)
 		}
 
-		switch cond.Operator {
-		case WorkflowConditionsOperatorEquals:
-			conditionsOK = conditionsOK && cond.Value == mapParams[cond.Variable]
+		ok, err := workflowCheckCondition(cond.Operator, mapParams[cond.Variable], cond.Value)
+		if err != nil {
+			return false, err
+		}
+		conditionsOK = conditionsOK && ok
+	}
 
-		case WorkflowConditionsOperatorNotEquals:
-			conditionsOK = conditionsOK && cond.Value != mapParams[cond.Variable]
+	return conditionsOK, nil
+}
+
+// workflowCheckCondition compares given variable value with the condition value for given operator.
+// If the variable value can't be parsed for a typed operator, the condition is not satisfied. An error
+// is returned only if the condition value is invalid.
+func workflowCheckCondition(operator, variable, value string) (bool, error) {
+	switch operator {
+	case WorkflowConditionsOperatorEquals:
+		return value == variable, nil
+
+	case WorkflowConditionsOperatorNotEquals:
+		return value != variable, nil
 
-		case WorkflowConditionsOperatorLessThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) < 0
+	case WorkflowConditionsOperatorLessThan:
+		return strings.Compare(variable, value) < 0, nil
 
-		case WorkflowConditionsOperatorLessOrEqualThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) <= 0
+	case WorkflowConditionsOperatorLessOrEqualThan:
+		return strings.Compare(variable, value) <= 0, nil
 
-		case WorkflowConditionsOperatorGreaterThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) > 0
+	case WorkflowConditionsOperatorGreaterThan:
+		return strings.Compare(variable, value) > 0, nil
 
-		case WorkflowConditionsOperatorGreaterOrEqualThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) >= 0
+	case WorkflowConditionsOperatorGreaterOrEqualThan:
+		return strings.Compare(variable, value) >= 0, nil
 
-		case WorkflowConditionsOperatorRegex:
-			match, err := regexp.MatchString(cond.Value, mapParams[cond.Variable])
-			if err != nil {
-				return false, fmt.Errorf("Unable to match string with regex %s (%v)", cond.Value, err)
-			}
-			conditionsOK = conditionsOK && match
+	case WorkflowConditionsOperatorRegex:
+		match, err := regexp.MatchString(value, variable)
+		if err != nil {
+			return false, fmt.Errorf("Unable to match string with regex %s (%v)", value, err)
 		}
+		return match, nil
+
+	case WorkflowConditionsOperatorIn:
+		return IsInArray(variable, workflowConditionsSplitList(value)), nil
+
+	case WorkflowConditionsOperatorNotIn:
+		return !IsInArray(variable, workflowConditionsSplitList(value)), nil
+
+	case WorkflowConditionsOperatorContains:
+		return strings.Contains(variable, value), nil
+
+	case WorkflowConditionsOperatorNumberLessThan, WorkflowConditionsOperatorNumberLessOrEqualThan,
+		WorkflowConditionsOperatorNumberGreaterThan, WorkflowConditionsOperatorNumberGreaterOrEqualThan:
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return false, fmt.Errorf("Unable to compare with %s, invalid number", value)
+		}
+		x, err := strconv.ParseFloat(strings.TrimSpace(variable), 64)
+		if err != nil {
+			return false, nil
+		}
+		var res int
+		if x < v {
+			res = -1
+		} else if x > v {
+			res = 1
+		}
+		return workflowCheckComparison(operator, res), nil
+
+	case WorkflowConditionsOperatorSemverLessThan, WorkflowConditionsOperatorSemverLessOrEqualThan,
+		WorkflowConditionsOperatorSemverGreaterThan, WorkflowConditionsOperatorSemverGreaterOrEqualThan:
+		v, err := semver.ParseTolerant(value)
+		if err != nil {
+			return false, fmt.Errorf("Unable to compare with %s, invalid semantic version", value)
+		}
+		x, err := semver.ParseTolerant(variable)
+		if err != nil {
+			return false, nil
+		}
+		return workflowCheckComparison(operator, x.Compare(v)), nil
+
+	case WorkflowConditionsOperatorDateLessThan, WorkflowConditionsOperatorDateLessOrEqualThan,
+		WorkflowConditionsOperatorDateGreaterThan, WorkflowConditionsOperatorDateGreaterOrEqualThan:
+		v, err := workflowConditionsParseDate(value)
+		if err != nil {
+			return false, fmt.Errorf("Unable to compare with %s, invalid date", value)
+		}
+		x, err := workflowConditionsParseDate(variable)
+		if err != nil {
+			return false, nil
+		}
+		var res int
+		if x.Before(v) {
+			res = -1
+		} else if x.After(v) {
+			res = 1
+		}
+		return workflowCheckComparison(operator, res), nil
 	}
 
-	return conditionsOK, nil
+	return true, nil
+}
+
+// workflowCheckComparison returns the result of a typed comparison for given operator and compare result (-1, 0 or 1).
+func workflowCheckComparison(operator string, res int) bool {
+	switch operator {
+	case WorkflowConditionsOperatorNumberLessThan, WorkflowConditionsOperatorSemverLessThan, WorkflowConditionsOperatorDateLessThan:
+		return res < 0
+	case WorkflowConditionsOperatorNumberLessOrEqualThan, WorkflowConditionsOperatorSemverLessOrEqualThan, WorkflowConditionsOperatorDateLessOrEqualThan:
+		return res <= 0
+	case WorkflowConditionsOperatorNumberGreaterThan, WorkflowConditionsOperatorSemverGreaterThan, WorkflowConditionsOperatorDateGreaterThan:
+		return res > 0
+	case WorkflowConditionsOperatorNumberGreaterOrEqualThan, WorkflowConditionsOperatorSemverGreaterOrEqualThan, WorkflowConditionsOperatorDateGreaterOrEqualThan:
+		return res >= 0
+	}
+	return false
+}
+
+// workflowConditionsSplitList returns values of a comma separated list.
+func workflowConditionsSplitList(value string) []string {
+	values := strings.Split(value, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
+
+func workflowConditionsParseDate(value string) (time.Time, error) {
+	var err error
+	for _, layout := range workflowConditionsDateLayouts {
+		var t time.Time
+		t, err = time.Parse(layout, strings.TrimSpace(value))
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
 }
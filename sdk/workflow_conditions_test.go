@@ -0,0 +1,52 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowCheckConditions(t *testing.T) {
+	params := []Parameter{
+		{Name: "cds.version", Type: StringParameter, Value: "10"},
+		{Name: "git.tag", Type: StringParameter, Value: "v1.10.0"},
+		{Name: "git.branch", Type: StringParameter, Value: "feat/my-feature"},
+		{Name: "cds.date", Type: StringParameter, Value: "2020-03-10"},
+	}
+
+	tests := []struct {
+		name      string
+		condition WorkflowNodeCondition
+		want      bool
+		wantErr   bool
+	}{
+		{"string gt", WorkflowNodeCondition{"cds.version", WorkflowConditionsOperatorGreaterThan, "9"}, false, false},
+		{"number gt", WorkflowNodeCondition{"cds.version", WorkflowConditionsOperatorNumberGreaterThan, "9"}, true, false},
+		{"number le", WorkflowNodeCondition{"cds.version", WorkflowConditionsOperatorNumberLessOrEqualThan, "10.0"}, true, false},
+		{"number invalid value", WorkflowNodeCondition{"cds.version", WorkflowConditionsOperatorNumberLessThan, "ten"}, false, true},
+		{"number invalid variable", WorkflowNodeCondition{"git.branch", WorkflowConditionsOperatorNumberLessThan, "10"}, false, false},
+		{"semver gt", WorkflowNodeCondition{"git.tag", WorkflowConditionsOperatorSemverGreaterThan, "v1.9.0"}, true, false},
+		{"semver lt", WorkflowNodeCondition{"git.tag", WorkflowConditionsOperatorSemverLessThan, "1.9.0"}, false, false},
+		{"semver ge", WorkflowNodeCondition{"git.tag", WorkflowConditionsOperatorSemverGreaterOrEqualThan, "1.10.0"}, true, false},
+		{"semver invalid value", WorkflowNodeCondition{"git.tag", WorkflowConditionsOperatorSemverGreaterThan, "latest"}, false, true},
+		{"date lt", WorkflowNodeCondition{"cds.date", WorkflowConditionsOperatorDateLessThan, "2020-03-11T00:00:00Z"}, true, false},
+		{"date ge", WorkflowNodeCondition{"cds.date", WorkflowConditionsOperatorDateGreaterOrEqualThan, "2020-03-11"}, false, false},
+		{"date invalid value", WorkflowNodeCondition{"cds.date", WorkflowConditionsOperatorDateGreaterThan, "yesterday"}, false, true},
+		{"in", WorkflowNodeCondition{"cds.version", WorkflowConditionsOperatorIn, "9, 10, 11"}, true, false},
+		{"not in", WorkflowNodeCondition{"cds.version", WorkflowConditionsOperatorNotIn, "9,10"}, false, false},
+		{"contains", WorkflowNodeCondition{"git.branch", WorkflowConditionsOperatorContains, "feat/"}, true, false},
+		{"regex", WorkflowNodeCondition{"git.branch", WorkflowConditionsOperatorRegex, "^feat/.*"}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WorkflowCheckConditions([]WorkflowNodeCondition{tt.condition}, params)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -41,20 +41,24 @@ func (n Node) GetHook(UUID string) *NodeHook {
 
 // NodeContext represents a node linked to a pipeline
 type NodeContext struct {
-	ID                        int64                  `json:"id" db:"id"`
-	NodeID                    int64                  `json:"node_id" db:"node_id"`
-	PipelineID                int64                  `json:"pipeline_id" db:"pipeline_id"`
-	PipelineName              string                 `json:"-" db:"-"`
-	ApplicationID             int64                  `json:"application_id" db:"application_id"`
-	ApplicationName           string                 `json:"-" db:"-"`
-	EnvironmentID             int64                  `json:"environment_id" db:"environment_id"`
-	EnvironmentName           string                 `json:"-" db:"-"`
-	ProjectIntegrationID      int64                  `json:"project_integration_id" db:"project_integration_id"`
-	ProjectIntegrationName    string                 `json:"-" db:"-"`
-	DefaultPayload            interface{}            `json:"default_payload,omitempty" db:"-"`
-	DefaultPipelineParameters []Parameter            `json:"default_pipeline_parameters" db:"-"`
-	Conditions                WorkflowNodeConditions `json:"conditions" db:"-"`
-	Mutex                     bool                   `json:"mutex" db:"mutex"`
+	ID                        int64                           `json:"id" db:"id"`
+	NodeID                    int64                           `json:"node_id" db:"node_id"`
+	PipelineID                int64                           `json:"pipeline_id" db:"pipeline_id"`
+	PipelineName              string                          `json:"-" db:"-"`
+	ApplicationID             int64                           `json:"application_id" db:"application_id"`
+	ApplicationName           string                          `json:"-" db:"-"`
+	EnvironmentID             int64                           `json:"environment_id" db:"environment_id"`
+	EnvironmentName           string                          `json:"-" db:"-"`
+	ProjectIntegrationID      int64                           `json:"project_integration_id" db:"project_integration_id"`
+	ProjectIntegrationName    string                          `json:"-" db:"-"`
+	DefaultPayload            interface{}                     `json:"default_payload,omitempty" db:"-"`
+	DefaultPipelineParameters []Parameter                     `json:"default_pipeline_parameters" db:"-"`
+	Conditions                WorkflowNodeConditions          `json:"conditions" db:"-"`
+	Mutex                     bool                            `json:"mutex" db:"mutex"`
+	Lock                      string                          `json:"lock,omitempty" db:"lock_name"`
+	Approval                  *WorkflowNodeApproval           `json:"approval,omitempty" db:"-"`
+	PreviewEnvironment        *WorkflowNodePreviewEnvironment `json:"preview_environment,omitempty" db:"-"`
+	Priority                  string                          `json:"priority,omitempty" db:"priority"`
 }
 
 // FilterHooksConfig filter all hooks configuration and remove somme configuration key
@@ -0,0 +1,50 @@
+package sdk
+
+import (
+	"strings"
+	"time"
+)
+
+// Priorities of the jobs in the queue.
+const (
+	JobPriorityHigh   = "high"
+	JobPriorityNormal = "normal"
+	JobPriorityLow    = "low"
+)
+
+// JobPriorities is the list of the available job priorities.
+var JobPriorities = []string{JobPriorityHigh, JobPriorityNormal, JobPriorityLow}
+
+// JobPriorityParameter is the build parameter that holds the priority of a node run.
+const JobPriorityParameter = "cds.priority"
+
+// JobPriorityAgingDelay is the time after which a job waiting in the queue is raised to the next priority level,
+// low priority jobs are then not starved.
+const JobPriorityAgingDelay = 30 * time.Minute
+
+// IsValidJobPriority returns an error if given priority is invalid, an empty priority is normal.
+func IsValidJobPriority(priority string) error {
+	if priority != "" && !IsInArray(priority, JobPriorities) {
+		return NewErrorFrom(ErrWrongRequest, "invalid priority %q, should be one of %s", priority, strings.Join(JobPriorities, ", "))
+	}
+	return nil
+}
+
+// PriorityLevel returns the level of the job in the queue at given time, 0 is the highest. The level of the priority
+// is raised by one for each JobPriorityAgingDelay the job waited in the queue.
+func (wnjr WorkflowNodeJobRun) PriorityLevel(now time.Time) int {
+	level := 1
+	switch wnjr.Priority {
+	case JobPriorityHigh:
+		level = 0
+	case JobPriorityLow:
+		level = 2
+	}
+	if wait := now.Sub(wnjr.Queued); wait > 0 {
+		level -= int(wait / JobPriorityAgingDelay)
+	}
+	if level < 0 {
+		return 0
+	}
+	return level
+}
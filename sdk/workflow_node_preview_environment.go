@@ -0,0 +1,95 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ovh/cds/sdk/interpolate"
+)
+
+// Preview environment statuses.
+const (
+	PreviewEnvironmentStatusActive         = "Active"
+	PreviewEnvironmentStatusTearingDown    = "TearingDown"
+	PreviewEnvironmentStatusTeardownFailed = "TeardownFailed"
+)
+
+// Build parameters given to the node run that tears down a preview environment.
+const (
+	PreviewEnvironmentTeardownParameter    = "cds.preview.teardown"
+	PreviewEnvironmentNameParameter        = "cds.preview.environment"
+	PreviewEnvironmentPullRequestParameter = "git.pr.id"
+)
+
+// WorkflowNodePreviewEnvironment is the preview environment of a workflow node. When it is set, the node runs
+// use a clone of the environment of the node, created on the first run for each computed name.
+type WorkflowNodePreviewEnvironment struct {
+	// Name is a template interpolated with the build parameters of the node run (ex: pr-{{.git.pr.id}})
+	Name string `json:"name"`
+	// Variables overrides the variables of the base environment, values are interpolated with the build parameters
+	Variables map[string]string `json:"variables,omitempty"`
+	// TeardownPipeline is the name of the pipeline run when the pull request is closed or the branch is deleted
+	TeardownPipeline string `json:"teardown_pipeline,omitempty"`
+}
+
+// IsValid returns an error if the preview environment is not valid.
+func (p WorkflowNodePreviewEnvironment) IsValid() error {
+	if p.Name == "" {
+		return NewErrorFrom(ErrWrongRequest, "invalid empty name for preview environment")
+	}
+	for name := range p.Variables {
+		if !NamePatternRegex.MatchString(name) {
+			return NewErrorFrom(ErrWrongRequest, "invalid variable name %q for preview environment, it should match %s", name, NamePattern)
+		}
+	}
+	if p.TeardownPipeline != "" && !NamePatternRegex.MatchString(p.TeardownPipeline) {
+		return NewErrorFrom(ErrWrongRequest, "invalid teardown pipeline name %q for preview environment", p.TeardownPipeline)
+	}
+	return nil
+}
+
+// ComputeName returns the name of the preview environment for given build parameters.
+func (p WorkflowNodePreviewEnvironment) ComputeName(params map[string]string) (string, error) {
+	name, err := interpolate.Do(p.Name, params)
+	if err != nil {
+		return "", NewErrorFrom(ErrWrongRequest, "cannot compute preview environment name from %q: %v", p.Name, err)
+	}
+	if !NamePatternRegex.MatchString(name) {
+		return "", NewErrorFrom(ErrWrongRequest, "invalid preview environment name %q computed from %q, it should match %s", name, p.Name, NamePattern)
+	}
+	return name, nil
+}
+
+// ComputeVariables returns the variables of the preview environment interpolated with given build parameters.
+func (p WorkflowNodePreviewEnvironment) ComputeVariables(params map[string]string) (map[string]string, error) {
+	vars := make(map[string]string, len(p.Variables))
+	for name, value := range p.Variables {
+		v, err := interpolate.Do(value, params)
+		if err != nil {
+			return nil, fmt.Errorf("cannot compute value of preview environment variable %s: %v", name, err)
+		}
+		vars[name] = v
+	}
+	return vars, nil
+}
+
+// EnvironmentPreview tracks an environment created for a workflow node with a preview environment, against the
+// branch and the pull request it was created for.
+type EnvironmentPreview struct {
+	ID                int64     `json:"id" db:"id"`
+	ProjectID         int64     `json:"project_id" db:"project_id"`
+	EnvironmentID     int64     `json:"environment_id" db:"environment_id"`
+	EnvironmentName   string    `json:"environment_name" db:"environment_name" cli:"environment,key"`
+	BaseEnvironmentID int64     `json:"base_environment_id" db:"base_environment_id"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id"`
+	WorkflowName      string    `json:"workflow_name" db:"workflow_name"`
+	WorkflowRunNumber int64     `json:"workflow_run_number" db:"workflow_run_number" cli:"number"`
+	WorkflowNodeName  string    `json:"workflow_node_name" db:"workflow_node_name" cli:"node"`
+	Repository        string    `json:"repository" db:"repository"`
+	Branch            string    `json:"branch" db:"branch" cli:"branch"`
+	PullRequestID     string    `json:"pull_request_id,omitempty" db:"pull_request_id" cli:"pull_request"`
+	TeardownPipeline  string    `json:"teardown_pipeline,omitempty" db:"teardown_pipeline"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+	LastModified      time.Time `json:"last_modified" db:"last_modified"`
+}
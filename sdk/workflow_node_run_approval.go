@@ -0,0 +1,96 @@
+package sdk
+
+import "time"
+
+// Approval gate statuses.
+const (
+	ApprovalStatusPending  = "Pending"
+	ApprovalStatusApproved = "Approved"
+	ApprovalStatusRejected = "Rejected"
+)
+
+// WorkflowNodeApproval is the approval gate of a workflow node. When it is set, the node runs
+// wait for a minimal number of approvals before being executed.
+type WorkflowNodeApproval struct {
+	MinApprovals int64 `json:"min_approvals"`
+	// Groups lists the names of the groups which members can approve, if empty any user with execute permission can
+	Groups []string `json:"groups,omitempty"`
+	// Timeout in seconds after which the node run is rejected, zero means no timeout
+	Timeout int64 `json:"timeout,omitempty"`
+}
+
+// IsValid returns an error if the approval gate is not valid.
+func (a WorkflowNodeApproval) IsValid() error {
+	if a.MinApprovals < 1 {
+		return NewErrorFrom(ErrWrongRequest, "invalid min approvals for approval gate, should be greater than 0")
+	}
+	if a.Timeout < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid timeout for approval gate")
+	}
+	for _, g := range a.Groups {
+		if g == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid empty group name for approval gate")
+		}
+	}
+	return nil
+}
+
+// TimeoutDuration returns the timeout of the approval gate as a time.Duration.
+func (a WorkflowNodeApproval) TimeoutDuration() time.Duration {
+	return time.Duration(a.Timeout) * time.Second
+}
+
+// WorkflowNodeRunApproval is the approval gate of a node run, it keeps the decisions given by approvers.
+type WorkflowNodeRunApproval struct {
+	ID                int64                             `json:"id" db:"id"`
+	ProjectID         int64                             `json:"project_id" db:"project_id"`
+	WorkflowID        int64                             `json:"workflow_id" db:"workflow_id"`
+	WorkflowName      string                            `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	WorkflowRunID     int64                             `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowRunNumber int64                             `json:"workflow_run_number" db:"workflow_run_number" cli:"number"`
+	WorkflowNodeRunID int64                             `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	WorkflowNodeName  string                            `json:"workflow_node_name" db:"workflow_node_name" cli:"node,key"`
+	MinApprovals      int64                             `json:"min_approvals" db:"min_approvals" cli:"min_approvals"`
+	Groups            StringSlice                       `json:"groups,omitempty" db:"approver_groups"`
+	Timeout           int64                             `json:"timeout,omitempty" db:"timeout"`
+	Status            string                            `json:"status" db:"status" cli:"status"`
+	Created           time.Time                         `json:"created" db:"created" cli:"created"`
+	LastModified      time.Time                         `json:"last_modified" db:"last_modified"`
+	Decisions         []WorkflowNodeRunApprovalDecision `json:"decisions,omitempty" db:"-"`
+}
+
+// Approvals returns the number of approvals given for the node run.
+func (a WorkflowNodeRunApproval) Approvals() int64 {
+	var n int64
+	for i := range a.Decisions {
+		if a.Decisions[i].Approved {
+			n++
+		}
+	}
+	return n
+}
+
+// ComputeStatus returns the status of the approval gate from its decisions, the gate is rejected
+// as soon as one approver rejects it.
+func (a WorkflowNodeRunApproval) ComputeStatus() string {
+	for i := range a.Decisions {
+		if !a.Decisions[i].Approved {
+			return ApprovalStatusRejected
+		}
+	}
+	if a.Approvals() >= a.MinApprovals {
+		return ApprovalStatusApproved
+	}
+	return ApprovalStatusPending
+}
+
+// WorkflowNodeRunApprovalDecision is the decision given by a user on a node run approval gate.
+type WorkflowNodeRunApprovalDecision struct {
+	ID                 int64     `json:"id" db:"id"`
+	ApprovalID         int64     `json:"approval_id" db:"workflow_node_run_approval_id"`
+	AuthentifiedUserID string    `json:"authentified_user_id" db:"authentified_user_id"`
+	Username           string    `json:"username" db:"username" cli:"username"`
+	Approved           bool      `json:"approved" db:"approved" cli:"approved"`
+	Comment            string    `json:"comment,omitempty" db:"comment" cli:"comment"`
+	Created            time.Time `json:"created" db:"created" cli:"created"`
+}
@@ -0,0 +1,33 @@
+package sdk
+
+import (
+	"regexp"
+	"time"
+)
+
+// WorkflowNodeRunLockNamePattern is the pattern for lock names.
+var WorkflowNodeRunLockNamePattern = regexp.MustCompile("^[a-zA-Z0-9._-]{1,256}$")
+
+// WorkflowNodeRunLock represents a named lock shared by all the workflows of a project.
+// A lock is held by one node run at a time, other node runs that need it are queued.
+type WorkflowNodeRunLock struct {
+	ID                int64     `json:"id" db:"id"`
+	ProjectID         int64     `json:"project_id" db:"project_id"`
+	Name              string    `json:"name" db:"name" cli:"name,key"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id"`
+	WorkflowName      string    `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowRunNumber int64     `json:"workflow_run_number" db:"workflow_run_number" cli:"number"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	WorkflowNodeName  string    `json:"workflow_node_name" db:"workflow_node_name" cli:"node"`
+	Held              bool      `json:"held" db:"held" cli:"held"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// IsValidWorkflowNodeRunLockName returns an error if given lock name is invalid.
+func IsValidWorkflowNodeRunLockName(name string) error {
+	if name != "" && !WorkflowNodeRunLockNamePattern.MatchString(name) {
+		return NewErrorFrom(ErrWrongRequest, "invalid lock name %q, it should match %s", name, WorkflowNodeRunLockNamePattern.String())
+	}
+	return nil
+}
@@ -138,3 +138,22 @@ func TestAncestorsSimple(t *testing.T) {
 
 	}
 }
+
+func TestWorkflowNodeRunApprovalComputeStatus(t *testing.T) {
+	a := WorkflowNodeRunApproval{MinApprovals: 2}
+	assert.Equal(t, ApprovalStatusPending, a.ComputeStatus())
+
+	a.Decisions = append(a.Decisions, WorkflowNodeRunApprovalDecision{Username: "foo", Approved: true})
+	assert.Equal(t, ApprovalStatusPending, a.ComputeStatus())
+	assert.Equal(t, int64(1), a.Approvals())
+
+	a.Decisions = append(a.Decisions, WorkflowNodeRunApprovalDecision{Username: "bar", Approved: true})
+	assert.Equal(t, ApprovalStatusApproved, a.ComputeStatus())
+
+	a.Decisions = append(a.Decisions, WorkflowNodeRunApprovalDecision{Username: "baz", Approved: false})
+	assert.Equal(t, ApprovalStatusRejected, a.ComputeStatus(), "a single rejection should reject the gate")
+
+	assert.NoError(t, WorkflowNodeApproval{MinApprovals: 1, Groups: []string{"ops"}}.IsValid())
+	assert.Error(t, WorkflowNodeApproval{}.IsValid())
+	assert.Error(t, WorkflowNodeApproval{MinApprovals: 1, Timeout: -1}.IsValid())
+}
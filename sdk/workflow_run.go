@@ -226,6 +226,7 @@ type WorkflowNodeRun struct {
 	Payload                interface{}                          `json:"payload,omitempty"`
 	PipelineParameters     []Parameter                          `json:"pipeline_parameters,omitempty"`
 	BuildParameters        []Parameter                          `json:"build_parameters,omitempty"`
+	Outputs                map[string]string                    `json:"outputs,omitempty"`
 	Artifacts              []WorkflowNodeRunArtifact            `json:"artifacts,omitempty"`
 	StaticFiles            []StaticFiles                        `json:"static_files,omitempty"`
 	Coverage               WorkflowNodeRunCoverage              `json:"coverage,omitempty"`
@@ -372,6 +373,7 @@ type WorkflowNodeJobRun struct {
 	IntegrationPluginBinaries []GRPCPluginBinary `json:"integration_plugin_binaries,omitempty"`
 	Header                    WorkflowRunHeaders `json:"header,omitempty"`
 	ContainsService           bool               `json:"contains_service,omitempty"`
+	Priority                  string             `json:"priority,omitempty"`
 }
 
 // WorkflowNodeJobRunSummary is a light representation of WorkflowNodeJobRun for CDS event
@@ -439,6 +441,7 @@ type WorkflowNodeRunManual struct {
 	Username           string      `json:"username" db:"-"`
 	Fullname           string      `json:"fullname" db:"-"`
 	Email              string      `json:"email" db:"-"`
+	Priority           string      `json:"priority,omitempty" db:"-"`
 }
 
 //GetName returns the name the artifact
@@ -460,19 +463,32 @@ func (w *WorkflowNodeRunArtifact) GetPath() string {
 
 type WorkflowQueue []WorkflowNodeJobRun
 
-func (q WorkflowQueue) Sort() {
-	//Count the number of WorkflowNodeJobRun per project_id
+// SortFairShare orders the queue by priority level, with aging, then shares the workers between the projects. The jobs
+// of a level are interleaved by project, oldest first, and the projects with the less running jobs come first.
+// running is the number of running jobs by project ID.
+func (q WorkflowQueue) SortFairShare(running map[int64]int, now time.Time) {
+	level := make(map[int64]int, len(q))
+	for _, j := range q {
+		level[j.ID] = j.PriorityLevel(now)
+	}
+	sort.SliceStable(q, func(i, j int) bool {
+		if level[q[i].ID] != level[q[j].ID] {
+			return level[q[i].ID] < level[q[j].ID]
+		}
+		return q[i].Queued.Before(q[j].Queued)
+	})
+
+	rank := make(map[int64]int, len(q))
 	n := make(map[int64]int, len(q))
 	for _, j := range q {
-		nb := n[j.ProjectID]
-		nb++
-		n[j.ProjectID] = nb
+		rank[j.ID] = running[j.ProjectID] + n[j.ProjectID]
+		n[j.ProjectID]++
 	}
 
-	sort.Slice(q, func(i, j int) bool {
-		p1 := n[q[i].ProjectID]
-		p2 := n[q[j].ProjectID]
-		return p1 < p2
+	sort.SliceStable(q, func(i, j int) bool {
+		if level[q[i].ID] != level[q[j].ID] {
+			return level[q[i].ID] < level[q[j].ID]
+		}
+		return rank[q[i].ID] < rank[q[j].ID]
 	})
-
 }
@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+// Artifact provenance constants, the statement follows the in-toto attestation format with a SLSA provenance predicate.
+const (
+	ProvenanceStatementType   = "https://in-toto.io/Statement/v0.1"
+	ProvenancePredicateType   = "https://slsa.dev/provenance/v0.2"
+	ProvenanceBuildType       = "https://github.com/ovh/cds/workflow-job@v1"
+	ProvenancePayloadType     = "application/vnd.in-toto+json"
+	ArtifactProvenanceKeyName = "proj-artifact-provenance"
+	ArtifactProvenanceSuffix  = ".provenance.json"
+)
+
+// ProvenanceStatement is an in-toto statement about the artifacts listed as subjects.
+type ProvenanceStatement struct {
+	Type          string              `json:"_type"`
+	Subject       []ProvenanceSubject `json:"subject"`
+	PredicateType string              `json:"predicateType"`
+	Predicate     ProvenancePredicate `json:"predicate"`
+}
+
+// ProvenanceSubject is an artifact identified by its name and digests.
+type ProvenanceSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// ProvenancePredicate describes how the subjects were produced.
+type ProvenancePredicate struct {
+	Builder     ProvenanceBuilder     `json:"builder"`
+	BuildType   string                `json:"buildType"`
+	BuildConfig ProvenanceBuildConfig `json:"buildConfig"`
+	Metadata    ProvenanceMetadata    `json:"metadata"`
+	Materials   []ProvenanceMaterial  `json:"materials,omitempty"`
+}
+
+// ProvenanceBuilder is the worker model that ran the job, empty for a worker started without model.
+type ProvenanceBuilder struct {
+	ID string `json:"id"`
+}
+
+// ProvenanceBuildConfig is the CDS job that produced the subjects.
+type ProvenanceBuildConfig struct {
+	Project     string `json:"project"`
+	Workflow    string `json:"workflow"`
+	RunNumber   int64  `json:"run_number"`
+	SubNumber   int64  `json:"sub_number"`
+	Node        string `json:"node"`
+	Job         string `json:"job"`
+	WorkerName  string `json:"worker_name"`
+	WorkerModel string `json:"worker_model,omitempty"`
+}
+
+// ProvenanceMetadata contains the job run information.
+type ProvenanceMetadata struct {
+	BuildInvocationID string    `json:"buildInvocationId"`
+	BuildStartedOn    time.Time `json:"buildStartedOn"`
+}
+
+// ProvenanceMaterial is a source used to produce the subjects, the repository checked out by the workflow.
+type ProvenanceMaterial struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// ArtifactProvenance is a signed provenance statement, stored as a DSSE envelope next to the artifact.
+type ArtifactProvenance struct {
+	PayloadType string                        `json:"payloadType"`
+	Payload     []byte                        `json:"payload"`
+	Signatures  []ArtifactProvenanceSignature `json:"signatures"`
+}
+
+// ArtifactProvenanceSignature is an armored PGP detached signature of the envelope.
+type ArtifactProvenanceSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// ProvenancePAE returns the pre-authentication encoding of a DSSE payload, this is what is signed.
+func ProvenancePAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// Statement returns the unverified statement of the envelope.
+func (p ArtifactProvenance) Statement() (*ProvenanceStatement, error) {
+	if p.PayloadType != ProvenancePayloadType {
+		return nil, NewErrorFrom(ErrInvalidData, "invalid provenance payload type %q", p.PayloadType)
+	}
+	var s ProvenanceStatement
+	if err := json.Unmarshal(p.Payload, &s); err != nil {
+		return nil, NewErrorWithStack(err, NewErrorFrom(ErrInvalidData, "cannot read provenance statement"))
+	}
+	if s.Type != ProvenanceStatementType || s.PredicateType != ProvenancePredicateType {
+		return nil, NewErrorFrom(ErrInvalidData, "invalid provenance statement type %q with predicate %q", s.Type, s.PredicateType)
+	}
+	return &s, nil
+}
+
+// Verify checks that the envelope is signed by given armored PGP public key and that one of its subjects
+// has given name and sha512 digest. It returns the verified statement.
+func (p ArtifactProvenance) Verify(publicKey, name, sha512sum string) (*ProvenanceStatement, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(publicKey))
+	if err != nil {
+		return nil, NewErrorWithStack(err, NewErrorFrom(ErrInvalidData, "cannot read provenance public key"))
+	}
+
+	pae := ProvenancePAE(p.PayloadType, p.Payload)
+	var signed bool
+	for _, s := range p.Signatures {
+		if _, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(pae), strings.NewReader(s.Sig)); err == nil {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return nil, NewErrorFrom(ErrInvalidData, "provenance is not signed by given key")
+	}
+
+	s, err := p.Statement()
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range s.Subject {
+		if sub.Name == name && sub.Digest["sha512"] == sha512sum {
+			return s, nil
+		}
+	}
+	return nil, NewErrorFrom(ErrInvalidData, "provenance doesn't match artifact %s with sha512 %s", name, sha512sum)
+}
@@ -0,0 +1,110 @@
+package sdk
+
+import (
+	"path"
+	"time"
+)
+
+// WorkflowRunRetention is the rule-based retention of the workflow runs, it replaces the history length and the purge tags.
+// The runs of the branches that don't match any rule are kept up to the history length of the workflow.
+type WorkflowRunRetention struct {
+	// DefaultBranch is the number of runs to keep on the default branch of the repository
+	DefaultBranch int64 `json:"default_branch,omitempty" yaml:"default_branch,omitempty"`
+	// Branches are the number of runs to keep on each branch matching a pattern, the first matching rule is used
+	Branches []WorkflowRunRetentionBranch `json:"branches,omitempty" yaml:"branches,omitempty"`
+	// DeletedBranchDays deletes the runs of the branches deleted from the repository after given number of days
+	DeletedBranchDays int64 `json:"deleted_branch_days,omitempty" yaml:"deleted_branch_days,omitempty"`
+	// KeepEnvironments are the environments the runs that deployed to are never purged
+	KeepEnvironments []string `json:"keep_environments,omitempty" yaml:"keep_environments,omitempty"`
+}
+
+// WorkflowRunRetentionBranch is the number of runs to keep on each branch matching the pattern (ex: feature/*).
+type WorkflowRunRetentionBranch struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Keep    int64  `json:"keep" yaml:"keep"`
+}
+
+// IsValid returns an error if the run retention is not valid.
+func (r WorkflowRunRetention) IsValid() error {
+	if r.DefaultBranch < 0 {
+		return NewErrorFrom(ErrWorkflowInvalid, "invalid run retention default_branch %d", r.DefaultBranch)
+	}
+	if r.DeletedBranchDays < 0 {
+		return NewErrorFrom(ErrWorkflowInvalid, "invalid run retention deleted_branch_days %d", r.DeletedBranchDays)
+	}
+	for _, b := range r.Branches {
+		if b.Pattern == "" {
+			return NewErrorFrom(ErrWorkflowInvalid, "invalid run retention branch rule without pattern")
+		}
+		if _, err := path.Match(b.Pattern, ""); err != nil {
+			return NewErrorFrom(ErrWorkflowInvalid, "invalid run retention branch pattern %q", b.Pattern)
+		}
+		if b.Keep < 1 {
+			return NewErrorFrom(ErrWorkflowInvalid, "invalid run retention keep %d for branch pattern %q", b.Keep, b.Pattern)
+		}
+	}
+	for _, e := range r.KeepEnvironments {
+		if e == "" {
+			return NewErrorFrom(ErrWorkflowInvalid, "invalid run retention empty environment")
+		}
+	}
+	return nil
+}
+
+// NeedsRepository returns true if the rules need the branches of the repository.
+func (r WorkflowRunRetention) NeedsRepository() bool {
+	return r.DefaultBranch > 0 || r.DeletedBranchDays > 0
+}
+
+// BranchRule returns the pattern and the number of runs to keep of the first rule matching the branch.
+func (r WorkflowRunRetention) BranchRule(branch string) (string, int64, bool) {
+	for _, b := range r.Branches {
+		if ok, _ := path.Match(b.Pattern, branch); ok {
+			return b.Pattern, b.Keep, true
+		}
+	}
+	return "", 0, false
+}
+
+// KeptEnvironment returns the first environment of the list that the rules keep.
+func (r WorkflowRunRetention) KeptEnvironment(environments []string) (string, bool) {
+	for _, e := range environments {
+		if IsInArray(e, r.KeepEnvironments) {
+			return e, true
+		}
+	}
+	return "", false
+}
+
+// WorkflowRunRetentionItem is a workflow run with the result of the run retention.
+type WorkflowRunRetentionItem struct {
+	WorkflowRunID int64     `json:"workflow_run_id" cli:"-"`
+	Number        int64     `json:"num" cli:"num"`
+	Status        string    `json:"status" cli:"status"`
+	LastModified  time.Time `json:"last_modified" cli:"last_modified"`
+	Branch        string    `json:"branch" cli:"branch"`
+	Environments  []string  `json:"environments,omitempty" cli:"-"`
+	Keep          bool      `json:"keep" cli:"keep"`
+	Reason        string    `json:"reason" cli:"reason"`
+}
+
+// WorkflowRunRetentionReport lists the runs of a workflow that are kept or purged by its run retention.
+type WorkflowRunRetentionReport struct {
+	ProjectKey    string                     `json:"project_key"`
+	WorkflowName  string                     `json:"workflow_name"`
+	Retention     WorkflowRunRetention       `json:"retention"`
+	HistoryLength int64                      `json:"history_length"`
+	DefaultBranch string                     `json:"default_branch,omitempty"`
+	Items         []WorkflowRunRetentionItem `json:"items"`
+}
+
+// PurgedRunIDs returns the ids of the runs to purge.
+func (r WorkflowRunRetentionReport) PurgedRunIDs() []int64 {
+	var ids []int64
+	for _, i := range r.Items {
+		if !i.Keep {
+			ids = append(ids, i.WorkflowRunID)
+		}
+	}
+	return ids
+}
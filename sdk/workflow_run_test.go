@@ -124,117 +124,60 @@ func TestWorkflowRunReport(t *testing.T) {
 	t.Log(s)
 }
 
-func TestWorkflowQueue_Sort(t *testing.T) {
-	now := time.Now()
+func TestWorkflowQueue_SortFairShare(t *testing.T) {
 	t10, _ := time.Parse(time.RFC3339, "2018-09-01T10:00:00+00:00")
 	t11, _ := time.Parse(time.RFC3339, "2018-09-01T11:00:00+00:00")
 	t12, _ := time.Parse(time.RFC3339, "2018-09-01T12:00:00+00:00")
 	t13, _ := time.Parse(time.RFC3339, "2018-09-01T13:00:00+00:00")
 	t14, _ := time.Parse(time.RFC3339, "2018-09-01T14:00:00+00:00")
-	t15, _ := time.Parse(time.RFC3339, "2018-09-01T15:00:00+00:00")
-	t16, _ := time.Parse(time.RFC3339, "2018-09-01T16:00:00+00:00")
-
-	tests := []struct {
-		name     string
-		q        WorkflowQueue
-		expected WorkflowQueue
-	}{
-		{
-			name: "test sort 1",
-			q: WorkflowQueue{
-				{
-					ProjectID:     1,
-					ID:            1,
-					Queued:        t10,
-					QueuedSeconds: now.Unix() - t10.Unix(),
-				},
-				{
-					ProjectID:     1,
-					ID:            2,
-					Queued:        t11,
-					QueuedSeconds: now.Unix() - t11.Unix(),
-				},
-				{
-					ProjectID:     2,
-					ID:            3,
-					Queued:        t12,
-					QueuedSeconds: now.Unix() - t12.Unix(),
-				},
-				{
-					ProjectID:     1,
-					ID:            4,
-					Queued:        t13,
-					QueuedSeconds: now.Unix() - t13.Unix(),
-				},
-				{
-					ProjectID:     1,
-					ID:            5,
-					Queued:        t14,
-					QueuedSeconds: now.Unix() - t14.Unix(),
-				},
-				{
-					ProjectID:     2,
-					ID:            6,
-					Queued:        t15,
-					QueuedSeconds: now.Unix() - t15.Unix(),
-				},
-				{
-					ProjectID:     1,
-					ID:            7,
-					Queued:        t16,
-					QueuedSeconds: now.Unix() - t16.Unix(),
-				},
-			},
-			expected: WorkflowQueue{
-				{
-					ProjectID:     2,
-					ID:            3,
-					Queued:        t12,
-					QueuedSeconds: now.Unix() - t12.Unix(),
-				},
-				{
-					ProjectID:     2,
-					ID:            6,
-					Queued:        t15,
-					QueuedSeconds: now.Unix() - t15.Unix(),
-				},
-				{
-					ProjectID:     1,
-					ID:            1,
-					Queued:        t10,
-					QueuedSeconds: now.Unix() - t10.Unix(),
-				},
-				{
-					ProjectID:     1,
-					ID:            2,
-					Queued:        t11,
-					QueuedSeconds: now.Unix() - t11.Unix(),
-				},
-				{
-					ProjectID:     1,
-					ID:            4,
-					Queued:        t13,
-					QueuedSeconds: now.Unix() - t13.Unix(),
-				},
-				{
-					ProjectID:     1,
-					ID:            5,
-					Queued:        t14,
-					QueuedSeconds: now.Unix() - t14.Unix(),
-				},
-				{
-					ProjectID:     1,
-					ID:            7,
-					Queued:        t16,
-					QueuedSeconds: now.Unix() - t16.Unix(),
-				},
-			},
-		},
+
+	q := WorkflowQueue{
+		{ProjectID: 1, ID: 1, Queued: t10},
+		{ProjectID: 1, ID: 2, Queued: t11},
+		{ProjectID: 1, ID: 3, Queued: t12},
+		{ProjectID: 2, ID: 4, Queued: t13},
+		{ProjectID: 3, ID: 5, Queued: t14},
+		{ProjectID: 2, ID: 6, Queued: t10},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.q.Sort()
-			assert.Equal(t, tt.expected, tt.q)
-		})
+
+	// Project 2 already has two running jobs
+	q.SortFairShare(map[int64]int{2: 2}, t14.Add(time.Hour))
+
+	ids := make([]int64, len(q))
+	for i := range q {
+		ids[i] = q[i].ID
+	}
+	assert.Equal(t, []int64{1, 5, 2, 6, 3, 4}, ids)
+}
+
+func TestWorkflowQueue_SortFairSharePriority(t *testing.T) {
+	t9, _ := time.Parse(time.RFC3339, "2018-09-01T09:00:00+00:00")
+	t10, _ := time.Parse(time.RFC3339, "2018-09-01T10:00:00+00:00")
+	t1010, _ := time.Parse(time.RFC3339, "2018-09-01T10:10:00+00:00")
+	t1020, _ := time.Parse(time.RFC3339, "2018-09-01T10:20:00+00:00")
+	t1040, _ := time.Parse(time.RFC3339, "2018-09-01T10:40:00+00:00")
+	t1045, _ := time.Parse(time.RFC3339, "2018-09-01T10:45:00+00:00")
+
+	q := WorkflowQueue{
+		// Waited more than the aging delay, raised to the high level
+		{ProjectID: 1, ID: 1, Queued: t10},
+		// Raised to the normal level
+		{ProjectID: 1, ID: 2, Queued: t1010, Priority: JobPriorityLow},
+		{ProjectID: 2, ID: 3, Queued: t1040, Priority: JobPriorityHigh},
+		{ProjectID: 1, ID: 4, Queued: t1020, Priority: JobPriorityNormal},
+		// An old low priority job is not starved
+		{ProjectID: 1, ID: 5, Queued: t9, Priority: JobPriorityLow},
 	}
+
+	q.SortFairShare(nil, t1045)
+
+	ids := make([]int64, len(q))
+	for i := range q {
+		ids[i] = q[i].ID
+	}
+	// The jobs of a level are shared between the projects
+	assert.Equal(t, []int64{5, 3, 1, 2, 4}, ids)
+
+	assert.Equal(t, 2, WorkflowNodeJobRun{Queued: t1045, Priority: JobPriorityLow}.PriorityLevel(t1045))
+	assert.Equal(t, 0, WorkflowNodeJobRun{Queued: t9, Priority: JobPriorityLow}.PriorityLevel(t1045))
 }
@@ -8,6 +8,7 @@ export class Action {
     group_id: number;
     name: string;
     step_name: string;
+    step_id: string;
     type: string;
     description = '';
     requirements: Array<Requirement>;
@@ -17,6 +18,9 @@ export class Action {
     always_executed: boolean;
     enabled: boolean;
     deprecated: boolean;
+    timeout: number;
+    retry: ActionRetry;
+    outputs: {[key: string]: string};
     group: Group;
     first_audit: AuditAction;
     last_audit: AuditAction;
@@ -28,6 +32,13 @@ export class Action {
     showAddStep: boolean;
 }
 
+export class ActionRetry {
+    max_attempts: number;
+    backoff: number;
+    when: Array<string>;
+    exit_codes: Array<number>;
+}
+
 export class Usage {
     pipelines: Array<UsagePipeline>;
     actions: Array<UsageAction>;
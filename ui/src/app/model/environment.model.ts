@@ -11,6 +11,7 @@ export class Environment {
     last_modified: number;
     usage: Usage;
     from_repository: string;
+    lock: string;
 
     mute: boolean;
 }
@@ -20,6 +20,16 @@ export class Job {
         this.action.enabled = true;
         this.ref = new Date().getTime();
     }
+
+    // matrixName returns the name of the job without the matrix combination suffix
+    // if the job was generated from a matrix, else undefined.
+    static matrixName(j: Job): string {
+        if (!j.action || !j.action.parameters || !j.action.parameters.find(p => p.name.startsWith('cds.matrix.'))) {
+            return undefined;
+        }
+        let i = j.action.name.lastIndexOf(' (');
+        return i > 0 ? j.action.name.substring(0, i) : j.action.name;
+    }
 }
 
 export class StepStatus {
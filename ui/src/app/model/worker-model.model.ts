@@ -43,6 +43,33 @@ export class ModelDocker {
     envs: {};
     cmd: string;
     memory: number;
+    pod_template: ModelPodTemplate;
+}
+
+export class ModelPodTemplate {
+    node_selector: {};
+    tolerations: Array<ModelPodToleration>;
+    service_account_name: string;
+    annotations: {};
+    volumes: Array<ModelPodVolume>;
+}
+
+export class ModelPodToleration {
+    key: string;
+    operator: string;
+    value: string;
+    effect: string;
+}
+
+export class ModelPodVolume {
+    name: string;
+    mount_path: string;
+    read_only: boolean;
+    host_path: string;
+    config_map: string;
+    secret: string;
+    persistent_volume_claim: string;
+    empty_dir: boolean;
 }
 
 export class ModelVirtualMachine {
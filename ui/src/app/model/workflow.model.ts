@@ -35,6 +35,8 @@ export class Workflow {
     usage: Usage;
     history_length: number;
     purge_tags: Array<string>;
+    artifact_retention: WorkflowArtifactRetention;
+    run_retention: WorkflowRunRetention;
     notifications: Array<WorkflowNotification>;
     from_repository: string;
     from_template: string;
@@ -347,6 +349,26 @@ export class Workflow {
     }
 }
 
+export class WorkflowArtifactRetention {
+    keep_last_per_branch: number;
+    keep_days: number;
+    keep_tags: Array<string>;
+    keep_releases: boolean;
+    dry_run: boolean;
+}
+
+export class WorkflowRunRetention {
+    default_branch: number;
+    branches: Array<WorkflowRunRetentionBranch>;
+    deleted_branch_days: number;
+    keep_environments: Array<string>;
+}
+
+export class WorkflowRunRetentionBranch {
+    pattern: string;
+    keep: number;
+}
+
 export class WorkflowPipelineNameImpact {
     nodes = new Array<WNode>();
 }
@@ -643,6 +665,22 @@ export class WNodeContext {
     default_pipeline_parameters: Array<Parameter>;
     conditions: WorkflowNodeConditions;
     mutex: boolean;
+    lock: string;
+    approval: WNodeApproval;
+    preview_environment: WNodePreviewEnvironment;
+    priority: string;
+}
+
+export class WNodeApproval {
+    min_approvals: number;
+    groups: Array<string>;
+    timeout: number;
+}
+
+export class WNodePreviewEnvironment {
+    name: string;
+    variables: { [key: string]: string };
+    teardown_pipeline: string;
 }
 
 export class WNodeOutgoingHook {
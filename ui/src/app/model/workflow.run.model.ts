@@ -84,6 +84,7 @@ export class WorkflowNodeRun implements WithKey {
     payload: {};
     pipeline_parameters: Array<Parameter>;
     build_parameters: Array<Parameter>;
+    outputs: {[key: string]: string};
     artifacts: Array<WorkflowNodeRunArtifact>;
     tests: Tests;
     commits: Array<Commit>;
@@ -151,6 +152,7 @@ export class WorkflowNodeJobRun {
     model: string;
     bookedby: Hatchery;
     spawninfos: Array<SpawnInfo>;
+    priority: string;
 
     // UI infos for queue
     duration: string;
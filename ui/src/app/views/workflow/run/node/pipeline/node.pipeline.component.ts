@@ -44,6 +44,8 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
     selectedRunJobParameters = {};
     mapJobStatus: Map<number, { status: string, warnings: number }> = new Map<number, { status: string, warnings: number }>();
     mapStepStatus: Map<string, StepStatus> = new Map<string, StepStatus>();
+    // matrix name for the first job of each group of jobs generated from a matrix
+    mapMatrixName: Map<number, string> = new Map<number, string>();
 
     previousStatus: string;
     manual = false;
@@ -143,6 +145,20 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
         if (this.nodeRun) {
             this.previousStatus = this.nodeRun.status;
         }
+        this.mapMatrixName = new Map<number, string>();
+        if (this.nodeRun.stages) {
+            this.nodeRun.stages.forEach(s => {
+                let previousMatrixName: string;
+                (s.jobs || []).forEach(j => {
+                    let matrixName = Job.matrixName(j);
+                    if (matrixName && matrixName !== previousMatrixName) {
+                        this.mapMatrixName.set(j.pipeline_action_id, matrixName);
+                    }
+                    previousMatrixName = matrixName;
+                });
+            });
+        }
+
         // Set selected job if needed or refresh step_status
         if (this.nodeRun.stages) {
             this.nodeRun.stages.forEach((s, sIndex) => {
@@ -11,7 +11,7 @@ import { Router } from '@angular/router';
 import { TranslateService } from '@ngx-translate/core';
 import { Store } from '@ngxs/store';
 import { Project } from 'app/model/project.model';
-import { Workflow } from 'app/model/workflow.model';
+import { Workflow, WorkflowArtifactRetention } from 'app/model/workflow.model';
 import { WorkflowRunService } from 'app/service/workflow/run/workflow.run.service';
 import { AutoUnsubscribe } from 'app/shared/decorator/autoUnsubscribe';
 import { WarningModalComponent } from 'app/shared/modal/warning/warning.component';
@@ -42,6 +42,9 @@ export class WorkflowAdminComponent implements OnInit, OnDestroy {
             if (this._workflow.purge_tags && this._workflow.purge_tags.length) {
                 this.purgeTag = this._workflow.purge_tags[0];
             }
+            this.artifactRetention = this._workflow.artifact_retention ?
+                cloneDeep(this._workflow.artifact_retention) : new WorkflowArtifactRetention();
+            this.artifactRetentionTags = (this.artifactRetention.keep_tags || []).join(',');
         }
     };
     get workflow() { return this._workflow };
@@ -57,6 +60,8 @@ export class WorkflowAdminComponent implements OnInit, OnDestroy {
     existingTags = new Array<string>();
     selectedTags = new Array<string>();
     purgeTag: string;
+    artifactRetention: WorkflowArtifactRetention;
+    artifactRetentionTags: string;
     iconUpdated = false;
     tagsToAdd = new Array<string>();
 
@@ -196,6 +201,15 @@ export class WorkflowAdminComponent implements OnInit, OnDestroy {
                 delete this._workflow.purge_tags;
             }
 
+            this.artifactRetention.keep_tags = (this.artifactRetentionTags || '').split(',')
+                .map(t => t.trim()).filter(t => t !== '');
+            if (this.artifactRetention.keep_last_per_branch || this.artifactRetention.keep_days ||
+                this.artifactRetention.keep_tags.length || this.artifactRetention.keep_releases) {
+                this._workflow.artifact_retention = this.artifactRetention;
+            } else {
+                delete this._workflow.artifact_retention;
+            }
+
             actions.push(this.store.dispatch(new UpdateWorkflow({
                 projectKey: this.project.key,
                 workflowName: this.oldName,